@@ -0,0 +1,79 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldingRange(t *testing.T) {
+	s := startServer()
+
+	client := bytes.NewBuffer(make([]byte, 0, 1024))
+	server := bytes.NewBuffer(make([]byte, 0, 1024))
+	serverStream := &TestStream{incoming: server, outgoing: client, closed: false}
+	defer serverStream.Close()
+	go s.ServeStream(serverStream)
+
+	clientStream := jsonrpc2.NewBufferedStream(&TestStream{incoming: client, outgoing: server, closed: false}, jsonrpc2.VSCodeObjectCodec{})
+	defer clientStream.Close()
+	conn := jsonrpc2.NewConn(context.Background(), clientStream, &ConfigurationHandler{t: t})
+	initialize(t, conn, protocol.InitializeParams{})
+
+	kind := string(protocol.FoldingRangeKindRegion)
+	testCases := []struct {
+		name       string
+		languageID protocol.LanguageIdentifier
+		fileName   string
+		content    string
+		ranges     []protocol.FoldingRange
+	}{
+		{
+			name:       "nested regions in a Compose file",
+			languageID: protocol.DockerComposeLanguage,
+			fileName:   "compose.yaml",
+			content:    "# region services\nservices:\n  # region web\n  web:\n    image: nginx\n  # endregion\n# endregion",
+			ranges: []protocol.FoldingRange{
+				{StartLine: 0, EndLine: 6, Kind: &kind},
+				{StartLine: 2, EndLine: 5, Kind: &kind},
+			},
+		},
+		{
+			name:       "regions are not folded in Dockerfiles",
+			languageID: protocol.DockerfileLanguage,
+			fileName:   "Dockerfile",
+			content:    "# region\nFROM scratch\n# endregion",
+			ranges:     nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			didOpen := protocol.DidOpenTextDocumentParams{
+				TextDocument: protocol.TextDocumentItem{
+					URI:        fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), tc.fileName)), "/")),
+					Text:       tc.content,
+					LanguageID: tc.languageID,
+					Version:    1,
+				},
+			}
+			err := conn.Notify(context.Background(), protocol.MethodTextDocumentDidOpen, didOpen)
+			require.NoError(t, err)
+
+			var ranges []protocol.FoldingRange
+			err = conn.Call(context.Background(), protocol.MethodTextDocumentFoldingRange, protocol.FoldingRangeParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: didOpen.TextDocument.URI},
+			}, &ranges)
+			require.NoError(t, err)
+			require.Equal(t, tc.ranges, ranges)
+		})
+	}
+}
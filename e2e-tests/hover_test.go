@@ -130,11 +130,7 @@ func testHover_Compose(t *testing.T, composeSupport bool) {
 	clientStream := jsonrpc2.NewBufferedStream(&TestStream{incoming: client, outgoing: server, closed: false}, jsonrpc2.VSCodeObjectCodec{})
 	defer clientStream.Close()
 	conn := jsonrpc2.NewConn(context.Background(), clientStream, &ConfigurationHandler{t: t})
-	initialize(t, conn, protocol.InitializeParams{
-		InitializationOptions: map[string]any{
-			"dockercomposeExperimental": map[string]bool{"composeSupport": composeSupport},
-		},
-	})
+	initializeComposeSupport(t, conn, composeSupport)
 
 	homedir, err := os.UserHomeDir()
 	require.NoError(t, err)
@@ -152,7 +148,7 @@ func testHover_Compose(t *testing.T, composeSupport bool) {
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "declared for backward compatibility, ignored. Please remove it.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/version-and-name/)",
+					Value: "declared for backward compatibility, ignored. Please remove it.\n\nType: `string`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/version-and-name/)",
 				},
 			},
 		},
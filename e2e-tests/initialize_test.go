@@ -93,23 +93,25 @@ func createDidChangeTextDocumentParams(homedir, testName, text string, version i
 }
 
 func createGuaranteedInitializeResult() protocol.InitializeResult {
-	syncKind := protocol.TextDocumentSyncKindFull
+	syncKind := protocol.TextDocumentSyncKindIncremental
 	return protocol.InitializeResult{
 		Capabilities: protocol.ServerCapabilities{
 			CodeActionProvider: protocol.CodeActionOptions{},
 			CompletionProvider: &protocol.CompletionOptions{
-				TriggerCharacters: []string{"/"},
+				TriggerCharacters: []string{"/", ":", " ", "-", "$"},
 			},
 			DefinitionProvider:        protocol.DefinitionOptions{},
 			DocumentHighlightProvider: &protocol.DocumentHighlightOptions{},
 			DocumentLinkProvider:      &protocol.DocumentLinkOptions{},
 			DocumentSymbolProvider:    protocol.DocumentSymbolOptions{},
 			ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
-				Commands: []string{types.TelemetryCallbackCommandId},
+				Commands: []string{types.TelemetryCallbackCommandId, types.SortServiceKeysCommandId, types.ToggleMappingFormCommandId, types.StartupOrderCommandId, types.GenerateEnvTemplateCommandId, types.NormalizeReferencesCommandId},
 			},
+			FoldingRangeProvider:     protocol.FoldingRangeOptions{},
 			HoverProvider:            protocol.HoverOptions{},
 			InlayHintProvider:        protocol.InlayHintOptions{},
 			InlineCompletionProvider: protocol.InlineCompletionOptions{},
+			ReferencesProvider:       protocol.ReferenceOptions{},
 			SemanticTokensProvider: protocol.SemanticTokensOptions{
 				Legend: protocol.SemanticTokensLegend{
 					TokenModifiers: []string{},
@@ -122,6 +124,12 @@ func createGuaranteedInitializeResult() protocol.InitializeResult {
 				OpenClose: &protocol.True,
 				Change:    &syncKind,
 			},
+			Workspace: &protocol.ServerCapabilitiesWorkspace{
+				WorkspaceFolders: &protocol.WorkspaceFoldersServerCapabilities{
+					Supported:           &protocol.True,
+					ChangeNotifications: &protocol.BoolOrString{Value: true},
+				},
+			},
 		},
 		ServerInfo: &protocol.InitializeResultServerInfo{
 			Name:    "docker-language-server",
@@ -137,6 +145,19 @@ func initialize(t *testing.T, conn *jsonrpc2.Conn, initializeParams protocol.Ini
 	initializeCheck(t, conn, initializeParams, expected)
 }
 
+// initializeComposeSupport initializes the server with Compose support
+// toggled on or off.
+func initializeComposeSupport(t *testing.T, conn *jsonrpc2.Conn, composeSupport bool) {
+	expected := createGuaranteedInitializeResult()
+	expected.Capabilities.DocumentFormattingProvider = protocol.DocumentFormattingOptions{}
+	expected.Capabilities.RenameProvider = protocol.RenameOptions{PrepareProvider: types.CreateBoolPointer(true)}
+	initializeCheck(t, conn, protocol.InitializeParams{
+		InitializationOptions: map[string]any{
+			"dockercomposeExperimental": map[string]bool{"composeSupport": composeSupport},
+		},
+	}, expected)
+}
+
 func initializeCheck(t *testing.T, conn *jsonrpc2.Conn, initializeParams protocol.InitializeParams, expected protocol.InitializeResult) {
 	if options, ok := initializeParams.InitializationOptions.(map[string]any); ok {
 		options["telemetry"] = "off"
@@ -294,6 +315,71 @@ func TestInitialize(t *testing.T) {
 				return expected
 			},
 		},
+		{
+			name: "code lens provider advertised if the client supports Bake builds",
+			params: protocol.InitializeParams{
+				Capabilities: protocol.ClientCapabilities{
+					Experimental: map[string]any{
+						"dockerLanguageServerCapabilities": map[string]any{
+							"commands": []string{types.BakeBuildCommandId},
+						},
+					},
+				},
+			},
+			result: func() protocol.InitializeResult {
+				expected := createGuaranteedInitializeResult()
+				expected.Capabilities.CodeLensProvider = &protocol.CodeLensOptions{}
+				expected.Capabilities.DocumentFormattingProvider = protocol.DocumentFormattingOptions{}
+				expected.Capabilities.RenameProvider = protocol.RenameOptions{PrepareProvider: types.CreateBoolPointer(true)}
+				return expected
+			},
+		},
+		{
+			name: "rename provider still advertised for Dockerfiles if Compose support is disabled",
+			params: protocol.InitializeParams{
+				InitializationOptions: map[string]any{
+					"dockercomposeExperimental": map[string]any{"composeSupport": false},
+				},
+			},
+			result: func() protocol.InitializeResult {
+				expected := createGuaranteedInitializeResult()
+				expected.Capabilities.DocumentFormattingProvider = protocol.DocumentFormattingOptions{}
+				expected.Capabilities.RenameProvider = protocol.RenameOptions{PrepareProvider: types.CreateBoolPointer(true)}
+				return expected
+			},
+		},
+		{
+			name: "save notifications requested if validating on save",
+			params: protocol.InitializeParams{
+				InitializationOptions: map[string]any{"validateOnSave": true},
+			},
+			result: func() protocol.InitializeResult {
+				expected := createGuaranteedInitializeResult()
+				syncKind := protocol.TextDocumentSyncKindIncremental
+				expected.Capabilities.TextDocumentSync = protocol.TextDocumentSyncOptions{
+					OpenClose: &protocol.True,
+					Change:    &syncKind,
+					Save:      &protocol.True,
+				}
+				expected.Capabilities.DocumentFormattingProvider = protocol.DocumentFormattingOptions{}
+				expected.Capabilities.RenameProvider = protocol.RenameOptions{PrepareProvider: types.CreateBoolPointer(true)}
+				return expected
+			},
+		},
+		{
+			name: "dynamically registered providers are not advertised",
+			params: protocol.InitializeParams{
+				Capabilities: protocol.ClientCapabilities{
+					TextDocument: &protocol.TextDocumentClientCapabilities{
+						Formatting: &protocol.DocumentFormattingClientCapabilities{DynamicRegistration: types.CreateBoolPointer(true)},
+						Rename:     &protocol.RenameClientCapabilities{DynamicRegistration: types.CreateBoolPointer(true)},
+					},
+				},
+			},
+			result: func() protocol.InitializeResult {
+				return createGuaranteedInitializeResult()
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -424,6 +510,14 @@ func TestRegisterCapability(t *testing.T) {
 			},
 			registrationParams: &protocol.RegistrationParams{
 				Registrations: []protocol.Registration{
+					{
+						ID:     "docker.lsp.dockerfile.textDocument.rename",
+						Method: "textDocument/rename",
+						RegisterOptions: map[string]any{
+							"documentSelector": []any{map[string]any{"language": "dockerfile"}},
+							"prepareProvider":  true,
+						},
+					},
 					{
 						ID:     "docker.lsp.dockercompose.textDocument.rename",
 						Method: "textDocument/rename",
@@ -29,11 +29,7 @@ func testPrepareRename(t *testing.T, composeSupport bool) {
 	clientStream := jsonrpc2.NewBufferedStream(&TestStream{incoming: client, outgoing: server, closed: false}, jsonrpc2.VSCodeObjectCodec{})
 	defer clientStream.Close()
 	conn := jsonrpc2.NewConn(context.Background(), clientStream, &ConfigurationHandler{t: t})
-	initialize(t, conn, protocol.InitializeParams{
-		InitializationOptions: map[string]any{
-			"dockercomposeExperimental": map[string]bool{"composeSupport": composeSupport},
-		},
-	})
+	initializeComposeSupport(t, conn, composeSupport)
 
 	homedir, err := os.UserHomeDir()
 	require.NoError(t, err)
@@ -42,7 +38,7 @@ func testPrepareRename(t *testing.T, composeSupport bool) {
 		name     string
 		content  string
 		position protocol.Position
-		result   *protocol.Range
+		result   *protocol.RangeWithPlaceholder
 	}{
 		{
 			name: "rename dependent service",
@@ -53,9 +49,12 @@ services:
       - test2
   test2:`,
 			position: protocol.Position{Line: 4, Character: 11},
-			result: &protocol.Range{
-				Start: protocol.Position{Line: 4, Character: 8},
-				End:   protocol.Position{Line: 4, Character: 13},
+			result: &protocol.RangeWithPlaceholder{
+				Range: protocol.Range{
+					Start: protocol.Position{Line: 4, Character: 8},
+					End:   protocol.Position{Line: 4, Character: 13},
+				},
+				Placeholder: "test2",
 			},
 		},
 	}
@@ -66,7 +65,7 @@ services:
 			err := conn.Notify(context.Background(), protocol.MethodTextDocumentDidOpen, didOpen)
 			require.NoError(t, err)
 
-			var result *protocol.Range
+			var result *protocol.RangeWithPlaceholder
 			err = conn.Call(context.Background(), protocol.MethodTextDocumentPrepareRename, protocol.PrepareRenameParams{
 				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
 					TextDocument: protocol.TextDocumentIdentifier{URI: didOpen.TextDocument.URI},
@@ -0,0 +1,193 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/configuration"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferences(t *testing.T) {
+	testReferences(t, true)
+	testReferences(t, false)
+}
+
+func testReferences(t *testing.T, composeSupport bool) {
+	s := startServer()
+
+	client := bytes.NewBuffer(make([]byte, 0, 1024))
+	server := bytes.NewBuffer(make([]byte, 0, 1024))
+	serverStream := &TestStream{incoming: server, outgoing: client, closed: false}
+	defer serverStream.Close()
+	go s.ServeStream(serverStream)
+
+	clientStream := jsonrpc2.NewBufferedStream(&TestStream{incoming: client, outgoing: server, closed: false}, jsonrpc2.VSCodeObjectCodec{})
+	defer clientStream.Close()
+	conn := jsonrpc2.NewConn(context.Background(), clientStream, &ConfigurationHandler{t: t})
+	initializeComposeSupport(t, conn, composeSupport)
+
+	homedir, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name               string
+		content            string
+		position           protocol.Position
+		includeDeclaration bool
+		ranges             []protocol.Range
+	}{
+		{
+			name: "dependent service with its declaration",
+			content: `
+services:
+  test:
+    depends_on:
+      - test2
+  test2:`,
+			position:           protocol.Position{Line: 4, Character: 11},
+			includeDeclaration: true,
+			ranges: []protocol.Range{
+				{Start: protocol.Position{Line: 4, Character: 8}, End: protocol.Position{Line: 4, Character: 13}},
+				{Start: protocol.Position{Line: 5, Character: 2}, End: protocol.Position{Line: 5, Character: 7}},
+			},
+		},
+		{
+			name: "dependent service without its declaration",
+			content: `
+services:
+  test:
+    depends_on:
+      - test2
+  test2:`,
+			position:           protocol.Position{Line: 5, Character: 4},
+			includeDeclaration: false,
+			ranges: []protocol.Range{
+				{Start: protocol.Position{Line: 4, Character: 8}, End: protocol.Position{Line: 4, Character: 13}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v (composeSupport=%v)", tc.name, composeSupport), func(t *testing.T) {
+			didOpen := createDidOpenTextDocumentParams(homedir, t.Name()+".yaml", tc.content, protocol.DockerComposeLanguage)
+			err := conn.Notify(context.Background(), protocol.MethodTextDocumentDidOpen, didOpen)
+			require.NoError(t, err)
+
+			var locations []protocol.Location
+			err = conn.Call(context.Background(), protocol.MethodTextDocumentReferences, protocol.ReferenceParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: didOpen.TextDocument.URI},
+					Position:     tc.position,
+				},
+				Context: protocol.ReferenceContext{IncludeDeclaration: tc.includeDeclaration},
+			}, &locations)
+			require.NoError(t, err)
+			if composeSupport {
+				expected := []protocol.Location{}
+				for _, rng := range tc.ranges {
+					expected = append(expected, protocol.Location{URI: didOpen.TextDocument.URI, Range: rng})
+				}
+				require.Equal(t, expected, locations)
+			} else {
+				require.Nil(t, locations)
+			}
+		})
+	}
+}
+
+type ProgressHandler struct {
+	t        *testing.T
+	mutex    sync.Mutex
+	progress map[string][]json.RawMessage
+}
+
+func (h *ProgressHandler) Handle(_ context.Context, conn *jsonrpc2.Conn, request *jsonrpc2.Request) {
+	switch request.Method {
+	case protocol.MethodProgress:
+		if request.Notif && request.Params != nil {
+			var params struct {
+				Token string          `json:"token"`
+				Value json.RawMessage `json:"value"`
+			}
+			require.NoError(h.t, json.Unmarshal(*request.Params, &params))
+			h.mutex.Lock()
+			h.progress[params.Token] = append(h.progress[params.Token], params.Value)
+			h.mutex.Unlock()
+		}
+	case protocol.ServerWorkspaceConfiguration:
+		if !request.Notif && request.Params != nil {
+			HandleConfiguration(h.t, conn, request, configuration.Experimental{})
+		}
+	}
+}
+
+func TestReferences_PartialResults(t *testing.T) {
+	s := startServer()
+
+	client := bytes.NewBuffer(make([]byte, 0, 1024))
+	server := bytes.NewBuffer(make([]byte, 0, 1024))
+	serverStream := &TestStream{incoming: server, outgoing: client, closed: false}
+	defer serverStream.Close()
+	go s.ServeStream(serverStream)
+
+	handler := &ProgressHandler{t: t, progress: map[string][]json.RawMessage{}}
+	clientStream := jsonrpc2.NewBufferedStream(&TestStream{incoming: client, outgoing: server, closed: false}, jsonrpc2.VSCodeObjectCodec{})
+	defer clientStream.Close()
+	conn := jsonrpc2.NewConn(context.Background(), clientStream, handler)
+	initializeComposeSupport(t, conn, true)
+
+	homedir, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	didOpen := createDidOpenTextDocumentParams(homedir, t.Name()+".yaml", `
+services:
+  test:
+    depends_on:
+      - test2
+  test2:`, protocol.DockerComposeLanguage)
+	err = conn.Notify(context.Background(), protocol.MethodTextDocumentDidOpen, didOpen)
+	require.NoError(t, err)
+
+	var locations []protocol.Location
+	workDoneToken := protocol.IntegerOrString{Value: "work"}
+	partialResultToken := protocol.IntegerOrString{Value: "partial"}
+	err = conn.Call(context.Background(), protocol.MethodTextDocumentReferences, protocol.ReferenceParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: didOpen.TextDocument.URI},
+			Position:     protocol.Position{Line: 4, Character: 11},
+		},
+		WorkDoneProgressParams: protocol.WorkDoneProgressParams{WorkDoneToken: &workDoneToken},
+		PartialResultParams:    protocol.PartialResultParams{PartialResultToken: &partialResultToken},
+		Context:                protocol.ReferenceContext{IncludeDeclaration: true},
+	}, &locations)
+	require.NoError(t, err)
+	require.Equal(t, []protocol.Location{}, locations)
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	require.Len(t, handler.progress["partial"], 1)
+	var partial []protocol.Location
+	require.NoError(t, json.Unmarshal(handler.progress["partial"][0], &partial))
+	require.Equal(t, []protocol.Location{
+		{URI: didOpen.TextDocument.URI, Range: protocol.Range{Start: protocol.Position{Line: 4, Character: 8}, End: protocol.Position{Line: 4, Character: 13}}},
+		{URI: didOpen.TextDocument.URI, Range: protocol.Range{Start: protocol.Position{Line: 5, Character: 2}, End: protocol.Position{Line: 5, Character: 7}}},
+	}, partial)
+
+	kinds := []string{}
+	for _, value := range handler.progress["work"] {
+		var progress struct {
+			Kind string `json:"kind"`
+		}
+		require.NoError(t, json.Unmarshal(value, &progress))
+		kinds = append(kinds, progress.Kind)
+	}
+	require.Equal(t, []string{"begin", "report", "end"}, kinds)
+}
@@ -29,11 +29,7 @@ func testRename(t *testing.T, composeSupport bool) {
 	clientStream := jsonrpc2.NewBufferedStream(&TestStream{incoming: client, outgoing: server, closed: false}, jsonrpc2.VSCodeObjectCodec{})
 	defer clientStream.Close()
 	conn := jsonrpc2.NewConn(context.Background(), clientStream, &ConfigurationHandler{t: t})
-	initialize(t, conn, protocol.InitializeParams{
-		InitializationOptions: map[string]any{
-			"dockercomposeExperimental": map[string]bool{"composeSupport": composeSupport},
-		},
-	})
+	initializeComposeSupport(t, conn, composeSupport)
 
 	homedir, err := os.UserHomeDir()
 	require.NoError(t, err)
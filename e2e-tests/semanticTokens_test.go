@@ -83,6 +83,14 @@ func TestSemanticTokensFull(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:               "interpolated variable in a Compose file",
+			languageIdentifier: protocol.DockerComposeLanguage,
+			content:            "services:\n  web:\n    image: ${IMAGE:-alpine}",
+			result: &protocol.SemanticTokens{
+				Data: []uint32{2, 13, 5, hcl.SemanticTokenTypeIndex(hcl.TokenType_Variable), 0},
+			},
+		},
 		{
 			name:               "open dockerfile.hcl (issue 84)",
 			languageIdentifier: protocol.DockerfileLanguage,
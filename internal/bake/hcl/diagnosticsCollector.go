@@ -40,12 +40,71 @@ var builtinArgs = []string{
 }
 
 type BakeHCLDiagnosticsCollector struct {
-	docs  *document.Manager
+	docs *document.Manager
+}
+
+func NewBakeHCLDiagnosticsCollector(docs *document.Manager) textdocument.DiagnosticsCollector {
+	return &BakeHCLDiagnosticsCollector{docs: docs}
+}
+
+// BakeScoutDiagnosticsCollector reports the vulnerabilities of the
+// images that the targets of a Bake file are tagged with.
+type BakeScoutDiagnosticsCollector struct {
 	scout scout.Service
 }
 
-func NewBakeHCLDiagnosticsCollector(docs *document.Manager, scout scout.Service) textdocument.DiagnosticsCollector {
-	return &BakeHCLDiagnosticsCollector{docs: docs, scout: scout}
+func NewBakeScoutDiagnosticsCollector(scout scout.Service) textdocument.DiagnosticsCollector {
+	return &BakeScoutDiagnosticsCollector{scout: scout}
+}
+
+func (c *BakeScoutDiagnosticsCollector) SupportsLanguageIdentifier(languageIdentifier protocol.LanguageIdentifier) bool {
+	return languageIdentifier == protocol.DockerBakeLanguage
+}
+
+// Deferrable returns true as analyzing images requires calls to Docker
+// Scout.
+func (c *BakeScoutDiagnosticsCollector) Deferrable() bool {
+	return true
+}
+
+func (c *BakeScoutDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder string, doc document.Document, text string) []protocol.Diagnostic {
+	file := doc.(document.BakeHCLDocument).File()
+	if file == nil {
+		return nil
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, block := range body.Blocks {
+		if block.Type == "target" && len(block.Labels) == 1 {
+			if attribute, ok := block.Body.Attributes["tags"]; ok {
+				if expr, ok := attribute.Expr.(*hclsyntax.TupleConsExpr); ok {
+					for _, e := range expr.Exprs {
+						if templateExpr, ok := e.(*hclsyntax.TemplateExpr); ok {
+							if templateExpr.IsStringLiteral() {
+								value, _ := templateExpr.Value(&hcl.EvalContext{})
+								target := value.AsString()
+								imageDiagnostics, err := c.scout.Analyze(protocol.DocumentUri(doc.URI()), target)
+								if err == nil {
+									for _, diagnostic := range imageDiagnostics {
+										if diagnostic.Kind == "critical_high_vulnerabilities" || diagnostic.Kind == "vulnerabilities" {
+											rng := templateExpr.SrcRange
+											diagnostics = append(diagnostics, scout.ConvertDiagnostic(diagnostic, source, createProtocolRange(rng, true), nil))
+											break
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return diagnostics
 }
 
 func UnwrapToHCL(err error) hcl.Diagnostics {
@@ -93,6 +152,7 @@ func (c *BakeHCLDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder
 		for _, hclDiagnostic := range hclDiagnostics {
 			diagnostic := protocol.Diagnostic{
 				Message:  fmt.Sprintf("%v (%v)", hclDiagnostic.Summary, hclDiagnostic.Detail),
+				Code:     &protocol.IntegerOrString{Value: "InvalidBakeFile"},
 				Source:   types.CreateStringPointer(source),
 				Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 			}
@@ -139,12 +199,14 @@ func (c *BakeHCLDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder
 		}
 	}
 
+	diagnostics = append(diagnostics, checkGroupTargets(body, source)...)
 	for _, block := range body.Blocks {
 		if block.Type == "target" && len(block.Labels) == 1 {
 			if _, ok := block.Body.Attributes["dockerfile-inline"]; ok {
 				if attribute, ok := block.Body.Attributes["dockerfile"]; ok {
 					diagnostics = append(diagnostics, protocol.Diagnostic{
 						Message:  "dockerfile attribute is ignored if dockerfile-inline is defined",
+						Code:     &protocol.IntegerOrString{Value: "IgnoredDockerfileAttribute"},
 						Source:   types.CreateStringPointer(source),
 						Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityWarning),
 						Tags:     []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary},
@@ -163,29 +225,6 @@ func (c *BakeHCLDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder
 				}
 			}
 
-			if attribute, ok := block.Body.Attributes["tags"]; ok {
-				if expr, ok := attribute.Expr.(*hclsyntax.TupleConsExpr); ok {
-					for _, e := range expr.Exprs {
-						if templateExpr, ok := e.(*hclsyntax.TemplateExpr); ok {
-							if templateExpr.IsStringLiteral() {
-								value, _ := templateExpr.Value(&hcl.EvalContext{})
-								target := value.AsString()
-								imageDiagnostics, err := c.scout.Analyze(protocol.DocumentUri(doc.URI()), target)
-								if err == nil {
-									for _, diagnostic := range imageDiagnostics {
-										if diagnostic.Kind == "critical_high_vulnerabilities" || diagnostic.Kind == "vulnerabilities" {
-											rng := templateExpr.SrcRange
-											diagnostics = append(diagnostics, scout.ConvertDiagnostic(diagnostic, source, createProtocolRange(rng, true), nil))
-											break
-										}
-									}
-								}
-							}
-						}
-					}
-				}
-			}
-
 			if attribute, ok := block.Body.Attributes["entitlements"]; ok {
 				if tupleConsExpr, ok := attribute.Expr.(*hclsyntax.TupleConsExpr); ok {
 					for _, e := range tupleConsExpr.Exprs {
@@ -194,6 +233,7 @@ func (c *BakeHCLDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder
 								value, _ := templateExpr.Value(&hcl.EvalContext{})
 								diagnostic := checkStringLiteral(
 									source,
+									"InvalidEntitlement",
 									value.AsString(),
 									"entitlements attribute must be either: network.host or security.insecure",
 									[]string{"network.host", "security.insecure"},
@@ -215,6 +255,7 @@ func (c *BakeHCLDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder
 						value, _ := templateExpr.Value(&hcl.EvalContext{})
 						diagnostic := checkStringLiteral(
 							source,
+							"InvalidNetworkMode",
 							value.AsString(),
 							"network attribute must be either: default, host, or none",
 							[]string{"default", "host", "none"},
@@ -306,6 +347,7 @@ func (c *BakeHCLDiagnosticsCollector) checkTargetArgs(nodes []*parser.Node, inpu
 		if _, ok := args[arg]; !ok {
 			diagnostic := createDiagnostic(
 				source,
+				"UndefinedArg",
 				fmt.Sprintf("'%v' not defined as an ARG in your Dockerfile", arg),
 				item.KeyExpr.Range(),
 			)
@@ -333,6 +375,7 @@ func (c *BakeHCLDiagnosticsCollector) checkTargetTarget(nodes []*parser.Node, ex
 	if !found {
 		return &protocol.Diagnostic{
 			Message:  "target could not be found in your Dockerfile",
+			Code:     &protocol.IntegerOrString{Value: "UndefinedDockerfileTarget"},
 			Source:   types.CreateStringPointer(source),
 			Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 			Range: protocol.Range{
@@ -361,16 +404,51 @@ func LiteralValue(expr hclsyntax.Expression) bool {
 	return false
 }
 
-func checkStringLiteral(diagnosticSource, attributeValue, message string, expectedValues []string, attributeRange hcl.Range) *protocol.Diagnostic {
+// checkGroupTargets reports the string literals in the targets
+// attribute of group blocks that do not refer to a target or group
+// that is defined in the file.
+func checkGroupTargets(body *hclsyntax.Body, source string) []protocol.Diagnostic {
+	names := []string{}
+	for _, block := range body.Blocks {
+		if (block.Type == "target" || block.Type == "group") && len(block.Labels) == 1 {
+			names = append(names, block.Labels[0])
+		}
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, block := range body.Blocks {
+		if block.Type != "group" {
+			continue
+		}
+
+		if attribute, ok := block.Body.Attributes["targets"]; ok {
+			if tupleConsExpr, ok := attribute.Expr.(*hclsyntax.TupleConsExpr); ok {
+				for _, e := range tupleConsExpr.Exprs {
+					if templateExpr, ok := e.(*hclsyntax.TemplateExpr); ok && templateExpr.IsStringLiteral() {
+						value, _ := templateExpr.Value(&hcl.EvalContext{})
+						target := value.AsString()
+						if !slices.Contains(names, target) {
+							diagnostics = append(diagnostics, *createDiagnostic(source, "UndefinedTarget", fmt.Sprintf("target %v could not be found in this file", target), templateExpr.SrcRange))
+						}
+					}
+				}
+			}
+		}
+	}
+	return diagnostics
+}
+
+func checkStringLiteral(diagnosticSource, code, attributeValue, message string, expectedValues []string, attributeRange hcl.Range) *protocol.Diagnostic {
 	if slices.Contains(expectedValues, attributeValue) {
 		return nil
 	}
-	return createDiagnostic(diagnosticSource, message, attributeRange)
+	return createDiagnostic(diagnosticSource, code, message, attributeRange)
 }
 
-func createDiagnostic(diagnosticSource, message string, attributeRange hcl.Range) *protocol.Diagnostic {
+func createDiagnostic(diagnosticSource, code, message string, attributeRange hcl.Range) *protocol.Diagnostic {
 	return &protocol.Diagnostic{
 		Message:  message,
+		Code:     &protocol.IntegerOrString{Value: code},
 		Source:   types.CreateStringPointer(diagnosticSource),
 		Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 		Range: protocol.Range{
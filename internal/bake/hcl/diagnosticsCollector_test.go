@@ -28,6 +28,7 @@ func TestCollectDiagnostics(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "Missing name for target (All target blocks must have 1 labels (name).)",
+					Code:     &protocol.IntegerOrString{Value: "InvalidBakeFile"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -43,6 +44,7 @@ func TestCollectDiagnostics(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "Invalid expression (Expected the start of an expression, but found an invalid expression token.)",
+					Code:     &protocol.IntegerOrString{Value: "InvalidBakeFile"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -77,6 +79,7 @@ func TestCollectDiagnostics(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "network attribute must be either: default, host, or none",
+					Code:     &protocol.IntegerOrString{Value: "InvalidNetworkMode"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -92,6 +95,7 @@ func TestCollectDiagnostics(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "entitlements attribute must be either: network.host or security.insecure",
+					Code:     &protocol.IntegerOrString{Value: "InvalidEntitlement"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -117,6 +121,7 @@ func TestCollectDiagnostics(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "'missing' not defined as an ARG in your Dockerfile",
+					Code:     &protocol.IntegerOrString{Value: "UndefinedArg"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -138,6 +143,7 @@ func TestCollectDiagnostics(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "'missing' not defined as an ARG in your Dockerfile",
+					Code:     &protocol.IntegerOrString{Value: "UndefinedArg"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -168,6 +174,7 @@ func TestCollectDiagnostics(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "target could not be found in your Dockerfile",
+					Code:     &protocol.IntegerOrString{Value: "UndefinedDockerfileTarget"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -188,6 +195,7 @@ func TestCollectDiagnostics(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "dockerfile attribute is ignored if dockerfile-inline is defined",
+					Code:     &protocol.IntegerOrString{Value: "IgnoredDockerfileAttribute"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityWarning),
 					Tags:     []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary},
@@ -264,6 +272,7 @@ target "build" {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "'NON_EXISTENT_VAR' not defined as an ARG in your Dockerfile",
+					Code:     &protocol.IntegerOrString{Value: "UndefinedArg"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -314,6 +323,7 @@ target "build" {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "'VAR' not defined as an ARG in your Dockerfile",
+					Code:     &protocol.IntegerOrString{Value: "UndefinedArg"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -331,6 +341,7 @@ target "build" {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "Invalid multi-line string (Quoted strings may not be split over multiple lines. To produce a multi-line string, either use the \\n escape to represent a newline character or use the \"heredoc\" multi-line template syntax.)",
+					Code:     &protocol.IntegerOrString{Value: "InvalidBakeFile"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -340,6 +351,7 @@ target "build" {
 				},
 				{
 					Message:  "Invalid multi-line string (Quoted strings may not be split over multiple lines. To produce a multi-line string, either use the \\n escape to represent a newline character or use the \"heredoc\" multi-line template syntax.)",
+					Code:     &protocol.IntegerOrString{Value: "InvalidBakeFile"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -349,6 +361,7 @@ target "build" {
 				},
 				{
 					Message:  "Unclosed template interpolation sequence (There is no closing brace for this interpolation sequence before the end of the quoted template. This might be caused by incorrect nesting inside the given expression.)",
+					Code:     &protocol.IntegerOrString{Value: "InvalidBakeFile"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -358,6 +371,32 @@ target "build" {
 				},
 			},
 		},
+		{
+			name:        "group targets that are defined",
+			content:     "group \"default\" {\n  targets = [\"t1\", \"nested\"]\n}\ngroup \"nested\" {\n  targets = [\"t1\"]\n}\ntarget \"t1\" {\n}",
+			diagnostics: []protocol.Diagnostic{},
+		},
+		{
+			name:    "group targets that are not defined",
+			content: "group \"default\" {\n  targets = [\"t1\", \"missing\"]\n}\ntarget \"t1\" {\n}",
+			diagnostics: []protocol.Diagnostic{
+				{
+					Message:  "target missing could not be found in this file",
+					Code:     &protocol.IntegerOrString{Value: "UndefinedTarget"},
+					Source:   types.CreateStringPointer("docker-language-server"),
+					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
+					Range: protocol.Range{
+						Start: protocol.Position{Line: 1, Character: 19},
+						End:   protocol.Position{Line: 1, Character: 28},
+					},
+				},
+			},
+		},
+		{
+			name:        "group targets that are not string literals are ignored",
+			content:     "variable \"T\" {\n  default = \"t1\"\n}\ngroup \"default\" {\n  targets = [\"${T}\"]\n}\ntarget \"t1\" {\n}",
+			diagnostics: []protocol.Diagnostic{},
+		},
 	}
 
 	wd, err := os.Getwd()
@@ -371,9 +410,11 @@ target "build" {
 		t.Run(tc.name, func(t *testing.T) {
 			manager := document.NewDocumentManager()
 			bytes := []byte(tc.content)
-			collector := &BakeHCLDiagnosticsCollector{docs: manager, scout: scout.NewService()}
+			collector := &BakeHCLDiagnosticsCollector{docs: manager}
+			scoutCollector := &BakeScoutDiagnosticsCollector{scout: scout.NewService(context.Background())}
 			doc := document.NewBakeHCLDocument(bakeFileURI, 1, bytes)
 			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			diagnostics = append(diagnostics, scoutCollector.CollectDiagnostics("docker-language-server", "", doc, "")...)
 			require.Equal(t, tc.diagnostics, diagnostics)
 		})
 	}
@@ -399,6 +440,7 @@ func TestCollectDiagnostics_WSL(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "target could not be found in your Dockerfile",
+					Code:     &protocol.IntegerOrString{Value: "UndefinedDockerfileTarget"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -421,6 +463,7 @@ func TestCollectDiagnostics_WSL(t *testing.T) {
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "'missing' not defined as an ARG in your Dockerfile",
+					Code:     &protocol.IntegerOrString{Value: "UndefinedArg"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -441,7 +484,7 @@ func TestCollectDiagnostics_WSL(t *testing.T) {
 			changed, err := manager.Write(context.Background(), uri.URI(dockerfileURI), protocol.DockerfileLanguage, 1, []byte(tc.dockerfileContent))
 			require.NoError(t, err)
 			require.True(t, changed)
-			collector := &BakeHCLDiagnosticsCollector{docs: manager, scout: scout.NewService()}
+			collector := &BakeHCLDiagnosticsCollector{docs: manager}
 			doc := document.NewBakeHCLDocument(bakeFileURI, 1, []byte(tc.content))
 			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
 			require.Equal(t, tc.diagnostics, diagnostics)
@@ -502,7 +545,7 @@ func TestCollectDiagnostics_Hierarchy(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			manager := document.NewDocumentManager()
 			bytes := []byte(tc.content)
-			collector := &BakeHCLDiagnosticsCollector{docs: manager, scout: scout.NewService()}
+			collector := &BakeHCLDiagnosticsCollector{docs: manager}
 			doc := document.NewBakeHCLDocument(bakeFileURI, 1, bytes)
 			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
 			require.Equal(t, tc.diagnostics, diagnostics)
@@ -522,7 +565,7 @@ func TestCollectDiagnostics_WSLHierarchy(t *testing.T) {
 			require.NoError(t, err)
 			require.True(t, changed)
 			bytes := []byte(tc.content)
-			collector := &BakeHCLDiagnosticsCollector{docs: manager, scout: scout.NewService()}
+			collector := &BakeHCLDiagnosticsCollector{docs: manager}
 			doc := document.NewBakeHCLDocument(bakeFileURI, 1, bytes)
 			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
 			require.Equal(t, tc.diagnostics, diagnostics)
@@ -3,10 +3,10 @@ package hcl
 import (
 	"context"
 	"errors"
-	"sort"
 
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl/v2"
@@ -137,39 +137,16 @@ func SemanticTokensFull(ctx context.Context, doc document.BakeHCLDocument, filen
 		}
 	}
 
-	sort.Slice(tokens, func(i, j int) bool {
-		if tokens[i].Range.Start.Line < tokens[j].Range.Start.Line {
-			return true
-		} else if tokens[i].Range.Start.Line > tokens[j].Range.Start.Line {
-			return false
-		}
-		return tokens[i].Range.Start.Column < tokens[j].Range.Start.Column
-	})
-
-	result := &protocol.SemanticTokens{}
-	currentLine := 0
-	currentOffset := 0
+	semanticTokens := []types.SemanticToken{}
 	for _, token := range tokens {
-		line := token.Range.Start.Line - 1
-		if line != currentLine {
-			currentOffset = 0
-		}
-
-		deltaLine := line - currentLine
-		startOffset := token.Range.Start.Column - 1
-		startCharacter := startOffset - currentOffset
-		tokenLength := token.Range.End.Column - token.Range.Start.Column
-
-		result.Data = append(result.Data, uint32(deltaLine))
-		result.Data = append(result.Data, uint32(startCharacter))
-		result.Data = append(result.Data, uint32(tokenLength))
-		result.Data = append(result.Data, TokenType(token.Type))
-		result.Data = append(result.Data, uint32(0)) // no modifiers at the moment
-
-		currentLine = line
-		currentOffset = startOffset
+		semanticTokens = append(semanticTokens, types.SemanticToken{
+			Line:      uint32(token.Range.Start.Line - 1),
+			Character: uint32(token.Range.Start.Column - 1),
+			Length:    uint32(token.Range.End.Column - token.Range.Start.Column),
+			Type:      TokenType(token.Type),
+		})
 	}
-
+	result := &protocol.SemanticTokens{Data: types.EncodeSemanticTokens(semanticTokens)}
 	return result, nil
 }
 
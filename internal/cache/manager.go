@@ -1,8 +1,12 @@
 package cache
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 type Key interface {
@@ -10,17 +14,20 @@ type Key interface {
 }
 
 type Fetcher[T any] interface {
-	Fetch(key Key) (T, error)
+	Fetch(ctx context.Context, key Key) (T, error)
 }
 
 type CacheManager[T any] interface {
-	Get(key Key) (T, error)
+	Get(ctx context.Context, key Key) (T, error)
 }
 
 type CacheManagerImpl[T any] struct {
 	mutex   sync.Mutex
 	cache   map[string]T
 	fetcher Fetcher[T]
+	// lookups shares a fetch of a key between the callers that ask for
+	// it while it is in flight
+	lookups singleflight.Group
 }
 
 func NewManager[T any](fetcher Fetcher[T]) CacheManager[T] {
@@ -30,24 +37,68 @@ func NewManager[T any](fetcher Fetcher[T]) CacheManager[T] {
 	}
 }
 
-func (c *CacheManagerImpl[T]) Get(key Key) (T, error) {
+// Get returns the cached value of the key or fetches it if it has not
+// been cached. The lock is not held while fetching so that a lookup
+// that is slow or has been cancelled does not block the others. Callers
+// that ask for the same key while it is being fetched wait for that
+// fetch instead of starting their own. If the caller that started the
+// fetch gives up on it then the fetch is started again for the callers
+// that are still waiting.
+func (c *CacheManagerImpl[T]) Get(ctx context.Context, key Key) (T, error) {
+	cacheKey := key.CacheKey()
+	var zero T
+	for {
+		if val, exists := c.cached(cacheKey); exists {
+			return val, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+
+		lookup := c.lookups.DoChan(cacheKey, func() (any, error) {
+			return c.fetch(ctx, key)
+		})
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case result := <-lookup:
+			if result.Err != nil {
+				if result.Shared && ctx.Err() == nil && errors.Is(result.Err, context.Canceled) {
+					// the caller that started the fetch has given up
+					continue
+				}
+				fetched, _ := result.Val.(T)
+				return fetched, result.Err
+			}
+			return result.Val.(T), nil
+		}
+	}
+}
+
+func (c *CacheManagerImpl[T]) cached(cacheKey string) (T, bool) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	val, exists := c.cache[cacheKey]
+	return val, exists
+}
 
+// fetch fetches the value of the key and caches it if it could be
+// fetched.
+func (c *CacheManagerImpl[T]) fetch(ctx context.Context, key Key) (T, error) {
 	cacheKey := key.CacheKey()
-	if val, exists := c.cache[cacheKey]; exists {
-		return val, nil
+	fetched, err := c.fetcher.Fetch(ctx, key)
+	if err != nil {
+		return fetched, err
 	}
 
-	fetched, err := c.fetcher.Fetch(key)
-	if err == nil {
-		c.cache[cacheKey] = fetched
-		// auto-expire after one hour
-		time.AfterFunc(1*time.Hour, func() {
-			c.mutex.Lock()
-			defer c.mutex.Unlock()
-			delete(c.cache, cacheKey)
-		})
-	}
-	return fetched, err
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.cache[cacheKey] = fetched
+	// auto-expire after one hour
+	time.AfterFunc(1*time.Hour, func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		delete(c.cache, cacheKey)
+	})
+	return fetched, nil
 }
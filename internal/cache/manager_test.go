@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testKey string
+
+func (k testKey) CacheKey() string {
+	return string(k)
+}
+
+// blockingFetcher blocks until the context has been cancelled unless
+// the key is "fast".
+type blockingFetcher struct {
+	calls atomic.Int32
+}
+
+func (f *blockingFetcher) Fetch(ctx context.Context, key Key) (string, error) {
+	f.calls.Add(1)
+	if key.CacheKey() == "fast" {
+		return "value", nil
+	}
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestGet_CancelledMidLookup(t *testing.T) {
+	fetcher := &blockingFetcher{}
+	manager := NewManager(fetcher)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	done := make(chan error)
+	go func() {
+		_, err := manager.Get(ctx, testKey("slow"))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "lookup did not return after its context was cancelled")
+	}
+
+	// the failed lookup must not have been cached
+	_, err := manager.Get(ctx, testKey("slow"))
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, int32(1), fetcher.calls.Load())
+}
+
+func TestGet_SlowLookupDoesNotBlockOthers(t *testing.T) {
+	fetcher := &blockingFetcher{}
+	manager := NewManager(fetcher)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_, _ = manager.Get(ctx, testKey("slow"))
+	}()
+
+	value, err := manager.Get(context.Background(), testKey("fast"))
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+
+	value, err = manager.Get(context.Background(), testKey("fast"))
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+}
+
+// gatedFetcher blocks until it is released or the context has been
+// cancelled.
+type gatedFetcher struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (f *gatedFetcher) Fetch(ctx context.Context, key Key) (string, error) {
+	f.calls.Add(1)
+	select {
+	case <-f.release:
+		return "value", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestGet_ConcurrentLookupsShareFetch(t *testing.T) {
+	fetcher := &gatedFetcher{release: make(chan struct{})}
+	manager := NewManager(fetcher)
+
+	wg := sync.WaitGroup{}
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := manager.Get(context.Background(), testKey("key"))
+			require.NoError(t, err)
+			require.Equal(t, "value", value)
+		}()
+	}
+	require.Eventually(t, func() bool { return fetcher.calls.Load() == 1 }, 5*time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(fetcher.release)
+	wg.Wait()
+	require.Equal(t, int32(1), fetcher.calls.Load())
+}
+
+func TestGet_SharedLookupCancelledByFirstCaller(t *testing.T) {
+	fetcher := &gatedFetcher{release: make(chan struct{})}
+	manager := NewManager(fetcher)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	first := make(chan error)
+	go func() {
+		_, err := manager.Get(ctx, testKey("key"))
+		first <- err
+	}()
+	require.Eventually(t, func() bool { return fetcher.calls.Load() == 1 }, 5*time.Second, time.Millisecond)
+
+	second := make(chan string)
+	go func() {
+		value, err := manager.Get(context.Background(), testKey("key"))
+		require.NoError(t, err)
+		second <- value
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-first, context.Canceled)
+
+	// the second caller starts the fetch again instead of failing
+	require.Eventually(t, func() bool { return fetcher.calls.Load() == 2 }, 5*time.Second, time.Millisecond)
+	close(fetcher.release)
+	select {
+	case value := <-second:
+		require.Equal(t, "value", value)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "second lookup did not return")
+	}
+}
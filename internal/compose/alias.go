@@ -0,0 +1,190 @@
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+const (
+	nodeKindMapping  = "mapping"
+	nodeKindSequence = "sequence"
+	nodeKindScalar   = "scalar value"
+	nodeKindNull     = "null value"
+)
+
+// aliasValidator walks a document in order so that every alias will
+// be resolved to the closest anchor with the same name that precedes
+// it.
+type aliasValidator struct {
+	source      string
+	anchors     map[string]ast.Node
+	diagnostics []protocol.Diagnostic
+}
+
+// validateAliases reports aliases that are used in a position that is
+// structurally incompatible with the node that their anchor refers to.
+// A merge key only accepts mappings and attributes that the schema
+// restricts to certain types must not alias a node of another type.
+func validateAliases(source string, node ast.Node) []protocol.Diagnostic {
+	validator := &aliasValidator{source: source, anchors: map[string]ast.Node{}}
+	validator.walk(node, []*jsonschema.Schema{composeSchema}, "")
+	return validator.diagnostics
+}
+
+func (v *aliasValidator) walk(node ast.Node, schemas []*jsonschema.Schema, attributeName string) {
+	switch n := node.(type) {
+	case *ast.TagNode:
+		v.walk(n.Value, schemas, attributeName)
+	case *ast.AnchorNode:
+		v.anchors[n.Name.GetToken().Value] = n.Value
+		v.walk(n.Value, schemas, attributeName)
+	case *ast.AliasNode:
+		v.checkAlias(n, schemas, attributeName)
+	case *ast.MappingNode:
+		for _, mappingValueNode := range n.Values {
+			v.walkMappingValue(mappingValueNode, schemas)
+		}
+	case *ast.MappingValueNode:
+		v.walkMappingValue(n, schemas)
+	case *ast.SequenceNode:
+		items := itemSchemas(schemas)
+		for _, item := range n.Values {
+			v.walk(item, items, attributeName)
+		}
+	}
+}
+
+func (v *aliasValidator) walkMappingValue(node *ast.MappingValueNode, schemas []*jsonschema.Schema) {
+	if _, ok := node.Key.(*ast.MergeKeyNode); ok {
+		v.walkMergeValue(node.Value, schemas)
+		return
+	}
+
+	attributeName := resolveAnchor(node.Key).GetToken().Value
+	v.walk(node.Value, childSchemas(schemas, attributeName), attributeName)
+}
+
+// walkMergeValue checks that the value of a merge key is a mapping or
+// a sequence of mappings.
+func (v *aliasValidator) walkMergeValue(node ast.Node, schemas []*jsonschema.Schema) {
+	switch n := node.(type) {
+	case *ast.AliasNode:
+		v.checkMergeAlias(n)
+	case *ast.SequenceNode:
+		for _, item := range n.Values {
+			if alias, ok := item.(*ast.AliasNode); ok {
+				v.checkMergeAlias(alias)
+			} else {
+				v.walk(item, schemas, "")
+			}
+		}
+	default:
+		v.walk(node, schemas, "")
+	}
+}
+
+func (v *aliasValidator) checkMergeAlias(alias *ast.AliasNode) {
+	name := alias.Value.GetToken().Value
+	if kind := v.aliasedKind(name); kind != "" && kind != nodeKindMapping {
+		v.report(alias, fmt.Sprintf("merge key << requires a mapping but alias *%v refers to a %v", name, kind))
+	}
+}
+
+func (v *aliasValidator) checkAlias(alias *ast.AliasNode, schemas []*jsonschema.Schema, attributeName string) {
+	if attributeName == "" {
+		return
+	}
+
+	name := alias.Value.GetToken().Value
+	kind := v.aliasedKind(name)
+	allowed := allowedNodeKinds(schemas)
+	if kind == "" || len(allowed) == 0 || slices.Contains(allowed, kind) {
+		return
+	}
+
+	expected := []string{}
+	for _, allowedKind := range allowed {
+		if allowedKind != nodeKindNull {
+			expected = append(expected, "a "+allowedKind)
+		}
+	}
+	if len(expected) > 0 {
+		v.report(alias, fmt.Sprintf("alias *%v refers to a %v but %v expects %v", name, kind, attributeName, strings.Join(expected, " or ")))
+	}
+}
+
+func (v *aliasValidator) report(alias *ast.AliasNode, message string) {
+	rng := createRange(alias.GetToken(), len(alias.Value.GetToken().Value)+1)
+	v.diagnostics = append(v.diagnostics, createValidationDiagnostic(v.source, protocol.DiagnosticSeverityError, "IncompatibleAlias", message, rng))
+}
+
+// aliasedKind returns the kind of node that the named anchor refers
+// to. An empty string will be returned if the anchor is not defined.
+func (v *aliasValidator) aliasedKind(name string) string {
+	node, ok := v.anchors[name]
+	if !ok {
+		return ""
+	}
+
+	for {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			node = n.Value
+			continue
+		case *ast.TagNode:
+			node = n.Value
+			continue
+		case *ast.MappingNode, *ast.MappingValueNode:
+			return nodeKindMapping
+		case *ast.SequenceNode:
+			return nodeKindSequence
+		case *ast.NullNode:
+			return nodeKindNull
+		case *ast.AliasNode:
+			return ""
+		}
+		return nodeKindScalar
+	}
+}
+
+// allowedNodeKinds returns the kinds of nodes that the given schemas
+// accept. Nil will be returned if the schemas do not restrict the
+// types that are allowed.
+func allowedNodeKinds(schemas []*jsonschema.Schema) []string {
+	expanded := expandSchemas(schemas)
+	if len(expanded) == 0 {
+		return nil
+	}
+
+	kinds := []string{}
+	for _, schema := range expanded {
+		if schema.Types == nil {
+			return nil
+		}
+
+		for _, t := range schema.Types.ToStrings() {
+			kind := nodeKindScalar
+			switch t {
+			case "object":
+				kind = nodeKindMapping
+			case "array":
+				kind = nodeKindSequence
+			case "null":
+				kind = nodeKindNull
+			}
+			if !slices.Contains(kinds, kind) {
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+	slices.SortFunc(kinds, func(a, b string) int {
+		order := []string{nodeKindMapping, nodeKindSequence, nodeKindScalar, nodeKindNull}
+		return slices.Index(order, a) - slices.Index(order, b)
+	})
+	return kinds
+}
@@ -0,0 +1,201 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// remoteContext returns true if the build context is not a folder on
+// the local file system but a Git repository or a URL.
+func remoteContext(context string) bool {
+	return strings.Contains(context, "://") || strings.HasPrefix(context, "git@") || strings.HasPrefix(context, "github.com/")
+}
+
+// resolvePath returns the path that the given path refers to when it
+// is relative to the given folder.
+func resolvePath(folder, path string, wslDollarSign bool) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	_, resolved := types.Concatenate(folder, path, wslDollarSign)
+	return resolved
+}
+
+// conflictingDockerfileDiagnostic reports the later of the dockerfile
+// and dockerfile_inline attributes of a build object as only one of
+// them can be set. Quick fixes to remove either attribute are offered
+// if the build object is not in flow style.
+func conflictingDockerfileDiagnostic(source string, build *ast.MappingNode, dockerfile, dockerfileInline *ast.MappingValueNode) protocol.Diagnostic {
+	earlier, later := dockerfile, dockerfileInline
+	if later.Key.GetToken().Position.Line < earlier.Key.GetToken().Position.Line {
+		earlier, later = later, earlier
+	}
+	t := resolveAnchor(later.Key).GetToken()
+	diagnostic := createValidationDiagnostic(
+		source,
+		protocol.DiagnosticSeverityError,
+		"ConflictingDockerfile",
+		fmt.Sprintf("%v must not be set when %v is used", t.Value, resolveAnchor(earlier.Key).GetToken().Value),
+		createRange(t, len(t.Value)),
+	)
+	if !build.IsFlowStyle {
+		edits := []types.NamedEdit{}
+		for _, attribute := range []*ast.MappingValueNode{dockerfile, dockerfileInline} {
+			edits = append(edits, types.NamedEdit{
+				Title: fmt.Sprintf("Remove %v", resolveAnchor(attribute.Key).GetToken().Value),
+				Edit:  "",
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: protocol.UInteger(attribute.Key.GetToken().Position.Line - 1)},
+					End:   protocol.Position{Line: protocol.UInteger(lastLine(attribute))},
+				},
+			})
+		}
+		diagnostic.Data = edits
+	}
+	return diagnostic
+}
+
+// validateConflictingDockerfiles checks that the build objects of the
+// services do not set both the dockerfile and dockerfile_inline
+// attributes as a Dockerfile that has been inlined cannot also be given
+// as a path.
+func validateConflictingDockerfiles(source string, root *ast.MappingNode) []protocol.Diagnostic {
+	diagnostics := []protocol.Diagnostic{}
+	matchPropertyPath([]string{"services", "*", "build"}, nil, root, func(key, value ast.Node) {
+		build, ok := resolveAnchor(value).(*ast.MappingNode)
+		if !ok {
+			return
+		}
+		dockerfile := mappingValue(build, "dockerfile")
+		dockerfileInline := mappingValue(build, "dockerfile_inline")
+		if dockerfile != nil && dockerfileInline != nil {
+			diagnostics = append(diagnostics, conflictingDockerfileDiagnostic(source, build, dockerfile, dockerfileInline))
+		}
+	})
+	return diagnostics
+}
+
+// validateBuildDockerfiles checks that the Dockerfiles that are given
+// as a path in the services' build objects exist in the build context.
+// Build objects that also inline a Dockerfile are reported by
+// validateConflictingDockerfiles instead. Remote build contexts cannot
+// be checked and are ignored.
+func validateBuildDockerfiles(source string, documentPath *document.DocumentPath, root *ast.MappingNode) []protocol.Diagnostic {
+	diagnostics := []protocol.Diagnostic{}
+	matchPropertyPath([]string{"services", "*", "build"}, nil, root, func(key, value ast.Node) {
+		build, ok := resolveAnchor(value).(*ast.MappingNode)
+		if !ok {
+			return
+		}
+		dockerfile := mappingValue(build, "dockerfile")
+		if dockerfile == nil || mappingValue(build, "dockerfile_inline") != nil {
+			return
+		}
+
+		context := mappingValue(build, "context")
+		if context == nil || documentPath == nil {
+			return
+		}
+		contextValue, ok := literalValue(resolveAnchor(context.Value).GetToken().Value)
+		if !ok || remoteContext(contextValue) {
+			return
+		}
+		dockerfileToken := resolveAnchor(dockerfile.Value).GetToken()
+		dockerfileValue, ok := literalValue(dockerfileToken.Value)
+		if !ok || dockerfileValue == "" {
+			return
+		}
+
+		contextPath := resolvePath(documentPath.Folder, contextValue, documentPath.WSLDollarSignHost)
+		if info, err := os.Stat(contextPath); err != nil || !info.IsDir() {
+			return
+		}
+		dockerfilePath := dockerfileValue
+		if !filepath.IsAbs(dockerfilePath) {
+			dockerfilePath = filepath.Join(contextPath, dockerfilePath)
+		}
+		if _, err := os.Stat(dockerfilePath); os.IsNotExist(err) {
+			diagnostics = append(diagnostics, createValidationDiagnostic(
+				source,
+				protocol.DiagnosticSeverityError,
+				"DockerfileNotFound",
+				fmt.Sprintf("Dockerfile %v could not be found in the build context %v", dockerfileValue, contextValue),
+				createRange(dockerfileToken, len(dockerfileToken.Value)),
+			))
+		}
+	})
+	return diagnostics
+}
+
+// buildOnlyAttributes are the attributes of a build object that are not
+// also attributes of a service.
+var buildOnlyAttributes = []string{
+	"additional_contexts",
+	"args",
+	"cache_from",
+	"cache_to",
+	"context",
+	"dockerfile",
+	"dockerfile_inline",
+	"entitlements",
+	"network",
+	"no_cache",
+	"platforms",
+	"provenance",
+	"pull",
+	"sbom",
+	"ssh",
+	"tags",
+	"target",
+}
+
+// misplacedBuildAttributes returns the attributes of the service that
+// can only be set in a build object if the service's build attribute
+// has been set to a string.
+func misplacedBuildAttributes(serviceAttributes *ast.MappingNode) []*ast.MappingValueNode {
+	build := mappingValue(serviceAttributes, "build")
+	if build == nil {
+		return nil
+	}
+	if _, ok := resolveAnchor(build.Value).(*ast.StringNode); !ok {
+		return nil
+	}
+	attributes := []*ast.MappingValueNode{}
+	for _, node := range serviceAttributes.Values {
+		if slices.Contains(buildOnlyAttributes, resolveAnchor(node.Key).GetToken().Value) {
+			attributes = append(attributes, node)
+		}
+	}
+	return attributes
+}
+
+// validateBuildString reports the attributes of a service that belong
+// in a build object when build has been set to a string as the string
+// only sets the build context. The build string can be converted to an
+// object with a code action.
+func validateBuildString(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	service, ok := resolveAnchor(value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+	diagnostics := []protocol.Diagnostic{}
+	for _, node := range misplacedBuildAttributes(service) {
+		t := node.Key.GetToken()
+		diagnostics = append(diagnostics, createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"BuildAttributeOutsideBuild",
+			fmt.Sprintf("%v must be set in the build object but build has been set to a string", t.Value),
+			createRange(t, len(t.Value)),
+		))
+	}
+	return diagnostics
+}
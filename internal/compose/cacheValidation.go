@@ -0,0 +1,102 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// cacheType describes a BuildKit cache backend and the parameters that
+// must be set when importing from or exporting to it.
+type cacheType struct {
+	importParameters []string
+	exportParameters []string
+	exportOnly       bool
+}
+
+var cacheTypes = map[string]cacheType{
+	"registry": {importParameters: []string{"ref"}, exportParameters: []string{"ref"}},
+	"local":    {importParameters: []string{"src"}, exportParameters: []string{"dest"}},
+	"inline":   {exportOnly: true},
+	"gha":      {},
+	"s3":       {importParameters: []string{"region", "bucket"}, exportParameters: []string{"region", "bucket"}},
+	"azblob":   {importParameters: []string{"account_url"}, exportParameters: []string{"account_url"}},
+}
+
+// cacheTypeNames returns the names of the cache types in the order
+// that they should be listed in a diagnostic.
+func cacheTypeNames(export bool) []string {
+	names := []string{"registry", "local", "gha", "s3", "azblob"}
+	if export {
+		names = append(names, "inline")
+	}
+	return names
+}
+
+// cacheSpecValidator creates a validator for the entries of a build's
+// cache_from or cache_to attribute. A plain image reference is treated
+// as a registry cache and is always valid.
+func cacheSpecValidator(export bool) func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	return func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+		// the offsets of the parameters are used for the ranges of the
+		// diagnostics so only values without any $ can be checked
+		s, ok := resolveAnchor(value).(*ast.StringNode)
+		if !ok || !strings.Contains(s.Value, "=") {
+			return nil
+		}
+		if kind, _ := classifyScalar(s.Value); kind != scalarLiteral {
+			return nil
+		}
+
+		pairs := parseKeyValuePairs(s.Value)
+		parameters := map[string]keyValuePair{}
+		for _, pair := range pairs {
+			parameters[pair.key] = pair
+		}
+
+		t := s.GetToken()
+		typePair, ok := parameters["type"]
+		if !ok {
+			return []protocol.Diagnostic{cacheSpecDiagnostic(source, "cache specification must set a type", t, 0, len(s.Value))}
+		}
+
+		spec, ok := cacheTypes[typePair.value]
+		if !ok || (spec.exportOnly && !export) {
+			message := fmt.Sprintf("unknown cache type %v (expected one of: %v)", typePair.value, strings.Join(cacheTypeNames(export), ", "))
+			if ok {
+				message = fmt.Sprintf("cache type %v can only be used in cache_to", typePair.value)
+			}
+			valueStart := typePair.start + len("type=")
+			return []protocol.Diagnostic{cacheSpecDiagnostic(source, message, t, valueStart, typePair.end)}
+		}
+
+		required := spec.importParameters
+		if export {
+			required = spec.exportParameters
+		}
+		diagnostics := []protocol.Diagnostic{}
+		for _, parameter := range required {
+			if pair, ok := parameters[parameter]; !ok || pair.value == "" {
+				message := fmt.Sprintf("cache type %v requires the %v parameter", typePair.value, parameter)
+				diagnostics = append(diagnostics, cacheSpecDiagnostic(source, message, t, 0, len(s.Value)))
+			}
+		}
+		return diagnostics
+	}
+}
+
+// cacheSpecDiagnostic creates a diagnostic for the given range of a
+// cache specification. The start and end are offsets within the value
+// of the string.
+func cacheSpecDiagnostic(source, message string, t *token.Token, start, end int) protocol.Diagnostic {
+	rng := createRange(t, end)
+	if t.Type == token.SingleQuoteType {
+		rng.Start.Character++
+		rng.End.Character++
+	}
+	rng.Start.Character += protocol.UInteger(start)
+	return createValidationDiagnostic(source, protocol.DiagnosticSeverityWarning, "InvalidCacheSpec", message, rng)
+}
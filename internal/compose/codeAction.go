@@ -0,0 +1,402 @@
+package compose
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// healthcheckTests are the test commands of the healthchecks that are
+// suggested for services that use a well-known image.
+var healthcheckTests = map[string]string{
+	"mariadb":  `["CMD", "healthcheck.sh", "--connect", "--innodb_initialized"]`,
+	"mongo":    `["CMD", "mongosh", "--quiet", "--eval", "db.adminCommand('ping')"]`,
+	"mysql":    `["CMD", "mysqladmin", "ping", "-h", "localhost"]`,
+	"nginx":    `["CMD", "curl", "-f", "http://localhost/"]`,
+	"postgres": `["CMD-SHELL", "pg_isready -U $${POSTGRES_USER:-postgres}"]`,
+	"redis":    `["CMD", "redis-cli", "ping"]`,
+	"valkey":   `["CMD", "valkey-cli", "ping"]`,
+}
+
+const defaultHealthcheckTest = `["CMD-SHELL", "curl -f http://localhost/ || exit 1"]`
+
+func CodeAction(doc document.ComposeDocument, params *protocol.CodeActionParams) []protocol.CodeAction {
+	file := doc.File()
+	if file == nil {
+		return nil
+	}
+
+	actions := []protocol.CodeAction{}
+	lines := strings.Split(string(doc.Input()), "\n")
+	for _, documentNode := range file.Docs {
+		actions = append(actions, inlineFragmentCodeActions(lines, documentNode.Body, params)...)
+		if mappingNode, ok := documentNode.Body.(*ast.MappingNode); ok {
+			actions = append(actions, addHealthcheckCodeActions(mappingNode, params)...)
+			actions = append(actions, migrateScaleCodeActions(mappingNode, params)...)
+			actions = append(actions, removeIgnoredHealthcheckAttributesCodeActions(mappingNode, params)...)
+			actions = append(actions, convertBuildStringCodeActions(lines, mappingNode, params)...)
+		}
+	}
+	return actions
+}
+
+// addHealthcheckCodeActions returns a code action that adds a
+// healthcheck to the service that the range is in if the service does
+// not already have one.
+func addHealthcheckCodeActions(root *ast.MappingNode, params *protocol.CodeActionParams) []protocol.CodeAction {
+	line := int(params.Range.Start.Line) + 1
+	for i, topLevelNode := range root.Values {
+		if resolveAnchor(topLevelNode.Key).GetToken().Value != "services" {
+			continue
+		}
+		servicesNode, ok := resolveAnchor(topLevelNode.Value).(*ast.MappingNode)
+		if !ok {
+			return nil
+		}
+
+		// a service ends where the next service or top-level attribute starts
+		nextLine := math.MaxInt
+		if i+1 < len(root.Values) {
+			nextLine = root.Values[i+1].Key.GetToken().Position.Line
+		}
+		for j, serviceNode := range servicesNode.Values {
+			keyLine := serviceNode.Key.GetToken().Position.Line
+			endLine := nextLine
+			if j+1 < len(servicesNode.Values) {
+				endLine = servicesNode.Values[j+1].Key.GetToken().Position.Line
+			}
+			if line < keyLine || line >= endLine {
+				continue
+			}
+
+			serviceAttributes, ok := resolveAnchor(serviceNode.Value).(*ast.MappingNode)
+			if !ok || serviceAttributes.IsFlowStyle || len(serviceAttributes.Values) == 0 || mappingValue(serviceAttributes, "healthcheck") != nil {
+				return nil
+			}
+
+			keyIndentation := serviceNode.Key.GetToken().Position.Column - 1
+			indentation := serviceAttributes.Values[0].Key.GetToken().Position.Column - 1
+			return []protocol.CodeAction{
+				{
+					Title: "Add healthcheck",
+					Kind:  types.CreateStringPointer(protocol.CodeActionKindRefactorRewrite),
+					Edit: &protocol.WorkspaceEdit{
+						Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+							params.TextDocument.URI: {
+								{
+									NewText: healthcheckText(serviceAttributes, strings.Repeat(" ", indentation), strings.Repeat(" ", indentation-keyIndentation)),
+									Range: protocol.Range{
+										Start: protocol.Position{Line: protocol.UInteger(keyLine), Character: 0},
+										End:   protocol.Position{Line: protocol.UInteger(keyLine), Character: 0},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+	}
+	return nil
+}
+
+// migrateScaleCodeActions returns a code action that moves the scale
+// attribute of a service on the range's line to deploy.replicas. The
+// deploy attribute is created if the service does not have one.
+func migrateScaleCodeActions(root *ast.MappingNode, params *protocol.CodeActionParams) []protocol.CodeAction {
+	line := int(params.Range.Start.Line) + 1
+	services := mappingValue(root, "services")
+	if services == nil {
+		return nil
+	}
+	servicesNode, ok := resolveAnchor(services.Value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+
+	for _, serviceNode := range servicesNode.Values {
+		serviceAttributes, ok := resolveAnchor(serviceNode.Value).(*ast.MappingNode)
+		if !ok || serviceAttributes.IsFlowStyle {
+			continue
+		}
+		scale := mappingValue(serviceAttributes, "scale")
+		if scale == nil || scale.Key.GetToken().Position.Line != line {
+			continue
+		}
+
+		edits := scaleMigrationEdits(serviceNode, serviceAttributes, scale)
+		if edits == nil {
+			return nil
+		}
+		return []protocol.CodeAction{
+			{
+				Title: "Move scale to deploy.replicas",
+				Kind:  types.CreateStringPointer(protocol.CodeActionKindQuickFix),
+				Edit: &protocol.WorkspaceEdit{
+					Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+						params.TextDocument.URI: edits,
+					},
+				},
+			},
+		}
+	}
+	return nil
+}
+
+// removeIgnoredHealthcheckAttributesCodeActions returns a code action
+// that removes the attributes of a disabled healthcheck that Compose
+// ignores if the range is on one of them. Only the lines of the ignored
+// attributes are removed so the disable attribute and the comments in
+// the healthcheck are left as they are.
+func removeIgnoredHealthcheckAttributesCodeActions(root *ast.MappingNode, params *protocol.CodeActionParams) []protocol.CodeAction {
+	line := int(params.Range.Start.Line) + 1
+	services := mappingValue(root, "services")
+	if services == nil {
+		return nil
+	}
+	servicesNode, ok := resolveAnchor(services.Value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+
+	for _, serviceNode := range servicesNode.Values {
+		serviceAttributes, ok := resolveAnchor(serviceNode.Value).(*ast.MappingNode)
+		if !ok {
+			continue
+		}
+		healthcheckAttribute := mappingValue(serviceAttributes, "healthcheck")
+		if healthcheckAttribute == nil {
+			continue
+		}
+		healthcheck := disabledHealthcheck(healthcheckAttribute.Value)
+		if healthcheck == nil || healthcheck.IsFlowStyle || mappingValue(healthcheck, "<<") != nil {
+			continue
+		}
+
+		edits := []protocol.TextEdit{}
+		inRange := false
+		for _, attribute := range healthcheck.Values {
+			t := attribute.Key.GetToken()
+			if t.Value == "disable" {
+				continue
+			}
+			inRange = inRange || t.Position.Line == line
+			edits = append(edits, protocol.TextEdit{
+				NewText: "",
+				Range: protocol.Range{
+					Start: protocol.Position{Line: protocol.UInteger(t.Position.Line - 1)},
+					End:   protocol.Position{Line: protocol.UInteger(lastLine(attribute))},
+				},
+			})
+		}
+		if !inRange {
+			continue
+		}
+		return []protocol.CodeAction{
+			{
+				Title: "Remove attributes ignored by the disabled healthcheck",
+				Kind:  types.CreateStringPointer(protocol.CodeActionKindQuickFix),
+				Edit: &protocol.WorkspaceEdit{
+					Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+						params.TextDocument.URI: edits,
+					},
+				},
+			},
+		}
+	}
+	return nil
+}
+
+// scaleMigrationEdits returns the edits that replace the scale attribute
+// of the service with deploy.replicas. Nil is returned if the service's
+// deploy attribute already sets the replicas or if it cannot be edited
+// line by line.
+func scaleMigrationEdits(serviceNode *ast.MappingValueNode, serviceAttributes *ast.MappingNode, scale *ast.MappingValueNode) []protocol.TextEdit {
+	keyIndentation := serviceNode.Key.GetToken().Position.Column - 1
+	indentation := scale.Key.GetToken().Position.Column - 1
+	value := resolveAnchor(scale.Value).GetToken().Value
+	scaleRange := protocol.Range{
+		Start: protocol.Position{Line: protocol.UInteger(scale.Key.GetToken().Position.Line - 1)},
+		End:   protocol.Position{Line: protocol.UInteger(lastLine(scale))},
+	}
+
+	deploy := mappingValue(serviceAttributes, "deploy")
+	if deploy == nil {
+		return []protocol.TextEdit{
+			{
+				NewText: fmt.Sprintf("%vdeploy:\n%v%vreplicas: %v\n", strings.Repeat(" ", indentation), strings.Repeat(" ", indentation), strings.Repeat(" ", indentation-keyIndentation), value),
+				Range:   scaleRange,
+			},
+		}
+	}
+
+	// the deploy attribute is only edited in place so an anchored or
+	// aliased deploy attribute that may be shared is left alone
+	deployNode, ok := deploy.Value.(*ast.MappingNode)
+	if !ok || deployNode.IsFlowStyle || len(deployNode.Values) == 0 || mappingValue(deployNode, "replicas") != nil {
+		return nil
+	}
+	first := deployNode.Values[0].Key.GetToken()
+	insertPosition := protocol.Position{Line: protocol.UInteger(first.Position.Line - 1)}
+	insert := protocol.TextEdit{
+		NewText: fmt.Sprintf("%vreplicas: %v\n", strings.Repeat(" ", first.Position.Column-1), value),
+		Range:   protocol.Range{Start: insertPosition, End: insertPosition},
+	}
+	remove := protocol.TextEdit{NewText: "", Range: scaleRange}
+	if deploy.Key.GetToken().Position.Line < scale.Key.GetToken().Position.Line {
+		return []protocol.TextEdit{insert, remove}
+	}
+	return []protocol.TextEdit{remove, insert}
+}
+
+// convertBuildStringCodeActions returns a code action that converts
+// the build string of a service to a build object with the string as
+// its context if the range's line is on the build attribute or on a
+// service attribute that can only be set in a build object. Those
+// attributes are moved into the new build object.
+func convertBuildStringCodeActions(lines []string, root *ast.MappingNode, params *protocol.CodeActionParams) []protocol.CodeAction {
+	line := int(params.Range.Start.Line) + 1
+	services := mappingValue(root, "services")
+	if services == nil {
+		return nil
+	}
+	servicesNode, ok := resolveAnchor(services.Value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+
+	for _, serviceNode := range servicesNode.Values {
+		serviceAttributes, ok := resolveAnchor(serviceNode.Value).(*ast.MappingNode)
+		if !ok || serviceAttributes.IsFlowStyle {
+			continue
+		}
+		build := mappingValue(serviceAttributes, "build")
+		if build == nil {
+			continue
+		}
+		moved := misplacedBuildAttributes(serviceAttributes)
+		onLine := build.Key.GetToken().Position.Line == line
+		for _, attribute := range moved {
+			onLine = onLine || attribute.Key.GetToken().Position.Line == line
+		}
+		if !onLine {
+			continue
+		}
+
+		edits := buildStringConversionEdits(lines, serviceNode, build, moved)
+		if edits == nil {
+			return nil
+		}
+		return []protocol.CodeAction{
+			{
+				Title: "Convert build string to object",
+				Kind:  types.CreateStringPointer(protocol.CodeActionKindRefactorRewrite),
+				Edit: &protocol.WorkspaceEdit{
+					Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+						params.TextDocument.URI: edits,
+					},
+				},
+			},
+		}
+	}
+	return nil
+}
+
+// buildStringConversionEdits returns the edits that replace the build
+// string of the service with a build object and that move the given
+// attributes into it. Nil is returned if the build string is not on
+// the same line as its key or if the service already sets a context
+// that would conflict with the build string.
+func buildStringConversionEdits(lines []string, serviceNode, build *ast.MappingValueNode, moved []*ast.MappingValueNode) []protocol.TextEdit {
+	buildKey := build.Key.GetToken()
+	if _, ok := resolveAnchor(build.Value).(*ast.StringNode); !ok || build.Value.GetToken().Position.Line != buildKey.Position.Line || len(lines) < buildKey.Position.Line {
+		return nil
+	}
+	for _, attribute := range moved {
+		if attribute.Key.GetToken().Value == "context" {
+			return nil
+		}
+	}
+	_, valueText, found := strings.Cut(lines[buildKey.Position.Line-1][buildKey.Position.Column-1:], ":")
+	if !found {
+		return nil
+	}
+
+	keyIndentation := serviceNode.Key.GetToken().Position.Column - 1
+	indentation := strings.Repeat(" ", buildKey.Position.Column-1)
+	unit := strings.Repeat(" ", buildKey.Position.Column-1-keyIndentation)
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%vbuild:\n", indentation))
+	sb.WriteString(fmt.Sprintf("%v%vcontext: %v\n", indentation, unit, strings.TrimSpace(valueText)))
+	removals := []protocol.TextEdit{}
+	for _, attribute := range moved {
+		start := attribute.Key.GetToken().Position.Line - 1
+		end := min(lastLine(attribute), len(lines))
+		for _, attributeLine := range lines[start:end] {
+			if strings.TrimSpace(attributeLine) == "" {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString(fmt.Sprintf("%v%v\n", unit, strings.TrimRight(attributeLine, "\r")))
+			}
+		}
+		removals = append(removals, protocol.TextEdit{
+			NewText: "",
+			Range: protocol.Range{
+				Start: protocol.Position{Line: protocol.UInteger(start)},
+				End:   protocol.Position{Line: protocol.UInteger(lastLine(attribute))},
+			},
+		})
+	}
+
+	edits := append(removals, protocol.TextEdit{
+		NewText: sb.String(),
+		Range: protocol.Range{
+			Start: protocol.Position{Line: protocol.UInteger(buildKey.Position.Line - 1)},
+			End:   protocol.Position{Line: protocol.UInteger(lastLine(build))},
+		},
+	})
+	slices.SortFunc(edits, func(a, b protocol.TextEdit) int {
+		return int(a.Range.Start.Line) - int(b.Range.Start.Line)
+	})
+	return edits
+}
+
+// healthcheckText creates a healthcheck attribute with default values
+// for the given service. The test command is tailored to the service's
+// image if it is a well-known image that has a known health command.
+func healthcheckText(serviceAttributes *ast.MappingNode, indentation, unit string) string {
+	test := defaultHealthcheckTest
+	if image := mappingValue(serviceAttributes, "image"); image != nil {
+		if s, ok := resolveAnchor(image.Value).(*ast.StringNode); ok {
+			if imageTest, ok := healthcheckTests[imageName(s.Value)]; ok {
+				test = imageTest
+			}
+		}
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%vhealthcheck:\n", indentation))
+	sb.WriteString(fmt.Sprintf("%v%vtest: %v\n", indentation, unit, test))
+	sb.WriteString(fmt.Sprintf("%v%vinterval: 30s\n", indentation, unit))
+	sb.WriteString(fmt.Sprintf("%v%vtimeout: 10s\n", indentation, unit))
+	sb.WriteString(fmt.Sprintf("%v%vretries: 3\n", indentation, unit))
+	sb.WriteString(fmt.Sprintf("%v%vstart_period: 10s\n", indentation, unit))
+	return sb.String()
+}
+
+// imageName returns the name of the repository of the image without
+// its registry, namespace, tag, or digest.
+func imageName(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if idx := strings.LastIndex(image, "/"); idx != -1 {
+		image = image[idx+1:]
+	}
+	image, _, _ = strings.Cut(image, ":")
+	return image
+}
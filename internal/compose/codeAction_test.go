@@ -0,0 +1,851 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func addHealthcheckAction(u protocol.DocumentUri, line protocol.UInteger, text string) []protocol.CodeAction {
+	return []protocol.CodeAction{
+		{
+			Title: "Add healthcheck",
+			Kind:  types.CreateStringPointer(protocol.CodeActionKindRefactorRewrite),
+			Edit: &protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+					u: {
+						{
+							NewText: text,
+							Range: protocol.Range{
+								Start: protocol.Position{Line: line, Character: 0},
+								End:   protocol.Position{Line: line, Character: 0},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCodeAction_AddHealthcheck(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	testCases := []struct {
+		name    string
+		content string
+		line    protocol.UInteger
+		actions func(u protocol.DocumentUri) []protocol.CodeAction
+	}{
+		{
+			name: "generic healthcheck for an unknown image",
+			content: `
+services:
+  web:
+    image: alpine`,
+			line: 2,
+			actions: func(u protocol.DocumentUri) []protocol.CodeAction {
+				return addHealthcheckAction(u, 3, "    healthcheck:\n      test: [\"CMD-SHELL\", \"curl -f http://localhost/ || exit 1\"]\n      interval: 30s\n      timeout: 10s\n      retries: 3\n      start_period: 10s\n")
+			},
+		},
+		{
+			name: "healthcheck tailored to a well-known image",
+			content: `
+services:
+  db:
+    image: docker.io/library/postgres:17
+    restart: always`,
+			line: 4,
+			actions: func(u protocol.DocumentUri) []protocol.CodeAction {
+				return addHealthcheckAction(u, 3, "    healthcheck:\n      test: [\"CMD-SHELL\", \"pg_isready -U $${POSTGRES_USER:-postgres}\"]\n      interval: 30s\n      timeout: 10s\n      retries: 3\n      start_period: 10s\n")
+			},
+		},
+		{
+			name: "healthcheck uses the indentation of the file",
+			content: `
+services:
+    cache:
+        image: redis
+    web:
+        image: nginx`,
+			line: 2,
+			actions: func(u protocol.DocumentUri) []protocol.CodeAction {
+				return addHealthcheckAction(u, 3, "        healthcheck:\n            test: [\"CMD\", \"redis-cli\", \"ping\"]\n            interval: 30s\n            timeout: 10s\n            retries: 3\n            start_period: 10s\n")
+			},
+		},
+		{
+			name: "second service of the file",
+			content: `
+services:
+  cache:
+    image: redis
+  web:
+    image: nginx:1.27
+networks:
+  backend:`,
+			line: 5,
+			actions: func(u protocol.DocumentUri) []protocol.CodeAction {
+				return addHealthcheckAction(u, 5, "    healthcheck:\n      test: [\"CMD\", \"curl\", \"-f\", \"http://localhost/\"]\n      interval: 30s\n      timeout: 10s\n      retries: 3\n      start_period: 10s\n")
+			},
+		},
+		{
+			name: "service already has a healthcheck",
+			content: `
+services:
+  web:
+    image: nginx
+    healthcheck:
+      disable: true`,
+			line: 3,
+			actions: func(u protocol.DocumentUri) []protocol.CodeAction {
+				return []protocol.CodeAction{}
+			},
+		},
+		{
+			name: "outside of a service",
+			content: `
+services:
+  web:
+    image: nginx
+networks:
+  backend:`,
+			line: 5,
+			actions: func(u protocol.DocumentUri) []protocol.CodeAction {
+				return []protocol.CodeAction{}
+			},
+		},
+		{
+			name: "service in flow style",
+			content: `
+services:
+  web: { image: nginx }`,
+			line: 2,
+			actions: func(u protocol.DocumentUri) []protocol.CodeAction {
+				return []protocol.CodeAction{}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := uri.URI(composeFileURI)
+			doc := document.NewComposeDocument(document.NewDocumentManager(), u, 1, []byte(tc.content))
+			actions := CodeAction(doc, &protocol.CodeActionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+				Range: protocol.Range{
+					Start: protocol.Position{Line: tc.line, Character: 0},
+					End:   protocol.Position{Line: tc.line, Character: 0},
+				},
+			})
+			require.Equal(t, tc.actions(composeFileURI), actions)
+		})
+	}
+}
+
+func TestCodeAction_InlineFragment(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	testCases := []struct {
+		name      string
+		content   string
+		line      protocol.UInteger
+		character protocol.UInteger
+		titles    []string
+		results   []string
+	}{
+		{
+			name: "scalar anchor with other aliases",
+			content: `
+x-image: &image nginx:1.27
+services:
+  web:
+    image: *image
+  proxy:
+    image: *image`,
+			line:      4,
+			character: 12,
+			titles:    []string{"Inline fragment here"},
+			results: []string{`
+x-image: &image nginx:1.27
+services:
+  web:
+    image: nginx:1.27
+  proxy:
+    image: *image`},
+		},
+		{
+			name: "quoted scalar anchor with a single alias",
+			content: `
+x-image: &image "nginx:1.27"
+services:
+  web:
+    image: *image`,
+			line:      4,
+			character: 17,
+			titles:    []string{"Inline fragment here", "Inline fragment here and remove anchor &image"},
+			results: []string{`
+x-image: &image "nginx:1.27"
+services:
+  web:
+    image: "nginx:1.27"`, `
+x-image: "nginx:1.27"
+services:
+  web:
+    image: "nginx:1.27"`},
+		},
+		{
+			name: "mapping anchor",
+			content: `
+x-logging: &logging
+  driver: json-file
+  options:
+    max-size: 10m # keep small
+services:
+  web:
+    logging: *logging # shared`,
+			line:      7,
+			character: 14,
+			titles:    []string{"Inline fragment here", "Inline fragment here and remove anchor &logging"},
+			results: []string{`
+x-logging: &logging
+  driver: json-file
+  options:
+    max-size: 10m # keep small
+services:
+  web:
+    logging: # shared
+      driver: json-file
+      options:
+        max-size: 10m # keep small`, `
+x-logging:
+  driver: json-file
+  options:
+    max-size: 10m # keep small
+services:
+  web:
+    logging: # shared
+      driver: json-file
+      options:
+        max-size: 10m # keep small`},
+		},
+		{
+			name: "sequence anchor",
+			content: `
+x-dns: &dns
+- 8.8.8.8
+- 1.1.1.1
+services:
+  web:
+    dns: *dns
+  db:
+    dns: *dns`,
+			line:      6,
+			character: 9,
+			titles:    []string{"Inline fragment here"},
+			results: []string{`
+x-dns: &dns
+- 8.8.8.8
+- 1.1.1.1
+services:
+  web:
+    dns:
+      - 8.8.8.8
+      - 1.1.1.1
+  db:
+    dns: *dns`},
+		},
+		{
+			name: "flow sequence anchor",
+			content: `
+services:
+  web:
+    command: &command ["echo", "hello"]
+  test:
+    command: *command`,
+			line:      5,
+			character: 13,
+			titles:    []string{"Inline fragment here", "Inline fragment here and remove anchor &command"},
+			results: []string{`
+services:
+  web:
+    command: &command ["echo", "hello"]
+  test:
+    command: ["echo", "hello"]`, `
+services:
+  web:
+    command: ["echo", "hello"]
+  test:
+    command: ["echo", "hello"]`},
+		},
+		{
+			name: "mapping anchor as a sequence item",
+			content: `
+x-volume: &volume
+  type: bind
+  source: ./data
+services:
+  web:
+    volumes:
+      - *volume
+      - ./logs:/logs
+  db:
+    volumes:
+      - *volume`,
+			line:      7,
+			character: 8,
+			titles:    []string{"Inline fragment here"},
+			results: []string{`
+x-volume: &volume
+  type: bind
+  source: ./data
+services:
+  web:
+    volumes:
+      - type: bind
+        source: ./data
+      - ./logs:/logs
+  db:
+    volumes:
+      - *volume`},
+		},
+		{
+			name: "merge key is flattened without overridden entries",
+			content: `
+x-base: &base
+  image: nginx
+  restart: always
+  environment:
+    A: b
+services:
+  web:
+    <<: *base # defaults
+    restart: "no"
+  db:
+    <<: *base`,
+			line:      8,
+			character: 9,
+			titles:    []string{"Inline fragment here"},
+			results: []string{`
+x-base: &base
+  image: nginx
+  restart: always
+  environment:
+    A: b
+services:
+  web:
+    image: nginx # defaults
+    environment:
+      A: b
+    restart: "no"
+  db:
+    <<: *base`},
+		},
+		{
+			name: "merge key with every entry overridden is removed",
+			content: `
+x-base: &base
+  image: nginx
+services:
+  web:
+    <<: *base
+    image: alpine`,
+			line:      5,
+			character: 9,
+			titles:    []string{"Inline fragment here", "Inline fragment here and remove anchor &base"},
+			results: []string{`
+x-base: &base
+  image: nginx
+services:
+  web:
+    image: alpine`, `
+x-base:
+  image: nginx
+services:
+  web:
+    image: alpine`},
+		},
+		{
+			name: "sequence of merged aliases is not flattened",
+			content: `
+x-a: &a
+  image: nginx
+x-b: &b
+  restart: always
+services:
+  web:
+    <<: [*a, *b]`,
+			line:      7,
+			character: 10,
+		},
+		{
+			name: "block mapping cannot be inlined into a flow mapping",
+			content: `
+x-logging: &logging
+  driver: json-file
+services:
+  web: { logging: *logging }`,
+			line:      4,
+			character: 20,
+		},
+		{
+			name: "cursor on the anchor instead of an alias",
+			content: `
+x-image: &image nginx
+services:
+  web:
+    image: *image`,
+			line:      1,
+			character: 12,
+		},
+	}
+
+	apply := func(content string, edits []protocol.TextEdit) string {
+		for i := len(edits) - 1; i >= 0; i-- {
+			content = string(document.ApplyContentChange([]byte(content), edits[i].Range, edits[i].NewText))
+		}
+		return content
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := uri.URI(composeFileURI)
+			doc := document.NewComposeDocument(document.NewDocumentManager(), u, 1, []byte(tc.content))
+			actions := CodeAction(doc, &protocol.CodeActionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+				Range: protocol.Range{
+					Start: protocol.Position{Line: tc.line, Character: tc.character},
+					End:   protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			})
+			titles := []string{}
+			results := []string{}
+			for _, action := range actions {
+				if *action.Kind == protocol.CodeActionKindRefactorInline {
+					titles = append(titles, action.Title)
+					results = append(results, apply(tc.content, action.Edit.Changes[composeFileURI]))
+				}
+			}
+			if tc.titles == nil {
+				require.Empty(t, titles)
+				return
+			}
+			require.Equal(t, tc.titles, titles)
+			require.Equal(t, tc.results, results)
+		})
+	}
+}
+
+func TestCodeAction_MigrateScale(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	testCases := []struct {
+		name    string
+		content string
+		line    protocol.UInteger
+		result  string
+	}{
+		{
+			name: "deploy is created",
+			content: `
+services:
+  web:
+    image: nginx
+    scale: 3
+    restart: always`,
+			line: 4,
+			result: `
+services:
+  web:
+    image: nginx
+    deploy:
+      replicas: 3
+    restart: always`,
+		},
+		{
+			name: "deploy is created with the indentation of the file",
+			content: `
+services:
+    web:
+        scale: "2"`,
+			line: 3,
+			result: `
+services:
+    web:
+        deploy:
+            replicas: 2
+`,
+		},
+		{
+			name: "replicas added to a deploy after scale",
+			content: `
+services:
+  web:
+    scale: 3
+    deploy:
+      mode: replicated`,
+			line: 3,
+			result: `
+services:
+  web:
+    deploy:
+      replicas: 3
+      mode: replicated`,
+		},
+		{
+			name: "replicas added to a deploy before scale",
+			content: `
+services:
+  web:
+    deploy:
+      mode: replicated
+    scale: 3`,
+			line: 5,
+			result: `
+services:
+  web:
+    deploy:
+      replicas: 3
+      mode: replicated
+`,
+		},
+		{
+			name: "interpolated scale is moved as is",
+			content: `
+services:
+  web:
+    scale: ${SCALE}`,
+			line: 3,
+			result: `
+services:
+  web:
+    deploy:
+      replicas: ${SCALE}
+`,
+		},
+		{
+			name: "deploy that already sets replicas",
+			content: `
+services:
+  web:
+    scale: 3
+    deploy:
+      replicas: 2`,
+			line: 3,
+		},
+		{
+			name: "deploy in flow style",
+			content: `
+services:
+  web:
+    scale: 3
+    deploy: { mode: replicated }`,
+			line: 3,
+		},
+		{
+			name: "line without scale",
+			content: `
+services:
+  web:
+    image: nginx
+    scale: 3`,
+			line: 3,
+		},
+	}
+
+	apply := func(content string, edits []protocol.TextEdit) string {
+		for i := len(edits) - 1; i >= 0; i-- {
+			content = string(document.ApplyContentChange([]byte(content), edits[i].Range, edits[i].NewText))
+		}
+		return content
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := uri.URI(composeFileURI)
+			doc := document.NewComposeDocument(document.NewDocumentManager(), u, 1, []byte(tc.content))
+			actions := CodeAction(doc, &protocol.CodeActionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+				Range: protocol.Range{
+					Start: protocol.Position{Line: tc.line, Character: 4},
+					End:   protocol.Position{Line: tc.line, Character: 4},
+				},
+			})
+			results := []string{}
+			for _, action := range actions {
+				if *action.Kind == protocol.CodeActionKindQuickFix {
+					require.Equal(t, "Move scale to deploy.replicas", action.Title)
+					results = append(results, apply(tc.content, action.Edit.Changes[composeFileURI]))
+				}
+			}
+			if tc.result == "" {
+				require.Empty(t, results)
+				return
+			}
+			require.Equal(t, []string{tc.result}, results)
+		})
+	}
+}
+
+func TestCodeAction_RemoveIgnoredHealthcheckAttributes(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	testCases := []struct {
+		name    string
+		content string
+		line    protocol.UInteger
+		result  string
+	}{
+		{
+			name: "attributes around disable",
+			content: `
+services:
+  web:
+    healthcheck:
+      test:
+        - CMD
+        - "true"
+      disable: true
+      interval: 10s
+    image: alpine`,
+			line: 4,
+			result: `
+services:
+  web:
+    healthcheck:
+      disable: true
+    image: alpine`,
+		},
+		{
+			name: "comments and the disable attribute are kept as they are",
+			content: `
+services:
+  web:
+    healthcheck:
+      # checks the web server
+      test: ["CMD", "curl", "-f", "http://localhost"]
+      # disabled while debugging
+      disable: true # TODO enable again
+      interval: 10s
+      # retries: 3
+    image: alpine`,
+			line: 8,
+			result: `
+services:
+  web:
+    healthcheck:
+      # checks the web server
+      # disabled while debugging
+      disable: true # TODO enable again
+      # retries: 3
+    image: alpine`,
+		},
+		{
+			name: "multi-line values are removed entirely",
+			content: `
+services:
+  web:
+    healthcheck:
+      disable: true
+      test: |
+        curl -f
+        http://localhost
+# comment
+    image: alpine`,
+			line: 5,
+			result: `
+services:
+  web:
+    healthcheck:
+      disable: true
+# comment
+    image: alpine`,
+		},
+		{
+			name: "line of the disable attribute",
+			content: `
+services:
+  web:
+    healthcheck:
+      disable: true
+      retries: 3`,
+			line: 4,
+		},
+		{
+			name: "healthcheck is not disabled",
+			content: `
+services:
+  web:
+    healthcheck:
+      disable: false
+      retries: 3`,
+			line: 5,
+		},
+		{
+			name: "flow style healthcheck",
+			content: `
+services:
+  web:
+    healthcheck: { disable: true, retries: 3 }`,
+			line: 3,
+		},
+	}
+
+	apply := func(content string, edits []protocol.TextEdit) string {
+		for i := len(edits) - 1; i >= 0; i-- {
+			content = string(document.ApplyContentChange([]byte(content), edits[i].Range, edits[i].NewText))
+		}
+		return content
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := uri.URI(composeFileURI)
+			doc := document.NewComposeDocument(document.NewDocumentManager(), u, 1, []byte(tc.content))
+			actions := CodeAction(doc, &protocol.CodeActionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+				Range: protocol.Range{
+					Start: protocol.Position{Line: tc.line, Character: 6},
+					End:   protocol.Position{Line: tc.line, Character: 6},
+				},
+			})
+			results := []string{}
+			for _, action := range actions {
+				if action.Title == "Remove attributes ignored by the disabled healthcheck" {
+					require.Equal(t, protocol.CodeActionKindQuickFix, *action.Kind)
+					results = append(results, apply(tc.content, action.Edit.Changes[composeFileURI]))
+				}
+			}
+			if tc.result == "" {
+				require.Empty(t, results)
+				return
+			}
+			require.Equal(t, []string{tc.result}, results)
+		})
+	}
+}
+
+func TestCodeAction_ConvertBuildString(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	testCases := []struct {
+		name    string
+		content string
+		line    protocol.UInteger
+		result  string
+	}{
+		{
+			name: "build string",
+			content: `
+services:
+  web:
+    build: ./dir
+    image: web`,
+			line: 3,
+			result: `
+services:
+  web:
+    build:
+      context: ./dir
+    image: web`,
+		},
+		{
+			name: "quoted build string with the indentation of the file",
+			content: `
+services:
+    web:
+        build: "./dir" # the web app`,
+			line: 3,
+			result: `
+services:
+    web:
+        build:
+            context: "./dir" # the web app
+`,
+		},
+		{
+			name: "build attributes are moved into the build object",
+			content: `
+services:
+  web:
+    dockerfile: Dockerfile.dev
+    build: ./dir
+    image: web
+    args:
+      - VERSION=1`,
+			line: 6,
+			result: `
+services:
+  web:
+    build:
+      context: ./dir
+      dockerfile: Dockerfile.dev
+      args:
+        - VERSION=1
+    image: web
+`,
+		},
+		{
+			name: "build object",
+			content: `
+services:
+  web:
+    build:
+      context: ./dir`,
+			line: 3,
+		},
+		{
+			name: "service context conflicts with the build string",
+			content: `
+services:
+  web:
+    build: ./dir
+    context: ./other`,
+			line: 3,
+		},
+		{
+			name: "line without build",
+			content: `
+services:
+  web:
+    image: web
+    build: ./dir`,
+			line: 3,
+		},
+	}
+
+	apply := func(content string, edits []protocol.TextEdit) string {
+		for i := len(edits) - 1; i >= 0; i-- {
+			content = string(document.ApplyContentChange([]byte(content), edits[i].Range, edits[i].NewText))
+		}
+		return content
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := uri.URI(composeFileURI)
+			doc := document.NewComposeDocument(document.NewDocumentManager(), u, 1, []byte(tc.content))
+			actions := CodeAction(doc, &protocol.CodeActionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+				Range: protocol.Range{
+					Start: protocol.Position{Line: tc.line, Character: 4},
+					End:   protocol.Position{Line: tc.line, Character: 4},
+				},
+			})
+			results := []string{}
+			for _, action := range actions {
+				if action.Title == "Convert build string to object" {
+					results = append(results, apply(tc.content, action.Edit.Changes[composeFileURI]))
+				}
+			}
+			if tc.result == "" {
+				require.Empty(t, results)
+				return
+			}
+			require.Equal(t, []string{tc.result}, results)
+		})
+	}
+}
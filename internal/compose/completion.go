@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 	"unicode"
@@ -123,7 +124,185 @@ var serviceProviderTypeModifier = textEditModifier{
 	},
 }
 
-var textEditModifiers = []textEditModifier{buildTargetModifier, serviceSuggestionModifier, serviceProviderModifier, serviceProviderTypeModifier}
+var developWatchModifier = textEditModifier{
+	isInterested: func(attributeName string, path []*ast.MappingValueNode) bool {
+		return attributeName == "watch" && len(path) == 3 && path[0].Key.GetToken().Value == "services" && path[2].Key.GetToken().Value == "develop"
+	},
+	modify: func(file *ast.File, manager *document.Manager, documentPath document.DocumentPath, edit protocol.TextEdit, attributeName, spacing string, path []*ast.MappingValueNode) protocol.TextEdit {
+		actions := []completionItemText{}
+		for _, action := range enumValues(lookupSchemas("services", path[1].Key.GetToken().Value, "develop", "watch", "[]", "action")) {
+			actions = append(actions, completionItemText{newText: action})
+		}
+		edit.NewText = fmt.Sprintf("watch:\n%v- action: %v\n%v  path: ${2:./}\n%v  target: ${3:/app}", spacing, createChoiceSnippetText(actions), spacing, spacing)
+		return edit
+	},
+}
+
+// blkioDeviceAttributes maps the list attributes of blkio_config to the
+// attribute and the placeholder value that is paired with the path of
+// the device in each of their items.
+var blkioDeviceAttributes = map[string][]string{
+	"device_read_bps":   {"rate", "1mb"},
+	"device_read_iops":  {"rate", "1000"},
+	"device_write_bps":  {"rate", "1mb"},
+	"device_write_iops": {"rate", "1000"},
+	"weight_device":     {"weight", "500"},
+}
+
+var blkioConfigModifier = textEditModifier{
+	isInterested: func(attributeName string, path []*ast.MappingValueNode) bool {
+		_, ok := blkioDeviceAttributes[attributeName]
+		return ok && len(path) == 3 && path[0].Key.GetToken().Value == "services" && path[2].Key.GetToken().Value == "blkio_config"
+	},
+	modify: func(file *ast.File, manager *document.Manager, documentPath document.DocumentPath, edit protocol.TextEdit, attributeName, spacing string, path []*ast.MappingValueNode) protocol.TextEdit {
+		device := blkioDeviceAttributes[attributeName]
+		edit.NewText = fmt.Sprintf("%v:\n%v- path: ${1:/dev/sda}\n%v  %v: ${2:%v}", attributeName, spacing, spacing, device[0], device[1])
+		return edit
+	},
+}
+
+// configContentModifier inserts the inline content of a config as a
+// literal block scalar as the content usually spans multiple lines.
+var configContentModifier = textEditModifier{
+	isInterested: func(attributeName string, path []*ast.MappingValueNode) bool {
+		return attributeName == "content" && len(path) == 2 && path[0].Key.GetToken().Value == "configs"
+	},
+	modify: func(file *ast.File, manager *document.Manager, documentPath document.DocumentPath, edit protocol.TextEdit, attributeName, spacing string, path []*ast.MappingValueNode) protocol.TextEdit {
+		edit.NewText = fmt.Sprintf("content: |\n%v$1", spacing)
+		return edit
+	},
+}
+
+var placementPreferencesModifier = textEditModifier{
+	isInterested: func(attributeName string, path []*ast.MappingValueNode) bool {
+		return attributeName == "preferences" && len(path) == 4 && path[0].Key.GetToken().Value == "services" && path[2].Key.GetToken().Value == "deploy" && path[3].Key.GetToken().Value == "placement"
+	},
+	modify: func(file *ast.File, manager *document.Manager, documentPath document.DocumentPath, edit protocol.TextEdit, attributeName, spacing string, path []*ast.MappingValueNode) protocol.TextEdit {
+		edit.NewText = fmt.Sprintf("preferences:\n%v- spread: node.labels.${1:zone}", spacing)
+		return edit
+	},
+}
+
+// volumeMountData returns the name of the data file with the values
+// of the given attribute of a service's long syntax volume mount. The
+// path is the path of the mapping that contains the attribute.
+func volumeMountData(attributeName string, path []*ast.MappingValueNode) (string, bool) {
+	if len(path) < 3 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "volumes" {
+		return "", false
+	}
+	switch {
+	case len(path) == 3 && attributeName == "consistency":
+		return "volumeConsistency", true
+	case len(path) == 4 && path[3].Key.GetToken().Value == "bind" && attributeName == "propagation":
+		return "bindPropagation", true
+	}
+	return "", false
+}
+
+var volumeMountModifier = textEditModifier{
+	isInterested: func(attributeName string, path []*ast.MappingValueNode) bool {
+		_, ok := volumeMountData(attributeName, path)
+		return ok
+	},
+	modify: func(file *ast.File, manager *document.Manager, documentPath document.DocumentPath, edit protocol.TextEdit, attributeName, spacing string, path []*ast.MappingValueNode) protocol.TextEdit {
+		name, _ := volumeMountData(attributeName, path)
+		values := []string{}
+		for _, itemText := range completionData(name) {
+			values = append(values, itemText.label)
+		}
+		edit.NewText = fmt.Sprintf("%v: ${1|%v|}", attributeName, strings.Join(values, ","))
+		return edit
+	},
+}
+
+// ipamConfigPlaceholders maps the attributes of an item of a network's
+// ipam.config list to the placeholder values of their snippets. The
+// addresses are in the same subnet so that the inserted values are
+// consistent with each other.
+var ipamConfigPlaceholders = map[string]string{
+	"subnet":   "${1:172.16.0.0/24}",
+	"ip_range": "${1:172.16.0.0/28}",
+	"gateway":  "${1:172.16.0.1}",
+}
+
+var ipamConfigModifier = textEditModifier{
+	isInterested: func(attributeName string, path []*ast.MappingValueNode) bool {
+		return len(path) == 4 && path[0].Key.GetToken().Value == "networks" && path[2].Key.GetToken().Value == "ipam" && path[3].Key.GetToken().Value == "config"
+	},
+	modify: func(file *ast.File, manager *document.Manager, documentPath document.DocumentPath, edit protocol.TextEdit, attributeName, spacing string, path []*ast.MappingValueNode) protocol.TextEdit {
+		if attributeName == "aux_addresses" {
+			edit.NewText = fmt.Sprintf("aux_addresses:\n%v${1:host1}: ${2:172.16.0.5}", spacing)
+		} else if placeholder, ok := ipamConfigPlaceholders[attributeName]; ok {
+			edit.NewText = fmt.Sprintf("%v: %v", attributeName, placeholder)
+		}
+		return edit
+	},
+}
+
+// healthcheckPlaceholders maps the attributes of a healthcheck that
+// are not durations to the snippets that are inserted for them. The
+// test is the same one that the code action for adding a healthcheck
+// falls back to.
+var healthcheckPlaceholders = map[string]string{
+	"test":    `test: ["CMD-SHELL", "${1:curl -f http://localhost/ || exit 1}"]`,
+	"retries": "retries: ${1:3}",
+}
+
+var healthcheckModifier = textEditModifier{
+	isInterested: func(attributeName string, path []*ast.MappingValueNode) bool {
+		_, ok := healthcheckPlaceholders[attributeName]
+		return ok && len(path) == 3 && path[0].Key.GetToken().Value == "services" && path[2].Key.GetToken().Value == "healthcheck"
+	},
+	modify: func(file *ast.File, manager *document.Manager, documentPath document.DocumentPath, edit protocol.TextEdit, attributeName, spacing string, path []*ast.MappingValueNode) protocol.TextEdit {
+		edit.NewText = healthcheckPlaceholders[attributeName]
+		return edit
+	},
+}
+
+// durationUnits is appended to the documentation of the attributes
+// that take a duration.
+const durationUnits = "Durations are written as a number followed by a unit such as `1m30s`. The accepted units are `us`, `ms`, `s`, `m`, and `h`."
+
+// durationAttributes maps the attributes that contain duration
+// attributes to the placeholder value of each duration attribute.
+var durationAttributes = map[string]map[string]string{
+	"services":       {"stop_grace_period": "10s"},
+	"healthcheck":    {"interval": "30s", "timeout": "30s", "start_period": "0s", "start_interval": "5s"},
+	"restart_policy": {"delay": "5s", "window": "0s"},
+}
+
+// durationPlaceholder returns the placeholder value of the attribute if
+// it takes a duration.
+func durationPlaceholder(attributeName string, path []*ast.MappingValueNode) (string, bool) {
+	if len(path) < 2 || path[0].Key.GetToken().Value != "services" {
+		return "", false
+	}
+	parent := ""
+	switch {
+	case len(path) == 2:
+		parent = "services"
+	case len(path) == 3 && path[2].Key.GetToken().Value == "healthcheck":
+		parent = "healthcheck"
+	case len(path) == 4 && path[2].Key.GetToken().Value == "deploy" && path[3].Key.GetToken().Value == "restart_policy":
+		parent = "restart_policy"
+	}
+	placeholder, ok := durationAttributes[parent][attributeName]
+	return placeholder, ok
+}
+
+var durationModifier = textEditModifier{
+	isInterested: func(attributeName string, path []*ast.MappingValueNode) bool {
+		_, ok := durationPlaceholder(attributeName, path)
+		return ok
+	},
+	modify: func(file *ast.File, manager *document.Manager, documentPath document.DocumentPath, edit protocol.TextEdit, attributeName, spacing string, path []*ast.MappingValueNode) protocol.TextEdit {
+		placeholder, _ := durationPlaceholder(attributeName, path)
+		edit.NewText = fmt.Sprintf("%v: ${1:%v}", attributeName, placeholder)
+		return edit
+	},
+}
+
+var textEditModifiers = []textEditModifier{buildTargetModifier, serviceSuggestionModifier, serviceProviderModifier, serviceProviderTypeModifier, developWatchModifier, blkioConfigModifier, configContentModifier, placementPreferencesModifier, volumeMountModifier, ipamConfigModifier, healthcheckModifier, durationModifier}
 
 func prefix(line string, character int) string {
 	sb := strings.Builder{}
@@ -169,6 +348,31 @@ func createTopLevelItems() []protocol.CompletionItem {
 	return items
 }
 
+// topLevelAttributes returns the names of the top-level attributes
+// that have already been defined in the YAML document that the given
+// line is in. Attributes on the given line are not included as they
+// are what is being completed.
+func topLevelAttributes(file *ast.File, line int) []string {
+	var body ast.Node
+	for _, doc := range file.Docs {
+		if doc.Start == nil || doc.Start.Position.Line < line {
+			body = doc.Body
+		}
+	}
+	mappingNode, ok := body.(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+	attributes := []string{}
+	for _, child := range mappingNode.Values {
+		key := resolveAnchor(child.Key).GetToken()
+		if key.Position.Line != line {
+			attributes = append(attributes, key.Value)
+		}
+	}
+	return attributes
+}
+
 func calculateTopLevelNodeOffset(file *ast.File) int {
 	if len(file.Docs) == 1 {
 		if m, ok := file.Docs[0].Body.(*ast.MappingNode); ok {
@@ -178,11 +382,22 @@ func calculateTopLevelNodeOffset(file *ast.File) int {
 	return -1
 }
 
-func Completion(ctx context.Context, params *protocol.CompletionParams, manager *document.Manager, doc document.ComposeDocument) (*protocol.CompletionList, error) {
+// Completion returns the completion items for the given position of
+// the Compose file. The given image names are suggested for a service's
+// image attribute in addition to the bundled list of common images.
+func Completion(ctx context.Context, params *protocol.CompletionParams, manager *document.Manager, doc document.ComposeDocument, imageNames []string) (*protocol.CompletionList, error) {
 	documentPath, err := doc.DocumentPath()
 	if err != nil {
 		return nil, fmt.Errorf("LSP client sent invalid URI: %v", params.TextDocument.URI)
 	}
+	// a line that was ignored because of a syntax error is not in the
+	// node tree so its context cannot be determined
+	if slices.Contains(doc.IgnoredLines(), int(params.Position.Line)) {
+		return nil, nil
+	}
+	if list, handled := triggeredCompletion(ctx, params, manager, doc, documentPath, imageNames); handled {
+		return list, nil
+	}
 
 	file := doc.File()
 	if file == nil || len(file.Docs) == 0 {
@@ -195,7 +410,11 @@ func Completion(ctx context.Context, params *protocol.CompletionParams, manager
 	lspLine := int(params.Position.Line)
 	topLevelNodeOffset := calculateTopLevelNodeOffset(file)
 	if topLevelNodeOffset != -1 && params.Position.Character == uint32(topLevelNodeOffset) {
-		return &protocol.CompletionList{Items: createTopLevelItems()}, nil
+		if doc.IsJSON() {
+			items := removeExistingAttributes(createJSONItems(params, schemaProperties(), 0), topLevelAttributes(file, lspLine+1))
+			return processItems(items, false), nil
+		}
+		return &protocol.CompletionList{Items: removeExistingAttributes(createTopLevelItems(), topLevelAttributes(file, lspLine+1))}, nil
 	}
 
 	lines := strings.Split(string(doc.Input()), "\n")
@@ -220,13 +439,23 @@ func Completion(ctx context.Context, params *protocol.CompletionParams, manager
 		if topLevelNodeOffset != -1 && params.Position.Character != uint32(topLevelNodeOffset) {
 			return nil, nil
 		}
-		return &protocol.CompletionList{Items: createTopLevelItems()}, nil
+		return &protocol.CompletionList{Items: removeExistingAttributes(createTopLevelItems(), topLevelAttributes(file, line))}, nil
 	} else if len(path) == 1 {
 		if path[0].Key.GetToken().Value == "include" {
 			schema := schemaProperties()["include"].Items.(*jsonschema.Schema)
 			items := createSchemaItems(params, schema.Ref.OneOf[1].Properties, lines, lspLine, whitespaceLine, prefixLength, file, manager, documentPath, path)
+			for i := range items {
+				items[i].Documentation = includeDocumentation[items[i].Label]
+			}
+			items = removeExistingAttributes(items, siblingAttributes(path, line, true))
 			items = append(items, folderStructureCompletionItems(documentPath, path, removeQuote(prefixContent))...)
 			return processItems(items, whitespaceLine), nil
+		} else if path[0].Key.GetToken().Value == "name" {
+			items := projectNameCompletionItems(documentPath, path[0], line, character, params)
+			if len(items) == 0 {
+				return nil, nil
+			}
+			return &protocol.CompletionList{Items: items}, nil
 		}
 		return nil, nil
 	} else if path[1].Key.GetToken().Position.Column >= character {
@@ -234,6 +463,17 @@ func Completion(ctx context.Context, params *protocol.CompletionParams, manager
 	}
 
 	path, nodeProps, arrayAttributes := nodeProperties(path, line, character)
+	if doc.IsJSON() {
+		properties, ok := nodeProps.(map[string]*jsonschema.Schema)
+		if !ok {
+			return nil, nil
+		}
+		items := removeExistingAttributes(createJSONItems(params, properties, prefixLength), existingAttributes(path, line, arrayAttributes))
+		if len(items) == 0 {
+			return nil, nil
+		}
+		return processItems(items, false), nil
+	}
 	dependencies := dependencyCompletionItems(file, documentPath, path, params, prefixLength)
 	if len(dependencies) > 0 {
 		return &protocol.CompletionList{Items: dependencies}, nil
@@ -254,7 +494,56 @@ func Completion(ctx context.Context, params *protocol.CompletionParams, manager
 	if len(items) == 0 {
 		items = volumeDependencyCompletionItems(file, path, params, prefixLength)
 	}
+	if len(items) == 0 {
+		items = providerOptionCompletionItems(path, line, params, prefixLength)
+	}
+	if len(items) == 0 {
+		items = securityOptCompletionItems(path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = capabilityCompletionItems(path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = deviceCapabilityCompletionItems(path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = placementCompletionItems(path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = volumeMountCompletionItems(path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = sysctlCompletionItems(path, lines[lspLine], removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = stopSignalCompletionItems(path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = deviceCompletionItems(path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = entitlementCompletionItems(path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = exposeProtocolCompletionItems(path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = environmentSourceCompletionItems(file, documentPath, path, params, prefixLength)
+	}
+	if len(items) == 0 {
+		items = namespaceModeCompletionItems(file, path, removeQuote(prefixContent), params)
+	}
+	if len(items) == 0 {
+		items = imageCompletionItems(path, removeQuote(prefixContent), imageNames, params)
+	}
+	if len(items) == 0 {
+		items = additionalContextCompletionItems(manager, file, documentPath, path, removeQuote(prefixContent), params)
+	}
 	schemaItems := createSchemaItems(params, nodeProps, lines, lspLine, whitespaceLine && arrayAttributes, prefixLength, file, manager, documentPath, path)
+	if _, ok := nodeProps.(map[string]*jsonschema.Schema); ok {
+		schemaItems = removeExistingAttributes(schemaItems, existingAttributes(path, line, arrayAttributes))
+		markRequiredAttributes(schemaItems, path)
+	}
 	items = append(items, schemaItems...)
 	if len(items) == 0 {
 		return nil, nil
@@ -262,6 +551,105 @@ func Completion(ctx context.Context, params *protocol.CompletionParams, manager
 	return processItems(items, whitespaceLine && arrayAttributes), nil
 }
 
+// siblingAttributes returns the names of the attributes that have
+// already been defined in the mapping that the given line is in. If
+// the mapping is an item of a sequence then arrayAttributes should be
+// true. Attributes on the given line are not included as they are
+// what is being completed.
+func siblingAttributes(path []*ast.MappingValueNode, line int, arrayAttributes bool) []string {
+	if len(path) == 0 {
+		return nil
+	}
+
+	var mappingNode *ast.MappingNode
+	switch value := resolveAnchor(path[len(path)-1].Value).(type) {
+	case *ast.MappingNode:
+		if !arrayAttributes {
+			mappingNode = value
+		}
+	case *ast.SequenceNode:
+		if arrayAttributes {
+			var candidate ast.Node
+			for _, item := range value.Values {
+				if item.GetToken().Position.Line <= line {
+					candidate = item
+				}
+			}
+			mappingNode, _ = candidate.(*ast.MappingNode)
+		}
+	}
+
+	if mappingNode == nil {
+		return nil
+	}
+	attributes := []string{}
+	for _, child := range mappingNode.Values {
+		key := resolveAnchor(child.Key).GetToken()
+		if key.Position.Line != line {
+			attributes = append(attributes, key.Value)
+		}
+	}
+	return attributes
+}
+
+// exclusiveAttributes maps an attribute of a service to the groups of
+// its attributes of which only one may be set.
+var exclusiveAttributes = map[string][]string{
+	"credential_spec": {"config", "file", "registry"},
+}
+
+// existingAttributes returns the attributes that should not be suggested
+// in the mapping that the given line is in. These are the attributes that
+// have already been defined and the attributes that cannot be set
+// together with one of them.
+func existingAttributes(path []*ast.MappingValueNode, line int, arrayAttributes bool) []string {
+	attributes := siblingAttributes(path, line, arrayAttributes)
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" {
+		return attributes
+	}
+	if group, ok := exclusiveAttributes[path[2].Key.GetToken().Value]; ok {
+		for _, attribute := range attributes {
+			if slices.Contains(group, attribute) {
+				return append(attributes, group...)
+			}
+		}
+	}
+	return attributes
+}
+
+// requiredAttributes maps an attribute of a service to the attributes
+// that must be set when it is written as an object.
+var requiredAttributes = map[string][]string{
+	"extends": {"service"},
+}
+
+// markRequiredAttributes preselects the items of the attributes that
+// must be set in the service attribute that is being completed and
+// notes in their details that they are required.
+func markRequiredAttributes(items []protocol.CompletionItem, path []*ast.MappingValueNode) {
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" {
+		return
+	}
+	required := requiredAttributes[path[2].Key.GetToken().Value]
+	for i := range items {
+		if slices.Contains(required, items[i].Label) {
+			items[i].Preselect = types.CreateBoolPointer(true)
+			if items[i].Detail != nil {
+				items[i].Detail = types.CreateStringPointer(fmt.Sprintf("%v (required)", *items[i].Detail))
+			}
+		}
+	}
+}
+
+func removeExistingAttributes(items []protocol.CompletionItem, attributes []string) []protocol.CompletionItem {
+	if len(attributes) == 0 {
+		return items
+	}
+	return slices.DeleteFunc(items, func(item protocol.CompletionItem) bool {
+		return slices.Contains(attributes, item.Label)
+	})
+}
+
 func removeQuote(prefix string) string {
 	if len(prefix) > 0 && (prefix[0] == 34 || prefix[0] == 39) {
 		return prefix[1:]
@@ -352,12 +740,117 @@ func createSchemaItems(params *protocol.CompletionParams, nodeProps any, lines [
 				}
 			}
 			item.TextEdit = modifyTextEdit(file, manager, documentPath, item.TextEdit.(protocol.TextEdit), attributeName, spacing, path)
+			if _, ok := durationPlaceholder(attributeName, path); ok {
+				if item.Documentation == nil {
+					item.Documentation = durationUnits
+				} else {
+					item.Documentation = fmt.Sprintf("%v\n\n%v", item.Documentation, durationUnits)
+				}
+			}
+			if acceptsKeyValueList(schema) {
+				items = append(items, keyValueFormItems(item, attributeName, spacing)...)
+				continue
+			}
 			items = append(items, item)
 		}
 	}
 	return items
 }
 
+// createJSONItems creates the items for the attributes of a Compose
+// file that has been written in JSON. The YAML specific completions
+// such as the block style snippets are not offered for such files so
+// an attribute is inserted with an empty value of the right type.
+func createJSONItems(params *protocol.CompletionParams, properties map[string]*jsonschema.Schema, wordPrefixLength protocol.UInteger) []protocol.CompletionItem {
+	items := []protocol.CompletionItem{}
+	for attributeName, schema := range properties {
+		item := protocol.CompletionItem{
+			Detail: extractDetail(schema),
+			Label:  attributeName,
+			TextEdit: protocol.TextEdit{
+				NewText: jsonInsertText(attributeName, schema),
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - wordPrefixLength,
+					},
+					End: params.Position,
+				},
+			},
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		}
+		if schema.Description != "" {
+			item.Documentation = schema.Description
+		} else if schema.Ref != nil && schema.Ref.Description != "" {
+			item.Documentation = schema.Ref.Description
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// jsonInsertText returns the snippet that inserts the attribute and a
+// value of its type into a Compose file that has been written in JSON.
+func jsonInsertText(attributeName string, schema *jsonschema.Schema) string {
+	if schema.Enum != nil {
+		options := []string{}
+		for i := range schema.Enum.Values {
+			options = append(options, schema.Enum.Values[i].(string))
+		}
+		slices.Sort(options)
+		return fmt.Sprintf("\"%v\": \"${1|%v|}\"", attributeName, strings.Join(options, ","))
+	}
+
+	schemaTypes := referencedTypes(schema)
+	if len(schemaTypes) == 1 && schemaTypes[0] == "array" {
+		return fmt.Sprintf("\"%v\": [$1]", attributeName)
+	}
+	if slices.Contains(schemaTypes, "object") {
+		return fmt.Sprintf("\"%v\": {$1}", attributeName)
+	}
+	if slices.Contains(schemaTypes, "boolean") {
+		return fmt.Sprintf("\"%v\": ${1|true,false|}", attributeName)
+	}
+	if slices.Contains(schemaTypes, "integer") || slices.Contains(schemaTypes, "number") {
+		return fmt.Sprintf("\"%v\": $1", attributeName)
+	}
+	return fmt.Sprintf("\"%v\": \"$1\"", attributeName)
+}
+
+// acceptsKeyValueList returns true if the schema accepts either a
+// mapping or a list of key=value strings such as labels or sysctls.
+func acceptsKeyValueList(schema *jsonschema.Schema) bool {
+	if schema.Ref == nil {
+		return false
+	}
+	return strings.HasSuffix(schema.Ref.Location, "/list_or_dict") || strings.HasSuffix(schema.Ref.Location, "/extra_hosts")
+}
+
+// keyValueFormItems replaces the given item with one item that inserts
+// the attribute as a mapping and another that inserts it as a list of
+// key=value strings so that the user can pick the form to use.
+func keyValueFormItems(item protocol.CompletionItem, attributeName, spacing string) []protocol.CompletionItem {
+	edit := item.TextEdit.(protocol.TextEdit)
+	objectItem := item
+	objectItem.Label = fmt.Sprintf("%v (as object)", attributeName)
+	objectItem.FilterText = types.CreateStringPointer(attributeName)
+	objectItem.Detail = types.CreateStringPointer("object")
+	objectItem.TextEdit = protocol.TextEdit{
+		NewText: fmt.Sprintf("%v:\n%v${1:key}: ${2:value}", attributeName, spacing),
+		Range:   edit.Range,
+	}
+	arrayItem := item
+	arrayItem.Label = fmt.Sprintf("%v (as array)", attributeName)
+	arrayItem.FilterText = types.CreateStringPointer(attributeName)
+	arrayItem.Detail = types.CreateStringPointer("array")
+	arrayItem.TextEdit = protocol.TextEdit{
+		NewText: fmt.Sprintf("%v:\n%v- ${1:key}=${2:value}", attributeName, spacing),
+		Range:   edit.Range,
+	}
+	return []protocol.CompletionItem{arrayItem, objectItem}
+}
+
 func processItems(items []protocol.CompletionItem, arrayPrefix bool) *protocol.CompletionList {
 	slices.SortFunc(items, func(a, b protocol.CompletionItem) int {
 		return strings.Compare(a.Label, b.Label)
@@ -397,9 +890,33 @@ func modifyTextEdit(file *ast.File, manager *document.Manager, documentPath docu
 	return edit
 }
 
+// includeDocumentation is the documentation of the attributes of an
+// include entry in its long form. The schema's descriptions of path
+// and env_file are hidden by their references so they are kept here.
+var includeDocumentation = map[string]string{
+	"env_file":          "The environment files to use when interpolating variables in the included Compose files. Defaults to the .env file in the project directory of the included files.",
+	"path":              "The Compose files to include. Relative paths are resolved from the directory of this Compose file.",
+	"project_directory": "The directory that relative paths in the included Compose files are resolved from. Defaults to the directory of the first included Compose file.",
+}
+
+// includesComposeFiles returns true if the path refers to an entry of
+// the top-level include attribute that can only be a Compose file.
+func includesComposeFiles(path []*ast.MappingValueNode) bool {
+	if path[0].Key.GetToken().Value != "include" {
+		return false
+	}
+	return len(path) == 1 || (len(path) == 2 && path[1].Key.GetToken().Value == "path")
+}
+
+func isComposeFile(name string) bool {
+	extension := strings.ToLower(filepath.Ext(name))
+	return extension == ".yaml" || extension == ".yml"
+}
+
 func folderStructureCompletionItems(documentPath document.DocumentPath, path []*ast.MappingValueNode, prefix string) []protocol.CompletionItem {
 	folder, hideFiles := directoryForNode(documentPath, path, prefix)
 	if folder != "" {
+		composeFilesOnly := includesComposeFiles(path)
 		items := []protocol.CompletionItem{}
 		entries, _ := os.ReadDir(folder)
 		for _, entry := range entries {
@@ -407,6 +924,13 @@ func folderStructureCompletionItems(documentPath document.DocumentPath, path []*
 				item := protocol.CompletionItem{Label: entry.Name()}
 				item.Kind = types.CreateCompletionItemKindPointer(protocol.CompletionItemKindFolder)
 				items = append(items, item)
+			} else if composeFilesOnly {
+				// a file cannot include itself
+				if isComposeFile(entry.Name()) && (filepath.Clean(folder) != filepath.Clean(documentPath.Folder) || entry.Name() != documentPath.FileName) {
+					item := protocol.CompletionItem{Label: entry.Name()}
+					item.Kind = types.CreateCompletionItemKindPointer(protocol.CompletionItemKindFile)
+					items = append(items, item)
+				}
 			} else if !hideFiles {
 				item := protocol.CompletionItem{Label: entry.Name()}
 				item.Kind = types.CreateCompletionItemKindPointer(protocol.CompletionItemKindFile)
@@ -447,9 +971,7 @@ func directoryForNode(documentPath document.DocumentPath, path []*ast.MappingVal
 			//     volumes:
 			//       - ...
 			switch path[2].Key.GetToken().Value {
-			case "env_file":
-				return directoryForPrefix(documentPath, prefix, documentPath.Folder, false), false
-			case "label_file":
+			case "env_file", "label_file":
 				return directoryForPrefix(documentPath, prefix, documentPath.Folder, false), false
 			case "volumes":
 				return directoryForPrefix(documentPath, prefix, "", true), false
@@ -568,17 +1090,27 @@ func findBuildStages(manager *document.Manager, dockerfileURI, dockerfilePath, p
 	return items
 }
 
+// buildDockerfile returns the path of the Dockerfile that the build
+// object uses. False is returned if the Dockerfile has been inlined.
+func buildDockerfile(mappingNode *ast.MappingNode) (string, bool) {
+	dockerfileAttributePath := "Dockerfile"
+	for _, buildAttribute := range mappingNode.Values {
+		switch buildAttribute.Key.GetToken().Value {
+		case "dockerfile_inline":
+			return "", false
+		case "dockerfile":
+			dockerfileAttributePath = buildAttribute.Value.GetToken().Value
+		}
+	}
+	return dockerfileAttributePath, true
+}
+
 func buildTargetCompletionItems(params *protocol.CompletionParams, manager *document.Manager, path []*ast.MappingValueNode, documentPath document.DocumentPath, prefixLength protocol.UInteger) ([]protocol.CompletionItem, bool) {
 	if len(path) == 4 && path[2].Key.GetToken().Value == "build" && path[3].Key.GetToken().Value == "target" {
 		if mappingNode, ok := path[2].Value.(*ast.MappingNode); ok {
-			dockerfileAttributePath := "Dockerfile"
-			for _, buildAttribute := range mappingNode.Values {
-				switch buildAttribute.Key.GetToken().Value {
-				case "dockerfile_inline":
-					return nil, true
-				case "dockerfile":
-					dockerfileAttributePath = buildAttribute.Value.GetToken().Value
-				}
+			dockerfileAttributePath, ok := buildDockerfile(mappingNode)
+			if !ok {
+				return nil, true
 			}
 
 			dockerfileURI, dockerfilePath := types.Concatenate(documentPath.Folder, dockerfileAttributePath, documentPath.WSLDollarSignHost)
@@ -620,6 +1152,70 @@ func createBuildStageItems(params *protocol.CompletionParams, manager *document.
 	return items
 }
 
+// additionalContextCompletionItems suggests the other services and the
+// build stages of the service's Dockerfile that an additional build
+// context can refer to with the service: and target: prefixes. Both the
+// mapping form and the list of name=value strings are supported.
+func additionalContextCompletionItems(manager *document.Manager, file *ast.File, documentPath document.DocumentPath, path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) < 4 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "build" || path[3].Key.GetToken().Value != "additional_contexts" {
+		return nil
+	}
+	if len(path) == 4 {
+		_, value, found := strings.Cut(prefix, "=")
+		if !found {
+			return nil
+		}
+		prefix = value
+	} else if len(path) == 5 {
+		key := path[4].Key.GetToken()
+		if key.Position.Line != int(params.Position.Line)+1 || key.Position.Column+len(key.Value) > int(params.Position.Character) {
+			return nil
+		}
+	} else {
+		return nil
+	}
+
+	itemTexts := []completionItemText{}
+	for _, service := range findDependencies(file, "services") {
+		if service != path[1].Key.GetToken().Value {
+			itemTexts = append(itemTexts, completionItemText{label: "service:" + service})
+		}
+	}
+	if mappingNode, ok := path[2].Value.(*ast.MappingNode); ok {
+		if dockerfileAttributePath, ok := buildDockerfile(mappingNode); ok {
+			dockerfileURI, dockerfilePath := types.Concatenate(documentPath.Folder, dockerfileAttributePath, documentPath.WSLDollarSignHost)
+			for _, stage := range findBuildStages(manager, dockerfileURI, dockerfilePath, "") {
+				itemTexts = append(itemTexts, completionItemText{label: "target:" + stage.label, documentation: stage.documentation})
+			}
+		}
+	}
+
+	items := []protocol.CompletionItem{}
+	for _, itemText := range itemTexts {
+		if !strings.HasPrefix(itemText.label, prefix) {
+			continue
+		}
+		item := protocol.CompletionItem{
+			Label: itemText.label,
+			TextEdit: protocol.TextEdit{
+				NewText: itemText.label,
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - protocol.UInteger(len(prefix)),
+					},
+					End: params.Position,
+				},
+			},
+		}
+		if itemText.documentation != "" {
+			item.Documentation = itemText.documentation
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 func dependencyCompletionItems(file *ast.File, documentPath document.DocumentPath, path []*ast.MappingValueNode, params *protocol.CompletionParams, prefixLength protocol.UInteger) []protocol.CompletionItem {
 	dependency := map[string]string{
 		"depends_on": "services",
@@ -681,6 +1277,527 @@ func volumeDependencyCompletionItems(
 	return items
 }
 
+// providerOptions are the documented options of the known provider
+// types. Providers are extensible so other options may be valid.
+var providerOptions = map[string][]completionItemText{
+	"model": {
+		{label: "context-size", newText: "context-size: ", documentation: "The maximum number of tokens that the model can process."},
+		{label: "model", newText: "model: ${1:ai/example-model}", documentation: "The name of the model to run such as `ai/smollm2`."},
+		{label: "runtime-flags", newText: "runtime-flags: ", documentation: "Raw command-line flags to pass to the inference engine."},
+	},
+}
+
+// providerOptionCompletionItems suggests the documented options of a
+// service's provider if the type of the provider is known.
+func providerOptionCompletionItems(path []*ast.MappingValueNode, line int, params *protocol.CompletionParams, prefixLength protocol.UInteger) []protocol.CompletionItem {
+	if len(path) != 4 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "provider" || path[3].Key.GetToken().Value != "options" {
+		return nil
+	}
+	typeNode := mappingValue(path[2].Value, "type")
+	if typeNode == nil {
+		return nil
+	}
+
+	items := []protocol.CompletionItem{}
+	existing := siblingAttributes(path, line, false)
+	for _, option := range providerOptions[resolveAnchor(typeNode.Value).GetToken().Value] {
+		if slices.Contains(existing, option.label) {
+			continue
+		}
+		items = append(items, protocol.CompletionItem{
+			Label:            option.label,
+			Documentation:    option.documentation,
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+			TextEdit: protocol.TextEdit{
+				NewText: option.newText,
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - prefixLength,
+					},
+					End: params.Position,
+				},
+			},
+		})
+	}
+	return items
+}
+
+// securityOptCompletionItems suggests the options of a service's
+// security_opt entries. If the entry is a label then the parts of the
+// label are suggested instead.
+func securityOptCompletionItems(path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	// a trailing colon makes the entry be parsed as a mapping
+	if len(path) == 4 && path[3].Key.GetToken().Value == "label" {
+		path = path[:3]
+	}
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "security_opt" {
+		return nil
+	}
+
+	options := completionData("securityOptions")
+	for _, separator := range []string{":", "="} {
+		if label, found := strings.CutPrefix(prefix, "label"+separator); found {
+			options = completionData("securityLabelOptions")
+			prefix = label
+			break
+		}
+	}
+	return dataCompletionItems(options, prefix, params)
+}
+
+// capabilityCompletionItems suggests the capabilities that can be
+// added to or dropped from the container of a service.
+func capabilityCompletionItems(path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" {
+		return nil
+	}
+	if attribute := path[2].Key.GetToken().Value; attribute != "cap_add" && attribute != "cap_drop" {
+		return nil
+	}
+	return dataCompletionItems(completionData("capabilities"), prefix, params)
+}
+
+// deviceCapabilityCompletionItems suggests the capabilities that a
+// device that is requested by a service's gpus attribute or by the
+// device reservations of its deploy attribute must have.
+func deviceCapabilityCompletionItems(path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) < 4 || path[0].Key.GetToken().Value != "services" || path[len(path)-1].Key.GetToken().Value != "capabilities" {
+		return nil
+	}
+	attributes := []string{}
+	for _, node := range path[2 : len(path)-1] {
+		attributes = append(attributes, node.Key.GetToken().Value)
+	}
+	if !slices.Equal(attributes, []string{"gpus"}) && !slices.Equal(attributes, []string{"deploy", "resources", "reservations", "devices"}) {
+		return nil
+	}
+	return dataCompletionItems(completionData("deviceCapabilities"), prefix, params)
+}
+
+// placementCompletionItems suggests the node attributes that the
+// placement constraints of a service's deploy attribute can match and
+// the node labels that its placement preferences can spread over.
+func placementCompletionItems(path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) < 5 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "deploy" || path[3].Key.GetToken().Value != "placement" {
+		return nil
+	}
+	switch {
+	case len(path) == 5 && path[4].Key.GetToken().Value == "constraints":
+		return dataCompletionItems(completionData("placementConstraints"), prefix, params)
+	case len(path) == 6 && path[4].Key.GetToken().Value == "preferences" && path[5].Key.GetToken().Value == "spread":
+		return dataCompletionItems(completionData("placementPreferences"), prefix, params)
+	}
+	return nil
+}
+
+// volumeMountCompletionItems suggests the values of the attributes of
+// a service's long syntax volume mount that accept a fixed set of
+// values that the schema does not enumerate.
+func volumeMountCompletionItems(path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) == 0 {
+		return nil
+	}
+	name, ok := volumeMountData(path[len(path)-1].Key.GetToken().Value, path[:len(path)-1])
+	if !ok {
+		return nil
+	}
+	return dataCompletionItems(completionData(name), prefix, params)
+}
+
+// sysctlCompletionItems suggests the kernel parameters that can be set
+// with a service's sysctls attribute. An entry of the list form
+// separates the parameter from its value with an equals sign while the
+// mapping form uses the parameter as a key.
+func sysctlCompletionItems(path []*ast.MappingValueNode, line, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "sysctls" {
+		return nil
+	}
+	if strings.ContainsAny(prefix, "=:") {
+		return nil
+	}
+
+	sysctls := completionData("sysctls")
+	if !strings.HasPrefix(strings.TrimSpace(line), "-") {
+		mappingForm := []completionItemText{}
+		for _, sysctl := range sysctls {
+			sysctl.newText = strings.Replace(sysctl.newText, "=", ": ", 1)
+			mappingForm = append(mappingForm, sysctl)
+		}
+		sysctls = mappingForm
+	}
+	return dataCompletionItems(sysctls, prefix, params)
+}
+
+// stopSignalCompletionItems suggests the signals that can stop the
+// container of a service.
+func stopSignalCompletionItems(path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "stop_signal" {
+		return nil
+	}
+	return dataCompletionItems(completionData("signals"), prefix, params)
+}
+
+// entitlements are the privileged entitlements that can be granted to
+// the build of a service with its build object's entitlements
+// attribute.
+var entitlements = []completionItemText{
+	{label: "network.host", newText: "network.host", documentation: "Allows the build to run with the host's network stack instead of an isolated network namespace. The build can reach any service that is listening on the host, so only grant this to builds that you trust."},
+	{label: "security.insecure", newText: "security.insecure", documentation: "Allows the build to run privileged containers without the default seccomp, AppArmor, and capability restrictions. A build with this entitlement can take full control of the builder's host, so only grant this to builds that you trust."},
+}
+
+// entitlementCompletionItems suggests the entitlements that can be
+// granted to the build of a service.
+func entitlementCompletionItems(path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) != 4 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "build" || path[3].Key.GetToken().Value != "entitlements" {
+		return nil
+	}
+
+	items := []protocol.CompletionItem{}
+	for _, entitlement := range entitlements {
+		items = append(items, protocol.CompletionItem{
+			Label:            entitlement.label,
+			Documentation:    entitlement.documentation,
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+			TextEdit: protocol.TextEdit{
+				NewText: entitlement.newText,
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - protocol.UInteger(len(prefix)),
+					},
+					End: params.Position,
+				},
+			},
+		})
+	}
+	return items
+}
+
+// exposedPort matches a port or a range of ports of a service's expose
+// attribute that may be followed by the start of a protocol suffix.
+var exposedPort = regexp.MustCompile(`^[0-9]+(-[0-9]+)?(/[a-z]*)?$`)
+
+// exposeProtocols are the protocols that a port of a service's expose
+// attribute can be suffixed with.
+var exposeProtocols = []completionItemText{
+	{label: "tcp", documentation: "Exposes the port for TCP traffic. This is the default if no protocol is given."},
+	{label: "udp", documentation: "Exposes the port for UDP traffic."},
+	{label: "sctp", documentation: "Exposes the port for SCTP traffic."},
+}
+
+// exposeProtocolCompletionItems suggests the protocol suffixes of the
+// port that is being typed as an entry of a service's expose attribute.
+// The entry may be an integer or a string so the suffixes are offered
+// as soon as a port has been typed.
+func exposeProtocolCompletionItems(path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "expose" || !exposedPort.MatchString(prefix) {
+		return nil
+	}
+
+	port, _, _ := strings.Cut(prefix, "/")
+	items := []protocol.CompletionItem{}
+	for _, exposeProtocol := range exposeProtocols {
+		items = append(items, protocol.CompletionItem{
+			Label:            fmt.Sprintf("%v/%v", port, exposeProtocol.label),
+			Documentation:    exposeProtocol.documentation,
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+			TextEdit: protocol.TextEdit{
+				NewText: fmt.Sprintf("%v/%v", port, exposeProtocol.label),
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - protocol.UInteger(len(prefix)),
+					},
+					End: params.Position,
+				},
+			},
+		})
+	}
+	return items
+}
+
+// deviceTemplates are snippets of the syntax of the entries of a
+// service's devices and device_cgroup_rules attributes. The paths are
+// left as placeholders as they depend on the host.
+var deviceTemplates = map[string][]completionItemText{
+	"devices": {
+		{label: "HOST_PATH:CONTAINER_PATH", newText: "${1:/dev/host}:${2:/dev/container}", documentation: "Maps a device of the host to a path in the container."},
+		{label: "HOST_PATH:CONTAINER_PATH:PERMISSIONS", newText: "${1:/dev/host}:${2:/dev/container}:${3|rwm,rw,r|}", documentation: "Maps a device of the host to a path in the container with the given cgroup permissions."},
+	},
+	"device_cgroup_rules": {
+		{label: "TYPE MAJOR:MINOR PERMISSIONS", newText: "${1|c,b,a|} ${2:*}:${3:*} ${4|rwm,rw,r,w,m|}", documentation: "Allows the container to access the character (c), block (b), or all (a) devices with the given major and minor numbers. A * matches any number."},
+	},
+}
+
+// deviceCompletionItems suggests templates for the entries of a
+// service's devices and device_cgroup_rules attributes.
+func deviceCompletionItems(path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" {
+		return nil
+	}
+	templates, ok := deviceTemplates[path[2].Key.GetToken().Value]
+	if !ok {
+		return nil
+	}
+
+	items := []protocol.CompletionItem{}
+	for _, template := range templates {
+		items = append(items, protocol.CompletionItem{
+			Label:            template.label,
+			Documentation:    template.documentation,
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+			TextEdit: protocol.TextEdit{
+				NewText: template.newText,
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - protocol.UInteger(len(prefix)),
+					},
+					End: params.Position,
+				},
+			},
+		})
+	}
+	return items
+}
+
+// environmentSourceCompletionItems suggests the names of the variables
+// that a top-level config or secret can take its value from with its
+// environment attribute.
+func environmentSourceCompletionItems(file *ast.File, documentPath document.DocumentPath, path []*ast.MappingValueNode, params *protocol.CompletionParams, prefixLength protocol.UInteger) []protocol.CompletionItem {
+	if len(path) != 3 || path[2].Key.GetToken().Value != "environment" {
+		return nil
+	}
+	if attribute := path[0].Key.GetToken().Value; attribute != "configs" && attribute != "secrets" {
+		return nil
+	}
+
+	items := []protocol.CompletionItem{}
+	for _, name := range environmentVariableNames(file, documentPath) {
+		items = append(items, protocol.CompletionItem{
+			Label: name,
+			TextEdit: protocol.TextEdit{
+				NewText: name,
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - prefixLength,
+					},
+					End: params.Position,
+				},
+			},
+		})
+	}
+	return items
+}
+
+// projectName sanitizes the given folder name into the project name
+// that Compose would use by default. Uppercase letters are lowercased,
+// characters other than letters, digits, dashes, and underscores are
+// dropped, and the name must not start with a dash or an underscore.
+func projectName(folder string) string {
+	sb := strings.Builder{}
+	for _, r := range strings.ToLower(folder) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			sb.WriteRune(r)
+		}
+	}
+	return strings.TrimLeft(sb.String(), "-_")
+}
+
+// projectNameCompletionItems suggests the name of the folder that the
+// Compose file is in as the value of an empty top-level name attribute
+// as Compose uses it as the project name if a name has not been set.
+func projectNameCompletionItems(documentPath document.DocumentPath, nameNode *ast.MappingValueNode, line, character int, params *protocol.CompletionParams) []protocol.CompletionItem {
+	t := nameNode.Key.GetToken()
+	if t.Position.Line != line || character <= t.Position.Column+len(t.Value) {
+		return nil
+	}
+	if _, ok := resolveAnchor(nameNode.Value).(*ast.NullNode); !ok {
+		return nil
+	}
+	name := projectName(filepath.Base(documentPath.Folder))
+	if name == "" {
+		return nil
+	}
+	return []protocol.CompletionItem{
+		{
+			Label:         name,
+			Documentation: "The name of the folder that the Compose file is in which is the project name that Compose uses by default.",
+			TextEdit: protocol.TextEdit{
+				NewText: name,
+				Range: protocol.Range{
+					Start: params.Position,
+					End:   params.Position,
+				},
+			},
+		},
+	}
+}
+
+// commonImages are the official images that are suggested for a
+// service's image attribute before a tag has been typed.
+var commonImages = []completionItemText{
+	{label: "alpine", documentation: "A minimal Docker image based on Alpine Linux."},
+	{label: "busybox", documentation: "A tiny image with many common UNIX utilities."},
+	{label: "caddy", documentation: "A web server with automatic HTTPS."},
+	{label: "debian", documentation: "The Debian Linux distribution."},
+	{label: "eclipse-temurin", documentation: "OpenJDK builds of the Java platform from the Eclipse Temurin project."},
+	{label: "golang", documentation: "The Go programming language."},
+	{label: "httpd", documentation: "The Apache HTTP Server."},
+	{label: "mariadb", documentation: "The MariaDB relational database server."},
+	{label: "memcached", documentation: "A distributed in-memory cache."},
+	{label: "mongo", documentation: "The MongoDB document database."},
+	{label: "mysql", documentation: "The MySQL relational database server."},
+	{label: "nginx", documentation: "The nginx web server and reverse proxy."},
+	{label: "node", documentation: "The Node.js JavaScript runtime."},
+	{label: "php", documentation: "The PHP scripting language."},
+	{label: "postgres", documentation: "The PostgreSQL relational database server."},
+	{label: "python", documentation: "The Python programming language."},
+	{label: "rabbitmq", documentation: "The RabbitMQ message broker."},
+	{label: "redis", documentation: "The Redis in-memory data store."},
+	{label: "ruby", documentation: "The Ruby programming language."},
+	{label: "rust", documentation: "The Rust programming language."},
+	{label: "traefik", documentation: "A cloud native reverse proxy and load balancer."},
+	{label: "ubuntu", documentation: "The Ubuntu Linux distribution."},
+	{label: "valkey", documentation: "The Valkey in-memory data store."},
+}
+
+// imageCompletionItems suggests common images and the configured image
+// names for a service's image attribute. The repository name is
+// inserted with a tag placeholder after it so that the tag can be
+// typed right away. Nothing is suggested once a tag or a digest has
+// been started.
+func imageCompletionItems(path []*ast.MappingValueNode, prefix string, imageNames []string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" || path[2].Key.GetToken().Value != "image" {
+		return nil
+	}
+	if strings.ContainsAny(prefix, ":@$") {
+		return nil
+	}
+
+	itemTexts := slices.Clone(commonImages)
+	for _, name := range imageNames {
+		if !slices.ContainsFunc(itemTexts, func(itemText completionItemText) bool { return itemText.label == name }) {
+			itemTexts = append(itemTexts, completionItemText{label: name})
+		}
+	}
+
+	items := []protocol.CompletionItem{}
+	for _, itemText := range itemTexts {
+		newText := itemText.label + ":${1:latest}"
+		// a configured name may already pin a tag or a digest
+		if strings.ContainsAny(itemText.label, ":@") {
+			newText = itemText.label
+		}
+		item := protocol.CompletionItem{
+			Label:            itemText.label,
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+			TextEdit: protocol.TextEdit{
+				NewText: newText,
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - protocol.UInteger(len(prefix)),
+					},
+					End: params.Position,
+				},
+			},
+		}
+		if itemText.documentation != "" {
+			item.Documentation = itemText.documentation
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// namespaceModes are the values that can be set in the attributes of
+// a service that decide which namespaces its container uses.
+var namespaceModes = map[string][]completionItemText{
+	"network_mode": {
+		{label: "bridge", newText: "bridge", documentation: "Connects the container to the default bridge network."},
+		{label: "container", newText: "container:${1:name}", documentation: "Uses the network stack of another container."},
+		{label: "host", newText: "host", documentation: "Uses the network stack of the host."},
+		{label: "none", newText: "none", documentation: "Disables all container networking."},
+		{label: "service", newText: "service:${1:name}", documentation: "Uses the network stack of another service's container."},
+	},
+	"ipc": {
+		{label: "container", newText: "container:${1:name}", documentation: "Shares the IPC namespace of another container."},
+		{label: "host", newText: "host", documentation: "Shares the IPC namespace of the host."},
+		{label: "none", newText: "none", documentation: "Uses a private IPC namespace without /dev/shm mounted."},
+		{label: "private", newText: "private", documentation: "Uses a private IPC namespace that cannot be shared with other containers."},
+		{label: "service", newText: "service:${1:name}", documentation: "Shares the IPC namespace of another service's container."},
+		{label: "shareable", newText: "shareable", documentation: "Uses a private IPC namespace that other containers can share."},
+	},
+	"pid": {
+		{label: "container", newText: "container:${1:name}", documentation: "Shares the PID namespace of another container."},
+		{label: "host", newText: "host", documentation: "Shares the PID namespace of the host so that the container can see the host's processes."},
+		{label: "service", newText: "service:${1:name}", documentation: "Shares the PID namespace of another service's container."},
+	},
+	"uts": {
+		{label: "host", newText: "host", documentation: "Shares the UTS namespace of the host so that the container uses the host's hostname."},
+	},
+}
+
+// namespaceModeCompletionItems suggests the values of a service's
+// network_mode, ipc, pid, or uts attribute. If the value has the
+// service: prefix then the other services of the file are suggested
+// instead for the attributes that accept it.
+func namespaceModeCompletionItems(file *ast.File, path []*ast.MappingValueNode, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	// a trailing colon makes the value be parsed as a mapping
+	if len(path) == 4 && namespaceModes[path[2].Key.GetToken().Value] != nil {
+		path = path[:3]
+	}
+	if len(path) != 3 || path[0].Key.GetToken().Value != "services" {
+		return nil
+	}
+	modes, ok := namespaceModes[path[2].Key.GetToken().Value]
+	if !ok {
+		return nil
+	}
+
+	itemTexts := modes
+	if strings.HasPrefix(prefix, "service:") && slices.ContainsFunc(modes, func(mode completionItemText) bool { return mode.label == "service" }) {
+		itemTexts = []completionItemText{}
+		for _, service := range findDependencies(file, "services") {
+			if service != path[1].Key.GetToken().Value {
+				itemTexts = append(itemTexts, completionItemText{label: "service:" + service, newText: "service:" + service})
+			}
+		}
+	}
+
+	items := []protocol.CompletionItem{}
+	for _, itemText := range itemTexts {
+		item := protocol.CompletionItem{
+			Label:            itemText.label,
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+			TextEdit: protocol.TextEdit{
+				NewText: itemText.newText,
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - protocol.UInteger(len(prefix)),
+					},
+					End: params.Position,
+				},
+			},
+		}
+		if itemText.documentation != "" {
+			item.Documentation = itemText.documentation
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 func namedDependencyCompletionItems(file *ast.File, path []*ast.MappingValueNode, serviceAttribute, dependencyType string, params *protocol.CompletionParams, prefixLength protocol.UInteger) []protocol.CompletionItem {
 	if len(path) == 3 && path[2].Key.GetToken().Value == serviceAttribute {
 		items := []protocol.CompletionItem{}
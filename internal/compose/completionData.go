@@ -0,0 +1,95 @@
+package compose
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+)
+
+// completionDataFiles are the curated lists of values that are
+// suggested by value completion. Every file is a JSON array of entries
+// and the name of the file without its extension names the list.
+//
+//go:embed data/*.json
+var completionDataFiles embed.FS
+
+// completionDataEntry is an entry of an embedded data file. The label
+// is inserted if no text has been given.
+type completionDataEntry struct {
+	Label         string `json:"label"`
+	NewText       string `json:"newText"`
+	Documentation string `json:"documentation"`
+}
+
+var completionDataSets map[string][]completionItemText
+
+func init() {
+	completionDataSets, _ = parseCompletionData(completionDataFiles)
+}
+
+// parseCompletionData parses the data files in the data folder of the
+// given file system into lists that are keyed by the files' names.
+func parseCompletionData(files fs.FS) (map[string][]completionItemText, error) {
+	names, err := fs.Glob(files, "data/*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	dataSets := map[string][]completionItemText{}
+	for _, name := range names {
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+		entries := []completionDataEntry{}
+		if err := json.Unmarshal(content, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse %v: %w", name, err)
+		}
+		itemTexts := []completionItemText{}
+		for _, entry := range entries {
+			newText := entry.NewText
+			if newText == "" {
+				newText = entry.Label
+			}
+			itemTexts = append(itemTexts, completionItemText{label: entry.Label, newText: newText, documentation: entry.Documentation})
+		}
+		dataSets[strings.TrimSuffix(path.Base(name), ".json")] = itemTexts
+	}
+	return dataSets, nil
+}
+
+// completionData returns the values of the named embedded data file.
+func completionData(name string) []completionItemText {
+	return completionDataSets[name]
+}
+
+// dataCompletionItems creates the items for the given values that
+// replace the prefix that has been typed.
+func dataCompletionItems(itemTexts []completionItemText, prefix string, params *protocol.CompletionParams) []protocol.CompletionItem {
+	items := []protocol.CompletionItem{}
+	for _, itemText := range itemTexts {
+		items = append(items, protocol.CompletionItem{
+			Label:            itemText.label,
+			Documentation:    itemText.documentation,
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+			TextEdit: protocol.TextEdit{
+				NewText: itemText.newText,
+				Range: protocol.Range{
+					Start: protocol.Position{
+						Line:      params.Position.Line,
+						Character: params.Position.Character - protocol.UInteger(len(prefix)),
+					},
+					End: params.Position,
+				},
+			},
+		})
+	}
+	return items
+}
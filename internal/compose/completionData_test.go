@@ -0,0 +1,24 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompletionData(t *testing.T) {
+	dataSets, err := parseCompletionData(completionDataFiles)
+	require.NoError(t, err)
+	require.Equal(t, dataSets, completionDataSets)
+
+	for _, name := range []string{"capabilities", "securityLabelOptions", "securityOptions", "signals", "sysctls"} {
+		t.Run(name, func(t *testing.T) {
+			require.NotEmpty(t, dataSets[name])
+			for _, itemText := range dataSets[name] {
+				require.NotEmpty(t, itemText.label)
+				require.NotEmpty(t, itemText.newText)
+				require.NotEmpty(t, itemText.documentation)
+			}
+		})
+	}
+}
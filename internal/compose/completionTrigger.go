@@ -0,0 +1,182 @@
+package compose
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+)
+
+// mappingKeyIndicator matches the content of a line up to the colon
+// that has just been typed after the key of a mapping.
+var mappingKeyIndicator = regexp.MustCompile(`^\s*(-\s+)?[^\s:#'"]+:$`)
+
+// triggerCharacter returns the character that triggered the completion
+// request or an empty string if completion was invoked in another way.
+func triggerCharacter(params *protocol.CompletionParams) string {
+	if params.Context == nil || params.Context.TriggerKind != protocol.CompletionTriggerKindTriggerCharacter || params.Context.TriggerCharacter == nil {
+		return ""
+	}
+	return *params.Context.TriggerCharacter
+}
+
+// triggeredCompletion handles the completion requests whose context is
+// decided by the content that has just been typed. Interpolated
+// variables are completed after a $ regardless of how completion was
+// triggered. A colon after a key or a dash that starts an entry of a
+// sequence completes the value that follows it while a space only
+// triggers completion after them. The returned bool is false if the
+// request should be handled as if completion had been invoked.
+func triggeredCompletion(ctx context.Context, params *protocol.CompletionParams, manager *document.Manager, doc document.ComposeDocument, documentPath document.DocumentPath, imageNames []string) (*protocol.CompletionList, bool) {
+	lines := strings.Split(string(doc.Input()), "\n")
+	if int(params.Position.Line) >= len(lines) || int(params.Position.Character) > len(lines[params.Position.Line]) {
+		return nil, false
+	}
+	line := lines[params.Position.Line]
+	before := line[:params.Position.Character]
+	trigger := triggerCharacter(params)
+	if start, ok := interpolationStart(before); ok {
+		return interpolationCompletion(doc, documentPath, line, start, params), true
+	} else if trigger == "$" {
+		return nil, true
+	}
+
+	switch trigger {
+	case ":":
+		if mappingKeyIndicator.MatchString(before) {
+			return indicatorCompletion(ctx, params, manager, doc, imageNames, lines), true
+		}
+	case "-":
+		if strings.TrimSpace(before) == "-" {
+			return indicatorCompletion(ctx, params, manager, doc, imageNames, lines), true
+		}
+	case " ":
+		if !strings.HasSuffix(before, ": ") && strings.TrimSpace(before) != "-" {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// interpolationStart returns the offset of the $ that starts the
+// variable being typed at the end of the given text. An escaped $$
+// does not start a variable.
+func interpolationStart(text string) (int, bool) {
+	start := strings.LastIndex(text, "$")
+	if start == -1 {
+		return -1, false
+	}
+	name := strings.TrimPrefix(text[start+1:], "{")
+	for i := range len(name) {
+		if !isVariableCharacter(name[i], i == 0) {
+			return -1, false
+		}
+	}
+	dollarSigns := 0
+	for i := start; i >= 0 && text[i] == '$'; i-- {
+		dollarSigns++
+	}
+	return start, dollarSigns%2 == 1
+}
+
+// interpolationCompletion suggests the variables that are declared in
+// the .env file or that are interpolated elsewhere in the Compose file
+// for the variable that starts at the given offset of the line. The
+// rest of the variable's name and the closing brace that a client may
+// have inserted automatically are replaced so that they are not
+// doubled.
+func interpolationCompletion(doc document.ComposeDocument, documentPath document.DocumentPath, line string, start int, params *protocol.CompletionParams) *protocol.CompletionList {
+	file := doc.File()
+	if file == nil {
+		return nil
+	}
+
+	filterPrefix := "$"
+	if strings.HasPrefix(line[start:], "${") {
+		filterPrefix = "${"
+	}
+	end := int(params.Position.Character)
+	for end < len(line) && isVariableCharacter(line[end], end == start+len(filterPrefix)) {
+		end++
+	}
+	// the variable that is being typed is one of the interpolated
+	// variables of the file so it is not suggested for itself
+	typed := line[start+len(filterPrefix) : end]
+	if filterPrefix == "${" && strings.HasPrefix(line[end:], "}") {
+		end++
+	}
+
+	items := []protocol.CompletionItem{}
+	for _, name := range environmentVariableNames(file, documentPath) {
+		if name == typed {
+			continue
+		}
+		items = append(items, protocol.CompletionItem{
+			Label:      name,
+			FilterText: types.CreateStringPointer(filterPrefix + name),
+			TextEdit: protocol.TextEdit{
+				NewText: "${" + name + "}",
+				Range: protocol.Range{
+					Start: protocol.Position{Line: params.Position.Line, Character: protocol.UInteger(start)},
+					End:   protocol.Position{Line: params.Position.Line, Character: protocol.UInteger(end)},
+				},
+			},
+		})
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return processItems(items, false)
+}
+
+// indicatorCompletion completes the value that follows the colon of a
+// mapping's key or the dash of a sequence's entry that has just been
+// typed. The items are computed as if a space had been typed after the
+// indicator and that space is then prepended to the items' text.
+func indicatorCompletion(ctx context.Context, params *protocol.CompletionParams, manager *document.Manager, doc document.ComposeDocument, imageNames []string, lines []string) *protocol.CompletionList {
+	character := params.Position.Character
+	line := lines[params.Position.Line]
+	lines[params.Position.Line] = line[:character] + " " + line[character:]
+	spaced := document.NewComposeDocument(manager, doc.URI(), doc.Version(), []byte(strings.Join(lines, "\n")))
+	list, err := Completion(ctx, &protocol.CompletionParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: params.TextDocument,
+			Position:     protocol.Position{Line: params.Position.Line, Character: character + 1},
+		},
+	}, manager, spaced, imageNames)
+	if err != nil || list == nil {
+		return nil
+	}
+
+	items := []protocol.CompletionItem{}
+	for _, item := range list.Items {
+		var newText string
+		switch edit := item.TextEdit.(type) {
+		case nil:
+			newText = item.Label
+			if item.InsertText != nil {
+				newText = *item.InsertText
+			}
+			item.InsertText = nil
+		case protocol.TextEdit:
+			if edit.Range.Start != edit.Range.End || edit.Range.Start.Line != params.Position.Line || edit.Range.Start.Character != character+1 {
+				continue
+			}
+			newText = edit.NewText
+		default:
+			continue
+		}
+		item.TextEdit = protocol.TextEdit{
+			NewText: " " + newText,
+			Range:   protocol.Range{Start: params.Position, End: params.Position},
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return &protocol.CompletionList{Items: items}
+}
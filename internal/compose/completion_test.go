@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -55,13 +56,31 @@ var topLevelNodes = []protocol.CompletionItem{
 	},
 }
 
+// topLevelNodesWithout returns the top-level node suggestions that
+// remain after the given attributes have been defined.
+func topLevelNodesWithout(attributes ...string) []protocol.CompletionItem {
+	return slices.DeleteFunc(slices.Clone(topLevelNodes), func(item protocol.CompletionItem) bool {
+		return slices.Contains(attributes, item.Label)
+	})
+}
+
 func serviceProperties(line, character, prefixLength protocol.UInteger, spacing string) []protocol.CompletionItem {
 	return []protocol.CompletionItem{
 		{
-			Label:            "annotations",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "annotations (as array)",
+			FilterText:       types.CreateStringPointer("annotations"),
+			Detail:           types.CreateStringPointer("array"),
+			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+			TextEdit:         textEdit(fmt.Sprintf("annotations:\n%v      - ${1:key}=${2:value}", spacing), line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "annotations (as object)",
+			FilterText:       types.CreateStringPointer("annotations"),
+			Detail:           types.CreateStringPointer("object"),
 			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-			TextEdit:         textEdit(fmt.Sprintf("annotations:\n%v      ", spacing), line, character, prefixLength),
+			TextEdit:         textEdit(fmt.Sprintf("annotations:\n%v      ${1:key}: ${2:value}", spacing), line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -312,10 +331,20 @@ func serviceProperties(line, character, prefixLength protocol.UInteger, spacing
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
 		{
-			Label:            "environment",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "environment (as array)",
+			FilterText:       types.CreateStringPointer("environment"),
+			Detail:           types.CreateStringPointer("array"),
+			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+			TextEdit:         textEdit(fmt.Sprintf("environment:\n%v      - ${1:key}=${2:value}", spacing), line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "environment (as object)",
+			FilterText:       types.CreateStringPointer("environment"),
+			Detail:           types.CreateStringPointer("object"),
 			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-			TextEdit:         textEdit(fmt.Sprintf("environment:\n%v      ", spacing), line, character, prefixLength),
+			TextEdit:         textEdit(fmt.Sprintf("environment:\n%v      ${1:key}: ${2:value}", spacing), line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -344,10 +373,20 @@ func serviceProperties(line, character, prefixLength protocol.UInteger, spacing
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
 		{
-			Label:            "extra_hosts",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "extra_hosts (as array)",
+			FilterText:       types.CreateStringPointer("extra_hosts"),
+			Detail:           types.CreateStringPointer("array"),
+			Documentation:    "Additional hostnames to be defined in the container's /etc/hosts file.",
+			TextEdit:         textEdit(fmt.Sprintf("extra_hosts:\n%v      - ${1:key}=${2:value}", spacing), line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "extra_hosts (as object)",
+			FilterText:       types.CreateStringPointer("extra_hosts"),
+			Detail:           types.CreateStringPointer("object"),
 			Documentation:    "Additional hostnames to be defined in the container's /etc/hosts file.",
-			TextEdit:         textEdit(fmt.Sprintf("extra_hosts:\n%v      ", spacing), line, character, prefixLength),
+			TextEdit:         textEdit(fmt.Sprintf("extra_hosts:\n%v      ${1:key}: ${2:value}", spacing), line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -422,10 +461,20 @@ func serviceProperties(line, character, prefixLength protocol.UInteger, spacing
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
 		{
-			Label:            "labels",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "labels (as array)",
+			FilterText:       types.CreateStringPointer("labels"),
+			Detail:           types.CreateStringPointer("array"),
+			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+			TextEdit:         textEdit(fmt.Sprintf("labels:\n%v      - ${1:key}=${2:value}", spacing), line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "labels (as object)",
+			FilterText:       types.CreateStringPointer("labels"),
+			Detail:           types.CreateStringPointer("object"),
 			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-			TextEdit:         textEdit(fmt.Sprintf("labels:\n%v      ", spacing), line, character, prefixLength),
+			TextEdit:         textEdit(fmt.Sprintf("labels:\n%v      ${1:key}: ${2:value}", spacing), line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -680,8 +729,8 @@ func serviceProperties(line, character, prefixLength protocol.UInteger, spacing
 		{
 			Label:            "stop_grace_period",
 			Detail:           types.CreateStringPointer("string"),
-			Documentation:    "Time to wait for the container to stop gracefully before sending SIGKILL (e.g., '1s', '1m30s').",
-			TextEdit:         textEdit("stop_grace_period: ", line, character, prefixLength),
+			Documentation:    "Time to wait for the container to stop gracefully before sending SIGKILL (e.g., '1s', '1m30s').\n\n" + durationUnits,
+			TextEdit:         textEdit("stop_grace_period: ${1:10s}", line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -702,10 +751,20 @@ func serviceProperties(line, character, prefixLength protocol.UInteger, spacing
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
 		{
-			Label:            "sysctls",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "sysctls (as array)",
+			FilterText:       types.CreateStringPointer("sysctls"),
+			Detail:           types.CreateStringPointer("array"),
+			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+			TextEdit:         textEdit(fmt.Sprintf("sysctls:\n%v      - ${1:key}=${2:value}", spacing), line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "sysctls (as object)",
+			FilterText:       types.CreateStringPointer("sysctls"),
+			Detail:           types.CreateStringPointer("object"),
 			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-			TextEdit:         textEdit(fmt.Sprintf("sysctls:\n%v      ", spacing), line, character, prefixLength),
+			TextEdit:         textEdit(fmt.Sprintf("sysctls:\n%v      ${1:key}: ${2:value}", spacing), line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -795,18 +854,38 @@ func serviceProperties(line, character, prefixLength protocol.UInteger, spacing
 func serviceBuildProperties(line, character, prefixLength protocol.UInteger) []protocol.CompletionItem {
 	return []protocol.CompletionItem{
 		{
-			Label:            "additional_contexts",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "additional_contexts (as array)",
+			FilterText:       types.CreateStringPointer("additional_contexts"),
+			Detail:           types.CreateStringPointer("array"),
 			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-			TextEdit:         textEdit("additional_contexts:\n        ", line, character, prefixLength),
+			TextEdit:         textEdit("additional_contexts:\n        - ${1:key}=${2:value}", line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
 		{
-			Label:            "args",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "additional_contexts (as object)",
+			FilterText:       types.CreateStringPointer("additional_contexts"),
+			Detail:           types.CreateStringPointer("object"),
+			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+			TextEdit:         textEdit("additional_contexts:\n        ${1:key}: ${2:value}", line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "args (as array)",
+			FilterText:       types.CreateStringPointer("args"),
+			Detail:           types.CreateStringPointer("array"),
+			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+			TextEdit:         textEdit("args:\n        - ${1:key}=${2:value}", line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "args (as object)",
+			FilterText:       types.CreateStringPointer("args"),
+			Detail:           types.CreateStringPointer("object"),
 			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-			TextEdit:         textEdit("args:\n        ", line, character, prefixLength),
+			TextEdit:         textEdit("args:\n        ${1:key}: ${2:value}", line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -859,10 +938,20 @@ func serviceBuildProperties(line, character, prefixLength protocol.UInteger) []p
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
 		{
-			Label:            "extra_hosts",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "extra_hosts (as array)",
+			FilterText:       types.CreateStringPointer("extra_hosts"),
+			Detail:           types.CreateStringPointer("array"),
+			Documentation:    "Additional hostnames to be defined in the container's /etc/hosts file.",
+			TextEdit:         textEdit("extra_hosts:\n        - ${1:key}=${2:value}", line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "extra_hosts (as object)",
+			FilterText:       types.CreateStringPointer("extra_hosts"),
+			Detail:           types.CreateStringPointer("object"),
 			Documentation:    "Additional hostnames to be defined in the container's /etc/hosts file.",
-			TextEdit:         textEdit("extra_hosts:\n        ", line, character, prefixLength),
+			TextEdit:         textEdit("extra_hosts:\n        ${1:key}: ${2:value}", line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -875,10 +964,20 @@ func serviceBuildProperties(line, character, prefixLength protocol.UInteger) []p
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
 		{
-			Label:            "labels",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "labels (as array)",
+			FilterText:       types.CreateStringPointer("labels"),
+			Detail:           types.CreateStringPointer("array"),
+			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+			TextEdit:         textEdit("labels:\n        - ${1:key}=${2:value}", line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "labels (as object)",
+			FilterText:       types.CreateStringPointer("labels"),
+			Detail:           types.CreateStringPointer("object"),
 			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-			TextEdit:         textEdit("labels:\n        ", line, character, prefixLength),
+			TextEdit:         textEdit("labels:\n        ${1:key}: ${2:value}", line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -955,10 +1054,20 @@ func serviceBuildProperties(line, character, prefixLength protocol.UInteger) []p
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
 		{
-			Label:            "ssh",
-			Detail:           types.CreateStringPointer("array or object"),
+			Label:            "ssh (as array)",
+			FilterText:       types.CreateStringPointer("ssh"),
+			Detail:           types.CreateStringPointer("array"),
+			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+			TextEdit:         textEdit("ssh:\n        - ${1:key}=${2:value}", line, character, prefixLength),
+			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+		},
+		{
+			Label:            "ssh (as object)",
+			FilterText:       types.CreateStringPointer("ssh"),
+			Detail:           types.CreateStringPointer("object"),
 			Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-			TextEdit:         textEdit("ssh:\n        ", line, character, prefixLength),
+			TextEdit:         textEdit("ssh:\n        ${1:key}: ${2:value}", line, character, prefixLength),
 			InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 			InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 		},
@@ -1005,6 +1114,35 @@ configs:
 `,
 			line:      3,
 			character: 0,
+			list: &protocol.CompletionList{
+				Items: topLevelNodesWithout("configs"),
+			},
+		},
+		{
+			name: "top level node suggestions omit the attributes that are already defined",
+			content: `
+services:
+  test:
+    image: alpine
+x-common:
+  key: value
+volumes:
+  data:
+`,
+			line:      8,
+			character: 0,
+			list: &protocol.CompletionList{
+				Items: topLevelNodesWithout("services", "volumes"),
+			},
+		},
+		{
+			name: "top level node suggestions include the attribute on the same line",
+			content: `
+services:
+  test:
+    image: alpine`,
+			line:      1,
+			character: 0,
 			list: &protocol.CompletionList{
 				Items: topLevelNodes,
 			},
@@ -1037,7 +1175,7 @@ configs:
 			line:      3,
 			character: 1,
 			list: &protocol.CompletionList{
-				Items: topLevelNodes,
+				Items: topLevelNodesWithout("configs"),
 			},
 		},
 		{
@@ -1116,7 +1254,7 @@ configs:
 						Label:            "content",
 						Detail:           types.CreateStringPointer("string"),
 						Documentation:    "Inline content of the config.",
-						TextEdit:         textEdit("content: ", 3, 4, 0),
+						TextEdit:         textEdit("content: |\n      $1", 3, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1145,10 +1283,20 @@ configs:
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
 					{
-						Label:            "labels",
-						Detail:           types.CreateStringPointer("array or object"),
+						Label:            "labels (as array)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("labels:\n      - ${1:key}=${2:value}", 3, 4, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "labels (as object)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("object"),
 						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-						TextEdit:         textEdit("labels:\n      ", 3, 4, 0),
+						TextEdit:         textEdit("labels:\n      ${1:key}: ${2:value}", 3, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1246,10 +1394,20 @@ networks:
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
 					{
-						Label:            "labels",
-						Detail:           types.CreateStringPointer("array or object"),
+						Label:            "labels (as array)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("labels:\n      - ${1:key}=${2:value}", 3, 4, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "labels (as object)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("object"),
 						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-						TextEdit:         textEdit("labels:\n      ", 3, 4, 0),
+						TextEdit:         textEdit("labels:\n      ${1:key}: ${2:value}", 3, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1315,10 +1473,20 @@ secrets:
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
 					{
-						Label:            "labels",
-						Detail:           types.CreateStringPointer("array or object"),
+						Label:            "labels (as array)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("labels:\n      - ${1:key}=${2:value}", 3, 4, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "labels (as object)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("object"),
 						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-						TextEdit:         textEdit("labels:\n      ", 3, 4, 0),
+						TextEdit:         textEdit("labels:\n      ${1:key}: ${2:value}", 3, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1412,10 +1580,20 @@ volumes:
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
 					{
-						Label:            "labels",
-						Detail:           types.CreateStringPointer("array or object"),
+						Label:            "labels (as array)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("labels:\n      - ${1:key}=${2:value}", 3, 4, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "labels (as object)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("object"),
 						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-						TextEdit:         textEdit("labels:\n      ", 3, 4, 0),
+						TextEdit:         textEdit("labels:\n      ${1:key}: ${2:value}", 3, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1464,7 +1642,7 @@ services:
 						Label:            "device_read_bps",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Limit read rate (bytes per second) from a device.",
-						TextEdit:         textEdit("device_read_bps:\n        - ", 4, 6, 0),
+						TextEdit:         textEdit("device_read_bps:\n        - path: ${1:/dev/sda}\n          rate: ${2:1mb}", 4, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1472,7 +1650,7 @@ services:
 						Label:            "device_read_iops",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Limit read rate (IO per second) from a device.",
-						TextEdit:         textEdit("device_read_iops:\n        - ", 4, 6, 0),
+						TextEdit:         textEdit("device_read_iops:\n        - path: ${1:/dev/sda}\n          rate: ${2:1000}", 4, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1480,7 +1658,7 @@ services:
 						Label:            "device_write_bps",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Limit write rate (bytes per second) to a device.",
-						TextEdit:         textEdit("device_write_bps:\n        - ", 4, 6, 0),
+						TextEdit:         textEdit("device_write_bps:\n        - path: ${1:/dev/sda}\n          rate: ${2:1mb}", 4, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1488,7 +1666,7 @@ services:
 						Label:            "device_write_iops",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Limit write rate (IO per second) to a device.",
-						TextEdit:         textEdit("device_write_iops:\n        - ", 4, 6, 0),
+						TextEdit:         textEdit("device_write_iops:\n        - path: ${1:/dev/sda}\n          rate: ${2:1000}", 4, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1504,7 +1682,7 @@ services:
 						Label:            "weight_device",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Block IO weight (relative weight) for specific devices.",
-						TextEdit:         textEdit("weight_device:\n        - ", 4, 6, 0),
+						TextEdit:         textEdit("weight_device:\n        - path: ${1:/dev/sda}\n          weight: ${2:500}", 4, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1531,10 +1709,20 @@ services:
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
 					{
-						Label:            "labels",
-						Detail:           types.CreateStringPointer("array or object"),
+						Label:            "labels (as array)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("labels:\n        - ${1:key}=${2:value}", 4, 6, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "labels (as object)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("object"),
 						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-						TextEdit:         textEdit("labels:\n        ", 4, 6, 0),
+						TextEdit:         textEdit("labels:\n        ${1:key}: ${2:value}", 4, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1706,7 +1894,7 @@ services:
 						Label:            "watch",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Configure watch mode for the service, which monitors file changes and performs actions in response.",
-						TextEdit:         textEdit("watch:\n        - action: ${1}\n          path: ${2}", 4, 6, 0),
+						TextEdit:         textEdit("watch:\n        - action: ${1|rebuild,restart,sync,sync+exec,sync+restart|}\n          path: ${2:./}\n          target: ${3:/app}", 4, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1773,7 +1961,7 @@ services:
 						Label:            "device_read_bps",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Limit read rate (bytes per second) from a device.",
-						TextEdit:         textEdit("device_read_bps:\n        - ", 5, 6, 0),
+						TextEdit:         textEdit("device_read_bps:\n        - path: ${1:/dev/sda}\n          rate: ${2:1mb}", 5, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1781,7 +1969,7 @@ services:
 						Label:            "device_read_iops",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Limit read rate (IO per second) from a device.",
-						TextEdit:         textEdit("device_read_iops:\n        - ", 5, 6, 0),
+						TextEdit:         textEdit("device_read_iops:\n        - path: ${1:/dev/sda}\n          rate: ${2:1000}", 5, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1789,7 +1977,7 @@ services:
 						Label:            "device_write_bps",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Limit write rate (bytes per second) to a device.",
-						TextEdit:         textEdit("device_write_bps:\n        - ", 5, 6, 0),
+						TextEdit:         textEdit("device_write_bps:\n        - path: ${1:/dev/sda}\n          rate: ${2:1mb}", 5, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1797,15 +1985,7 @@ services:
 						Label:            "device_write_iops",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Limit write rate (IO per second) to a device.",
-						TextEdit:         textEdit("device_write_iops:\n        - ", 5, 6, 0),
-						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
-						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
-					},
-					{
-						Label:            "weight",
-						Detail:           types.CreateStringPointer("integer or string"),
-						Documentation:    "Block IO weight (relative weight) for the service, between 10 and 1000.",
-						TextEdit:         textEdit("weight: ", 5, 6, 0),
+						TextEdit:         textEdit("device_write_iops:\n        - path: ${1:/dev/sda}\n          rate: ${2:1000}", 5, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1813,7 +1993,7 @@ services:
 						Label:            "weight_device",
 						Detail:           types.CreateStringPointer("array"),
 						Documentation:    "Block IO weight (relative weight) for specific devices.",
-						TextEdit:         textEdit("weight_device:\n        - ", 5, 6, 0),
+						TextEdit:         textEdit("weight_device:\n        - path: ${1:/dev/sda}\n          weight: ${2:500}", 5, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -1928,7 +2108,21 @@ services:
 			line:      4,
 			character: 4,
 			list: &protocol.CompletionList{
-				Items: serviceProperties(4, 4, 0, ""),
+				Items: withoutItems(serviceProperties(4, 4, 0, ""), "image"),
+			},
+		},
+		{
+			name: "attributes already defined before and after the cursor are not suggested",
+			content: `
+services:
+  postgres:
+    image: alpine
+    
+    build: .`,
+			line:      4,
+			character: 4,
+			list: &protocol.CompletionList{
+				Items: withoutItems(serviceProperties(4, 4, 0, ""), "build", "image"),
 			},
 		},
 		{
@@ -1954,7 +2148,7 @@ services:
 			line:      5,
 			character: 4,
 			list: &protocol.CompletionList{
-				Items: serviceProperties(5, 4, 0, ""),
+				Items: withoutItems(serviceProperties(5, 4, 0, ""), "blkio_config"),
 			},
 		},
 		{
@@ -2162,7 +2356,7 @@ services:
 			line:      5,
 			character: 4,
 			list: &protocol.CompletionList{
-				Items: serviceProperties(5, 4, 0, ""),
+				Items: withoutItems(serviceProperties(5, 4, 0, ""), "networks"),
 			},
 		},
 		{
@@ -2189,7 +2383,7 @@ services:
 						Label:            "consistency",
 						Detail:           types.CreateStringPointer("string"),
 						Documentation:    "The consistency requirements for the mount. Available values are platform specific.",
-						TextEdit:         textEdit("consistency: ", 5, 8, 0),
+						TextEdit:         textEdit("consistency: ${1|cached,consistent,delegated|}", 5, 8, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -2277,7 +2471,7 @@ services:
 						Label:            "consistency",
 						Detail:           types.CreateStringPointer("string"),
 						Documentation:    "The consistency requirements for the mount. Available values are platform specific.",
-						TextEdit:         textEdit("consistency: ", 6, 8, 0),
+						TextEdit:         textEdit("consistency: ${1|cached,consistent,delegated|}", 6, 8, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -2366,7 +2560,7 @@ services:
 						Label:            "propagation",
 						Detail:           types.CreateStringPointer("string"),
 						Documentation:    "The propagation mode for the bind mount: 'shared', 'slave', 'private', 'rshared', 'rslave', or 'rprivate'.",
-						TextEdit:         textEdit("propagation: ", 7, 10, 0),
+						TextEdit:         textEdit("propagation: ${1|private,rprivate,rshared,rslave,shared,slave|}", 7, 10, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -2404,10 +2598,20 @@ services:
 			list: &protocol.CompletionList{
 				Items: []protocol.CompletionItem{
 					{
-						Label:            "labels",
-						Detail:           types.CreateStringPointer("array or object"),
+						Label:            "labels (as array)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("labels:\n            - ${1:key}=${2:value}", 7, 10, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "labels (as object)",
+						FilterText:       types.CreateStringPointer("labels"),
+						Detail:           types.CreateStringPointer("object"),
 						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
-						TextEdit:         textEdit("labels:\n            ", 7, 10, 0),
+						TextEdit:         textEdit("labels:\n            ${1:key}: ${2:value}", 7, 10, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -2430,6 +2634,39 @@ services:
 				},
 			},
 		},
+		{
+			name: "properties of a volume array item's tmpfs attributes under a service object",
+			content: `
+services:
+  test:
+    image: alpine
+    volumes:
+      - type: tmpfs
+        tmpfs:
+          `,
+			line:      7,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					{
+						Label:            "mode",
+						Detail:           types.CreateStringPointer("number or string"),
+						Documentation:    "File mode of the tmpfs in octal.",
+						TextEdit:         textEdit("mode: ", 7, 10, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "size",
+						Detail:           types.CreateStringPointer("integer or string"),
+						Documentation:    "Size of the tmpfs mount in bytes.",
+						TextEdit:         textEdit("size: ", 7, 10, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+				},
+			},
+		},
 		{
 			name: "enum properties for a string attribute directly as an item is suggested",
 			content: `
@@ -2530,6 +2767,51 @@ services:
 				},
 			},
 		},
+		{
+			name: "action values of the develop's watch array items",
+			content: `
+services:
+  test:
+    develop:
+      watch:
+        - action: `,
+			line:      5,
+			character: 18,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					{
+						Label:         "rebuild",
+						Documentation: "Action to take when a change is detected: rebuild the container, sync files, restart the container, sync and restart, or sync and execute a command.",
+						Detail:        types.CreateStringPointer("string"),
+						TextEdit:      textEdit("rebuild", 5, 18, 0),
+					},
+					{
+						Label:         "restart",
+						Documentation: "Action to take when a change is detected: rebuild the container, sync files, restart the container, sync and restart, or sync and execute a command.",
+						Detail:        types.CreateStringPointer("string"),
+						TextEdit:      textEdit("restart", 5, 18, 0),
+					},
+					{
+						Label:         "sync",
+						Documentation: "Action to take when a change is detected: rebuild the container, sync files, restart the container, sync and restart, or sync and execute a command.",
+						Detail:        types.CreateStringPointer("string"),
+						TextEdit:      textEdit("sync", 5, 18, 0),
+					},
+					{
+						Label:         "sync+exec",
+						Documentation: "Action to take when a change is detected: rebuild the container, sync files, restart the container, sync and restart, or sync and execute a command.",
+						Detail:        types.CreateStringPointer("string"),
+						TextEdit:      textEdit("sync+exec", 5, 18, 0),
+					},
+					{
+						Label:         "sync+restart",
+						Documentation: "Action to take when a change is detected: rebuild the container, sync files, restart the container, sync and restart, or sync and execute a command.",
+						Detail:        types.CreateStringPointer("string"),
+						TextEdit:      textEdit("sync+restart", 5, 18, 0),
+					},
+				},
+			},
+		},
 		{
 			name: "enum properties for a string attribute is suggested",
 			content: `
@@ -2564,11 +2846,11 @@ services:
 services:
   test:
     volumes:
-      - type: bind
-        bind:
-          propagation:`,
+      - type: volume
+        volume:
+          subpath:`,
 			line:      6,
-			character: 22,
+			character: 18,
 			list:      nil,
 		},
 		{
@@ -2710,47 +2992,178 @@ services:
 			},
 		},
 		{
-			name: "param character is outside document range",
+			name: "properties of a gpus array item",
 			content: `
 services:
   test:
-    `,
-			line:      3,
-			character: 5,
-			list:      nil,
-		},
-		{
-			name:      "param line is outside document range",
-			content:   "",
-			line:      1,
-			character: 0,
-			list:      nil,
-		},
-		{
-			name:      "completion is on a line before the first node and after it positionally",
-			content:   " \nservices:",
-			line:      0,
-			character: 1,
-			list:      nil,
-		},
-		{
-			name:      "completion is on a line before the first node and before it positionally",
-			content:   " \n  services:",
-			line:      0,
-			character: 1,
-			list:      nil,
-		},
-		{
-			name:      "include properties with no array hyphen present",
-			content:   "include:\n  ",
-			line:      1,
-			character: 2,
-			list: &protocol.CompletionList{
-				Items: []protocol.CompletionItem{
+    gpus:
+      - `,
+			line:      4,
+			character: 8,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					{
+						Label:            "capabilities",
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "A list of unique string values.",
+						TextEdit:         textEdit("capabilities:\n          - ", 4, 8, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "count",
+						Detail:           types.CreateStringPointer("integer or string"),
+						Documentation:    "Number of GPUs to use.",
+						TextEdit:         textEdit("count: ", 4, 8, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "device_ids",
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "A list of unique string values.",
+						TextEdit:         textEdit("device_ids:\n          - ", 4, 8, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "driver",
+						Detail:           types.CreateStringPointer("string"),
+						Documentation:    "GPU driver to use (e.g., 'nvidia').",
+						TextEdit:         textEdit("driver: ", 4, 8, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "options (as array)",
+						FilterText:       types.CreateStringPointer("options"),
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("options:\n          - ${1:key}=${2:value}", 4, 8, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "options (as object)",
+						FilterText:       types.CreateStringPointer("options"),
+						Detail:           types.CreateStringPointer("object"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("options:\n          ${1:key}: ${2:value}", 4, 8, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+				},
+			},
+		},
+		{
+			name: "properties of a device reservation array item",
+			content: `
+services:
+  test:
+    deploy:
+      resources:
+        reservations:
+          devices:
+            - `,
+			line:      7,
+			character: 14,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					{
+						Label:            "capabilities",
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "A list of unique string values.",
+						TextEdit:         textEdit("capabilities:\n                - ", 7, 14, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "count",
+						Detail:           types.CreateStringPointer("integer or string"),
+						Documentation:    "Number of devices of this type to reserve.",
+						TextEdit:         textEdit("count: ", 7, 14, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "device_ids",
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "A list of unique string values.",
+						TextEdit:         textEdit("device_ids:\n                - ", 7, 14, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "driver",
+						Detail:           types.CreateStringPointer("string"),
+						Documentation:    "Device driver to use (e.g., 'nvidia').",
+						TextEdit:         textEdit("driver: ", 7, 14, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "options (as array)",
+						FilterText:       types.CreateStringPointer("options"),
+						Detail:           types.CreateStringPointer("array"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("options:\n                - ${1:key}=${2:value}", 7, 14, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+					{
+						Label:            "options (as object)",
+						FilterText:       types.CreateStringPointer("options"),
+						Detail:           types.CreateStringPointer("object"),
+						Documentation:    "Either a dictionary mapping keys to values, or a list of strings.",
+						TextEdit:         textEdit("options:\n                ${1:key}: ${2:value}", 7, 14, 0),
+						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+					},
+				},
+			},
+		},
+		{
+			name: "param character is outside document range",
+			content: `
+services:
+  test:
+    `,
+			line:      3,
+			character: 5,
+			list:      nil,
+		},
+		{
+			name:      "param line is outside document range",
+			content:   "",
+			line:      1,
+			character: 0,
+			list:      nil,
+		},
+		{
+			name:      "completion is on a line before the first node and after it positionally",
+			content:   " \nservices:",
+			line:      0,
+			character: 1,
+			list:      nil,
+		},
+		{
+			name:      "completion is on a line before the first node and before it positionally",
+			content:   " \n  services:",
+			line:      0,
+			character: 1,
+			list:      nil,
+		},
+		{
+			name:      "include properties with no array hyphen present",
+			content:   "include:\n  ",
+			line:      1,
+			character: 2,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
 					{
 						Label:            "env_file",
 						Detail:           types.CreateStringPointer("array or string"),
-						Documentation:    "Either a single string or a list of strings.",
+						Documentation:    "The environment files to use when interpolating variables in the included Compose files. Defaults to the .env file in the project directory of the included files.",
 						TextEdit:         textEdit("- env_file:", 1, 2, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -2758,7 +3171,7 @@ services:
 					{
 						Label:            "path",
 						Detail:           types.CreateStringPointer("array or string"),
-						Documentation:    "Either a single string or a list of strings.",
+						Documentation:    "The Compose files to include. Relative paths are resolved from the directory of this Compose file.",
 						TextEdit:         textEdit("- path:", 1, 2, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -2766,7 +3179,7 @@ services:
 					{
 						Label:            "project_directory",
 						Detail:           types.CreateStringPointer("string"),
-						Documentation:    "Path to resolve relative paths set in the Compose file",
+						Documentation:    "The directory that relative paths in the included Compose files are resolved from. Defaults to the directory of the first included Compose file.",
 						TextEdit:         textEdit("- project_directory: ", 1, 2, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -2784,7 +3197,7 @@ services:
 					{
 						Label:            "env_file",
 						Detail:           types.CreateStringPointer("array or string"),
-						Documentation:    "Either a single string or a list of strings.",
+						Documentation:    "The environment files to use when interpolating variables in the included Compose files. Defaults to the .env file in the project directory of the included files.",
 						TextEdit:         textEdit("env_file:", 1, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -2792,7 +3205,7 @@ services:
 					{
 						Label:            "path",
 						Detail:           types.CreateStringPointer("array or string"),
-						Documentation:    "Either a single string or a list of strings.",
+						Documentation:    "The Compose files to include. Relative paths are resolved from the directory of this Compose file.",
 						TextEdit:         textEdit("path:", 1, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -2800,7 +3213,7 @@ services:
 					{
 						Label:            "project_directory",
 						Detail:           types.CreateStringPointer("string"),
-						Documentation:    "Path to resolve relative paths set in the Compose file",
+						Documentation:    "The directory that relative paths in the included Compose files are resolved from. Defaults to the directory of the first included Compose file.",
 						TextEdit:         textEdit("project_directory: ", 1, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -2818,7 +3231,7 @@ services:
 					{
 						Label:            "env_file",
 						Detail:           types.CreateStringPointer("array or string"),
-						Documentation:    "Either a single string or a list of strings.",
+						Documentation:    "The environment files to use when interpolating variables in the included Compose files. Defaults to the .env file in the project directory of the included files.",
 						TextEdit:         textEdit("env_file:", 1, 5, 1),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -2826,7 +3239,7 @@ services:
 					{
 						Label:            "path",
 						Detail:           types.CreateStringPointer("array or string"),
-						Documentation:    "Either a single string or a list of strings.",
+						Documentation:    "The Compose files to include. Relative paths are resolved from the directory of this Compose file.",
 						TextEdit:         textEdit("path:", 1, 5, 1),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -2834,7 +3247,7 @@ services:
 					{
 						Label:            "project_directory",
 						Detail:           types.CreateStringPointer("string"),
-						Documentation:    "Path to resolve relative paths set in the Compose file",
+						Documentation:    "The directory that relative paths in the included Compose files are resolved from. Defaults to the directory of the first included Compose file.",
 						TextEdit:         textEdit("project_directory: ", 1, 5, 1),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -2867,7 +3280,7 @@ services:
 					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
 					Position:     protocol.Position{Line: tc.line, Character: tc.character},
 				},
-			}, manager, doc)
+			}, manager, doc, nil)
 			require.NoError(t, err)
 			require.Equal(t, tc.list, list)
 		})
@@ -3032,8 +3445,9 @@ services:
 					},
 					{
 						Label:            "service",
-						Detail:           types.CreateStringPointer("string"),
+						Detail:           types.CreateStringPointer("string (required)"),
 						Documentation:    "The name of the service to extend.",
+						Preselect:        types.CreateBoolPointer(true),
 						TextEdit:         textEdit("service: ", 5, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -3065,8 +3479,9 @@ services:
 					},
 					{
 						Label:            "service",
-						Detail:           types.CreateStringPointer("string"),
+						Detail:           types.CreateStringPointer("string (required)"),
 						Documentation:    "The name of the service to extend.",
+						Preselect:        types.CreateBoolPointer(true),
 						TextEdit:         textEdit("service: ${1|test2|}", 5, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -3089,18 +3504,11 @@ services:
 			character: 6,
 			list: &protocol.CompletionList{
 				Items: []protocol.CompletionItem{
-					{
-						Label:            "file",
-						Detail:           types.CreateStringPointer("string"),
-						Documentation:    "The file path where the service to extend is defined.",
-						TextEdit:         textEdit("file: ", 6, 6, 0),
-						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
-						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
-					},
 					{
 						Label:            "service",
-						Detail:           types.CreateStringPointer("string"),
+						Detail:           types.CreateStringPointer("string (required)"),
 						Documentation:    "The name of the service to extend.",
+						Preselect:        types.CreateBoolPointer(true),
 						TextEdit:         textEdit("service: ${1|test2|}", 6, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -3124,24 +3532,59 @@ services:
 			list: &protocol.CompletionList{
 				Items: []protocol.CompletionItem{
 					{
-						Label:            "file",
-						Detail:           types.CreateStringPointer("string"),
-						Documentation:    "The file path where the service to extend is defined.",
-						TextEdit:         textEdit("file: ", 6, 6, 0),
+						Label:            "service",
+						Detail:           types.CreateStringPointer("string (required)"),
+						Documentation:    "The name of the service to extend.",
+						Preselect:        types.CreateBoolPointer(true),
+						TextEdit:         textEdit("service: ", 6, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
+				},
+			},
+		},
+		{
+			name: "extends object attributes with the service already set",
+			content: `
+services:
+  test:
+    image: alpine
+    extends:
+      service: test2
+      
+  test2:
+    image: alpine`,
+			line:      6,
+			character: 6,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
 					{
-						Label:            "service",
+						Label:            "file",
 						Detail:           types.CreateStringPointer("string"),
-						Documentation:    "The name of the service to extend.",
-						TextEdit:         textEdit("service: ", 6, 6, 0),
+						Documentation:    "The file path where the service to extend is defined.",
+						TextEdit:         textEdit("file: ", 6, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
 				},
 			},
 		},
+		{
+			name: "extends object attributes with both attributes set",
+			content: `
+services:
+  test:
+    image: alpine
+    extends:
+      service: test2
+      file: compose.yaml
+      
+  test2:
+    image: alpine`,
+			line:      7,
+			character: 6,
+			list:      nil,
+		},
 		{
 			name: "extends' service attribute with a file pointing somewhere else",
 			content: `
@@ -3270,7 +3713,7 @@ volumes:
 						Label:            "consistency",
 						Detail:           types.CreateStringPointer("string"),
 						Documentation:    "The consistency requirements for the mount. Available values are platform specific.",
-						TextEdit:         textEdit("consistency: ", 5, 8, 0),
+						TextEdit:         textEdit("consistency: ${1|cached,consistent,delegated|}", 5, 8, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -3366,7 +3809,7 @@ volumes:
 						Label:            "consistency",
 						Detail:           types.CreateStringPointer("string"),
 						Documentation:    "The consistency requirements for the mount. Available values are platform specific.",
-						TextEdit:         textEdit("consistency: ", 6, 8, 0),
+						TextEdit:         textEdit("consistency: ${1|cached,consistent,delegated|}", 6, 8, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -3460,7 +3903,7 @@ volumes:
 						Label:            "consistency",
 						Detail:           types.CreateStringPointer("string"),
 						Documentation:    "The consistency requirements for the mount. Available values are platform specific.",
-						TextEdit:         textEdit("consistency: ", 5, 9, 1),
+						TextEdit:         textEdit("consistency: ${1|cached,consistent,delegated|}", 5, 9, 1),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -3554,7 +3997,7 @@ volumes:
 						Label:            "consistency",
 						Detail:           types.CreateStringPointer("string"),
 						Documentation:    "The consistency requirements for the mount. Available values are platform specific.",
-						TextEdit:         textEdit("- consistency: ", 5, 6, 0),
+						TextEdit:         textEdit("- consistency: ${1|cached,consistent,delegated|}", 5, 6, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
 					},
@@ -4033,7 +4476,7 @@ models:
 					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
 					Position:     protocol.Position{Line: tc.line, Character: tc.character},
 				},
-			}, nil, doc)
+			}, nil, doc, nil)
 			require.NoError(t, err)
 			require.Equal(t, tc.list, list)
 		})
@@ -4364,7 +4807,7 @@ services:
 			line:      5,
 			character: 6,
 			list: func() *protocol.CompletionList {
-				items := serviceBuildProperties(5, 6, 0)
+				items := withoutItems(serviceBuildProperties(5, 6, 0), "dockerfile")
 				for i := range items {
 					if items[i].Label == "target" {
 						items[i].TextEdit = textEdit("target: ${1|bstage,astage|}", 5, 6, 0)
@@ -4412,7 +4855,7 @@ services:
 					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
 					Position:     protocol.Position{Line: tc.line, Character: tc.character},
 				},
-			}, manager, doc)
+			}, manager, doc, nil)
 			require.NoError(t, err)
 			require.Equal(t, tc.list(), list)
 		})
@@ -4505,7 +4948,7 @@ services:
 			line:      5,
 			character: 6,
 			list: func() *protocol.CompletionList {
-				items := serviceBuildProperties(5, 6, 0)
+				items := withoutItems(serviceBuildProperties(5, 6, 0), "dockerfile")
 				for i := range items {
 					if items[i].Label == "target" {
 						items[i].TextEdit = textEdit("target: ${1|bstage,astage|}", 5, 6, 0)
@@ -4534,7 +4977,7 @@ services:
 					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
 					Position:     protocol.Position{Line: tc.line, Character: tc.character},
 				},
-			}, manager, doc)
+			}, manager, doc, nil)
 			require.NoError(t, err)
 			require.Equal(t, tc.list(), list)
 		})
@@ -4579,6 +5022,88 @@ services:
 				},
 			},
 		},
+		{
+			name: "options of a model provider",
+			content: `
+services:
+  custom:
+    provider:
+      type: model
+      options:
+        `,
+			line:      6,
+			character: 8,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					providerOptionItem("context-size", "The maximum number of tokens that the model can process.", "context-size: ", 6, 8, 0),
+					providerOptionItem("model", "The name of the model to run such as `ai/smollm2`.", "model: ${1:ai/example-model}", 6, 8, 0),
+					providerOptionItem("runtime-flags", "Raw command-line flags to pass to the inference engine.", "runtime-flags: ", 6, 8, 0),
+				},
+			},
+		},
+		{
+			name: "options of a model provider with a prefix",
+			content: `
+services:
+  custom:
+    provider:
+      type: model
+      options:
+        co`,
+			line:      6,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					providerOptionItem("context-size", "The maximum number of tokens that the model can process.", "context-size: ", 6, 10, 2),
+					providerOptionItem("model", "The name of the model to run such as `ai/smollm2`.", "model: ${1:ai/example-model}", 6, 10, 2),
+					providerOptionItem("runtime-flags", "Raw command-line flags to pass to the inference engine.", "runtime-flags: ", 6, 10, 2),
+				},
+			},
+		},
+		{
+			name: "options of a model provider excludes the ones already set",
+			content: `
+services:
+  custom:
+    provider:
+      type: model
+      options:
+        model: ai/smollm2
+        `,
+			line:      7,
+			character: 8,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					providerOptionItem("context-size", "The maximum number of tokens that the model can process.", "context-size: ", 7, 8, 0),
+					providerOptionItem("runtime-flags", "Raw command-line flags to pass to the inference engine.", "runtime-flags: ", 7, 8, 0),
+				},
+			},
+		},
+		{
+			name: "options of a provider with an unknown type",
+			content: `
+services:
+  custom:
+    provider:
+      type: custom-provider
+      options:
+        `,
+			line:      6,
+			character: 8,
+			list:      nil,
+		},
+		{
+			name: "options of a provider without a type",
+			content: `
+services:
+  custom:
+    provider:
+      options:
+        `,
+			line:      5,
+			character: 8,
+			list:      nil,
+		},
 	}
 
 	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
@@ -4592,21 +5117,1736 @@ services:
 					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
 					Position:     protocol.Position{Line: tc.line, Character: tc.character},
 				},
-			}, manager, doc)
+			}, manager, doc, nil)
 			require.NoError(t, err)
 			require.Equal(t, tc.list, list)
 		})
 	}
 }
 
-func TestCompletion_NoResultExpected(t *testing.T) {
-	dir, err := os.MkdirTemp(os.TempDir(), fmt.Sprintf("%v-%v", t.Name(), time.Now().UnixMilli()))
-	require.NoError(t, err)
-	t.Cleanup(func() {
-		require.NoError(t, os.RemoveAll(dir))
-	})
-
-	fileStructure := []struct {
+func securityOptItems(line, character, prefixLength protocol.UInteger, newTextPrefix string) []protocol.CompletionItem {
+	return []protocol.CompletionItem{
+		providerOptionItem("apparmor", "The AppArmor profile to apply to the container. Use `unconfined` to run the container without the default AppArmor profile.", newTextPrefix+"apparmor:${1:profile}", line, character, prefixLength),
+		providerOptionItem("label", "Sets an SELinux label on the container.", newTextPrefix+"label:", line, character, prefixLength),
+		providerOptionItem("no-new-privileges", "Prevents the processes of the container from gaining additional privileges.", newTextPrefix+"no-new-privileges:${1|true,false|}", line, character, prefixLength),
+		providerOptionItem("seccomp", "The seccomp profile to apply to the container. Use `unconfined` to run the container without the default seccomp profile.", newTextPrefix+"seccomp:${1:profile.json}", line, character, prefixLength),
+	}
+}
+
+func securityLabelItems(line, character, prefixLength protocol.UInteger) []protocol.CompletionItem {
+	return []protocol.CompletionItem{
+		providerOptionItem("disable", "Turns off SELinux labeling for the container.", "disable", line, character, prefixLength),
+		providerOptionItem("level", "The SELinux level of the container's label.", "level:${1:LEVEL}", line, character, prefixLength),
+		providerOptionItem("role", "The SELinux role of the container's label.", "role:${1:ROLE}", line, character, prefixLength),
+		providerOptionItem("type", "The SELinux type of the container's label.", "type:${1:TYPE}", line, character, prefixLength),
+		providerOptionItem("user", "The SELinux user of the container's label.", "user:${1:USER}", line, character, prefixLength),
+	}
+}
+
+func TestCompletion_SecurityOpt(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "security_opt entry",
+			content: `
+services:
+  web:
+    security_opt:
+      - `,
+			line:      4,
+			character: 8,
+			list:      &protocol.CompletionList{Items: securityOptItems(4, 8, 0, "")},
+		},
+		{
+			name: "security_opt entry without a hyphen",
+			content: `
+services:
+  web:
+    security_opt:
+      `,
+			line:      4,
+			character: 6,
+			list:      &protocol.CompletionList{Items: securityOptItems(4, 6, 0, "- ")},
+		},
+		{
+			name: "security_opt entry with a prefix",
+			content: `
+services:
+  web:
+    security_opt:
+      - sec`,
+			line:      4,
+			character: 11,
+			list:      &protocol.CompletionList{Items: securityOptItems(4, 11, 3, "")},
+		},
+		{
+			name: "parts of a label",
+			content: `
+services:
+  web:
+    security_opt:
+      - label:`,
+			line:      4,
+			character: 14,
+			list:      &protocol.CompletionList{Items: securityLabelItems(4, 14, 0)},
+		},
+		{
+			name: "parts of a label with a prefix",
+			content: `
+services:
+  web:
+    security_opt:
+      - label=ty`,
+			line:      4,
+			character: 16,
+			list:      &protocol.CompletionList{Items: securityLabelItems(4, 16, 2)},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_Devices(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "devices entry",
+			content: `
+services:
+  web:
+    devices:
+      - `,
+			line:      4,
+			character: 8,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					providerOptionItem("HOST_PATH:CONTAINER_PATH", "Maps a device of the host to a path in the container.", "${1:/dev/host}:${2:/dev/container}", 4, 8, 0),
+					providerOptionItem("HOST_PATH:CONTAINER_PATH:PERMISSIONS", "Maps a device of the host to a path in the container with the given cgroup permissions.", "${1:/dev/host}:${2:/dev/container}:${3|rwm,rw,r|}", 4, 8, 0),
+					schemaItem("permissions", "string", "Cgroup permissions for the device (rwm).", "permissions: ", 4, 8, 0),
+					schemaItem("source", "string", "Path on the host to the device.", "source: ", 4, 8, 0),
+					schemaItem("target", "string", "Path in the container where the device will be mapped.", "target: ", 4, 8, 0),
+				},
+			},
+		},
+		{
+			name: "device_cgroup_rules entry with a prefix",
+			content: `
+services:
+  web:
+    device_cgroup_rules:
+      - c`,
+			line:      4,
+			character: 9,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					providerOptionItem("TYPE MAJOR:MINOR PERMISSIONS", "Allows the container to access the character (c), block (b), or all (a) devices with the given major and minor numbers. A * matches any number.", "${1|c,b,a|} ${2:*}:${3:*} ${4|rwm,rw,r,w,m|}", 4, 9, 1),
+				},
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_Entitlements(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "entitlements entry",
+			content: `
+services:
+  web:
+    build:
+      entitlements:
+        - `,
+			line:      5,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					providerOptionItem("network.host", entitlements[0].documentation, "network.host", 5, 10, 0),
+					providerOptionItem("security.insecure", entitlements[1].documentation, "security.insecure", 5, 10, 0),
+				},
+			},
+		},
+		{
+			name: "entitlements entry with a prefix",
+			content: `
+services:
+  web:
+    build:
+      context: .
+      entitlements:
+        - sec`,
+			line:      6,
+			character: 13,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					providerOptionItem("network.host", entitlements[0].documentation, "network.host", 6, 13, 3),
+					providerOptionItem("security.insecure", entitlements[1].documentation, "security.insecure", 6, 13, 3),
+				},
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func exposeItems(port string, line, character, prefixLength protocol.UInteger) []protocol.CompletionItem {
+	items := []protocol.CompletionItem{}
+	for _, exposeProtocol := range exposeProtocols {
+		label := fmt.Sprintf("%v/%v", port, exposeProtocol.label)
+		items = append(items, providerOptionItem(label, exposeProtocol.documentation, label, line, character, prefixLength))
+	}
+	slices.SortFunc(items, func(a, b protocol.CompletionItem) int {
+		return strings.Compare(a.Label, b.Label)
+	})
+	return items
+}
+
+func TestCompletion_ExposeProtocols(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "integer port",
+			content: `
+services:
+  web:
+    expose:
+      - 80`,
+			line:      4,
+			character: 10,
+			list:      &protocol.CompletionList{Items: exposeItems("80", 4, 10, 2)},
+		},
+		{
+			name: "port followed by a slash",
+			content: `
+services:
+  web:
+    expose:
+      - 80/`,
+			line:      4,
+			character: 11,
+			list:      &protocol.CompletionList{Items: exposeItems("80", 4, 11, 3)},
+		},
+		{
+			name: "string port range with a partial protocol",
+			content: `
+services:
+  web:
+    expose:
+      - "8000-8010/u"`,
+			line:      4,
+			character: 20,
+			list:      &protocol.CompletionList{Items: exposeItems("8000-8010", 4, 20, 11)},
+		},
+		{
+			name: "flow sequence",
+			content: `
+services:
+  web:
+    expose: [80, 443]`,
+			line:      3,
+			character: 20,
+			list:      &protocol.CompletionList{Items: exposeItems("443", 3, 20, 3)},
+		},
+		{
+			name: "interpolated port",
+			content: `
+services:
+  web:
+    expose:
+      - ${PORT}`,
+			line:      4,
+			character: 15,
+			list:      nil,
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_Healthcheck(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "healthcheck attributes without the test that has been set",
+			content: `
+services:
+  web:
+    healthcheck:
+      test: ["CMD", "true"]
+      `,
+			line:      5,
+			character: 6,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("disable", "boolean or string", "Disable any container-specified healthcheck. Set to true to disable.", "disable: ${1|true,false|}", 5, 6, 0),
+					schemaItem("interval", "string", "Time between running the check (e.g., '1s', '1m30s'). Default: 30s.\n\n"+durationUnits, "interval: ${1:30s}", 5, 6, 0),
+					schemaItem("retries", "number or string", "Number of consecutive failures needed to consider the container as unhealthy. Default: 3.", "retries: ${1:3}", 5, 6, 0),
+					schemaItem("start_interval", "string", "Time between running the check during the start period (e.g., '1s', '1m30s'). Default: interval value.\n\n"+durationUnits, "start_interval: ${1:5s}", 5, 6, 0),
+					schemaItem("start_period", "string", "Start period for the container to initialize before starting health-retries countdown (e.g., '1s', '1m30s'). Default: 0s.\n\n"+durationUnits, "start_period: ${1:0s}", 5, 6, 0),
+					schemaItem("timeout", "string", "Maximum time to allow one check to run (e.g., '1s', '1m30s'). Default: 30s.\n\n"+durationUnits, "timeout: ${1:30s}", 5, 6, 0),
+				},
+			},
+		},
+		{
+			name: "healthcheck attributes with a prefix",
+			content: `
+services:
+  web:
+    healthcheck:
+      start_`,
+			line:      4,
+			character: 12,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("disable", "boolean or string", "Disable any container-specified healthcheck. Set to true to disable.", "disable: ${1|true,false|}", 4, 12, 6),
+					schemaItem("interval", "string", "Time between running the check (e.g., '1s', '1m30s'). Default: 30s.\n\n"+durationUnits, "interval: ${1:30s}", 4, 12, 6),
+					schemaItem("retries", "number or string", "Number of consecutive failures needed to consider the container as unhealthy. Default: 3.", "retries: ${1:3}", 4, 12, 6),
+					schemaItem("start_interval", "string", "Time between running the check during the start period (e.g., '1s', '1m30s'). Default: interval value.\n\n"+durationUnits, "start_interval: ${1:5s}", 4, 12, 6),
+					schemaItem("start_period", "string", "Start period for the container to initialize before starting health-retries countdown (e.g., '1s', '1m30s'). Default: 0s.\n\n"+durationUnits, "start_period: ${1:0s}", 4, 12, 6),
+					schemaItem("test", "array or string", "The test to perform to check container health. Can be a string or a list. The first item is either NONE, CMD, or CMD-SHELL. If it's CMD, the rest of the command is exec'd. If it's CMD-SHELL, the rest is run in the shell.", `test: ["CMD-SHELL", "${1:curl -f http://localhost/ || exit 1}"]`, 4, 12, 6),
+					schemaItem("timeout", "string", "Maximum time to allow one check to run (e.g., '1s', '1m30s'). Default: 30s.\n\n"+durationUnits, "timeout: ${1:30s}", 4, 12, 6),
+				},
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_Durations(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "healthcheck attributes",
+			content: `
+services:
+  web:
+    healthcheck:
+      `,
+			line:      4,
+			character: 6,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("disable", "boolean or string", "Disable any container-specified healthcheck. Set to true to disable.", "disable: ${1|true,false|}", 4, 6, 0),
+					schemaItem("interval", "string", "Time between running the check (e.g., '1s', '1m30s'). Default: 30s.\n\n"+durationUnits, "interval: ${1:30s}", 4, 6, 0),
+					schemaItem("retries", "number or string", "Number of consecutive failures needed to consider the container as unhealthy. Default: 3.", "retries: ${1:3}", 4, 6, 0),
+					schemaItem("start_interval", "string", "Time between running the check during the start period (e.g., '1s', '1m30s'). Default: interval value.\n\n"+durationUnits, "start_interval: ${1:5s}", 4, 6, 0),
+					schemaItem("start_period", "string", "Start period for the container to initialize before starting health-retries countdown (e.g., '1s', '1m30s'). Default: 0s.\n\n"+durationUnits, "start_period: ${1:0s}", 4, 6, 0),
+					schemaItem("test", "array or string", "The test to perform to check container health. Can be a string or a list. The first item is either NONE, CMD, or CMD-SHELL. If it's CMD, the rest of the command is exec'd. If it's CMD-SHELL, the rest is run in the shell.", `test: ["CMD-SHELL", "${1:curl -f http://localhost/ || exit 1}"]`, 4, 6, 0),
+					schemaItem("timeout", "string", "Maximum time to allow one check to run (e.g., '1s', '1m30s'). Default: 30s.\n\n"+durationUnits, "timeout: ${1:30s}", 4, 6, 0),
+				},
+			},
+		},
+		{
+			name: "restart_policy attributes with a prefix",
+			content: `
+services:
+  web:
+    deploy:
+      restart_policy:
+        de`,
+			line:      5,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("condition", "string", "Condition for restarting the container: 'none', 'on-failure', 'any'.", "condition: ", 5, 10, 2),
+					schemaItem("delay", "string", "Delay between restart attempts (e.g., '1s', '1m30s').\n\n"+durationUnits, "delay: ${1:5s}", 5, 10, 2),
+					schemaItem("max_attempts", "integer or string", "Maximum number of restart attempts before giving up.", "max_attempts: ", 5, 10, 2),
+					schemaItem("window", "string", "Time window used to evaluate the restart policy (e.g., '1s', '1m30s').\n\n"+durationUnits, "window: ${1:0s}", 5, 10, 2),
+				},
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func dataItems(name string, line, character, prefixLength protocol.UInteger) []protocol.CompletionItem {
+	items := []protocol.CompletionItem{}
+	for _, itemText := range completionData(name) {
+		items = append(items, providerOptionItem(itemText.label, itemText.documentation, itemText.newText, line, character, prefixLength))
+	}
+	return items
+}
+
+func TestCompletion_DataValues(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "cap_add entry",
+			content: `
+services:
+  web:
+    cap_add:
+      - NET`,
+			line:      4,
+			character: 11,
+			list:      &protocol.CompletionList{Items: dataItems("capabilities", 4, 11, 3)},
+		},
+		{
+			name: "cap_drop entry",
+			content: `
+services:
+  web:
+    cap_drop:
+      - `,
+			line:      4,
+			character: 8,
+			list:      &protocol.CompletionList{Items: dataItems("capabilities", 4, 8, 0)},
+		},
+		{
+			name: "sysctls list entry",
+			content: `
+services:
+  web:
+    sysctls:
+      - net.`,
+			line:      4,
+			character: 12,
+			list:      &protocol.CompletionList{Items: dataItems("sysctls", 4, 12, 4)},
+		},
+		{
+			name: "sysctls mapping key",
+			content: `
+services:
+  web:
+    sysctls:
+      net.`,
+			line:      4,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					providerOptionItem("fs.mqueue.msg_max", "The maximum number of messages in a POSIX message queue.", "fs.mqueue.msg_max: ${1:10}", 4, 10, 4),
+					providerOptionItem("fs.mqueue.queues_max", "The maximum number of POSIX message queues.", "fs.mqueue.queues_max: ${1:256}", 4, 10, 4),
+					providerOptionItem("kernel.msgmax", "The maximum size in bytes of a System V message.", "kernel.msgmax: ${1:8192}", 4, 10, 4),
+					providerOptionItem("kernel.msgmnb", "The maximum number of bytes in a System V message queue.", "kernel.msgmnb: ${1:16384}", 4, 10, 4),
+					providerOptionItem("kernel.msgmni", "The maximum number of System V message queues.", "kernel.msgmni: ${1:32000}", 4, 10, 4),
+					providerOptionItem("kernel.sem", "The limits of System V semaphores: the semaphores per set, the semaphores in total, the operations per call, and the number of sets.", "kernel.sem: ${1:250 32000 32 128}", 4, 10, 4),
+					providerOptionItem("kernel.shm_rmid_forced", "Whether System V shared memory segments are destroyed when no process is attached to them anymore.", "kernel.shm_rmid_forced: ${1:0}", 4, 10, 4),
+					providerOptionItem("kernel.shmall", "The maximum number of pages of System V shared memory.", "kernel.shmall: ${1:18446744073692774399}", 4, 10, 4),
+					providerOptionItem("kernel.shmmax", "The maximum size in bytes of a System V shared memory segment.", "kernel.shmmax: ${1:18446744073692774399}", 4, 10, 4),
+					providerOptionItem("kernel.shmmni", "The maximum number of System V shared memory segments.", "kernel.shmmni: ${1:4096}", 4, 10, 4),
+					providerOptionItem("net.core.somaxconn", "The maximum length of the queue of pending connections of a listening socket.", "net.core.somaxconn: ${1:1024}", 4, 10, 4),
+					providerOptionItem("net.ipv4.ip_forward", "Whether IPv4 packets are forwarded between interfaces.", "net.ipv4.ip_forward: ${1:1}", 4, 10, 4),
+					providerOptionItem("net.ipv4.ip_local_port_range", "The range of ports that are used for outgoing connections.", "net.ipv4.ip_local_port_range: ${1:32768 60999}", 4, 10, 4),
+					providerOptionItem("net.ipv4.ip_unprivileged_port_start", "The first port that can be bound without the NET_BIND_SERVICE capability.", "net.ipv4.ip_unprivileged_port_start: ${1:0}", 4, 10, 4),
+					providerOptionItem("net.ipv4.ping_group_range", "The range of groups that are allowed to create ICMP echo sockets.", "net.ipv4.ping_group_range: ${1:0 2147483647}", 4, 10, 4),
+					providerOptionItem("net.ipv4.tcp_keepalive_time", "The seconds that a TCP connection is idle before keepalive probes are sent.", "net.ipv4.tcp_keepalive_time: ${1:7200}", 4, 10, 4),
+					providerOptionItem("net.ipv4.tcp_syncookies", "Whether TCP SYN cookies are sent when the queue of a socket overflows.", "net.ipv4.tcp_syncookies: ${1:1}", 4, 10, 4),
+					providerOptionItem("net.ipv6.conf.all.disable_ipv6", "Whether IPv6 is disabled on all interfaces.", "net.ipv6.conf.all.disable_ipv6: ${1:1}", 4, 10, 4),
+				},
+			},
+		},
+		{
+			name: "sysctls value is not completed",
+			content: `
+services:
+  web:
+    sysctls:
+      - net.core.somaxconn=`,
+			line:      4,
+			character: 27,
+			list:      nil,
+		},
+		{
+			name: "stop_signal",
+			content: `
+services:
+  web:
+    stop_signal: SIG`,
+			line:      3,
+			character: 20,
+			list:      &protocol.CompletionList{Items: dataItems("signals", 3, 20, 3)},
+		},
+		{
+			name: "gpus capabilities entry",
+			content: `
+services:
+  web:
+    gpus:
+      - driver: nvidia
+        capabilities:
+          - gr`,
+			line:      6,
+			character: 14,
+			list:      &protocol.CompletionList{Items: dataItems("deviceCapabilities", 6, 14, 2)},
+		},
+		{
+			name: "deploy device reservation capabilities entry",
+			content: `
+services:
+  web:
+    deploy:
+      resources:
+        reservations:
+          devices:
+            - capabilities:
+                - `,
+			line:      8,
+			character: 18,
+			list:      &protocol.CompletionList{Items: dataItems("deviceCapabilities", 8, 18, 0)},
+		},
+		{
+			name: "placement constraints entry",
+			content: `
+services:
+  web:
+    deploy:
+      placement:
+        constraints:
+          - `,
+			line:      6,
+			character: 12,
+			list:      &protocol.CompletionList{Items: dataItems("placementConstraints", 6, 12, 0)},
+		},
+		{
+			name: "placement constraints entry with a prefix",
+			content: `
+services:
+  web:
+    deploy:
+      placement:
+        constraints:
+          - node.`,
+			line:      6,
+			character: 17,
+			list:      &protocol.CompletionList{Items: dataItems("placementConstraints", 6, 17, 5)},
+		},
+		{
+			name: "placement preferences spread",
+			content: `
+services:
+  web:
+    deploy:
+      placement:
+        preferences:
+          - spread: `,
+			line:      6,
+			character: 20,
+			list:      &protocol.CompletionList{Items: dataItems("placementPreferences", 6, 20, 0)},
+		},
+		{
+			name: "bind propagation of a long syntax volume",
+			content: `
+services:
+  web:
+    volumes:
+      - type: bind
+        bind:
+          propagation: `,
+			line:      6,
+			character: 23,
+			list:      &protocol.CompletionList{Items: dataItems("bindPropagation", 6, 23, 0)},
+		},
+		{
+			name: "bind propagation of a long syntax volume with a prefix",
+			content: `
+services:
+  web:
+    volumes:
+      - type: bind
+        bind:
+          propagation: rs`,
+			line:      6,
+			character: 25,
+			list:      &protocol.CompletionList{Items: dataItems("bindPropagation", 6, 25, 2)},
+		},
+		{
+			name: "consistency of a long syntax volume",
+			content: `
+services:
+  web:
+    volumes:
+      - type: bind
+        consistency: `,
+			line:      5,
+			character: 21,
+			list:      &protocol.CompletionList{Items: dataItems("volumeConsistency", 5, 21, 0)},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func imageItems(line, character, prefixLength protocol.UInteger, configured ...protocol.CompletionItem) []protocol.CompletionItem {
+	items := []protocol.CompletionItem{}
+	for _, image := range commonImages {
+		items = append(items, providerOptionItem(image.label, image.documentation, image.label+":${1:latest}", line, character, prefixLength))
+	}
+	items = append(items, configured...)
+	slices.SortFunc(items, func(a, b protocol.CompletionItem) int {
+		return strings.Compare(a.Label, b.Label)
+	})
+	return items
+}
+
+func configuredImageItem(label, newText string, line, character, prefixLength protocol.UInteger) protocol.CompletionItem {
+	return protocol.CompletionItem{
+		Label:            label,
+		TextEdit:         textEdit(newText, line, character, prefixLength),
+		InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+		InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+	}
+}
+
+func TestCompletion_Images(t *testing.T) {
+	testCases := []struct {
+		name       string
+		content    string
+		line       uint32
+		character  uint32
+		imageNames []string
+		list       *protocol.CompletionList
+	}{
+		{
+			name: "empty image value",
+			content: `
+services:
+  web:
+    image: `,
+			line:      3,
+			character: 11,
+			list:      &protocol.CompletionList{Items: imageItems(3, 11, 0)},
+		},
+		{
+			name: "partial image name",
+			content: `
+services:
+  web:
+    image: ngi`,
+			line:      3,
+			character: 14,
+			list:      &protocol.CompletionList{Items: imageItems(3, 14, 3)},
+		},
+		{
+			name: "partial image name in quotes",
+			content: `
+services:
+  web:
+    image: "ngi"`,
+			line:      3,
+			character: 15,
+			list:      &protocol.CompletionList{Items: imageItems(3, 15, 3)},
+		},
+		{
+			name: "configured image names are appended",
+			content: `
+services:
+  web:
+    image: `,
+			line:       3,
+			character:  11,
+			imageNames: []string{"ghcr.io/example/app", "nginx", "registry.example.com/base:1.0"},
+			list: &protocol.CompletionList{
+				Items: imageItems(
+					3, 11, 0,
+					configuredImageItem("ghcr.io/example/app", "ghcr.io/example/app:${1:latest}", 3, 11, 0),
+					configuredImageItem("registry.example.com/base:1.0", "registry.example.com/base:1.0", 3, 11, 0),
+				),
+			},
+		},
+		{
+			name: "tag has been started",
+			content: `
+services:
+  web:
+    image: nginx:1`,
+			line:      3,
+			character: 17,
+			list:      nil,
+		},
+		{
+			name: "interpolated image",
+			content: `
+services:
+  web:
+    image: ${IMAGE`,
+			line:      3,
+			character: 17,
+			list:      nil,
+		},
+		{
+			name: "image of a service that is also built",
+			content: `
+services:
+  web:
+    build:
+      context: .
+    image: `,
+			line:      5,
+			character: 11,
+			list:      &protocol.CompletionList{Items: imageItems(5, 11, 0)},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, tc.imageNames)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func networkModeItems(line, character, prefixLength protocol.UInteger) []protocol.CompletionItem {
+	return []protocol.CompletionItem{
+		providerOptionItem("bridge", "Connects the container to the default bridge network.", "bridge", line, character, prefixLength),
+		providerOptionItem("container", "Uses the network stack of another container.", "container:${1:name}", line, character, prefixLength),
+		providerOptionItem("host", "Uses the network stack of the host.", "host", line, character, prefixLength),
+		providerOptionItem("none", "Disables all container networking.", "none", line, character, prefixLength),
+		providerOptionItem("service", "Uses the network stack of another service's container.", "service:${1:name}", line, character, prefixLength),
+	}
+}
+
+func namespaceModeItems(attribute string, line, character, prefixLength protocol.UInteger) []protocol.CompletionItem {
+	items := []protocol.CompletionItem{}
+	for _, mode := range namespaceModes[attribute] {
+		items = append(items, providerOptionItem(mode.label, mode.documentation, mode.newText, line, character, prefixLength))
+	}
+	return items
+}
+
+func networkModeServiceItem(service string, line, character, prefixLength protocol.UInteger) protocol.CompletionItem {
+	return protocol.CompletionItem{
+		Label:            "service:" + service,
+		TextEdit:         textEdit("service:"+service, line, character, prefixLength),
+		InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+		InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+	}
+}
+
+func TestCompletion_NamespaceModes(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "network_mode value",
+			content: `
+services:
+  web:
+    network_mode: `,
+			line:      3,
+			character: 18,
+			list:      &protocol.CompletionList{Items: networkModeItems(3, 18, 0)},
+		},
+		{
+			name: "network_mode value with a prefix",
+			content: `
+services:
+  web:
+    network_mode: ho`,
+			line:      3,
+			character: 20,
+			list:      &protocol.CompletionList{Items: networkModeItems(3, 20, 2)},
+		},
+		{
+			name: "services after the service: prefix",
+			content: `
+services:
+  web:
+    network_mode: "service:"
+  db:
+    image: postgres
+  cache:
+    image: redis`,
+			line:      3,
+			character: 27,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					networkModeServiceItem("cache", 3, 27, 8),
+					networkModeServiceItem("db", 3, 27, 8),
+				},
+			},
+		},
+		{
+			name: "services after the service: prefix with a partial name",
+			content: `
+services:
+  web:
+    network_mode: service:d
+  db:
+    image: postgres`,
+			line:      3,
+			character: 27,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					networkModeServiceItem("db", 3, 27, 9),
+				},
+			},
+		},
+		{
+			name: "services after the service: prefix in a quoted value",
+			content: `
+services:
+  web:
+    network_mode: "service:d"
+  db:
+    image: postgres`,
+			line:      3,
+			character: 28,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					networkModeServiceItem("db", 3, 28, 9),
+				},
+			},
+		},
+		{
+			name: "ipc value",
+			content: `
+services:
+  web:
+    ipc: `,
+			line:      3,
+			character: 9,
+			list:      &protocol.CompletionList{Items: namespaceModeItems("ipc", 3, 9, 0)},
+		},
+		{
+			name: "pid value with a prefix",
+			content: `
+services:
+  web:
+    pid: h`,
+			line:      3,
+			character: 10,
+			list:      &protocol.CompletionList{Items: namespaceModeItems("pid", 3, 10, 1)},
+		},
+		{
+			name: "uts value",
+			content: `
+services:
+  web:
+    uts: `,
+			line:      3,
+			character: 9,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					providerOptionItem("host", "Shares the UTS namespace of the host so that the container uses the host's hostname.", "host", 3, 9, 0),
+				},
+			},
+		},
+		{
+			name: "services after the service: prefix of ipc",
+			content: `
+services:
+  web:
+    ipc: service:d
+  db:
+    image: postgres`,
+			line:      3,
+			character: 18,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					networkModeServiceItem("db", 3, 18, 9),
+				},
+			},
+		},
+		{
+			name: "services after the service: prefix of pid",
+			content: `
+services:
+  web:
+    pid: "service:"
+  db:
+    image: postgres`,
+			line:      3,
+			character: 18,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					networkModeServiceItem("db", 3, 18, 8),
+				},
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_ProjectName(t *testing.T) {
+	folder := filepath.Join(t.TempDir(), "_My Project.v2")
+	require.NoError(t, os.Mkdir(folder, 0755))
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/"))
+
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name:      "empty name value",
+			content:   "name: \nservices:\n  web:\n    image: alpine",
+			line:      0,
+			character: 6,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					{
+						Label:         "myprojectv2",
+						Documentation: "The name of the folder that the Compose file is in which is the project name that Compose uses by default.",
+						TextEdit: protocol.TextEdit{
+							NewText: "myprojectv2",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 0, Character: 6},
+								End:   protocol.Position{Line: 0, Character: 6},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "name already has a value",
+			content:   "name: app\nservices:\n  web:\n    image: alpine",
+			line:      0,
+			character: 9,
+			list:      nil,
+		},
+		{
+			name:      "inside the name attribute",
+			content:   "name: \nservices:\n  web:\n    image: alpine",
+			line:      0,
+			character: 2,
+			list:      nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func schemaItem(label, detail, documentation, newText string, line, character, prefixLength protocol.UInteger) protocol.CompletionItem {
+	item := providerOptionItem(label, documentation, newText, line, character, prefixLength)
+	item.Detail = types.CreateStringPointer(detail)
+	return item
+}
+
+func TestCompletion_ResourceEnvironmentSource(t *testing.T) {
+	folder := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(folder, ".env"), []byte("# comment\nDB_PASSWORD=secret\n\nexport API_KEY=key\n"), 0644))
+
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "environment of a secret",
+			content: `
+secrets:
+  password:
+    environment: `,
+			line:      3,
+			character: 17,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					{Label: "API_KEY", TextEdit: textEdit("API_KEY", 3, 17, 0)},
+					{Label: "DB_PASSWORD", TextEdit: textEdit("DB_PASSWORD", 3, 17, 0)},
+				},
+			},
+		},
+		{
+			name: "environment of a config includes interpolated variables",
+			content: `
+services:
+  web:
+    image: nginx:${TAG}
+configs:
+  settings:
+    environment: DB`,
+			line:      6,
+			character: 19,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					{Label: "API_KEY", TextEdit: textEdit("API_KEY", 6, 19, 2)},
+					{Label: "DB_PASSWORD", TextEdit: textEdit("DB_PASSWORD", 6, 19, 2)},
+					{Label: "TAG", TextEdit: textEdit("TAG", 6, 19, 2)},
+				},
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_JSON(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "top-level attributes",
+			content: `{
+  "services": {
+    "web": {
+      "image": "nginx"
+    }
+  },
+  
+}`,
+			line:      6,
+			character: 2,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("configs", "object", "Configurations that are shared among multiple services.", `"configs": {$1}`, 6, 2, 0),
+					schemaItem("include", "array", "compose sub-projects to be included.", `"include": [$1]`, 6, 2, 0),
+					schemaItem("models", "object", "Language models that will be used by your application.", `"models": {$1}`, 6, 2, 0),
+					schemaItem("name", "string", "define the Compose project name, until user defines one explicitly.", `"name": "$1"`, 6, 2, 0),
+					schemaItem("networks", "object", "Networks that are shared among multiple services.", `"networks": {$1}`, 6, 2, 0),
+					schemaItem("secrets", "object", "Secrets that are shared among multiple services.", `"secrets": {$1}`, 6, 2, 0),
+					schemaItem("version", "string", "declared for backward compatibility, ignored. Please remove it.", `"version": "$1"`, 6, 2, 0),
+					schemaItem("volumes", "object", "Named volumes that are shared among multiple services.", `"volumes": {$1}`, 6, 2, 0),
+				},
+			},
+		},
+		{
+			name: "healthcheck attributes",
+			content: `{
+  "services": {
+    "web": {
+      "healthcheck": {
+        "test": ["CMD", "true"],
+        
+      }
+    }
+  }
+}`,
+			line:      5,
+			character: 8,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("disable", "boolean or string", "Disable any container-specified healthcheck. Set to true to disable.", `"disable": ${1|true,false|}`, 5, 8, 0),
+					schemaItem("interval", "string", "Time between running the check (e.g., '1s', '1m30s'). Default: 30s.", `"interval": "$1"`, 5, 8, 0),
+					schemaItem("retries", "number or string", "Number of consecutive failures needed to consider the container as unhealthy. Default: 3.", `"retries": $1`, 5, 8, 0),
+					schemaItem("start_interval", "string", "Time between running the check during the start period (e.g., '1s', '1m30s'). Default: interval value.", `"start_interval": "$1"`, 5, 8, 0),
+					schemaItem("start_period", "string", "Start period for the container to initialize before starting health-retries countdown (e.g., '1s', '1m30s'). Default: 0s.", `"start_period": "$1"`, 5, 8, 0),
+					schemaItem("timeout", "string", "Maximum time to allow one check to run (e.g., '1s', '1m30s'). Default: 30s.", `"timeout": "$1"`, 5, 8, 0),
+				},
+			},
+		},
+		{
+			name: "value of an attribute",
+			content: `{
+  "services": {
+    "web": {
+      "image": 
+    }
+  }
+}`,
+			line:      3,
+			character: 15,
+			list:      nil,
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.json")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_BlkioConfig(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "device rate list item",
+			content: `
+services:
+  web:
+    blkio_config:
+      device_read_bps:
+        - `,
+			line:      5,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("path", "string", "Path to the device (e.g., '/dev/sda').", "path: ", 5, 10, 0),
+					schemaItem("rate", "integer or string", "Rate limit in bytes per second or IO operations per second.", "rate: ", 5, 10, 0),
+				},
+			},
+		},
+		{
+			name: "device rate list item without a hyphen",
+			content: `
+services:
+  web:
+    blkio_config:
+      device_write_iops:
+        `,
+			line:      5,
+			character: 8,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("path", "string", "Path to the device (e.g., '/dev/sda').", "- path: ", 5, 8, 0),
+					schemaItem("rate", "integer or string", "Rate limit in bytes per second or IO operations per second.", "- rate: ", 5, 8, 0),
+				},
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_ResourceContent(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		item      *protocol.CompletionItem
+	}{
+		{
+			name: "content of a config after its name",
+			content: `
+configs:
+  nginx:
+    name: nginx.conf
+    `,
+			line:      4,
+			character: 4,
+			item: &protocol.CompletionItem{
+				Label:            "content",
+				Detail:           types.CreateStringPointer("string"),
+				Documentation:    "Inline content of the config.",
+				TextEdit:         textEdit("content: |\n      $1", 4, 4, 0),
+				InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+				InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+			},
+		},
+		{
+			name: "secrets cannot have inline content",
+			content: `
+secrets:
+  password:
+    `,
+			line:      3,
+			character: 4,
+			item:      nil,
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.NotNil(t, list)
+			var item *protocol.CompletionItem
+			for i := range list.Items {
+				if list.Items[i].Label == "content" {
+					item = &list.Items[i]
+				}
+			}
+			require.Equal(t, tc.item, item)
+		})
+	}
+}
+
+func TestCompletion_Placement(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "placement attributes",
+			content: `
+services:
+  web:
+    deploy:
+      placement:
+        `,
+			line:      5,
+			character: 8,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("constraints", "array", "Placement constraints for the service (e.g., 'node.role==manager').", "constraints:\n          - ", 5, 8, 0),
+					schemaItem("max_replicas_per_node", "integer or string", "Maximum number of replicas of the service.", "max_replicas_per_node: ", 5, 8, 0),
+					schemaItem("preferences", "array", "Placement preferences for the service.", "preferences:\n          - spread: node.labels.${1:zone}", 5, 8, 0),
+				},
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_CredentialSpec(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "credential_spec attributes",
+			content: `
+services:
+  web:
+    credential_spec:
+      `,
+			line:      4,
+			character: 6,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("config", "string", "The name of the credential spec Config to use.", "config: ", 4, 6, 0),
+					schemaItem("file", "string", "Path to a credential spec file.", "file: ", 4, 6, 0),
+					schemaItem("registry", "string", "Path to a credential spec in the Windows registry.", "registry: ", 4, 6, 0),
+				},
+			},
+		},
+		{
+			name: "other sources are not suggested once one has been set",
+			content: `
+services:
+  web:
+    credential_spec:
+      file: spec.json
+      `,
+			line:      5,
+			character: 6,
+			list:      nil,
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_IpamConfig(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "ipam config list item",
+			content: `
+networks:
+  front:
+    ipam:
+      config:
+        - `,
+			line:      5,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("aux_addresses", "object", "Auxiliary IPv4 or IPv6 addresses used by Network driver.", "aux_addresses:\n            ${1:host1}: ${2:172.16.0.5}", 5, 10, 0),
+					schemaItem("gateway", "string", "IPv4 or IPv6 gateway for the subnet.", "gateway: ${1:172.16.0.1}", 5, 10, 0),
+					schemaItem("ip_range", "string", "Range of IPs from which to allocate container IPs.", "ip_range: ${1:172.16.0.0/28}", 5, 10, 0),
+					schemaItem("subnet", "string", "Subnet in CIDR format that represents a network segment.", "subnet: ${1:172.16.0.0/24}", 5, 10, 0),
+				},
+			},
+		},
+		{
+			name: "second attribute of an ipam config list item",
+			content: `
+networks:
+  front:
+    ipam:
+      config:
+        - subnet: 172.16.0.0/24
+          `,
+			line:      6,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("aux_addresses", "object", "Auxiliary IPv4 or IPv6 addresses used by Network driver.", "aux_addresses:\n            ${1:host1}: ${2:172.16.0.5}", 6, 10, 0),
+					schemaItem("gateway", "string", "IPv4 or IPv6 gateway for the subnet.", "gateway: ${1:172.16.0.1}", 6, 10, 0),
+					schemaItem("ip_range", "string", "Range of IPs from which to allocate container IPs.", "ip_range: ${1:172.16.0.0/28}", 6, 10, 0),
+					schemaItem("subnet", "string", "Subnet in CIDR format that represents a network segment.", "subnet: ${1:172.16.0.0/24}", 6, 10, 0),
+				},
+			},
+		},
+		{
+			name: "ipam config list item without a hyphen",
+			content: `
+networks:
+  front:
+    ipam:
+      config:
+        `,
+			line:      5,
+			character: 8,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					schemaItem("aux_addresses", "object", "Auxiliary IPv4 or IPv6 addresses used by Network driver.", "- aux_addresses:\n            ${1:host1}: ${2:172.16.0.5}", 5, 8, 0),
+					schemaItem("gateway", "string", "IPv4 or IPv6 gateway for the subnet.", "- gateway: ${1:172.16.0.1}", 5, 8, 0),
+					schemaItem("ip_range", "string", "Range of IPs from which to allocate container IPs.", "- ip_range: ${1:172.16.0.0/28}", 5, 8, 0),
+					schemaItem("subnet", "string", "Subnet in CIDR format that represents a network segment.", "- subnet: ${1:172.16.0.0/24}", 5, 8, 0),
+				},
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func additionalContextItem(label, documentation string, line, character, prefixLength protocol.UInteger) protocol.CompletionItem {
+	item := protocol.CompletionItem{
+		Label:    label,
+		TextEdit: textEdit(label, line, character, prefixLength),
+	}
+	if documentation != "" {
+		item.Documentation = documentation
+	}
+	return item
+}
+
+func TestCompletion_AdditionalContexts(t *testing.T) {
+	dockerfileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "Dockerfile")), "/"))
+
+	testCases := []struct {
+		name              string
+		dockerfileContent string
+		content           string
+		line              uint32
+		character         uint32
+		list              *protocol.CompletionList
+	}{
+		{
+			name:              "services and build stages",
+			dockerfileContent: "FROM alpine AS base\nFROM scratch AS release",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: 
+  db:
+    image: postgres
+  cache:
+    image: redis`,
+			line:      5,
+			character: 14,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					additionalContextItem("service:cache", "", 5, 14, 0),
+					additionalContextItem("service:db", "", 5, 14, 0),
+					additionalContextItem("target:base", "alpine", 5, 14, 0),
+					additionalContextItem("target:release", "scratch", 5, 14, 0),
+				},
+			},
+		},
+		{
+			name:              "service: prefix",
+			dockerfileContent: "FROM alpine AS base",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: service:d
+  db:
+    image: postgres`,
+			line:      5,
+			character: 23,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					additionalContextItem("service:db", "", 5, 23, 9),
+				},
+			},
+		},
+		{
+			name:              "target: prefix in a quoted value",
+			dockerfileContent: "FROM alpine AS base\nFROM scratch AS release",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: "target:re"
+  db:
+    image: postgres`,
+			line:      5,
+			character: 24,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					additionalContextItem("target:release", "scratch", 5, 24, 9),
+				},
+			},
+		},
+		{
+			name:              "build stages of a custom Dockerfile",
+			dockerfileContent: "FROM alpine AS base",
+			content: `
+services:
+  web:
+    build:
+      dockerfile: Dockerfile2
+      additional_contexts:
+        base: target:`,
+			line:      6,
+			character: 21,
+			list:      nil,
+		},
+		{
+			name:              "no build stages for an inlined Dockerfile",
+			dockerfileContent: "FROM alpine AS base",
+			content: `
+services:
+  web:
+    build:
+      dockerfile_inline: FROM scratch AS inline
+      additional_contexts:
+        base: 
+  db:
+    image: postgres`,
+			line:      6,
+			character: 14,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					additionalContextItem("service:db", "", 6, 14, 0),
+				},
+			},
+		},
+		{
+			name:              "list of name=value strings",
+			dockerfileContent: "FROM alpine AS base",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        - base=s
+  db:
+    image: postgres`,
+			line:      5,
+			character: 16,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					additionalContextItem("service:db", "", 5, 16, 1),
+				},
+			},
+		},
+		{
+			name:              "name of a context is not completed",
+			dockerfileContent: "FROM alpine AS base",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: service:db
+  db:
+    image: postgres`,
+			line:      5,
+			character: 10,
+			list:      nil,
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			changed, err := manager.Write(context.Background(), uri.URI(dockerfileURI), protocol.DockerfileLanguage, 1, []byte(tc.dockerfileContent))
+			require.NoError(t, err)
+			require.True(t, changed)
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func TestCompletion_NoResultExpected(t *testing.T) {
+	dir, err := os.MkdirTemp(os.TempDir(), fmt.Sprintf("%v-%v", t.Name(), time.Now().UnixMilli()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+
+	fileStructure := []struct {
 		name  string
 		isDir bool
 	}{
@@ -4725,7 +6965,7 @@ services:
 					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
 					Position:     protocol.Position{Line: tc.line, Character: tc.character},
 				},
-			}, manager, doc)
+			}, manager, doc, nil)
 			require.NoError(t, err)
 			require.Equal(t, tc.list, list)
 		})
@@ -4980,7 +7220,7 @@ services:
 					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
 					Position:     protocol.Position{Line: tc.line, Character: tc.character},
 				},
-			}, manager, doc)
+			}, manager, doc, nil)
 			require.NoError(t, err)
 			require.Equal(t, tc.list, list)
 		})
@@ -5136,7 +7376,8 @@ include:
 			content: `
 include:
   - path: `,
-			hideFiles: false,
+			// only folders and Compose files are suggested
+			hideFiles: true,
 			line:      2,
 			character: 10,
 		},
@@ -5146,7 +7387,8 @@ include:
 include:
   - path:
     - `,
-			hideFiles: false,
+			// only folders and Compose files are suggested
+			hideFiles: true,
 			line:      3,
 			character: 6,
 		},
@@ -5254,7 +7496,7 @@ include:
 						TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
 						Position:     protocol.Position{Line: tc.line, Character: tc.character + setup.offset},
 					},
-				}, manager, doc)
+				}, manager, doc, nil)
 				require.NoError(t, err)
 				if tc.hideFiles {
 					require.Equal(t, setup.folderResult, list)
@@ -5266,6 +7508,69 @@ include:
 	}
 }
 
+func TestCompletion_IncludeComposeFiles(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+	}{
+		{
+			name:      "path attribute",
+			content:   "include:\n  - path: ",
+			line:      1,
+			character: 10,
+		},
+		{
+			name:      "path attribute's string array items",
+			content:   "include:\n  - path:\n    - ",
+			line:      2,
+			character: 6,
+		},
+	}
+
+	dir, err := os.MkdirTemp(os.TempDir(), fmt.Sprintf("%v-%v", t.Name(), time.Now().UnixMilli()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+	for _, name := range []string{"base.yml", "compose.yaml", "notes.txt", "other.YAML"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte{}, 0644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(dir, "compose.yaml")), "/"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					{
+						Label: "base.yml",
+						Kind:  types.CreateCompletionItemKindPointer(protocol.CompletionItemKindFile),
+					},
+					{
+						Label: "other.YAML",
+						Kind:  types.CreateCompletionItemKindPointer(protocol.CompletionItemKindFile),
+					},
+					{
+						Label: "sub",
+						Kind:  types.CreateCompletionItemKindPointer(protocol.CompletionItemKindFolder),
+					},
+				},
+			}, list)
+		})
+	}
+}
+
 func TestCompletion_FileStructureMerged(t *testing.T) {
 	testCases := []struct {
 		name      string
@@ -5283,10 +7588,6 @@ include:
 			character: 4,
 			list: &protocol.CompletionList{
 				Items: []protocol.CompletionItem{
-					{
-						Label: "a.txt",
-						Kind:  types.CreateCompletionItemKindPointer(protocol.CompletionItemKindFile),
-					},
 					{
 						Label: "b",
 						Kind:  types.CreateCompletionItemKindPointer(protocol.CompletionItemKindFolder),
@@ -5294,7 +7595,7 @@ include:
 					{
 						Label:            "env_file",
 						Detail:           types.CreateStringPointer("array or string"),
-						Documentation:    "Either a single string or a list of strings.",
+						Documentation:    "The environment files to use when interpolating variables in the included Compose files. Defaults to the .env file in the project directory of the included files.",
 						TextEdit:         textEdit("env_file:", 2, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -5306,7 +7607,7 @@ include:
 					{
 						Label:            "path",
 						Detail:           types.CreateStringPointer("array or string"),
-						Documentation:    "Either a single string or a list of strings.",
+						Documentation:    "The Compose files to include. Relative paths are resolved from the directory of this Compose file.",
 						TextEdit:         textEdit("path:", 2, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -5314,7 +7615,7 @@ include:
 					{
 						Label:            "project_directory",
 						Detail:           types.CreateStringPointer("string"),
-						Documentation:    "Path to resolve relative paths set in the Compose file",
+						Documentation:    "The directory that relative paths in the included Compose files are resolved from. Defaults to the directory of the first included Compose file.",
 						TextEdit:         textEdit("project_directory: ", 2, 4, 0),
 						InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
 						InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
@@ -5336,7 +7637,7 @@ include:
 					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
 					Position:     protocol.Position{Line: tc.line, Character: tc.character},
 				},
-			}, manager, doc)
+			}, manager, doc, nil)
 			require.NoError(t, err)
 			require.Equal(t, tc.list, list)
 		})
@@ -5386,3 +7687,182 @@ func createFileStructure(t *testing.T) string {
 	}
 	return dir
 }
+
+// withoutItems removes the completion items with the given labels.
+func withoutItems(items []protocol.CompletionItem, labels ...string) []protocol.CompletionItem {
+	return slices.DeleteFunc(items, func(item protocol.CompletionItem) bool {
+		return slices.Contains(labels, item.Label)
+	})
+}
+
+func providerOptionItem(label, documentation, newText string, line, character, prefixLength protocol.UInteger) protocol.CompletionItem {
+	return protocol.CompletionItem{
+		Label:            label,
+		Documentation:    documentation,
+		TextEdit:         textEdit(newText, line, character, prefixLength),
+		InsertTextMode:   types.CreateInsertTextModePointer(protocol.InsertTextModeAsIs),
+		InsertTextFormat: types.CreateInsertTextFormatPointer(protocol.InsertTextFormatSnippet),
+	}
+}
+
+// spacedItems prepends a space to the text that the given items insert
+// as is done when completion is triggered by a colon or a dash.
+func spacedItems(items []protocol.CompletionItem, line, character protocol.UInteger) []protocol.CompletionItem {
+	for i := range items {
+		edit := items[i].TextEdit.(protocol.TextEdit)
+		items[i].TextEdit = textEdit(" "+edit.NewText, line, character, 0)
+	}
+	return items
+}
+
+func variableItem(name, filterText string, line, start, end protocol.UInteger) protocol.CompletionItem {
+	return protocol.CompletionItem{
+		Label:      name,
+		FilterText: types.CreateStringPointer(filterText),
+		TextEdit: protocol.TextEdit{
+			NewText: "${" + name + "}",
+			Range: protocol.Range{
+				Start: protocol.Position{Line: line, Character: start},
+				End:   protocol.Position{Line: line, Character: end},
+			},
+		},
+	}
+}
+
+func TestCompletion_TriggerCharacters(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		trigger   string
+		list      *protocol.CompletionList
+	}{
+		{
+			name: "$ completes interpolated variables",
+			content: `
+services:
+  web:
+    image: nginx:${TAG}
+    command: echo $`,
+			line:      4,
+			character: 19,
+			trigger:   "$",
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{variableItem("TAG", "$TAG", 4, 18, 19)},
+			},
+		},
+		{
+			name: "invoked completion of ${ replaces the closing brace",
+			content: `
+services:
+  web:
+    image: nginx:${TAG}
+    command: echo ${}`,
+			line:      4,
+			character: 20,
+			trigger:   "",
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{variableItem("TAG", "${TAG", 4, 18, 21)},
+			},
+		},
+		{
+			name: "$ that is escaped",
+			content: `
+services:
+  web:
+    image: nginx:${TAG}
+    command: echo $$`,
+			line:      4,
+			character: 20,
+			trigger:   "$",
+			list:      nil,
+		},
+		{
+			name: ": after a key completes its value",
+			content: `
+services:
+  web:
+    image:`,
+			line:      3,
+			character: 10,
+			trigger:   ":",
+			list:      &protocol.CompletionList{Items: spacedItems(imageItems(3, 11, 0), 3, 10)},
+		},
+		{
+			name: ": in a value is completed as usual",
+			content: `
+services:
+  web:
+    image: nginx:`,
+			line:      3,
+			character: 17,
+			trigger:   ":",
+			list:      nil,
+		},
+		{
+			name: "- that starts an entry completes the entry",
+			content: `
+services:
+  web:
+    build:
+      entitlements:
+        -`,
+			line:      5,
+			character: 9,
+			trigger:   "-",
+			list: &protocol.CompletionList{
+				Items: spacedItems([]protocol.CompletionItem{
+					providerOptionItem("network.host", entitlements[0].documentation, "network.host", 5, 10, 0),
+					providerOptionItem("security.insecure", entitlements[1].documentation, "security.insecure", 5, 10, 0),
+				}, 5, 9),
+			},
+		},
+		{
+			name: "space after a key completes its value",
+			content: `
+services:
+  web:
+    image: `,
+			line:      3,
+			character: 11,
+			trigger:   " ",
+			list:      &protocol.CompletionList{Items: imageItems(3, 11, 0)},
+		},
+		{
+			name: "space of the indentation is ignored",
+			content: `
+services:
+  web:
+    `,
+			line:      3,
+			character: 4,
+			trigger:   " ",
+			list:      nil,
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+			completionContext := &protocol.CompletionContext{TriggerKind: protocol.CompletionTriggerKindInvoked}
+			if tc.trigger != "" {
+				completionContext = &protocol.CompletionContext{
+					TriggerKind:      protocol.CompletionTriggerKindTriggerCharacter,
+					TriggerCharacter: &tc.trigger,
+				}
+			}
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+				Context: completionContext,
+			}, manager, doc, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
@@ -2,21 +2,31 @@ package compose
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
 	"github.com/docker/docker-language-server/internal/types"
 	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+	"go.lsp.dev/uri"
 )
 
 func insideRange(rng protocol.Range, line, character protocol.UInteger) bool {
 	return rng.Start.Line == line && rng.Start.Character <= character && character <= rng.End.Character
 }
 
-func Definition(ctx context.Context, definitionLinkSupport bool, doc document.ComposeDocument, params *protocol.DefinitionParams) (any, error) {
+func Definition(ctx context.Context, definitionLinkSupport bool, manager *document.Manager, doc document.ComposeDocument, params *protocol.DefinitionParams) (any, error) {
+	if result := extendsDefinition(ctx, definitionLinkSupport, manager, doc, params.Position); result != nil {
+		return result, nil
+	}
+
 	name, dependency := DocumentHighlights(doc, params.Position)
 	if len(dependency.documentHighlights) == 0 {
-		return nil, nil
+		return buildStageDefinition(ctx, definitionLinkSupport, manager, doc, params), nil
 	}
 
 	targetURI := params.TextDocument.URI
@@ -82,3 +92,303 @@ func dependencyLookup(doc document.ComposeDocument, dependencyType, name string)
 	}
 	return nil, ""
 }
+
+// buildStageDefinition resolves a target: reference in the
+// additional_contexts of a service's build object to the build stage
+// of the service's Dockerfile.
+func buildStageDefinition(ctx context.Context, definitionLinkSupport bool, manager *document.Manager, doc document.ComposeDocument, params *protocol.DefinitionParams) any {
+	file := doc.File()
+	if file == nil || len(file.Docs) == 0 {
+		return nil
+	}
+	services := mappingValue(file.Docs[0].Body, "services")
+	if services == nil {
+		return nil
+	}
+	servicesNode, ok := resolveAnchor(services.Value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+
+	line := int(params.Position.Line) + 1
+	character := int(params.Position.Character) + 1
+	for _, serviceNode := range servicesNode.Values {
+		for _, t := range additionalContextTokens(serviceNode.Value) {
+			if !strings.HasPrefix(t.Value, "target:") {
+				continue
+			}
+			stageToken := subToken(t, len("target:"))
+			if !inToken(stageToken, line, character) {
+				continue
+			}
+
+			build := mappingValue(serviceNode.Value, "build")
+			buildNode, ok := resolveAnchor(build.Value).(*ast.MappingNode)
+			if !ok {
+				return nil
+			}
+			dockerfileAttributePath, ok := buildDockerfile(buildNode)
+			if !ok {
+				return nil
+			}
+			documentPath, err := doc.DocumentPath()
+			if err != nil {
+				return nil
+			}
+			dockerfileURI, dockerfilePath := types.Concatenate(documentPath.Folder, dockerfileAttributePath, documentPath.WSLDollarSignHost)
+			stageRange := buildStageRange(ctx, manager, dockerfileURI, dockerfilePath, stageToken.Value)
+			if stageRange == nil {
+				return nil
+			}
+			sourceRange := createRange(stageToken, len(stageToken.Value))
+			return types.CreateDefinitionResult(definitionLinkSupport, *stageRange, &sourceRange, protocol.URI(dockerfileURI))
+		}
+	}
+	return nil
+}
+
+// buildStageRange returns the range of the FROM instruction that
+// declares the given build stage in the Dockerfile.
+func buildStageRange(ctx context.Context, manager *document.Manager, dockerfileURI, dockerfilePath, stage string) *protocol.Range {
+	bytes, nodes := document.OpenDockerfile(ctx, manager, dockerfileURI, dockerfilePath)
+	lines := strings.Split(string(bytes), "\n")
+	for _, child := range nodes {
+		if strings.EqualFold(child.Value, "FROM") {
+			if child.Next != nil && child.Next.Next != nil && strings.EqualFold(child.Next.Next.Value, "AS") && child.Next.Next.Next != nil && child.Next.Next.Next.Value == stage {
+				endLine := strings.TrimSuffix(lines[child.EndLine-1], "\r")
+				return &protocol.Range{
+					Start: protocol.Position{Line: uint32(child.StartLine) - 1, Character: 0},
+					End:   protocol.Position{Line: uint32(child.EndLine) - 1, Character: uint32(len(endLine))},
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// extendsReference returns the token of the name of the service that
+// the given service extends and the file that the extended service is
+// in. The file is empty if the extended service is in the same file.
+// False is returned if the service does not extend another service or
+// if the reference is interpolated.
+func extendsReference(serviceNode ast.Node) (*token.Token, string, bool) {
+	extends := mappingValue(serviceNode, "extends")
+	if extends == nil {
+		return nil, "", false
+	}
+
+	var nameNode ast.Node
+	file := ""
+	switch n := resolveAnchor(extends.Value).(type) {
+	case *ast.StringNode:
+		nameNode = n
+	case *ast.MappingNode:
+		service := mappingValue(n, "service")
+		if service == nil {
+			return nil, "", false
+		}
+		nameNode = resolveAnchor(service.Value)
+		if fileNode := mappingValue(n, "file"); fileNode != nil {
+			var ok bool
+			file, ok = literalValue(resolveAnchor(fileNode.Value).GetToken().Value)
+			if !ok {
+				return nil, "", false
+			}
+		}
+	}
+
+	stringNode, ok := nameNode.(*ast.StringNode)
+	if !ok {
+		return nil, "", false
+	}
+	if _, ok := literalValue(stringNode.Value); !ok {
+		return nil, "", false
+	}
+	return stringNode.GetToken(), file, true
+}
+
+// extendsTarget is the location of the declaration of a service that
+// another service extends.
+type extendsTarget struct {
+	uri protocol.URI
+	rng protocol.Range
+}
+
+// defaultComposeFiles are the names of the files that Compose loads if
+// no file has been specified. Each of them is loaded together with the
+// override file of the same name if it exists.
+var defaultComposeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// overrideFile returns the name of the file that Compose loads together
+// with the file of the given name. A default Compose file is paired with
+// its override file and an override file with its default Compose file.
+// False is returned if the file is not loaded with another file.
+func overrideFile(name string) (string, bool) {
+	for _, composeFile := range defaultComposeFiles {
+		extension := filepath.Ext(composeFile)
+		override := strings.TrimSuffix(composeFile, extension) + ".override" + extension
+		switch name {
+		case composeFile:
+			return override, true
+		case override:
+			return composeFile, true
+		}
+	}
+	return "", false
+}
+
+// serviceDeclaration returns the named service of the given document or
+// nil if the document does not declare it.
+func serviceDeclaration(doc document.ComposeDocument, name string) *ast.MappingValueNode {
+	composeFile := doc.File()
+	if composeFile == nil || len(composeFile.Docs) == 0 {
+		return nil
+	}
+	services := mappingValue(composeFile.Docs[0].Body, "services")
+	if services == nil {
+		return nil
+	}
+	return mappingValue(services.Value, name)
+}
+
+// projectServiceDeclaration looks for the named service in the files
+// that are part of the same project as the given document. These are
+// the files that the document includes and the file that Compose loads
+// together with it.
+func projectServiceDeclaration(ctx context.Context, manager *document.Manager, doc document.ComposeDocument, name string) (document.ComposeDocument, *ast.MappingValueNode) {
+	uris := []string{}
+	files, _ := doc.IncludedFiles()
+	for u := range files {
+		uris = append(uris, u)
+	}
+	slices.Sort(uris)
+	if documentPath, err := doc.DocumentPath(); err == nil {
+		if override, ok := overrideFile(documentPath.FileName); ok {
+			overrideURI, _ := types.Concatenate(documentPath.Folder, override, documentPath.WSLDollarSignHost)
+			uris = append(uris, overrideURI)
+		}
+	}
+
+	for _, u := range uris {
+		if projectDocument := document.OpenComposeFile(ctx, manager, uri.URI(u)); projectDocument != nil {
+			if service := serviceDeclaration(projectDocument, name); service != nil {
+				return projectDocument, service
+			}
+		}
+	}
+	return nil, nil
+}
+
+// extendedServices follows the chain of services that starts with the
+// named service in the given file. The file is resolved against the
+// folder of the given document and the document itself is used if the
+// file is empty. Files in another workspace folder are not followed. A
+// service that is not declared in the document itself is looked up in
+// the rest of the document's project. The chain ends when a service
+// does not extend another service, when a service cannot be found, or
+// when a service is reached again. The service that starts the chain is
+// considered as having been reached already.
+func extendedServices(ctx context.Context, manager *document.Manager, doc document.ComposeDocument, origin, name, file string) []extendsTarget {
+	targets := []extendsTarget{}
+	visited := map[string]bool{fmt.Sprintf("%v#%v", doc.URI(), origin): true}
+	for {
+		if file != "" {
+			documentPath, err := doc.DocumentPath()
+			if err != nil {
+				return targets
+			}
+			fileURI, _ := types.Concatenate(documentPath.Folder, file, documentPath.WSLDollarSignHost)
+			if !manager.SameWorkspaceFolder(doc.URI(), uri.URI(fileURI)) {
+				return targets
+			}
+			if !samePath(string(doc.URI()), fileURI) {
+				doc = document.OpenComposeFile(ctx, manager, uri.URI(fileURI))
+				if doc == nil {
+					return targets
+				}
+			}
+		}
+
+		service := serviceDeclaration(doc, name)
+		if service == nil && file == "" {
+			doc, service = projectServiceDeclaration(ctx, manager, doc, name)
+		}
+		if service == nil {
+			return targets
+		}
+
+		key := fmt.Sprintf("%v#%v", doc.URI(), name)
+		if visited[key] {
+			return targets
+		}
+		visited[key] = true
+
+		t := resolveAnchor(service.Key).GetToken()
+		targets = append(targets, extendsTarget{uri: protocol.URI(doc.URI()), rng: createRange(t, len(t.Value))})
+
+		next, nextFile, ok := extendsReference(service.Value)
+		if !ok {
+			return targets
+		}
+		name, _ = literalValue(next.Value)
+		file = nextFile
+	}
+}
+
+// extendsDefinition returns the declaration of the service that the
+// extends attribute under the cursor points to. If that service extends
+// another service then the chain is followed and the service at the end
+// of it is included as an additional location. Nil is returned if the
+// cursor is not on an extends attribute's service so that the request
+// can be handled by other means. A service in the same file that does
+// not extend anything is also left to the document highlights.
+func extendsDefinition(ctx context.Context, definitionLinkSupport bool, manager *document.Manager, doc document.ComposeDocument, position protocol.Position) any {
+	file := doc.File()
+	if file == nil || len(file.Docs) == 0 {
+		return nil
+	}
+	services := mappingValue(file.Docs[0].Body, "services")
+	if services == nil {
+		return nil
+	}
+	servicesNode, ok := resolveAnchor(services.Value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+
+	for _, serviceNode := range servicesNode.Values {
+		t, extendsFile, ok := extendsReference(resolveAnchor(serviceNode.Value))
+		if !ok || !inToken(t, int(position.Line)+1, int(position.Character)+1) {
+			continue
+		}
+
+		name, _ := literalValue(t.Value)
+		targets := extendedServices(ctx, manager, doc, resolveAnchor(serviceNode.Key).GetToken().Value, name, extendsFile)
+		if len(targets) == 0 || (extendsFile == "" && len(targets) == 1 && targets[0].uri == protocol.URI(doc.URI())) {
+			return nil
+		}
+		if len(targets) > 2 {
+			targets = []extendsTarget{targets[0], targets[len(targets)-1]}
+		}
+
+		originRange := createRange(t, len(t.Value))
+		if !definitionLinkSupport {
+			locations := []protocol.Location{}
+			for _, target := range targets {
+				locations = append(locations, protocol.Location{Range: target.rng, URI: target.uri})
+			}
+			return locations
+		}
+		links := []protocol.LocationLink{}
+		for _, target := range targets {
+			links = append(links, protocol.LocationLink{
+				OriginSelectionRange: &originRange,
+				TargetRange:          target.rng,
+				TargetSelectionRange: target.rng,
+				TargetURI:            target.uri,
+			})
+		}
+		return links
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
 	"github.com/stretchr/testify/require"
 	"go.lsp.dev/uri"
 )
@@ -27,13 +28,13 @@ func TestDefinition_Services(t *testing.T) {
 		}
 
 		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
-			locations, err := Definition(context.Background(), false, doc, &params)
+			locations, err := Definition(context.Background(), false, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.locations(composeFileURI), locations)
 		})
 
 		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
-			links, err := Definition(context.Background(), true, doc, &params)
+			links, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.links(composeFileURI), links)
 		})
@@ -53,13 +54,13 @@ func TestDefinition_Networks(t *testing.T) {
 		}
 
 		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
-			locations, err := Definition(context.Background(), false, doc, &params)
+			locations, err := Definition(context.Background(), false, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.locations(composeFileURI), locations)
 		})
 
 		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
-			links, err := Definition(context.Background(), true, doc, &params)
+			links, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.links(composeFileURI), links)
 		})
@@ -79,13 +80,13 @@ func TestDefinition_Volumes(t *testing.T) {
 		}
 
 		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
-			locations, err := Definition(context.Background(), false, doc, &params)
+			locations, err := Definition(context.Background(), false, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.locations(composeFileURI), locations)
 		})
 
 		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
-			links, err := Definition(context.Background(), true, doc, &params)
+			links, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.links(composeFileURI), links)
 		})
@@ -105,13 +106,13 @@ func TestDefinition_Configs(t *testing.T) {
 		}
 
 		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
-			locations, err := Definition(context.Background(), false, doc, &params)
+			locations, err := Definition(context.Background(), false, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.locations(composeFileURI), locations)
 		})
 
 		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
-			links, err := Definition(context.Background(), true, doc, &params)
+			links, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.links(composeFileURI), links)
 		})
@@ -131,13 +132,13 @@ func TestDefinition_Secrets(t *testing.T) {
 		}
 
 		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
-			locations, err := Definition(context.Background(), false, doc, &params)
+			locations, err := Definition(context.Background(), false, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.locations(composeFileURI), locations)
 		})
 
 		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
-			links, err := Definition(context.Background(), true, doc, &params)
+			links, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.links(composeFileURI), links)
 		})
@@ -157,13 +158,13 @@ func TestDefinition_Models(t *testing.T) {
 		}
 
 		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
-			locations, err := Definition(context.Background(), false, doc, &params)
+			locations, err := Definition(context.Background(), false, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.locations(composeFileURI), locations)
 		})
 
 		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
-			links, err := Definition(context.Background(), true, doc, &params)
+			links, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.links(composeFileURI), links)
 		})
@@ -183,13 +184,13 @@ func TestDefinition_Fragments(t *testing.T) {
 		}
 
 		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
-			locations, err := Definition(context.Background(), false, doc, &params)
+			locations, err := Definition(context.Background(), false, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.locations(composeFileURI), locations)
 		})
 
 		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
-			links, err := Definition(context.Background(), true, doc, &params)
+			links, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.links(composeFileURI), links)
 		})
@@ -502,15 +503,554 @@ networks: string`,
 		}
 
 		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
-			locations, err := Definition(context.Background(), false, doc, &params)
+			locations, err := Definition(context.Background(), false, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.locations, locations)
 		})
 
 		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
-			links, err := Definition(context.Background(), true, doc, &params)
+			links, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &params)
 			require.NoError(t, err)
 			require.Equal(t, tc.links, links)
 		})
 	}
 }
+
+func TestDefinition_WorkspaceFolders(t *testing.T) {
+	folderA := filepath.Join(os.TempDir(), "a")
+	folderB := filepath.Join(os.TempDir(), "b")
+	fileURI := func(folder, name string) string {
+		return fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, name)), "/"))
+	}
+	webLocation := func(folder string) []protocol.Location {
+		return []protocol.Location{
+			{
+				URI: fileURI(folder, "compose.other.yaml"),
+				Range: protocol.Range{
+					Start: protocol.Position{Line: 2, Character: 2},
+					End:   protocol.Position{Line: 2, Character: 5},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		workspaceFolders []string
+		content          string
+		line             protocol.UInteger
+		character        protocol.UInteger
+		locations        any
+	}{
+		{
+			name:             "included web service of the same folder is used over the other folder's",
+			workspaceFolders: []string{folderA, folderB},
+			content: `
+include:
+  - ../b/compose.other.yaml
+  - compose.other.yaml
+services:
+  test:
+    depends_on:
+      - web`,
+			line:      7,
+			character: 9,
+			locations: webLocation(folderA),
+		},
+		{
+			name:             "web service included from another folder is not resolved",
+			workspaceFolders: []string{folderA, folderB},
+			content: `
+include:
+  - ../b/compose.other.yaml
+services:
+  test:
+    depends_on:
+      - web`,
+			line:      6,
+			character: 9,
+			locations: nil,
+		},
+		{
+			name:             "web service included from another folder is resolved without workspace folders",
+			workspaceFolders: nil,
+			content: `
+include:
+  - ../b/compose.other.yaml
+services:
+  test:
+    depends_on:
+      - web`,
+			line:      6,
+			character: 9,
+			locations: webLocation(folderB),
+		},
+		{
+			name:             "web service extended from another folder is not resolved",
+			workspaceFolders: []string{folderA, folderB},
+			content: `
+services:
+  test:
+    extends:
+      file: ../b/compose.other.yaml
+      service: web`,
+			line:      5,
+			character: 16,
+			locations: nil,
+		},
+		{
+			name:             "web service extended from the same folder is resolved",
+			workspaceFolders: []string{folderA, folderB},
+			content: `
+services:
+  test:
+    extends:
+      file: compose.other.yaml
+      service: web`,
+			line:      5,
+			character: 16,
+			locations: webLocation(folderA),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := document.NewDocumentManager()
+			mgr.SetWorkspaceFolders(tc.workspaceFolders)
+			for _, folder := range []string{folderA, folderB} {
+				changed, err := mgr.Write(context.Background(), uri.URI(fileURI(folder, "compose.other.yaml")), protocol.DockerComposeLanguage, 1, []byte(`
+services:
+  web:
+    image: nginx`))
+				require.NoError(t, err)
+				require.True(t, changed)
+			}
+
+			composeFileURI := fileURI(folderA, "compose.yaml")
+			doc := document.NewComposeDocument(mgr, uri.URI(composeFileURI), 1, []byte(tc.content))
+			params := protocol.DefinitionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}
+			locations, err := Definition(context.Background(), false, mgr, doc, &params)
+			require.NoError(t, err)
+			require.Equal(t, tc.locations, locations)
+		})
+	}
+}
+
+func TestDefinition_AdditionalContextsTarget(t *testing.T) {
+	dockerfileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "Dockerfile")), "/"))
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		locations any
+		links     any
+	}{
+		{
+			name: "target reference to a build stage",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: target:builder`,
+			line:      5,
+			character: 22,
+			locations: []protocol.Location{
+				{
+					URI: dockerfileURI,
+					Range: protocol.Range{
+						Start: protocol.Position{Line: 0, Character: 0},
+						End:   protocol.Position{Line: 0, Character: 22},
+					},
+				},
+			},
+			links: []protocol.LocationLink{
+				{
+					OriginSelectionRange: &protocol.Range{
+						Start: protocol.Position{Line: 5, Character: 21},
+						End:   protocol.Position{Line: 5, Character: 28},
+					},
+					TargetRange: protocol.Range{
+						Start: protocol.Position{Line: 0, Character: 0},
+						End:   protocol.Position{Line: 0, Character: 22},
+					},
+					TargetSelectionRange: protocol.Range{
+						Start: protocol.Position{Line: 0, Character: 0},
+						End:   protocol.Position{Line: 0, Character: 22},
+					},
+					TargetURI: dockerfileURI,
+				},
+			},
+		},
+		{
+			name: "target reference in the list form",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        - base=target:builder`,
+			line:      5,
+			character: 22,
+			locations: []protocol.Location{
+				{
+					URI: dockerfileURI,
+					Range: protocol.Range{
+						Start: protocol.Position{Line: 0, Character: 0},
+						End:   protocol.Position{Line: 0, Character: 22},
+					},
+				},
+			},
+			links: []protocol.LocationLink{
+				{
+					OriginSelectionRange: &protocol.Range{
+						Start: protocol.Position{Line: 5, Character: 22},
+						End:   protocol.Position{Line: 5, Character: 29},
+					},
+					TargetRange: protocol.Range{
+						Start: protocol.Position{Line: 0, Character: 0},
+						End:   protocol.Position{Line: 0, Character: 22},
+					},
+					TargetSelectionRange: protocol.Range{
+						Start: protocol.Position{Line: 0, Character: 0},
+						End:   protocol.Position{Line: 0, Character: 22},
+					},
+					TargetURI: dockerfileURI,
+				},
+			},
+		},
+		{
+			name: "target reference to an unknown build stage",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: target:unknown`,
+			line:      5,
+			character: 22,
+			locations: nil,
+			links:     nil,
+		},
+		{
+			name: "registry image reference",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: docker-image://alpine`,
+			line:      5,
+			character: 22,
+			locations: nil,
+			links:     nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		manager := document.NewDocumentManager()
+		changed, err := manager.Write(context.Background(), uri.URI(dockerfileURI), protocol.DockerfileLanguage, 1, []byte("FROM alpine AS builder\nFROM scratch"))
+		require.NoError(t, err)
+		require.True(t, changed)
+		doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(tc.content))
+		params := protocol.DefinitionParams{
+			TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+				Position:     protocol.Position{Line: tc.line, Character: tc.character},
+			},
+		}
+
+		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
+			locations, err := Definition(context.Background(), false, manager, doc, &params)
+			require.NoError(t, err)
+			require.Equal(t, tc.locations, locations)
+		})
+
+		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
+			links, err := Definition(context.Background(), true, manager, doc, &params)
+			require.NoError(t, err)
+			require.Equal(t, tc.links, links)
+		})
+	}
+}
+
+func TestDefinition_JSON(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.json")), "/"))
+	content := `{
+  "services": {
+    "web": {
+      "image": "nginx",
+      "depends_on": ["db"],
+      "networks": ["frontend"]
+    },
+    "db": {
+      "image": "postgres"
+    }
+  },
+  "networks": {
+    "frontend": {}
+  }
+}`
+	testCases := []struct {
+		name      string
+		line      uint32
+		character uint32
+		links     any
+	}{
+		{
+			name:      "depends_on service",
+			line:      4,
+			character: 23,
+			links: types.CreateDefinitionResult(true, protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 5},
+				End:   protocol.Position{Line: 7, Character: 7},
+			}, &protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 22},
+				End:   protocol.Position{Line: 4, Character: 24},
+			}, composeFileURI),
+		},
+		{
+			name:      "network",
+			line:      5,
+			character: 22,
+			links: types.CreateDefinitionResult(true, protocol.Range{
+				Start: protocol.Position{Line: 12, Character: 5},
+				End:   protocol.Position{Line: 12, Character: 13},
+			}, &protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 20},
+				End:   protocol.Position{Line: 5, Character: 28},
+			}, composeFileURI),
+		},
+		{
+			name:      "image",
+			line:      3,
+			character: 18,
+			links:     nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := document.NewDocumentManager()
+			doc := document.NewComposeDocument(manager, uri.URI(composeFileURI), 1, []byte(content))
+			links, err := Definition(context.Background(), true, manager, doc, &protocol.DefinitionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.links, links)
+		})
+	}
+}
+
+func TestDefinition_ExtendsChain(t *testing.T) {
+	folder := t.TempDir()
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/"))
+	baseFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "base.yaml")), "/"))
+	commonFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "common.yaml")), "/"))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "base.yaml"), []byte(`
+services:
+  base:
+    extends:
+      file: common.yaml
+      service: common`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "common.yaml"), []byte(`
+services:
+  common:
+    image: alpine`), 0644))
+	overrideFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.override.yaml")), "/"))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "compose.override.yaml"), []byte(`
+services:
+  overlay:
+    extends:
+      file: common.yaml
+      service: common
+  shared:
+    image: alpine`), 0644))
+
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		origin    protocol.Range
+		targets   []extendsTarget
+	}{
+		{
+			name: "three services in the same file",
+			content: `
+services:
+  a:
+    extends: b
+  b:
+    extends:
+      service: c
+  c:
+    image: alpine`,
+			line:      3,
+			character: 14,
+			origin:    protocol.Range{Start: protocol.Position{Line: 3, Character: 13}, End: protocol.Position{Line: 3, Character: 14}},
+			targets: []extendsTarget{
+				{uri: composeFileURI, rng: protocol.Range{Start: protocol.Position{Line: 4, Character: 2}, End: protocol.Position{Line: 4, Character: 3}}},
+				{uri: composeFileURI, rng: protocol.Range{Start: protocol.Position{Line: 7, Character: 2}, End: protocol.Position{Line: 7, Character: 3}}},
+			},
+		},
+		{
+			name: "four services in the same file only include the base",
+			content: `
+services:
+  a:
+    extends: b
+  b:
+    extends: c
+  c:
+    extends: d
+  d:
+    image: alpine`,
+			line:      3,
+			character: 13,
+			origin:    protocol.Range{Start: protocol.Position{Line: 3, Character: 13}, End: protocol.Position{Line: 3, Character: 14}},
+			targets: []extendsTarget{
+				{uri: composeFileURI, rng: protocol.Range{Start: protocol.Position{Line: 4, Character: 2}, End: protocol.Position{Line: 4, Character: 3}}},
+				{uri: composeFileURI, rng: protocol.Range{Start: protocol.Position{Line: 8, Character: 2}, End: protocol.Position{Line: 8, Character: 3}}},
+			},
+		},
+		{
+			name: "cyclic chain stops at the repeated service",
+			content: `
+services:
+  a:
+    extends: b
+  b:
+    extends: c
+  c:
+    extends: a`,
+			line:      3,
+			character: 13,
+			origin:    protocol.Range{Start: protocol.Position{Line: 3, Character: 13}, End: protocol.Position{Line: 3, Character: 14}},
+			targets: []extendsTarget{
+				{uri: composeFileURI, rng: protocol.Range{Start: protocol.Position{Line: 4, Character: 2}, End: protocol.Position{Line: 4, Character: 3}}},
+				{uri: composeFileURI, rng: protocol.Range{Start: protocol.Position{Line: 6, Character: 2}, End: protocol.Position{Line: 6, Character: 3}}},
+			},
+		},
+		{
+			name: "three services across files",
+			content: `
+services:
+  a:
+    extends:
+      file: base.yaml
+      service: base`,
+			line:      5,
+			character: 17,
+			origin:    protocol.Range{Start: protocol.Position{Line: 5, Character: 15}, End: protocol.Position{Line: 5, Character: 19}},
+			targets: []extendsTarget{
+				{uri: baseFileURI, rng: protocol.Range{Start: protocol.Position{Line: 2, Character: 2}, End: protocol.Position{Line: 2, Character: 6}}},
+				{uri: commonFileURI, rng: protocol.Range{Start: protocol.Position{Line: 2, Character: 2}, End: protocol.Position{Line: 2, Character: 8}}},
+			},
+		},
+		{
+			name: "service in another file that does not extend anything",
+			content: `
+services:
+  a:
+    extends:
+      file: common.yaml
+      service: common`,
+			line:      5,
+			character: 17,
+			origin:    protocol.Range{Start: protocol.Position{Line: 5, Character: 15}, End: protocol.Position{Line: 5, Character: 21}},
+			targets: []extendsTarget{
+				{uri: commonFileURI, rng: protocol.Range{Start: protocol.Position{Line: 2, Character: 2}, End: protocol.Position{Line: 2, Character: 8}}},
+			},
+		},
+		{
+			name: "service that is only declared in the override file",
+			content: `
+services:
+  a:
+    extends: shared`,
+			line:      3,
+			character: 15,
+			origin:    protocol.Range{Start: protocol.Position{Line: 3, Character: 13}, End: protocol.Position{Line: 3, Character: 19}},
+			targets: []extendsTarget{
+				{uri: overrideFileURI, rng: protocol.Range{Start: protocol.Position{Line: 6, Character: 2}, End: protocol.Position{Line: 6, Character: 8}}},
+			},
+		},
+		{
+			name: "service object that is only declared in the override file",
+			content: `
+services:
+  a:
+    extends:
+      service: overlay`,
+			line:      4,
+			character: 17,
+			origin:    protocol.Range{Start: protocol.Position{Line: 4, Character: 15}, End: protocol.Position{Line: 4, Character: 22}},
+			targets: []extendsTarget{
+				{uri: overrideFileURI, rng: protocol.Range{Start: protocol.Position{Line: 2, Character: 2}, End: protocol.Position{Line: 2, Character: 9}}},
+				{uri: commonFileURI, rng: protocol.Range{Start: protocol.Position{Line: 2, Character: 2}, End: protocol.Position{Line: 2, Character: 8}}},
+			},
+		},
+		{
+			name: "service that is declared in an included file",
+			content: `
+include:
+  - base.yaml
+services:
+  a:
+    extends: base`,
+			line:      5,
+			character: 15,
+			origin:    protocol.Range{Start: protocol.Position{Line: 5, Character: 13}, End: protocol.Position{Line: 5, Character: 17}},
+			targets: []extendsTarget{
+				{uri: baseFileURI, rng: protocol.Range{Start: protocol.Position{Line: 2, Character: 2}, End: protocol.Position{Line: 2, Character: 6}}},
+				{uri: commonFileURI, rng: protocol.Range{Start: protocol.Position{Line: 2, Character: 2}, End: protocol.Position{Line: 2, Character: 8}}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(tc.content))
+		params := protocol.DefinitionParams{
+			TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+				Position:     protocol.Position{Line: tc.line, Character: tc.character},
+			},
+		}
+
+		t.Run(fmt.Sprintf("%v (Location)", tc.name), func(t *testing.T) {
+			locations := []protocol.Location{}
+			for _, target := range tc.targets {
+				locations = append(locations, protocol.Location{URI: target.uri, Range: target.rng})
+			}
+			result, err := Definition(context.Background(), false, document.NewDocumentManager(), doc, &params)
+			require.NoError(t, err)
+			require.Equal(t, locations, result)
+		})
+
+		t.Run(fmt.Sprintf("%v (LocationLink)", tc.name), func(t *testing.T) {
+			links := []protocol.LocationLink{}
+			for _, target := range tc.targets {
+				links = append(links, protocol.LocationLink{
+					OriginSelectionRange: &tc.origin,
+					TargetURI:            target.uri,
+					TargetRange:          target.rng,
+					TargetSelectionRange: target.rng,
+				})
+			}
+			result, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &params)
+			require.NoError(t, err)
+			require.Equal(t, links, result)
+		})
+	}
+}
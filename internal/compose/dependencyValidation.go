@@ -0,0 +1,134 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// declaresAttribute checks if the given service declares the attribute
+// itself or inherits it from a merged anchor or the service that it
+// extends. The result is only certain if every service that it extends
+// could be found in this file.
+func declaresAttribute(root *ast.MappingNode, serviceNode ast.Node, attribute string, visited map[string]bool) (declared, certain bool) {
+	mappingNode, ok := resolveAnchor(serviceNode).(*ast.MappingNode)
+	if !ok {
+		return false, false
+	}
+	if mappingValue(mappingNode, attribute) != nil {
+		return true, true
+	}
+
+	for _, child := range mappingNode.Values {
+		if _, ok := child.Key.(*ast.MergeKeyNode); !ok {
+			continue
+		}
+		merged := []ast.Node{child.Value}
+		if sequenceNode, ok := resolveAnchor(child.Value).(*ast.SequenceNode); ok {
+			merged = sequenceNode.Values
+		}
+		for _, node := range merged {
+			if declared, certain := declaresAttribute(root, node, attribute, visited); declared || !certain {
+				return declared, certain
+			}
+		}
+	}
+
+	extends := mappingValue(mappingNode, "extends")
+	if extends == nil {
+		return false, true
+	}
+	var service string
+	switch n := resolveAnchor(extends.Value).(type) {
+	case *ast.StringNode:
+		service = n.Value
+	case *ast.MappingNode:
+		if mappingValue(n, "file") != nil {
+			return false, false
+		}
+		if serviceName := mappingValue(n, "service"); serviceName != nil {
+			service = resolveAnchor(serviceName.Value).GetToken().Value
+		}
+	}
+	if service == "" || visited[service] {
+		return false, false
+	}
+	visited[service] = true
+	if services := mappingValue(root, "services"); services != nil {
+		if extended := mappingValue(services.Value, service); extended != nil {
+			return declaresAttribute(root, extended.Value, attribute, visited)
+		}
+	}
+	return false, false
+}
+
+// validateHealthyDependencies reports dependencies that wait for a
+// service to become healthy when the service has no healthcheck as the
+// condition can then never be satisfied. Services that are built are
+// ignored as their Dockerfile may declare a HEALTHCHECK instruction.
+func validateHealthyDependencies(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	dependencies, ok := resolveAnchor(value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+	services := mappingValue(root, "services")
+	if services == nil {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, dependency := range dependencies.Values {
+		condition := mappingValue(dependency.Value, "condition")
+		if condition == nil {
+			continue
+		}
+		conditionNode, ok := resolveAnchor(condition.Value).(*ast.StringNode)
+		if !ok {
+			continue
+		}
+		if literal, ok := literalValue(conditionNode.Value); !ok || literal != "service_healthy" {
+			continue
+		}
+
+		name := resolveAnchor(dependency.Key).GetToken().Value
+		service := mappingValue(services.Value, name)
+		if service == nil {
+			continue
+		}
+		if built, certain := declaresAttribute(root, service.Value, "build", map[string]bool{name: true}); built || !certain {
+			continue
+		}
+		if declared, certain := declaresAttribute(root, service.Value, "healthcheck", map[string]bool{name: true}); declared || !certain {
+			continue
+		}
+
+		t := conditionNode.GetToken()
+		diagnostic := createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityWarning,
+			"MissingHealthcheck",
+			fmt.Sprintf("service %v has no healthcheck so it can never become healthy, consider adding a healthcheck to %v", name, name),
+			createRange(t, len(t.Value)),
+		)
+		if serviceAttributes, ok := resolveAnchor(service.Value).(*ast.MappingNode); ok && !serviceAttributes.IsFlowStyle && len(serviceAttributes.Values) > 0 {
+			keyLine := service.Key.GetToken().Position.Line
+			keyIndentation := service.Key.GetToken().Position.Column - 1
+			indentation := serviceAttributes.Values[0].Key.GetToken().Position.Column - 1
+			diagnostic.Data = []types.NamedEdit{
+				{
+					Title: fmt.Sprintf("Add healthcheck to %v", name),
+					Edit:  healthcheckText(serviceAttributes, strings.Repeat(" ", indentation), strings.Repeat(" ", indentation-keyIndentation)),
+					Range: &protocol.Range{
+						Start: protocol.Position{Line: protocol.UInteger(keyLine)},
+						End:   protocol.Position{Line: protocol.UInteger(keyLine)},
+					},
+				},
+			}
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
 	"github.com/docker/docker-language-server/internal/types"
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
 )
 
 type ComposeDiagnosticsCollector struct {
@@ -23,13 +24,15 @@ func (c *ComposeDiagnosticsCollector) SupportsLanguageIdentifier(languageIdentif
 }
 
 func (c *ComposeDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder string, doc document.Document, text string) []protocol.Diagnostic {
-	err := doc.(document.ComposeDocument).ParsingError()
+	composeDoc := doc.(document.ComposeDocument)
+	err := composeDoc.ParsingError()
 	if err != nil {
 		var syntaxError *yaml.SyntaxError
 		if errors.As(err, &syntaxError) {
 			return []protocol.Diagnostic{
 				{
 					Message:  syntaxError.Message,
+					Code:     &protocol.IntegerOrString{Value: "InvalidYAML"},
 					Source:   types.CreateStringPointer(source),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -46,5 +49,95 @@ func (c *ComposeDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder
 			}
 		}
 	}
-	return nil
+
+	file := composeDoc.File()
+	if file == nil {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, documentNode := range file.Docs {
+		diagnostics = append(diagnostics, validateAliases(source, documentNode.Body)...)
+		if mappingNode, ok := documentNode.Body.(*ast.MappingNode); ok {
+			diagnostics = append(diagnostics, validateConflictingDockerfiles(source, mappingNode)...)
+			diagnostics = append(diagnostics, validateTopLevelAttributes(source, mappingNode)...)
+			for _, validator := range propertyValidators {
+				matchPropertyPath(validator.path, nil, mappingNode, func(key, value ast.Node) {
+					diagnostics = append(diagnostics, validator.validate(source, mappingNode, key, value)...)
+				})
+			}
+		}
+	}
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	return diagnostics
+}
+
+// ComposeDeferrableDiagnosticsCollector runs the checks of a Compose
+// file that read other files, such as the ones that follow extends and
+// include chains or that look for referenced files, along with the
+// checks that compare every port mapping of a service. If the server
+// has been configured to validate on save then these checks will only
+// be run when the file is opened or saved.
+type ComposeDeferrableDiagnosticsCollector struct {
+}
+
+// deferrablePropertyValidators are the property validators that are
+// run by the ComposeDeferrableDiagnosticsCollector.
+var deferrablePropertyValidators = []propertyValidator{
+	{
+		path:     []string{"services", "*"},
+		validate: validateDuplicatePorts,
+	},
+}
+
+func NewComposeDeferrableDiagnosticsCollector() textdocument.DiagnosticsCollector {
+	return &ComposeDeferrableDiagnosticsCollector{}
+}
+
+func (c *ComposeDeferrableDiagnosticsCollector) SupportsLanguageIdentifier(languageIdentifier protocol.LanguageIdentifier) bool {
+	return languageIdentifier == protocol.DockerComposeLanguage
+}
+
+// Deferrable returns true as the checks need to read other files.
+func (c *ComposeDeferrableDiagnosticsCollector) Deferrable() bool {
+	return true
+}
+
+func (c *ComposeDeferrableDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder string, doc document.Document, text string) []protocol.Diagnostic {
+	composeDoc := doc.(document.ComposeDocument)
+	var syntaxError *yaml.SyntaxError
+	if errors.As(composeDoc.ParsingError(), &syntaxError) {
+		return nil
+	}
+
+	file := composeDoc.File()
+	if file == nil {
+		return nil
+	}
+
+	var documentPath *document.DocumentPath
+	if path, err := composeDoc.DocumentPath(); err == nil {
+		documentPath = &path
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, documentNode := range file.Docs {
+		if mappingNode, ok := documentNode.Body.(*ast.MappingNode); ok {
+			diagnostics = append(diagnostics, validateExtendsCycles(source, documentPath, mappingNode)...)
+			diagnostics = append(diagnostics, validateBuildDockerfiles(source, documentPath, mappingNode)...)
+			diagnostics = append(diagnostics, validateIncludeCycle(source, composeDoc, documentPath, mappingNode)...)
+			diagnostics = append(diagnostics, validateServiceFiles(source, documentPath, mappingNode)...)
+			for _, validator := range deferrablePropertyValidators {
+				matchPropertyPath(validator.path, nil, mappingNode, func(key, value ast.Node) {
+					diagnostics = append(diagnostics, validator.validate(source, mappingNode, key, value)...)
+				})
+			}
+		}
+	}
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	return diagnostics
 }
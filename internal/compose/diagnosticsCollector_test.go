@@ -1,6 +1,7 @@
 package compose
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"os"
@@ -29,6 +30,7 @@ service:
 			diagnostics: []protocol.Diagnostic{
 				{
 					Message:  "found character '\t' that cannot start any token",
+					Code:     &protocol.IntegerOrString{Value: "InvalidYAML"},
 					Source:   types.CreateStringPointer("docker-language-server"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Range: protocol.Range{
@@ -38,6 +40,3389 @@ service:
 				},
 			},
 		},
+		{
+			name: "only the syntax error of a malformed service is reported",
+			content: `
+services:
+  web:
+    image: nginx
+  broken:
+    image: [nginx
+  db:
+    image: postgres`,
+			diagnostics: []protocol.Diagnostic{
+				{
+					Message:  "',' or ']' must be specified",
+					Code:     &protocol.IntegerOrString{Value: "InvalidYAML"},
+					Source:   types.CreateStringPointer("docker-language-server"),
+					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
+					Range: protocol.Range{
+						Start: protocol.Position{Line: 6, Character: 2},
+						End:   protocol.Position{Line: 6, Character: math.MaxUint32},
+					},
+				},
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_IntegerRanges(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid values are accepted",
+			content: `
+services:
+  test:
+    restart: on-failure:3
+    scale: 2
+    pids_limit: -1
+    oom_score_adj: -1000
+    deploy:
+      replicas: "3"
+      restart_policy:
+        max_attempts: 0`,
+			diagnostics: []protocol.Diagnostic{deprecatedScaleDiagnostic(4)},
+		},
+		{
+			name: "interpolated values are ignored",
+			content: `
+services:
+  test:
+    restart: on-failure:${RETRIES}
+    scale: ${SCALE}
+    oom_score_adj: "${SCORE:-0}"`,
+			diagnostics: []protocol.Diagnostic{deprecatedScaleDiagnostic(4)},
+		},
+		{
+			name: "escaped dollar signs are validated as literal values",
+			content: `
+services:
+  test:
+    restart: on-failure:$$1
+    scale: "$${SCALE}"`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("on-failure retry count must be a non-negative integer (found $1)", 3, 13, 27),
+				integerDiagnostic("scale must be a non-negative integer", 4, 12, 21),
+				deprecatedScaleDiagnostic(4),
+			},
+		},
+		{
+			name: "variables after an escaped dollar sign are ignored",
+			content: `
+services:
+  test:
+    scale: "$$${SCALE}"`,
+			diagnostics: []protocol.Diagnostic{deprecatedScaleDiagnostic(3)},
+		},
+		{
+			name: "lone dollar signs are ignored",
+			content: `
+services:
+  test:
+    scale: "1$"`,
+			diagnostics: []protocol.Diagnostic{deprecatedScaleDiagnostic(3)},
+		},
+		{
+			name: "restart without a count is accepted",
+			content: `
+services:
+  test:
+    restart: on-failure`,
+			diagnostics: nil,
+		},
+		{
+			name: "negative restart count",
+			content: `
+services:
+  test:
+    restart: on-failure:-1`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("on-failure retry count must be a non-negative integer (found -1)", 3, 13, 26),
+			},
+		},
+		{
+			name: "non-integer restart count in double quotes",
+			content: `
+services:
+  test:
+    restart: "on-failure:abc"`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("on-failure retry count must be a non-negative integer (found abc)", 3, 14, 28),
+			},
+		},
+		{
+			name: "negative scale",
+			content: `
+services:
+  test:
+    scale: -2`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("scale must be a non-negative integer", 3, 11, 13),
+				deprecatedScaleDiagnostic(3),
+			},
+		},
+		{
+			name: "pids_limit below -1",
+			content: `
+services:
+  test:
+    pids_limit: -2`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("pids_limit must be an integer greater than or equal to -1", 3, 16, 18),
+			},
+		},
+		{
+			name: "oom_score_adj out of range",
+			content: `
+services:
+  test:
+    oom_score_adj: 1001`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("oom_score_adj must be an integer between -1000 and 1000", 3, 19, 23),
+			},
+		},
+		{
+			name: "replicas as a float",
+			content: `
+services:
+  test:
+    deploy:
+      replicas: 1.5`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("replicas must be a non-negative integer", 4, 16, 19),
+			},
+		},
+		{
+			name: "max_attempts as a non-numeric string",
+			content: `
+services:
+  test:
+    deploy:
+      restart_policy:
+        max_attempts: abc`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("max_attempts must be a non-negative integer", 5, 22, 25),
+			},
+		},
+		{
+			name: "anchored service is validated",
+			content: `
+services:
+  test: &anchor
+    scale: -1`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("scale must be a non-negative integer", 3, 11, 13),
+				deprecatedScaleDiagnostic(3),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_Aliases(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "merging a mapping is accepted",
+			content: `
+x-base: &base
+  image: alpine
+services:
+  test:
+    <<: *base`,
+			diagnostics: nil,
+		},
+		{
+			name: "merging a sequence",
+			content: `
+x-list: &list
+  - alpine
+services:
+  test:
+    <<: *list`,
+			diagnostics: []protocol.Diagnostic{
+				aliasDiagnostic("merge key << requires a mapping but alias *list refers to a sequence", 5, 8, 13),
+			},
+		},
+		{
+			name: "merging a scalar",
+			content: `
+x-image: &image alpine
+services:
+  test:
+    <<: *image`,
+			diagnostics: []protocol.Diagnostic{
+				aliasDiagnostic("merge key << requires a mapping but alias *image refers to a scalar value", 4, 8, 14),
+			},
+		},
+		{
+			name: "merging a sequence of aliases only flags the incompatible ones",
+			content: `
+x-base: &base
+  image: alpine
+x-list: &list
+  - alpine
+services:
+  test:
+    <<: [*base, *list]`,
+			diagnostics: []protocol.Diagnostic{
+				aliasDiagnostic("merge key << requires a mapping but alias *list refers to a sequence", 7, 16, 21),
+			},
+		},
+		{
+			name: "aliasing a mapping where a string is expected",
+			content: `
+x-base: &base
+  image: alpine
+services:
+  test:
+    image: *base`,
+			diagnostics: []protocol.Diagnostic{
+				aliasDiagnostic("alias *base refers to a mapping but image expects a scalar value", 5, 11, 16),
+			},
+		},
+		{
+			name: "aliasing a scalar where a mapping is expected",
+			content: `
+x-value: &value abc
+services:
+  test:
+    deploy: *value`,
+			diagnostics: []protocol.Diagnostic{
+				aliasDiagnostic("alias *value refers to a scalar value but deploy expects a mapping", 4, 12, 18),
+			},
+		},
+		{
+			name: "aliasing a sequence where either a mapping or a sequence is accepted",
+			content: `
+x-env: &env
+  - A=B
+services:
+  test:
+    environment: *env`,
+			diagnostics: nil,
+		},
+		{
+			name: "aliasing a mapping where a sequence item is expected",
+			content: `
+x-base: &base
+  image: alpine
+services:
+  test:
+    dns_search:
+      - *base`,
+			diagnostics: []protocol.Diagnostic{
+				aliasDiagnostic("alias *base refers to a mapping but dns_search expects a scalar value", 6, 8, 13),
+			},
+		},
+		{
+			name: "aliases resolve to the closest preceding anchor",
+			content: `
+x-first: &value
+  image: alpine
+x-second: &value alpine
+services:
+  test:
+    image: *value`,
+			diagnostics: nil,
+		},
+		{
+			name: "extensions accept anything",
+			content: `
+x-base: &base
+  image: alpine
+services:
+  test:
+    x-custom: *base`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func aliasDiagnostic(message string, line, start, end protocol.UInteger) protocol.Diagnostic {
+	return validationDiagnostic("IncompatibleAlias", message, protocol.DiagnosticSeverityError, line, start, end)
+}
+
+func TestCollectDiagnostics_MountSources(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "compatible sources are accepted",
+			content: `
+services:
+  test:
+    volumes:
+      - type: volume
+        source: data
+        target: /data
+      - type: volume
+        target: /anonymous
+      - type: bind
+        source: ./src
+        target: /src
+      - type: tmpfs
+        target: /tmp
+volumes:
+  data:`,
+			diagnostics: nil,
+		},
+		{
+			name: "volume mount with a path",
+			content: `
+services:
+  test:
+    volumes:
+      - type: volume
+        source: ./data
+        target: /data`,
+			diagnostics: []protocol.Diagnostic{
+				mountDiagnostic("source of a volume mount must be a named volume but ./data is a path", 5, 16, 22),
+			},
+		},
+		{
+			name: "bind mount without a source",
+			content: `
+services:
+  test:
+    volumes:
+      - type: bind
+        target: /data`,
+			diagnostics: []protocol.Diagnostic{
+				mountDiagnostic("bind mount requires a source path on the host", 4, 14, 18),
+			},
+		},
+		{
+			name: "bind mount with a named volume",
+			content: `
+services:
+  test:
+    volumes:
+      - type: bind
+        source: data
+        target: /data
+volumes:
+  data:`,
+			diagnostics: []protocol.Diagnostic{
+				mountDiagnostic("source of a bind mount must be a path on the host but data is a named volume", 5, 16, 20),
+			},
+		},
+		{
+			name: "tmpfs mount with a source",
+			content: `
+services:
+  test:
+    volumes:
+      - type: tmpfs
+        source: data
+        target: /data`,
+			diagnostics: []protocol.Diagnostic{
+				mountDiagnostic("tmpfs mount must not have a source", 5, 8, 14),
+			},
+		},
+		{
+			name: "interpolated values are ignored",
+			content: `
+services:
+  test:
+    volumes:
+      - type: ${TYPE}
+        source: ./data
+      - type: volume
+        source: ${SOURCE}`,
+			diagnostics: nil,
+		},
+		{
+			name: "escaped dollar signs in a volume source",
+			content: `
+services:
+  test:
+    volumes:
+      - type: volume
+        source: ./$$HOME`,
+			diagnostics: []protocol.Diagnostic{
+				mountDiagnostic("source of a volume mount must be a named volume but ./$HOME is a path", 5, 16, 24),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func mismatchedMountOptionsDiagnostic(options, mountType string, line, start, lastLine protocol.UInteger, removable bool) protocol.Diagnostic {
+	message := fmt.Sprintf("%v options only apply to %v mounts and are ignored by this %v mount", options, options, mountType)
+	diagnostic := validationDiagnostic("MismatchedMountOptions", message, protocol.DiagnosticSeverityWarning, line, start, start+protocol.UInteger(len(options)))
+	if removable {
+		diagnostic.Data = []types.NamedEdit{
+			{
+				Title: fmt.Sprintf("Remove %v options", options),
+				Edit:  "",
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: line},
+					End:   protocol.Position{Line: lastLine},
+				},
+			},
+		}
+	}
+	return diagnostic
+}
+
+func TestCollectDiagnostics_VolumeModes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid modes",
+			content: `
+services:
+  web:
+    volumes:
+      - data:/data:ro
+      - ./src:/src:rw,z
+      - ./cache:/cache:cached
+      - /data
+      - logs:/logs`,
+			diagnostics: nil,
+		},
+		{
+			name: "unknown mode",
+			content: `
+services:
+  web:
+    volumes:
+      - data:/data:readonly`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidVolumeMode", `unknown volume mode "readonly"`, protocol.DiagnosticSeverityError, 4, 19, 27),
+			},
+		},
+		{
+			name: "unknown mode among several modes",
+			content: `
+services:
+  web:
+    volumes:
+      - "./src:/src:ro,x,Z"`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidVolumeMode", `unknown volume mode "x"`, protocol.DiagnosticSeverityError, 4, 23, 24),
+			},
+		},
+		{
+			name: "Windows host path",
+			content: `
+services:
+  web:
+    volumes:
+      - C:\data:/data:ro
+      - D:/logs:/logs:rx`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidVolumeMode", `unknown volume mode "rx"`, protocol.DiagnosticSeverityError, 5, 22, 24),
+			},
+		},
+		{
+			name: "Windows host and container paths",
+			content: `
+services:
+  web:
+    volumes:
+      - C:\data:C:\data:ro`,
+			diagnostics: nil,
+		},
+		{
+			name: "interpolated volume",
+			content: `
+services:
+  web:
+    volumes:
+      - ${DATA}:/data:${MODE}`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_MismatchedMountOptions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "options that match the type are accepted",
+			content: `
+services:
+  test:
+    volumes:
+      - type: volume
+        source: data
+        target: /data
+        volume:
+          nocopy: true
+      - type: bind
+        source: ./src
+        target: /src
+        bind:
+          create_host_path: true
+      - type: tmpfs
+        target: /tmp
+        tmpfs:
+          size: 1000
+volumes:
+  data:`,
+			diagnostics: nil,
+		},
+		{
+			name: "bind options on a volume mount",
+			content: `
+services:
+  test:
+    volumes:
+      - type: volume
+        source: data
+        target: /data
+        bind:
+          propagation: rshared
+volumes:
+  data:`,
+			diagnostics: []protocol.Diagnostic{
+				mismatchedMountOptionsDiagnostic("bind", "volume", 7, 8, 9, true),
+			},
+		},
+		{
+			name: "volume options on a bind mount",
+			content: `
+services:
+  test:
+    volumes:
+      - type: bind
+        source: ./src
+        target: /src
+        volume:
+          nocopy: true
+          subpath: app`,
+			diagnostics: []protocol.Diagnostic{
+				mismatchedMountOptionsDiagnostic("volume", "bind", 7, 8, 10, true),
+			},
+		},
+		{
+			name: "bind and tmpfs options on a volume mount",
+			content: `
+services:
+  test:
+    volumes:
+      - type: volume
+        tmpfs:
+          size: 1000
+        target: /data
+        bind:
+          propagation: rshared`,
+			diagnostics: []protocol.Diagnostic{
+				mismatchedMountOptionsDiagnostic("tmpfs", "volume", 5, 8, 7, true),
+				mismatchedMountOptionsDiagnostic("bind", "volume", 8, 8, 10, true),
+			},
+		},
+		{
+			name: "volume options on a bind mount in flow style",
+			content: `
+services:
+  test:
+    volumes:
+      - {type: bind, source: ./src, target: /src, volume: {nocopy: true}}`,
+			diagnostics: []protocol.Diagnostic{
+				mismatchedMountOptionsDiagnostic("volume", "bind", 4, 50, 5, false),
+			},
+		},
+		{
+			name: "interpolated type is ignored",
+			content: `
+services:
+  test:
+    volumes:
+      - type: ${TYPE}
+        source: data
+        target: /data
+        bind:
+          propagation: rshared`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+func TestCollectDiagnostics_Links(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "linked services that are defined",
+			content: `
+services:
+  web:
+    links:
+      - db
+      - cache:redis
+  db:
+    image: postgres
+  cache:
+    image: redis`,
+			diagnostics: nil,
+		},
+		{
+			name: "linked service that is not defined",
+			content: `
+services:
+  web:
+    links:
+      - db`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("UndefinedService", "linked service db could not be found in this file", protocol.DiagnosticSeverityError, 4, 8, 10),
+			},
+		},
+		{
+			name: "linked service with an alias that is not defined",
+			content: `
+services:
+  web:
+    links:
+      - "db:database"`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("UndefinedService", "linked service db could not be found in this file", protocol.DiagnosticSeverityError, 4, 9, 11),
+			},
+		},
+		{
+			name: "only the service of a link with an alias is flagged",
+			content: `
+services:
+  web:
+    links:
+      - missing:web`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("UndefinedService", "linked service missing could not be found in this file", protocol.DiagnosticSeverityError, 4, 8, 15),
+			},
+		},
+		{
+			name: "services from included files are not checked",
+			content: `
+include:
+  - other.yaml
+services:
+  web:
+    links:
+      - db`,
+			diagnostics: nil,
+		},
+		{
+			name: "interpolated links are ignored",
+			content: `
+services:
+  web:
+    links:
+      - ${SERVICE}`,
+			diagnostics: nil,
+		},
+		{
+			name: "escaped links are validated as literal values",
+			content: `
+services:
+  web:
+    links:
+      - $$db`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("UndefinedService", "linked service $db could not be found in this file", protocol.DiagnosticSeverityError, 4, 8, 12),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_ContainerNames(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "unique container names",
+			content: `
+services:
+  web:
+    container_name: web
+  db:
+    container_name: db`,
+			diagnostics: nil,
+		},
+		{
+			name: "duplicated container names",
+			content: `
+services:
+  web:
+    container_name: app
+  db:
+    container_name: "app"
+  cache:
+    container_name: app`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("DuplicateContainerName", "container name app is also used by db, cache", protocol.DiagnosticSeverityWarning, 3, 20, 23),
+				validationDiagnostic("DuplicateContainerName", "container name app is also used by web, cache", protocol.DiagnosticSeverityWarning, 5, 21, 24),
+				validationDiagnostic("DuplicateContainerName", "container name app is also used by web, db", protocol.DiagnosticSeverityWarning, 7, 20, 23),
+			},
+		},
+		{
+			name: "interpolated container names are ignored",
+			content: `
+services:
+  web:
+    container_name: ${NAME}
+  db:
+    container_name: ${NAME}`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_DeviceCgroupRules(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid rules",
+			content: `
+services:
+  web:
+    device_cgroup_rules:
+      - c 1:3 mr
+      - a *:* rwm
+      - "b 7:* w"
+      - ${RULE}`,
+			diagnostics: nil,
+		},
+		{
+			name: "invalid rules",
+			content: `
+services:
+  web:
+    device_cgroup_rules:
+      - c 1:3
+      - x 1:3 r
+      - c 1 r
+      - c 1:3 rx`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidDeviceCgroupRule", "device cgroup rule c 1:3 must be in the form TYPE MAJOR:MINOR PERMISSIONS", protocol.DiagnosticSeverityError, 4, 8, 13),
+				validationDiagnostic("InvalidDeviceCgroupRule", "invalid device type x in device cgroup rule (expected one of: a, b, c)", protocol.DiagnosticSeverityError, 5, 8, 15),
+				validationDiagnostic("InvalidDeviceCgroupRule", "invalid device numbers 1 in device cgroup rule (expected MAJOR:MINOR with numbers or *)", protocol.DiagnosticSeverityError, 6, 8, 13),
+				validationDiagnostic("InvalidDeviceCgroupRule", "invalid device permissions rx in device cgroup rule (expected a combination of r, w, m)", protocol.DiagnosticSeverityError, 7, 8, 16),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_IPAddresses(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid addresses",
+			content: `
+services:
+  web:
+    image: nginx
+    extra_hosts:
+      - somehost=162.242.195.82
+      - otherhost:50.31.209.229
+      - myhostv6=::1
+      - bracketed=[fe80::1]
+      - host.docker.internal:host-gateway
+    ports:
+      - "127.0.0.1:8080:80"
+      - "[::1]:8443:443"
+      - target: 80
+        host_ip: 2001:db8::1
+networks:
+  backend:
+    ipam:
+      config:
+        - subnet: 172.28.0.0/16
+          ip_range: 172.28.5.0/24
+          gateway: 172.28.5.254
+          aux_addresses:
+            host1: 172.28.1.5
+        - subnet: 2001:db8:abcd::/64`,
+			diagnostics: nil,
+		},
+		{
+			name: "invalid extra_hosts addresses in the list form",
+			content: `
+services:
+  web:
+    image: nginx
+    extra_hosts:
+      - somehost=162.242.195.300
+      - "otherhost:localhost"
+      - host.docker.internal=host_gateway`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidIPAddress", "162.242.195.300 is not a valid IP address", protocol.DiagnosticSeverityError, 5, 17, 32),
+				validationDiagnostic("InvalidIPAddress", "localhost is not a valid IP address", protocol.DiagnosticSeverityError, 6, 19, 28),
+				validationDiagnostic("InvalidIPAddress", "host_gateway is not a valid IP address", protocol.DiagnosticSeverityError, 7, 29, 41),
+			},
+		},
+		{
+			name: "invalid extra_hosts addresses in the mapping form",
+			content: `
+services:
+  web:
+    image: nginx
+    extra_hosts:
+      somehost: 10.0.0.256
+      multiple:
+        - "::1"
+        - ::g
+      gateway: host-gateway`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidIPAddress", "10.0.0.256 is not a valid IP address", protocol.DiagnosticSeverityError, 5, 16, 26),
+				validationDiagnostic("InvalidIPAddress", "::g is not a valid IP address", protocol.DiagnosticSeverityError, 8, 10, 13),
+			},
+		},
+		{
+			name: "invalid port host addresses",
+			content: `
+services:
+  web:
+    image: nginx
+    ports:
+      - "127.0.0.300:8080:80"
+      - target: 80
+        host_ip: localhost`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidIPAddress", "127.0.0.300 is not a valid IP address", protocol.DiagnosticSeverityError, 5, 9, 20),
+				validationDiagnostic("InvalidIPAddress", "localhost is not a valid IP address", protocol.DiagnosticSeverityError, 7, 17, 26),
+			},
+		},
+		{
+			name: "invalid ipam configuration",
+			content: `
+networks:
+  backend:
+    ipam:
+      config:
+        - subnet: 172.28.0.0/33
+          ip_range: 172.28.5.0
+          gateway: 172.28.5.254/24
+          aux_addresses:
+            host1: 2001:db8:::1`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidSubnet", "172.28.0.0/33 is not a valid subnet in CIDR notation", protocol.DiagnosticSeverityError, 5, 18, 31),
+				validationDiagnostic("InvalidSubnet", "172.28.5.0 is not a valid subnet in CIDR notation", protocol.DiagnosticSeverityError, 6, 20, 30),
+				validationDiagnostic("InvalidIPAddress", "172.28.5.254/24 is not a valid IP address", protocol.DiagnosticSeverityError, 7, 19, 34),
+				validationDiagnostic("InvalidIPAddress", "2001:db8:::1 is not a valid IP address", protocol.DiagnosticSeverityError, 9, 19, 31),
+			},
+		},
+		{
+			name: "interpolated addresses are ignored",
+			content: `
+services:
+  web:
+    image: nginx
+    extra_hosts:
+      - somehost=${HOST_IP}
+    ports:
+      - "${BIND_ADDRESS}:8080:80"`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func conflictingEnvFileDiagnostic(path string, required bool, startLine, start, endLine, end protocol.UInteger) protocol.Diagnostic {
+	message := fmt.Sprintf("env file %v is declared with required: %v but an earlier entry declares it with required: %v", path, required, !required)
+	diagnostic := validationDiagnostic("ConflictingEnvFile", message, protocol.DiagnosticSeverityWarning, startLine, start, end)
+	diagnostic.Range.End.Line = endLine
+	return diagnostic
+}
+
+func TestCollectDiagnostics_EnvFiles(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "same flags are not reported",
+			content: `
+services:
+  web:
+    image: nginx
+    env_file:
+      - .env
+      - path: ./.env
+        required: true
+      - path: other.env
+        required: false
+      - path: other.env
+        required: "false"`,
+			diagnostics: nil,
+		},
+		{
+			name: "string entry conflicts with an optional object entry",
+			content: `
+services:
+  web:
+    image: nginx
+    env_file:
+      - .env
+      - path: ./.env
+        required: false`,
+			diagnostics: []protocol.Diagnostic{
+				conflictingEnvFileDiagnostic(".env", false, 6, 8, 7, 23),
+			},
+		},
+		{
+			name: "optional object entry followed by a string entry",
+			content: `
+services:
+  web:
+    image: nginx
+    env_file:
+      - path: default.env
+        required: false
+      - default.env`,
+			diagnostics: []protocol.Diagnostic{
+				conflictingEnvFileDiagnostic("default.env", true, 7, 8, 7, 19),
+			},
+		},
+		{
+			name: "interpolated entries are ignored",
+			content: `
+services:
+  web:
+    image: nginx
+    env_file:
+      - path: .env
+        required: false
+      - path: .env
+        required: ${REQUIRED}
+      - path: ${ENV_FILE}`,
+			diagnostics: nil,
+		},
+	}
+
+	folder := t.TempDir()
+	for _, name := range []string{".env", "other.env", "default.env"} {
+		require.NoError(t, os.WriteFile(filepath.Join(folder, name), []byte{}, 0644))
+	}
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func fileNotFoundDiagnostic(description, path string, line, start protocol.UInteger) protocol.Diagnostic {
+	return validationDiagnostic("FileNotFound", fmt.Sprintf("%v %v could not be found", description, path), protocol.DiagnosticSeverityError, line, start, start+protocol.UInteger(len(path)))
+}
+
+func TestCollectDiagnostics_ServiceFiles(t *testing.T) {
+	folder := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "app.properties"), []byte{}, 0644))
+
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics func(attribute, description string) []protocol.Diagnostic
+	}{
+		{
+			name: "existing file",
+			content: `
+services:
+  web:
+    image: nginx
+    %v: app.properties`,
+			diagnostics: func(attribute, description string) []protocol.Diagnostic { return nil },
+		},
+		{
+			name: "missing file",
+			content: `
+services:
+  web:
+    image: nginx
+    %v: missing.properties`,
+			diagnostics: func(attribute, description string) []protocol.Diagnostic {
+				return []protocol.Diagnostic{fileNotFoundDiagnostic(description, "missing.properties", 4, protocol.UInteger(len(attribute)+6))}
+			},
+		},
+		{
+			name: "missing file in double quotes",
+			content: `
+services:
+  web:
+    image: nginx
+    %v: "missing.properties"`,
+			diagnostics: func(attribute, description string) []protocol.Diagnostic {
+				return []protocol.Diagnostic{fileNotFoundDiagnostic(description, "missing.properties", 4, protocol.UInteger(len(attribute)+7))}
+			},
+		},
+		{
+			name: "list with an existing and a missing file",
+			content: `
+services:
+  web:
+    image: nginx
+    %v:
+      - ./app.properties
+      - config/missing.properties`,
+			diagnostics: func(attribute, description string) []protocol.Diagnostic {
+				return []protocol.Diagnostic{fileNotFoundDiagnostic(description, "config/missing.properties", 6, 8)}
+			},
+		},
+		{
+			name: "absolute path to an existing file",
+			content: `
+services:
+  web:
+    image: nginx
+    %v: ` + filepath.ToSlash(filepath.Join(folder, "app.properties")),
+			diagnostics: func(attribute, description string) []protocol.Diagnostic { return nil },
+		},
+		{
+			name: "interpolated paths are ignored",
+			content: `
+services:
+  web:
+    image: nginx
+    %v:
+      - ${FILE}
+      - $FOLDER/missing.properties`,
+			diagnostics: func(attribute, description string) []protocol.Diagnostic { return nil },
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		for _, serviceFile := range serviceFileAttributes {
+			t.Run(fmt.Sprintf("%v (%v)", tc.name, serviceFile.attribute), func(t *testing.T) {
+				collector := NewComposeDeferrableDiagnosticsCollector()
+				doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(fmt.Sprintf(tc.content, serviceFile.attribute)))
+				diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+				require.Equal(t, tc.diagnostics(serviceFile.attribute, serviceFile.description), diagnostics)
+			})
+		}
+	}
+}
+
+func TestCollectDiagnostics_EnvFileRequiredFlag(t *testing.T) {
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(t.TempDir(), "compose.yaml")), "/")))
+	content := `
+services:
+  web:
+    image: nginx
+    env_file:
+      - path: optional.env
+        required: false
+      - path: required.env`
+	collector := NewComposeDeferrableDiagnosticsCollector()
+	doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(content))
+	diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+	require.Equal(t, []protocol.Diagnostic{fileNotFoundDiagnostic("env file", "required.env", 7, 14)}, diagnostics)
+}
+func TestCollectDiagnostics_EnvironmentEntries(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid entries",
+			content: `
+services:
+  web:
+    environment:
+      - KEY=value
+      - MESSAGE=hello world
+      - PASSTHROUGH
+      - EMPTY=`,
+			diagnostics: nil,
+		},
+		{
+			name: "mapping form is ignored",
+			content: `
+services:
+  web:
+    environment:
+      KEY value: value`,
+			diagnostics: nil,
+		},
+		{
+			name: "entry without an equals sign",
+			content: `
+services:
+  web:
+    environment:
+      - KEY value`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("MalformedEnvironmentEntry", "environment entry KEY value contains whitespace but no equals sign (expected KEY=value or KEY)", protocol.DiagnosticSeverityWarning, 4, 8, 17),
+			},
+		},
+		{
+			name: "variable name with whitespace",
+			content: `
+services:
+  web:
+    environment:
+      - "MY KEY=value"`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("MalformedEnvironmentEntry", "environment variable name MY KEY must not contain whitespace", protocol.DiagnosticSeverityWarning, 4, 9, 21),
+			},
+		},
+		{
+			name: "empty entry and missing variable name",
+			content: `
+services:
+  web:
+    environment:
+      - ""
+      - =value`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("MalformedEnvironmentEntry", "environment entry must not be empty", protocol.DiagnosticSeverityWarning, 4, 9, 9),
+				validationDiagnostic("MalformedEnvironmentEntry", "environment entry =value is missing a variable name", protocol.DiagnosticSeverityWarning, 5, 8, 14),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_ExtendsCycles(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "extends chain without a cycle",
+			content: `
+services:
+  a:
+    extends: b
+  b:
+    extends: c
+  c:
+    image: alpine`,
+			diagnostics: nil,
+		},
+		{
+			name: "service extends itself",
+			content: `
+services:
+  a:
+    extends: a`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("CircularExtends", "service a extends itself through the cycle a -> a", protocol.DiagnosticSeverityError, 3, 13, 14),
+			},
+		},
+		{
+			name: "two services extending each other",
+			content: `
+services:
+  a:
+    extends: b
+  b:
+    extends:
+      service: a`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("CircularExtends", "service a extends itself through the cycle a -> b -> a", protocol.DiagnosticSeverityError, 3, 13, 14),
+				validationDiagnostic("CircularExtends", "service b extends itself through the cycle b -> a -> b", protocol.DiagnosticSeverityError, 6, 15, 16),
+			},
+		},
+		{
+			name: "service extending a cycle is not a part of it",
+			content: `
+services:
+  a:
+    extends: b
+  b:
+    extends: c
+  c:
+    extends: b`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("CircularExtends", "service b extends itself through the cycle b -> c -> b", protocol.DiagnosticSeverityError, 5, 13, 14),
+				validationDiagnostic("CircularExtends", "service c extends itself through the cycle c -> b -> c", protocol.DiagnosticSeverityError, 7, 13, 14),
+			},
+		},
+		{
+			name: "file attribute pointing to the same file",
+			content: `
+services:
+  a:
+    extends:
+      file: ./compose.yaml
+      service: a`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("CircularExtends", "service a extends itself through the cycle a -> a", protocol.DiagnosticSeverityError, 5, 15, 16),
+			},
+		},
+		{
+			name: "file attribute pointing to another file",
+			content: `
+services:
+  a:
+    extends:
+      file: other.yaml
+      service: a`,
+			diagnostics: nil,
+		},
+		{
+			name: "interpolated service names are ignored",
+			content: `
+services:
+  a:
+    extends: ${SERVICE}`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDeferrableDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_IncludeCycles(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		includedFiles map[string]string
+		diagnostics   []protocol.Diagnostic
+	}{
+		{
+			name: "included file without a cycle",
+			content: `
+include:
+  - compose.first.yaml`,
+			includedFiles: map[string]string{
+				"compose.first.yaml":  "include:\n  - compose.second.yaml",
+				"compose.second.yaml": "services:\n  web:\n    image: nginx",
+			},
+			diagnostics: nil,
+		},
+		{
+			name: "two files including each other",
+			content: `
+include:
+  - compose.other.yaml`,
+			includedFiles: map[string]string{
+				"compose.other.yaml": "include:\n  - compose.yaml",
+			},
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("CircularInclude", "compose.other.yaml is included through the cycle compose.yaml -> compose.other.yaml -> compose.yaml", protocol.DiagnosticSeverityError, 2, 4, 22),
+			},
+		},
+		{
+			name: "file including itself",
+			content: `
+include:
+  - path: compose.yaml`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("CircularInclude", "compose.yaml is included through the cycle compose.yaml -> compose.yaml", protocol.DiagnosticSeverityError, 2, 10, 22),
+			},
+		},
+		{
+			name: "cycle between the included files",
+			content: `
+include:
+  - compose.first.yaml
+  - compose.second.yaml`,
+			includedFiles: map[string]string{
+				"compose.first.yaml":  "include:\n  - compose.second.yaml",
+				"compose.second.yaml": "include:\n  - compose.first.yaml",
+			},
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("CircularInclude", "compose.first.yaml is included through the cycle compose.yaml -> compose.first.yaml -> compose.second.yaml -> compose.first.yaml", protocol.DiagnosticSeverityError, 2, 4, 22),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := document.NewDocumentManager()
+			for name, content := range tc.includedFiles {
+				u := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), name)), "/")))
+				changed, err := mgr.Write(context.Background(), u, protocol.DockerComposeLanguage, 1, []byte(content))
+				require.NoError(t, err)
+				require.True(t, changed)
+			}
+			collector := NewComposeDeferrableDiagnosticsCollector()
+			doc := document.NewComposeDocument(mgr, composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_BuildDockerfiles(t *testing.T) {
+	folder := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(folder, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "app", "Dockerfile.dev"), []byte("FROM scratch"), 0644))
+
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "Dockerfile exists in the build context",
+			content: `
+services:
+  web:
+    build:
+      context: ./app
+      dockerfile: Dockerfile.dev`,
+			diagnostics: nil,
+		},
+		{
+			name: "Dockerfile does not exist in the build context",
+			content: `
+services:
+  web:
+    build:
+      context: app
+      dockerfile: "Dockerfile.prod"`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("DockerfileNotFound", "Dockerfile Dockerfile.prod could not be found in the build context app", protocol.DiagnosticSeverityError, 5, 19, 34),
+			},
+		},
+		{
+			name: "Dockerfile is relative to the build context and not the Compose file",
+			content: `
+services:
+  web:
+    build:
+      context: .
+      dockerfile: Dockerfile.dev`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("DockerfileNotFound", "Dockerfile Dockerfile.dev could not be found in the build context .", protocol.DiagnosticSeverityError, 5, 18, 32),
+			},
+		},
+		{
+			name: "build context that does not exist is not checked",
+			content: `
+services:
+  web:
+    build:
+      context: ./missing
+      dockerfile: Dockerfile`,
+			diagnostics: nil,
+		},
+		{
+			name: "remote build context is not checked",
+			content: `
+services:
+  web:
+    build:
+      context: https://github.com/docker/compose.git
+      dockerfile: Dockerfile.prod`,
+			diagnostics: nil,
+		},
+		{
+			name: "interpolated Dockerfile is not checked",
+			content: `
+services:
+  web:
+    build:
+      context: app
+      dockerfile: ${DOCKERFILE}`,
+			diagnostics: nil,
+		},
+		{
+			name: "dockerfile and dockerfile_inline are both set",
+			content: `
+services:
+  web:
+    build:
+      dockerfile: Dockerfile
+      dockerfile_inline: FROM scratch`,
+			diagnostics: []protocol.Diagnostic{
+				conflictingDockerfilesDiagnostic("dockerfile_inline", "dockerfile", 5, 6, 23, 4, 5, 5, 6),
+			},
+		},
+		{
+			name: "multiline dockerfile_inline before dockerfile",
+			content: `
+services:
+  web:
+    build:
+      dockerfile_inline: |
+        FROM scratch
+        COPY . /app
+      dockerfile: Dockerfile
+      context: .`,
+			diagnostics: []protocol.Diagnostic{
+				conflictingDockerfilesDiagnostic("dockerfile", "dockerfile_inline", 7, 6, 16, 7, 8, 4, 7),
+			},
+		},
+		{
+			name: "conflicting attributes in flow style cannot be removed",
+			content: `
+services:
+  web:
+    build: { dockerfile: Dockerfile, dockerfile_inline: FROM scratch }`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("ConflictingDockerfile", "dockerfile_inline must not be set when dockerfile is used", protocol.DiagnosticSeverityError, 3, 37, 54),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := NewComposeDiagnosticsCollector().CollectDiagnostics("docker-language-server", "", doc, "")
+			diagnostics = append(diagnostics, NewComposeDeferrableDiagnosticsCollector().CollectDiagnostics("docker-language-server", "", doc, "")...)
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_ResourceSources(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "configs and secrets with sources",
+			content: `
+configs:
+  file:
+    file: ./config.txt
+  environment:
+    environment: CONFIG
+  content:
+    content: value
+  external:
+    external: true
+secrets:
+  file:
+    file: ./secret.txt
+  environment:
+    environment: SECRET
+  external:
+    external: true`,
+			diagnostics: nil,
+		},
+		{
+			name: "config without a source",
+			content: `
+configs:
+  config:
+    name: my-config`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("MissingResourceSource", "config config must set one of file, environment, content, external", protocol.DiagnosticSeverityError, 2, 2, 8),
+			},
+		},
+		{
+			name: "secret without any attributes",
+			content: `
+secrets:
+  secret:`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("MissingResourceSource", "secret secret must set one of file, environment, external", protocol.DiagnosticSeverityError, 2, 2, 8),
+			},
+		},
+		{
+			name: "secret with content is not a valid source",
+			content: `
+secrets:
+  secret:
+    content: value`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("MissingResourceSource", "secret secret must set one of file, environment, external", protocol.DiagnosticSeverityError, 2, 2, 8),
+			},
+		},
+		{
+			name: "source may come from a merge key",
+			content: `
+x-config: &config
+  file: ./config.txt
+configs:
+  config:
+    <<: *config`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_AdditionalContexts(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "additional contexts of services that are defined",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: service:db
+        stage: target:builder
+        image: docker-image://alpine
+  db:
+    image: postgres`,
+			diagnostics: nil,
+		},
+		{
+			name: "additional context of a service that is not defined",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: service:db`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("UndefinedService", "additional context service db could not be found in this file", protocol.DiagnosticSeverityError, 5, 22, 24),
+			},
+		},
+		{
+			name: "quoted additional context of a service that is not defined",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: "service:db"`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("UndefinedService", "additional context service db could not be found in this file", protocol.DiagnosticSeverityError, 5, 23, 25),
+			},
+		},
+		{
+			name: "list form additional context of a service that is not defined",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        - base=service:db`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("UndefinedService", "additional context service db could not be found in this file", protocol.DiagnosticSeverityError, 5, 23, 25),
+			},
+		},
+		{
+			name: "shared namespaces of services that are defined",
+			content: `
+services:
+  web:
+    ipc: service:db
+    network_mode: "service:db"
+    pid: service:db
+  db:
+    image: postgres
+    ipc: shareable
+    pid: host`,
+			diagnostics: nil,
+		},
+		{
+			name: "shared namespaces of services that are not defined",
+			content: `
+services:
+  web:
+    ipc: service:db
+    network_mode: "service:db"
+    pid: service:cache`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("UndefinedService", "ipc service db could not be found in this file", protocol.DiagnosticSeverityError, 3, 17, 19),
+				validationDiagnostic("UndefinedService", "network_mode service db could not be found in this file", protocol.DiagnosticSeverityError, 4, 27, 29),
+				validationDiagnostic("UndefinedService", "pid service cache could not be found in this file", protocol.DiagnosticSeverityError, 5, 17, 22),
+			},
+		},
+		{
+			name: "services from included files are not checked",
+			content: `
+include:
+  - other.yaml
+services:
+  web:
+    build:
+      additional_contexts:
+        base: service:db`,
+			diagnostics: nil,
+		},
+		{
+			name: "interpolated additional contexts are ignored",
+			content: `
+services:
+  web:
+    build:
+      additional_contexts:
+        base: service:${SERVICE}`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_CacheSpecs(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid cache specifications",
+			content: `
+services:
+  test:
+    build:
+      context: .
+      cache_from:
+        - alpine:3.21
+        - type=registry,ref=user/app:cache
+        - type=local,src=/tmp/cache
+        - type=gha
+        - ${CACHE}
+      cache_to:
+        - user/app:cache
+        - type=inline
+        - type=local,dest=/tmp/cache
+        - type=s3,region=us-east-1,bucket=cache`,
+			diagnostics: nil,
+		},
+		{
+			name: "unknown cache type",
+			content: `
+services:
+  test:
+    build:
+      cache_from:
+        - type=unknown,ref=user/app:cache`,
+			diagnostics: []protocol.Diagnostic{
+				cacheDiagnostic("unknown cache type unknown (expected one of: registry, local, gha, s3, azblob)", 5, 15, 22),
+			},
+		},
+		{
+			name: "inline cache cannot be imported",
+			content: `
+services:
+  test:
+    build:
+      cache_from:
+        - "type=inline"`,
+			diagnostics: []protocol.Diagnostic{
+				cacheDiagnostic("cache type inline can only be used in cache_to", 5, 16, 22),
+			},
+		},
+		{
+			name: "missing type",
+			content: `
+services:
+  test:
+    build:
+      cache_to:
+        - ref=user/app:cache`,
+			diagnostics: []protocol.Diagnostic{
+				cacheDiagnostic("cache specification must set a type", 5, 10, 28),
+			},
+		},
+		{
+			name: "registry cache without a ref",
+			content: `
+services:
+  test:
+    build:
+      cache_from:
+        - 'type=registry'`,
+			diagnostics: []protocol.Diagnostic{
+				cacheDiagnostic("cache type registry requires the ref parameter", 5, 11, 24),
+			},
+		},
+		{
+			name: "local cache exported without a destination",
+			content: `
+services:
+  test:
+    build:
+      cache_to:
+        - type=local,src=/tmp/cache`,
+			diagnostics: []protocol.Diagnostic{
+				cacheDiagnostic("cache type local requires the dest parameter", 5, 10, 35),
+			},
+		},
+		{
+			name: "s3 cache with missing parameters",
+			content: `
+services:
+  test:
+    build:
+      cache_to:
+        - type=s3`,
+			diagnostics: []protocol.Diagnostic{
+				cacheDiagnostic("cache type s3 requires the region parameter", 5, 10, 17),
+				cacheDiagnostic("cache type s3 requires the bucket parameter", 5, 10, 17),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_MountOptions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid modes and owners",
+			content: `
+services:
+  test:
+    configs:
+      - source: config
+        mode: 0444
+        uid: "1000"
+        gid: "1000"
+      - source: other
+        mode: "0o440"
+    secrets:
+      - source: secret
+        mode: 288
+      - source: other
+        mode: ${MODE}
+    volumes:
+      - type: tmpfs
+        target: /tmp
+        tmpfs:
+          mode: "1777"`,
+			diagnostics: nil,
+		},
+		{
+			name: "octal mode with an invalid digit",
+			content: `
+services:
+  test:
+    configs:
+      - source: config
+        mode: 0999`,
+			diagnostics: []protocol.Diagnostic{
+				fileModeDiagnostic("0999", 5, 14, 18),
+			},
+		},
+		{
+			name: "string mode with an invalid digit",
+			content: `
+services:
+  test:
+    secrets:
+      - source: secret
+        mode: "0448"`,
+			diagnostics: []protocol.Diagnostic{
+				fileModeDiagnostic("0448", 5, 15, 19),
+			},
+		},
+		{
+			name: "mode that is too large",
+			content: `
+services:
+  test:
+    secrets:
+      - source: secret
+        mode: 010000`,
+			diagnostics: []protocol.Diagnostic{
+				fileModeDiagnostic("010000", 5, 14, 20),
+			},
+		},
+		{
+			name: "fractional mode",
+			content: `
+services:
+  test:
+    configs:
+      - source: config
+        mode: 4.4`,
+			diagnostics: []protocol.Diagnostic{
+				fileModeDiagnostic("4.4", 5, 14, 17),
+			},
+		},
+		{
+			name: "non-numeric uid and negative gid",
+			content: `
+services:
+  test:
+    configs:
+      - source: config
+        uid: root
+        gid: "-1"`,
+			diagnostics: []protocol.Diagnostic{
+				integerDiagnostic("uid must be a non-negative integer", 5, 13, 17),
+				integerDiagnostic("gid must be a non-negative integer", 6, 14, 16),
+			},
+		},
+		{
+			name: "valid read_only and propagation",
+			content: `
+services:
+  test:
+    volumes:
+      - type: bind
+        source: ./data
+        target: /data
+        read_only: "true"
+        bind:
+          propagation: rslave
+      - type: bind
+        source: ./cache
+        target: /cache
+        read_only: false
+        bind:
+          propagation: ${PROPAGATION}`,
+			diagnostics: nil,
+		},
+		{
+			name: "invalid read_only and propagation",
+			content: `
+services:
+  test:
+    volumes:
+      - type: bind
+        source: ./data
+        target: /data
+        read_only: "yes"
+        bind:
+          propagation: public`,
+			diagnostics: []protocol.Diagnostic{
+				enumDiagnostic("invalid read_only value yes (expected one of: true, false)", 7, 20, 23),
+				enumDiagnostic("invalid propagation value public (expected one of: shared, slave, private, rshared, rslave, rprivate)", 9, 23, 29),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func fileModeDiagnostic(mode string, line, start, end protocol.UInteger) protocol.Diagnostic {
+	return validationDiagnostic("InvalidFileMode", fmt.Sprintf("mode must be an octal permission mode between 0000 and 7777 (found %v)", mode), protocol.DiagnosticSeverityWarning, line, start, end)
+}
+
+func enumDiagnostic(message string, line, start, end protocol.UInteger) protocol.Diagnostic {
+	return validationDiagnostic("InvalidEnumValue", message, protocol.DiagnosticSeverityWarning, line, start, end)
+}
+
+func cacheDiagnostic(message string, line, start, end protocol.UInteger) protocol.Diagnostic {
+	return validationDiagnostic("InvalidCacheSpec", message, protocol.DiagnosticSeverityWarning, line, start, end)
+}
+
+func mountDiagnostic(message string, line, start, end protocol.UInteger) protocol.Diagnostic {
+	return validationDiagnostic("MountSourceMismatch", message, protocol.DiagnosticSeverityError, line, start, end)
+}
+
+func deprecatedScaleDiagnostic(line protocol.UInteger) protocol.Diagnostic {
+	diagnostic := validationDiagnostic("DeprecatedScale", "scale is deprecated, use deploy.replicas instead", protocol.DiagnosticSeverityInformation, line, 4, 9)
+	diagnostic.Tags = []protocol.DiagnosticTag{protocol.DiagnosticTagDeprecated}
+	return diagnostic
+}
+
+func integerDiagnostic(message string, line, start, end protocol.UInteger) protocol.Diagnostic {
+	return validationDiagnostic("InvalidIntegerValue", message, protocol.DiagnosticSeverityError, line, start, end)
+}
+
+func validationDiagnostic(code, message string, severity protocol.DiagnosticSeverity, line, start, end protocol.UInteger) protocol.Diagnostic {
+	return protocol.Diagnostic{
+		Message:  message,
+		Code:     &protocol.IntegerOrString{Value: code},
+		Source:   types.CreateStringPointer("docker-language-server"),
+		Severity: types.CreateDiagnosticSeverityPointer(severity),
+		Range: protocol.Range{
+			Start: protocol.Position{Line: line, Character: start},
+			End:   protocol.Position{Line: line, Character: end},
+		},
+	}
+}
+
+func TestCollectDiagnostics_ResourceNames(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid names",
+			content: `
+services:
+  web-app_1.0:
+    image: nginx
+networks:
+  Backend:
+volumes:
+  data.01:`,
+			diagnostics: nil,
+		},
+		{
+			name: "service name with a space",
+			content: `
+services:
+  "web app":
+    image: nginx`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidResourceName", `service name "web app" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 2, 3, 10),
+			},
+		},
+		{
+			name: "service name with a trailing space",
+			content: `
+services:
+  "web ":
+    image: nginx`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidResourceName", `service name "web " must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 2, 3, 7),
+			},
+		},
+		{
+			name: "anchored service name is validated on its name",
+			content: `
+services:
+  &anchor web/app:
+    image: nginx`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidResourceName", `service name "web/app" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 2, 10, 17),
+			},
+		},
+		{
+			name: "top-level resources with invalid names",
+			content: `
+networks:
+  front:end:
+volumes:
+  dätä:
+configs:
+  config#1:
+    file: ./config.txt
+secrets:
+  secret!:
+    file: ./secret.txt
+models:
+  my@model:
+    model: ai/model`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidResourceName", `network name "front:end" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 2, 2, 11),
+				validationDiagnostic("InvalidResourceName", `volume name "dätä" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 4, 2, 8),
+				validationDiagnostic("InvalidResourceName", `config name "config#1" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 6, 2, 10),
+				validationDiagnostic("InvalidResourceName", `secret name "secret!" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 9, 2, 9),
+				validationDiagnostic("InvalidResourceName", `model name "my@model" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 12, 2, 10),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_Profiles(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid profiles",
+			content: `
+services:
+  web:
+    image: nginx
+    profiles:
+      - debug
+      - front-end_1.0`,
+			diagnostics: nil,
+		},
+		{
+			name: "profile with invalid characters",
+			content: `
+services:
+  web:
+    image: nginx
+    profiles:
+      - "front end"
+      - debug!`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidResourceName", `profile name "front end" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 5, 9, 18),
+				validationDiagnostic("InvalidResourceName", `profile name "debug!" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 6, 8, 14),
+			},
+		},
+		{
+			name: "profile in the flow style",
+			content: `
+services:
+  web:
+    image: nginx
+    profiles: [debug, "*"]`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidResourceName", `profile name "*" must only contain letters, digits, periods, underscores, and hyphens`, protocol.DiagnosticSeverityError, 4, 23, 24),
+			},
+		},
+		{
+			name: "interpolated profile is ignored",
+			content: `
+services:
+  web:
+    image: nginx
+    profiles:
+      - ${PROFILE}`,
+			diagnostics: nil,
+		},
+		{
+			name: "profile with the name of a service",
+			content: `
+services:
+  web:
+    image: nginx
+    profiles:
+      - db
+  db:
+    image: postgres
+    profiles:
+      - db`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("ProfileNameConflict", "profile db has the same name as a service", protocol.DiagnosticSeverityWarning, 5, 8, 10),
+				validationDiagnostic("ProfileNameConflict", "profile db has the same name as a service", protocol.DiagnosticSeverityWarning, 9, 8, 10),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func redundantExposeDiagnostic(port string, line, start, end protocol.UInteger, removable bool) protocol.Diagnostic {
+	diagnostic := validationDiagnostic("RedundantExpose", fmt.Sprintf("port %v is already published by ports and does not need to be exposed", port), protocol.DiagnosticSeverityHint, line, start, end)
+	diagnostic.Tags = []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary}
+	if removable {
+		diagnostic.Data = []types.NamedEdit{
+			{
+				Title: "Remove redundant expose entry",
+				Edit:  "",
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: line},
+					End:   protocol.Position{Line: line + 1},
+				},
+			},
+		}
+	}
+	return diagnostic
+}
+
+func redundantCapabilitiesDiagnostic(attribute string, line, start, end, lastLine protocol.UInteger, removable bool) protocol.Diagnostic {
+	diagnostic := validationDiagnostic("RedundantCapabilities", fmt.Sprintf("%v has no effect as privileged already grants all capabilities to the container", attribute), protocol.DiagnosticSeverityHint, line, start, end)
+	diagnostic.Tags = []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary}
+	if removable {
+		diagnostic.Data = []types.NamedEdit{
+			{
+				Title: fmt.Sprintf("Remove redundant %v", attribute),
+				Edit:  "",
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: line},
+					End:   protocol.Position{Line: lastLine},
+				},
+			},
+		}
+	}
+	return diagnostic
+}
+
+func TestCollectDiagnostics_PrivilegedCapabilities(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "capabilities without privileged",
+			content: `
+services:
+  web:
+    cap_add:
+      - NET_ADMIN`,
+			diagnostics: nil,
+		},
+		{
+			name: "capabilities of a service that is not privileged",
+			content: `
+services:
+  web:
+    privileged: false
+    cap_add:
+      - NET_ADMIN`,
+			diagnostics: nil,
+		},
+		{
+			name: "cap_add and cap_drop of a privileged service",
+			content: `
+services:
+  web:
+    privileged: true
+    cap_add:
+      - NET_ADMIN
+      - SYS_TIME
+    cap_drop: [ALL]
+    image: alpine`,
+			diagnostics: []protocol.Diagnostic{
+				redundantCapabilitiesDiagnostic("cap_add", 4, 4, 11, 7, true),
+				redundantCapabilitiesDiagnostic("cap_drop", 7, 4, 12, 8, true),
+			},
+		},
+		{
+			name: "privileged as a string",
+			content: `
+services:
+  web:
+    privileged: "true"
+    cap_drop:
+      - ALL`,
+			diagnostics: []protocol.Diagnostic{
+				redundantCapabilitiesDiagnostic("cap_drop", 4, 4, 12, 6, true),
+			},
+		},
+		{
+			name: "privileged as a string that is false",
+			content: `
+services:
+  web:
+    privileged: "0"
+    cap_drop:
+      - ALL`,
+			diagnostics: nil,
+		},
+		{
+			name: "interpolated privileged flag",
+			content: `
+services:
+  web:
+    privileged: ${PRIVILEGED}
+    cap_drop:
+      - ALL`,
+			diagnostics: nil,
+		},
+		{
+			name: "flow style service cannot have its lines removed",
+			content: `
+services:
+  web: { privileged: true, cap_add: [NET_ADMIN] }`,
+			diagnostics: []protocol.Diagnostic{
+				redundantCapabilitiesDiagnostic("cap_add", 2, 27, 34, 0, false),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func globalModeReplicasDiagnostic(line, start, end, lastLine protocol.UInteger, removable bool) protocol.Diagnostic {
+	diagnostic := validationDiagnostic("GlobalModeReplicas", "replicas cannot be set when the deploy mode is global", protocol.DiagnosticSeverityError, line, start, end)
+	if removable {
+		diagnostic.Data = []types.NamedEdit{
+			{
+				Title: "Remove replicas",
+				Edit:  "",
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: line},
+					End:   protocol.Position{Line: lastLine},
+				},
+			},
+		}
+	}
+	return diagnostic
+}
+
+func TestCollectDiagnostics_GlobalModeReplicas(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "replicas in the replicated mode",
+			content: `
+services:
+  web:
+    deploy:
+      mode: replicated
+      replicas: 2`,
+			diagnostics: nil,
+		},
+		{
+			name: "global mode without replicas",
+			content: `
+services:
+  web:
+    deploy:
+      mode: global`,
+			diagnostics: nil,
+		},
+		{
+			name: "replicas in the global mode",
+			content: `
+services:
+  web:
+    deploy:
+      replicas: 2
+      mode: global`,
+			diagnostics: []protocol.Diagnostic{
+				globalModeReplicasDiagnostic(4, 6, 14, 5, true),
+			},
+		},
+		{
+			name: "replicas in the global mode set with a string",
+			content: `
+services:
+  web:
+    deploy:
+      mode: "global"
+      replicas: 2`,
+			diagnostics: []protocol.Diagnostic{
+				globalModeReplicasDiagnostic(5, 6, 14, 6, true),
+			},
+		},
+		{
+			name: "interpolated mode",
+			content: `
+services:
+  web:
+    deploy:
+      mode: ${MODE}
+      replicas: 2`,
+			diagnostics: nil,
+		},
+		{
+			name: "flow style deploy cannot have its lines removed",
+			content: `
+services:
+  web:
+    deploy: { mode: global, replicas: 2 }`,
+			diagnostics: []protocol.Diagnostic{
+				globalModeReplicasDiagnostic(3, 28, 36, 0, false),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_BuildPlatforms(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "platform is built",
+			content: `
+services:
+  web:
+    platform: linux/arm64
+    build:
+      platforms:
+        - linux/amd64
+        - linux/arm64`,
+			diagnostics: nil,
+		},
+		{
+			name: "platform is built under another name",
+			content: `
+services:
+  web:
+    platform: linux/aarch64
+    build:
+      platforms:
+        - linux/arm64/v8`,
+			diagnostics: nil,
+		},
+		{
+			name: "platform is not built",
+			content: `
+services:
+  web:
+    platform: linux/arm64
+    build:
+      platforms:
+        - linux/amd64`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("PlatformNotBuilt", "platform linux/arm64 is not one of the platforms in build.platforms", protocol.DiagnosticSeverityWarning, 3, 14, 25),
+			},
+		},
+		{
+			name: "quoted platform is not built",
+			content: `
+services:
+  web:
+    platform: "linux/arm64"
+    build:
+      platforms: [ linux/amd64, linux/arm/v7 ]`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("PlatformNotBuilt", "platform linux/arm64 is not one of the platforms in build.platforms", protocol.DiagnosticSeverityWarning, 3, 15, 26),
+			},
+		},
+		{
+			name: "build without platforms",
+			content: `
+services:
+  web:
+    platform: linux/arm64
+    build: .`,
+			diagnostics: nil,
+		},
+		{
+			name: "interpolated platform",
+			content: `
+services:
+  web:
+    platform: ${PLATFORM}
+    build:
+      platforms:
+        - linux/amd64`,
+			diagnostics: nil,
+		},
+		{
+			name: "interpolated build platform",
+			content: `
+services:
+  web:
+    platform: linux/arm64
+    build:
+      platforms:
+        - linux/amd64
+        - ${PLATFORM}`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_CredentialSpec(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "single source",
+			content: `
+services:
+  web:
+    credential_spec:
+      file: spec.json`,
+			diagnostics: nil,
+		},
+		{
+			name: "two sources",
+			content: `
+services:
+  web:
+    credential_spec:
+      registry: spec.reg
+      file: spec.json`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("ConflictingCredentialSpec", "only one of config, file, registry can be set in credential_spec", protocol.DiagnosticSeverityError, 4, 6, 14),
+				validationDiagnostic("ConflictingCredentialSpec", "only one of config, file, registry can be set in credential_spec", protocol.DiagnosticSeverityError, 5, 6, 10),
+			},
+		},
+		{
+			name: "extension attributes are not sources",
+			content: `
+services:
+  web:
+    credential_spec:
+      config: spec
+      x-note: managed`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_BuildString(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "build string",
+			content: `
+services:
+  web:
+    build: ./web
+    image: web`,
+			diagnostics: nil,
+		},
+		{
+			name: "build attributes next to a build string",
+			content: `
+services:
+  web:
+    build: ./web
+    dockerfile: Dockerfile.dev
+    args:
+      - VERSION=1`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("BuildAttributeOutsideBuild", "dockerfile must be set in the build object but build has been set to a string", protocol.DiagnosticSeverityError, 4, 4, 14),
+				validationDiagnostic("BuildAttributeOutsideBuild", "args must be set in the build object but build has been set to a string", protocol.DiagnosticSeverityError, 5, 4, 8),
+			},
+		},
+		{
+			name: "build attributes without a build",
+			content: `
+services:
+  web:
+    target: dev`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("UnknownServiceAttribute", "unknown attribute target of service web, extension attributes must start with x-", protocol.DiagnosticSeverityWarning, 3, 4, 10),
+			},
+		},
+		{
+			name: "build object",
+			content: `
+services:
+  web:
+    build:
+      context: ./web
+    labels:
+      - app=web`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func unknownTopLevelAttributeDiagnostic(name, suggestion string, line protocol.UInteger) protocol.Diagnostic {
+	if suggestion == "" {
+		return validationDiagnostic("UnknownTopLevelAttribute", fmt.Sprintf("unknown top-level attribute %v, extension attributes must start with x-", name), protocol.DiagnosticSeverityWarning, line, 0, protocol.UInteger(len(name)))
+	}
+	diagnostic := validationDiagnostic("UnknownTopLevelAttribute", fmt.Sprintf("unknown top-level attribute %v, did you mean %v?", name, suggestion), protocol.DiagnosticSeverityWarning, line, 0, protocol.UInteger(len(name)))
+	diagnostic.Data = []types.NamedEdit{
+		{
+			Title: fmt.Sprintf("Change attribute name to %v", suggestion),
+			Edit:  suggestion,
+		},
+	}
+	return diagnostic
+}
+
+func TestCollectDiagnostics_UnknownTopLevelAttribute(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "known and extension attributes",
+			content: `
+name: project
+x-common:
+  image: alpine
+services:
+  web:
+    image: nginx`,
+			diagnostics: nil,
+		},
+		{
+			name: "service instead of services",
+			content: `
+service:
+  web:
+    image: nginx`,
+			diagnostics: []protocol.Diagnostic{unknownTopLevelAttributeDiagnostic("service", "services", 1)},
+		},
+		{
+			name: "misspelled attributes",
+			content: `
+netwroks:
+  front:
+volume:
+  data:`,
+			diagnostics: []protocol.Diagnostic{
+				unknownTopLevelAttributeDiagnostic("netwroks", "networks", 1),
+				unknownTopLevelAttributeDiagnostic("volume", "volumes", 3),
+			},
+		},
+		{
+			name: "abbreviated attribute",
+			content: `
+net:
+  front:`,
+			diagnostics: []protocol.Diagnostic{unknownTopLevelAttributeDiagnostic("net", "networks", 1)},
+		},
+		{
+			name: "abbreviation of more than one attribute",
+			content: `
+se:
+  web:
+    image: nginx`,
+			diagnostics: []protocol.Diagnostic{unknownTopLevelAttributeDiagnostic("se", "", 1)},
+		},
+		{
+			name: "attribute that is not close to a known attribute",
+			content: `
+common:
+  image: alpine`,
+			diagnostics: []protocol.Diagnostic{unknownTopLevelAttributeDiagnostic("common", "", 1)},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func unknownServiceAttributeDiagnostic(name, suggestion string, line protocol.UInteger) protocol.Diagnostic {
+	diagnostic := validationDiagnostic("UnknownServiceAttribute", fmt.Sprintf("unknown attribute %v of service web, did you mean %v?", name, suggestion), protocol.DiagnosticSeverityWarning, line, 4, 4+protocol.UInteger(len(name)))
+	diagnostic.Data = []types.NamedEdit{
+		{
+			Title: fmt.Sprintf("Change attribute name to %v", suggestion),
+			Edit:  suggestion,
+		},
+	}
+	return diagnostic
+}
+
+func TestCollectDiagnostics_UnknownServiceAttribute(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "known and extension attributes",
+			content: `
+x-defaults: &defaults
+  restart: always
+services:
+  web:
+    <<: *defaults
+    image: nginx
+    x-owner: web-team`,
+			diagnostics: nil,
+		},
+		{
+			name: "common misspellings",
+			content: `
+services:
+  web:
+    enviroment:
+      - DEBUG=1
+    depend_on:
+      - db
+    port:
+      - "8080:80"
+    volume:
+      - data:/data
+    restrat: always`,
+			diagnostics: []protocol.Diagnostic{
+				unknownServiceAttributeDiagnostic("enviroment", "environment", 3),
+				unknownServiceAttributeDiagnostic("depend_on", "depends_on", 5),
+				unknownServiceAttributeDiagnostic("port", "ports", 7),
+				unknownServiceAttributeDiagnostic("volume", "volumes", 9),
+				unknownServiceAttributeDiagnostic("restrat", "restart", 11),
+			},
+		},
+		{
+			name: "abbreviated attributes",
+			content: `
+services:
+  web:
+    im: nginx
+    ulim:
+      nofile: 1024`,
+			diagnostics: []protocol.Diagnostic{
+				unknownServiceAttributeDiagnostic("im", "image", 3),
+				unknownServiceAttributeDiagnostic("ulim", "ulimits", 4),
+			},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_RedundantExpose(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "exposed port is not published",
+			content: `
+services:
+  web:
+    expose:
+      - "3000"
+    ports:
+      - "8080:80"`,
+			diagnostics: nil,
+		},
+		{
+			name: "exposed port is published by the short syntax",
+			content: `
+services:
+  web:
+    expose:
+      - "80"
+      - 3000
+    ports:
+      - "127.0.0.1:8080:80"
+      - 3000`,
+			diagnostics: []protocol.Diagnostic{
+				redundantExposeDiagnostic("80", 4, 9, 11, true),
+				redundantExposeDiagnostic("3000", 5, 8, 12, true),
+			},
+		},
+		{
+			name: "exposed port is published by the long syntax",
+			content: `
+services:
+  web:
+    expose:
+      - 80
+    ports:
+      - target: 80
+        published: "8080"`,
+			diagnostics: []protocol.Diagnostic{
+				redundantExposeDiagnostic("80", 4, 8, 10, true),
+			},
+		},
+		{
+			name: "protocols must match",
+			content: `
+services:
+  web:
+    expose:
+      - 53/udp
+      - 53/tcp
+    ports:
+      - "53:53/udp"
+      - target: 5353
+        protocol: udp`,
+			diagnostics: []protocol.Diagnostic{
+				redundantExposeDiagnostic("53/udp", 4, 8, 14, true),
+			},
+		},
+		{
+			name: "exposed range within a published range",
+			content: `
+services:
+  web:
+    expose: ["8001-8002"]
+    ports:
+      - "[::1]:9000-9010:8000-8010"`,
+			diagnostics: []protocol.Diagnostic{
+				redundantExposeDiagnostic("8001-8002", 3, 14, 23, false),
+			},
+		},
+		{
+			name: "interpolated values are ignored",
+			content: `
+services:
+  web:
+    expose:
+      - ${PORT}
+      - "80"
+    ports:
+      - "8080:${PORT}"
+      - "${HOST_PORT}:80"`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func conflictingDockerfilesDiagnostic(attribute, other string, line, start, end, dockerfileStart, dockerfileEnd, inlineStart, inlineEnd protocol.UInteger) protocol.Diagnostic {
+	diagnostic := validationDiagnostic("ConflictingDockerfile", fmt.Sprintf("%v must not be set when %v is used", attribute, other), protocol.DiagnosticSeverityError, line, start, end)
+	diagnostic.Data = []types.NamedEdit{
+		{
+			Title: "Remove dockerfile",
+			Edit:  "",
+			Range: &protocol.Range{
+				Start: protocol.Position{Line: dockerfileStart},
+				End:   protocol.Position{Line: dockerfileEnd},
+			},
+		},
+		{
+			Title: "Remove dockerfile_inline",
+			Edit:  "",
+			Range: &protocol.Range{
+				Start: protocol.Position{Line: inlineStart},
+				End:   protocol.Position{Line: inlineEnd},
+			},
+		},
+	}
+	return diagnostic
+}
+
+func duplicatePortDiagnostic(mapping string, startLine, start, endLine, end protocol.UInteger, removable bool) protocol.Diagnostic {
+	diagnostic := validationDiagnostic("DuplicatePort", fmt.Sprintf("port mapping %v is already declared by this service", mapping), protocol.DiagnosticSeverityWarning, startLine, start, end)
+	diagnostic.Range.End.Line = endLine
+	if removable {
+		diagnostic.Data = []types.NamedEdit{
+			{
+				Title: "Remove duplicate port entry",
+				Edit:  "",
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: startLine},
+					End:   protocol.Position{Line: endLine + 1},
+				},
+			},
+		}
+	}
+	return diagnostic
+}
+
+func TestCollectDiagnostics_DuplicatePorts(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "different mappings",
+			content: `
+services:
+  web:
+    ports:
+      - "8080:80"
+      - "8081:80"
+      - "8080:80/udp"
+      - "127.0.0.1:8080:80"
+      - 80
+  web2:
+    ports:
+      - "8080:80"`,
+			diagnostics: nil,
+		},
+		{
+			name: "short syntax duplicates",
+			content: `
+services:
+  web:
+    ports:
+      - "8080:80"
+      - "8080:80/tcp"
+      - 3000
+      - 3000`,
+			diagnostics: []protocol.Diagnostic{
+				duplicatePortDiagnostic("8080:80/tcp", 5, 9, 5, 20, true),
+				duplicatePortDiagnostic("3000/tcp", 7, 8, 7, 12, true),
+			},
+		},
+		{
+			name: "long syntax duplicates a short syntax entry",
+			content: `
+services:
+  web:
+    ports:
+      - "127.0.0.1:8080:80"
+      - target: 80
+        published: "8080"
+        host_ip: 127.0.0.1`,
+			diagnostics: []protocol.Diagnostic{
+				duplicatePortDiagnostic("127.0.0.1:8080:80/tcp", 5, 8, 7, 26, true),
+			},
+		},
+		{
+			name: "flow sequence duplicates cannot be removed",
+			content: `
+services:
+  web:
+    ports: ["8080:80", "8080:80"]`,
+			diagnostics: []protocol.Diagnostic{
+				duplicatePortDiagnostic("8080:80/tcp", 3, 24, 3, 31, false),
+			},
+		},
+		{
+			name: "interpolated values are ignored",
+			content: `
+services:
+  web:
+    ports:
+      - "${PORT}:80"
+      - "${PORT}:80"`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDeferrableDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func ignoredHealthcheckDiagnostic(attribute string, line, start, end protocol.UInteger) protocol.Diagnostic {
+	diagnostic := validationDiagnostic("IgnoredAttribute", fmt.Sprintf("%v is ignored because the healthcheck is disabled", attribute), protocol.DiagnosticSeverityWarning, line, start, end)
+	diagnostic.Tags = []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary}
+	return diagnostic
+}
+
+func TestCollectDiagnostics_DisabledHealthcheck(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "healthcheck is not disabled",
+			content: `
+services:
+  web:
+    healthcheck:
+      test: ["CMD", "true"]
+      disable: false`,
+			diagnostics: nil,
+		},
+		{
+			name: "healthcheck is only disabled",
+			content: `
+services:
+  web:
+    healthcheck:
+      disable: true`,
+			diagnostics: nil,
+		},
+		{
+			name: "ignored attributes around disable",
+			content: `
+services:
+  web:
+    healthcheck:
+      test:
+        - CMD
+        - "true"
+      disable: true
+      interval: 10s
+    image: alpine`,
+			diagnostics: []protocol.Diagnostic{
+				ignoredHealthcheckDiagnostic("test", 4, 6, 10),
+				ignoredHealthcheckDiagnostic("interval", 8, 6, 14),
+			},
+		},
+		{
+			name: "multi-line values are removed entirely",
+			content: `
+services:
+  web:
+    healthcheck:
+      disable: true
+      test: |
+        curl -f
+        http://localhost
+# comment
+    image: alpine`,
+			diagnostics: []protocol.Diagnostic{
+				ignoredHealthcheckDiagnostic("test", 5, 6, 10),
+			},
+		},
+		{
+			name: "flow style healthcheck",
+			content: `
+services:
+  web:
+    healthcheck: { disable: true, retries: 3 }`,
+			diagnostics: []protocol.Diagnostic{
+				ignoredHealthcheckDiagnostic("retries", 3, 34, 41),
+			},
+		},
+		{
+			name: "disable as a string is not checked",
+			content: `
+services:
+  web:
+    healthcheck:
+      disable: "true"
+      retries: 3`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func missingHealthcheckDiagnostic(service string, line, start, end protocol.UInteger, edit *types.NamedEdit) protocol.Diagnostic {
+	diagnostic := validationDiagnostic("MissingHealthcheck", fmt.Sprintf("service %v has no healthcheck so it can never become healthy, consider adding a healthcheck to %v", service, service), protocol.DiagnosticSeverityWarning, line, start, end)
+	if edit != nil {
+		diagnostic.Data = []types.NamedEdit{*edit}
+	}
+	return diagnostic
+}
+
+func TestCollectDiagnostics_HealthyDependencies(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "dependency without a healthcheck",
+			content: `
+services:
+  a:
+    image: alpine
+    depends_on:
+      b:
+        condition: service_healthy
+  b:
+    image: redis`,
+			diagnostics: []protocol.Diagnostic{
+				missingHealthcheckDiagnostic("b", 6, 19, 34, &types.NamedEdit{
+					Title: "Add healthcheck to b",
+					Edit:  "    healthcheck:\n      test: [\"CMD\", \"redis-cli\", \"ping\"]\n      interval: 30s\n      timeout: 10s\n      retries: 3\n      start_period: 10s\n",
+					Range: &protocol.Range{
+						Start: protocol.Position{Line: 8},
+						End:   protocol.Position{Line: 8},
+					},
+				}),
+			},
+		},
+		{
+			name: "flow style dependency without a healthcheck",
+			content: `
+services:
+  a:
+    image: alpine
+    depends_on:
+      b:
+        condition: service_healthy
+  b: { image: redis }`,
+			diagnostics: []protocol.Diagnostic{
+				missingHealthcheckDiagnostic("b", 6, 19, 34, nil),
+			},
+		},
+		{
+			name: "dependency with a healthcheck",
+			content: `
+services:
+  a:
+    image: alpine
+    depends_on:
+      b:
+        condition: service_healthy
+  b:
+    image: redis
+    healthcheck:
+      test: ["CMD", "redis-cli", "ping"]`,
+			diagnostics: nil,
+		},
+		{
+			name: "healthcheck merged from an anchor",
+			content: `
+x-health: &health
+  healthcheck:
+    test: ["CMD", "true"]
+services:
+  a:
+    image: alpine
+    depends_on:
+      b:
+        condition: service_healthy
+  b:
+    <<: *health
+    image: redis`,
+			diagnostics: nil,
+		},
+		{
+			name: "healthcheck inherited from an extended service",
+			content: `
+services:
+  a:
+    image: alpine
+    depends_on:
+      b:
+        condition: service_healthy
+  b:
+    extends: base
+  base:
+    image: redis
+    healthcheck:
+      test: ["CMD", "true"]`,
+			diagnostics: nil,
+		},
+		{
+			name: "service extended from another file is not checked",
+			content: `
+services:
+  a:
+    image: alpine
+    depends_on:
+      b:
+        condition: service_healthy
+  b:
+    extends:
+      file: common.yaml
+      service: base`,
+			diagnostics: nil,
+		},
+		{
+			name: "built services may have a HEALTHCHECK instruction",
+			content: `
+services:
+  a:
+    image: alpine
+    depends_on:
+      b:
+        condition: service_healthy
+  b:
+    build: .`,
+			diagnostics: nil,
+		},
+		{
+			name: "other conditions are not checked",
+			content: `
+services:
+  a:
+    image: alpine
+    depends_on:
+      b:
+        condition: service_started
+  b:
+    image: redis`,
+			diagnostics: nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewComposeDiagnosticsCollector()
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCollectDiagnostics_ImageReferences(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name: "valid references",
+			content: `
+services:
+  a:
+    image: alpine
+  b:
+    image: docker.io/library/alpine:3.21
+  c:
+    image: localhost:5000/team/app@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+  d:
+    image: e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+  e:
+    build: .
+    image: registry.example.com/app:latest`,
+			diagnostics: nil,
+		},
+		{
+			name: "interpolated references are ignored",
+			content: `
+services:
+  a:
+    image: ${REGISTRY}/app:${TAG:-latest}`,
+			diagnostics: nil,
+		},
+		{
+			name: "double colon",
+			content: `
+services:
+  a:
+    build: .
+    image: app::latest`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidImageReference", "invalid image reference app::latest: invalid reference format", protocol.DiagnosticSeverityError, 4, 11, 22),
+			},
+		},
+		{
+			name: "uppercase repository name",
+			content: `
+services:
+  a:
+    image: "Alpine:3.21"`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidImageReference", "invalid image reference Alpine:3.21: invalid reference format: repository name (library/Alpine) must be lowercase", protocol.DiagnosticSeverityError, 3, 12, 23),
+			},
+		},
+		{
+			name: "invalid character",
+			content: `
+services:
+  a:
+    image: app!:latest`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidImageReference", "invalid image reference app!:latest: invalid reference format", protocol.DiagnosticSeverityError, 3, 11, 22),
+			},
+		},
+		{
+			name: "build tags",
+			content: `
+services:
+  a:
+    build:
+      context: .
+      tags:
+        - app:1.0
+        - app:1.0:extra`,
+			diagnostics: []protocol.Diagnostic{
+				validationDiagnostic("InvalidImageReference", "invalid image reference app:1.0:extra: invalid reference format", protocol.DiagnosticSeverityError, 7, 10, 23),
+			},
+		},
 	}
 
 	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
@@ -71,6 +71,138 @@ func extendedServiceReferences(servicesNode *ast.MappingNode) []*token.Token {
 	return tokens
 }
 
+// linkReferences returns the tokens of the services that are linked
+// to in the links attribute of a service. An entry may be in either a
+// SERVICE or SERVICE:ALIAS form and only the service part is returned
+// as the alias is a name that is local to the linking service.
+func linkReferences(servicesNode *ast.MappingNode) []*token.Token {
+	tokens := []*token.Token{}
+	for _, t := range serviceDependencyReferences(servicesNode, "links", true) {
+		tokens = append(tokens, volumeToken(t))
+	}
+	return tokens
+}
+
+// additionalContextToken returns the token of the value of an
+// additional build context. Entries of the list form are NAME=VALUE
+// strings and only the VALUE part is returned for them.
+func additionalContextToken(node ast.Node, listForm bool) *token.Token {
+	t := resolveAnchor(node).GetToken()
+	if t == nil || !listForm {
+		return t
+	}
+	idx := strings.Index(t.Value, "=")
+	if idx == -1 {
+		return nil
+	}
+	return subToken(t, idx+1)
+}
+
+// subToken returns a token for the part of the given token's value
+// that starts at the given offset.
+func subToken(t *token.Token, offset int) *token.Token {
+	position := *t.Position
+	position.Column += offset
+	position.Offset += offset
+	return &token.Token{
+		Type:     t.Type,
+		Value:    t.Value[offset:],
+		Position: &position,
+	}
+}
+
+// additionalContextTokens returns the tokens of the values of the
+// additional_contexts attribute of the given service's build object.
+func additionalContextTokens(serviceNode ast.Node) []*token.Token {
+	tokens := []*token.Token{}
+	build := mappingValue(serviceNode, "build")
+	if build == nil {
+		return tokens
+	}
+	additionalContexts := mappingValue(build.Value, "additional_contexts")
+	if additionalContexts == nil {
+		return tokens
+	}
+	switch n := resolveAnchor(additionalContexts.Value).(type) {
+	case *ast.MappingNode:
+		for _, context := range n.Values {
+			if t := additionalContextToken(context.Value, false); t != nil {
+				tokens = append(tokens, t)
+			}
+		}
+	case *ast.SequenceNode:
+		for _, context := range n.Values {
+			if t := additionalContextToken(context, true); t != nil {
+				tokens = append(tokens, t)
+			}
+		}
+	}
+	return tokens
+}
+
+// serviceReferenceToken returns the token of the service name in a
+// value with the service: prefix or nil if the value does not have the
+// prefix.
+func serviceReferenceToken(t *token.Token) *token.Token {
+	if t == nil || !strings.HasPrefix(t.Value, "service:") {
+		return nil
+	}
+	return subToken(t, len("service:"))
+}
+
+// additionalContextReferences returns the tokens of the services that
+// are used as additional build contexts with the service: prefix.
+func additionalContextReferences(servicesNode *ast.MappingNode) []*token.Token {
+	tokens := []*token.Token{}
+	for _, serviceNode := range servicesNode.Values {
+		for _, t := range additionalContextTokens(serviceNode.Value) {
+			if t = serviceReferenceToken(t); t != nil {
+				tokens = append(tokens, t)
+			}
+		}
+	}
+	return tokens
+}
+
+// sharedNamespaceAttributes are the service attributes that can join
+// the namespace of another service's container with the service:
+// prefix.
+var sharedNamespaceAttributes = []string{"ipc", "network_mode", "pid"}
+
+// sharedNamespaceReferences returns the tokens of the services whose
+// namespaces are joined by the ipc, network_mode, and pid attributes of
+// other services.
+func sharedNamespaceReferences(servicesNode *ast.MappingNode) []*token.Token {
+	tokens := []*token.Token{}
+	for _, serviceNode := range servicesNode.Values {
+		for _, attribute := range sharedNamespaceAttributes {
+			if value := mappingValue(serviceNode.Value, attribute); value != nil {
+				if stringNode, ok := resolveAnchor(value.Value).(*ast.StringNode); ok {
+					if t := serviceReferenceToken(stringNode.GetToken()); t != nil {
+						tokens = append(tokens, t)
+					}
+				}
+			}
+		}
+	}
+	return tokens
+}
+
+// linkToken returns a token for the service of a SERVICE:ALIAS entry
+// of a service's links. The token is returned as is if the entry does
+// not have an alias.
+func linkToken(t *token.Token) *token.Token {
+	service, _, found := strings.Cut(t.Value, ":")
+	if !found {
+		return t
+	}
+	return &token.Token{
+		Type:     t.Type,
+		Value:    service,
+		Position: t.Position,
+	}
+}
+
 func volumeToken(t *token.Token) *token.Token {
 	idx := strings.Index(t.Value, ":")
 	if idx != -1 {
@@ -273,78 +405,27 @@ func DocumentHighlights(doc document.ComposeDocument, position protocol.Position
 	line := int(position.Line) + 1
 	character := int(position.Character) + 1
 	if mappingNode, ok := file.Docs[0].Body.(*ast.MappingNode); ok {
-		var networkRefs []*token.Token
-		var volumeRefs []*token.Token
-		var configRefs []*token.Token
-		var secretRefs []*token.Token
-		var modelRefs []*token.Token
-		var networkDeclarations []*token.Token
-		var volumeDeclarations []*token.Token
-		var configDeclarations []*token.Token
-		var secretDeclarations []*token.Token
-		var modelDeclarations []*token.Token
-		for _, node := range mappingNode.Values {
-			name, value := convertTopLevelNode(node)
-			if name == nil || value == nil {
-				continue
-			}
-
-			switch name.Value {
-			case "services":
-				refs := serviceDependencyReferences(value, "depends_on", false)
-				refs = append(refs, extendedServiceReferences(value)...)
-				decls := declarations(value)
-				name, highlights := highlightReferences("services", refs, decls, line, character)
+		for _, provider := range referenceProviders {
+			for _, scope := range provider.scopes(mappingNode) {
+				name, highlights := highlightReferences(provider.dependencyType, scope.references, scope.declarations, line, character)
 				if len(highlights.documentHighlights) > 0 {
 					return name, highlights
 				}
-				networkRefs = serviceDependencyReferences(value, "networks", false)
-				configRefs = serviceDependencyReferences(value, "configs", true)
-				secretRefs = serviceDependencyReferences(value, "secrets", true)
-				modelRefs = serviceDependencyReferences(value, "models", false)
-				volumeRefs = volumeReferences(value)
-			case "networks":
-				networkDeclarations = declarations(value)
-			case "volumes":
-				volumeDeclarations = declarations(value)
-			case "configs":
-				configDeclarations = declarations(value)
-			case "secrets":
-				secretDeclarations = declarations(value)
-			case "models":
-				modelDeclarations = declarations(value)
 			}
 		}
-		name, highlights := highlightReferences("networks", networkRefs, networkDeclarations, line, character)
-		if len(highlights.documentHighlights) > 0 {
-			return name, highlights
-		}
-		name, highlights = highlightReferences("volumes", volumeRefs, volumeDeclarations, line, character)
-		if len(highlights.documentHighlights) > 0 {
-			return name, highlights
-		}
-		name, highlights = highlightReferences("configs", configRefs, configDeclarations, line, character)
-		if len(highlights.documentHighlights) > 0 {
-			return name, highlights
-		}
-		name, highlights = highlightReferences("secrets", secretRefs, secretDeclarations, line, character)
-		if len(highlights.documentHighlights) > 0 {
-			return name, highlights
-		}
-		name, highlights = highlightReferences("models", modelRefs, modelDeclarations, line, character)
-		if len(highlights.documentHighlights) > 0 {
-			return name, highlights
-		}
 
+		name := ""
 		fragments := []protocol.DocumentHighlight{}
 		anchor, aliases := fragmentReference(mappingNode, line, character)
 		if anchor != nil {
+			name = anchor.Name.GetToken().Value
 			fragments = append(fragments, documentHighlightFromToken(anchor.Name.GetToken(), protocol.DocumentHighlightKindWrite))
 		}
 		for i := range aliases {
+			name = aliases[i].Value.GetToken().Value
 			fragments = append(fragments, documentHighlightFromToken(aliases[i].Value.GetToken(), protocol.DocumentHighlightKindRead))
 		}
-		return "", dependencyReference{documentHighlights: fragments}
+		return name, dependencyReference{documentHighlights: fragments}
 	}
 	return "", dependencyReference{documentHighlights: nil}
 }
@@ -40,7 +40,7 @@ var serviceReferenceTestCases = []struct {
 	links         func(protocol.DocumentUri) any
 	ranges        []protocol.DocumentHighlight
 	renameEdits   func(protocol.DocumentUri) *protocol.WorkspaceEdit
-	prepareRename *protocol.Range
+	prepareRename *protocol.RangeWithPlaceholder
 }{
 	{
 		name: "write highlight on a service",
@@ -82,9 +82,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -127,9 +130,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -172,9 +178,12 @@ services: &anchor
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -206,9 +215,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -240,9 +252,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -274,9 +289,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -308,9 +326,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -342,9 +363,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 16},
-			End:   protocol.Position{Line: 4, Character: 21},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 16},
+				End:   protocol.Position{Line: 4, Character: 21},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -376,9 +400,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 9},
-			End:   protocol.Position{Line: 4, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 9},
+				End:   protocol.Position{Line: 4, Character: 14},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -433,9 +460,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 9},
-			End:   protocol.Position{Line: 4, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 9},
+				End:   protocol.Position{Line: 4, Character: 14},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -490,9 +520,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -524,9 +557,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -558,9 +594,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -591,9 +630,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 3, Character: 26},
-			End:   protocol.Position{Line: 3, Character: 31},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 3, Character: 26},
+				End:   protocol.Position{Line: 3, Character: 31},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -626,9 +668,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -687,9 +732,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -743,9 +791,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -799,9 +850,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 2},
-			End:   protocol.Position{Line: 5, Character: 7},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 2},
+				End:   protocol.Position{Line: 5, Character: 7},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -860,9 +914,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 8},
-			End:   protocol.Position{Line: 6, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 8},
+				End:   protocol.Position{Line: 6, Character: 13},
+			},
+			Placeholder: "redis",
 		},
 	},
 	{
@@ -924,9 +981,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 8, Character: 6},
-			End:   protocol.Position{Line: 8, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 8, Character: 6},
+				End:   protocol.Position{Line: 8, Character: 11},
+			},
+			Placeholder: "redis",
 		},
 	},
 	{
@@ -980,9 +1040,191 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 13},
-			End:   protocol.Position{Line: 5, Character: 17},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 13},
+				End:   protocol.Position{Line: 5, Character: 17},
+			},
+			Placeholder: "test",
+		},
+	},
+	{
+		name: "additional_contexts service reference",
+		content: `
+services:
+  test:
+    image: alpine
+  test2:
+    build:
+      additional_contexts:
+        base: service:test`,
+		line:      7,
+		character: 23,
+		locations: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(false, protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			}, nil, u)
+		},
+		links: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(true, protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			}, &protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 22},
+				End:   protocol.Position{Line: 7, Character: 26},
+			}, u)
+		},
+		ranges: []protocol.DocumentHighlight{
+			documentHighlight(2, 2, 2, 6, protocol.DocumentHighlightKindWrite),
+			documentHighlight(7, 22, 7, 26, protocol.DocumentHighlightKindRead),
+		},
+		renameEdits: func(u protocol.DocumentUri) *protocol.WorkspaceEdit {
+			return &protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+					u: {
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 2, Character: 2},
+								End:   protocol.Position{Line: 2, Character: 6},
+							},
+						},
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 7, Character: 22},
+								End:   protocol.Position{Line: 7, Character: 26},
+							},
+						},
+					},
+				},
+			}
+		},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 22},
+				End:   protocol.Position{Line: 7, Character: 26},
+			},
+			Placeholder: "test",
+		},
+	},
+	{
+		name: "ipc service reference",
+		content: `
+services:
+  test:
+    image: alpine
+  test2:
+    ipc: service:test`,
+		line:      5,
+		character: 19,
+		locations: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(false, protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			}, nil, u)
+		},
+		links: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(true, protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			}, &protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 17},
+				End:   protocol.Position{Line: 5, Character: 21},
+			}, u)
+		},
+		ranges: []protocol.DocumentHighlight{
+			documentHighlight(2, 2, 2, 6, protocol.DocumentHighlightKindWrite),
+			documentHighlight(5, 17, 5, 21, protocol.DocumentHighlightKindRead),
+		},
+		renameEdits: func(u protocol.DocumentUri) *protocol.WorkspaceEdit {
+			return &protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+					u: {
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 2, Character: 2},
+								End:   protocol.Position{Line: 2, Character: 6},
+							},
+						},
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 5, Character: 17},
+								End:   protocol.Position{Line: 5, Character: 21},
+							},
+						},
+					},
+				},
+			}
+		},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 17},
+				End:   protocol.Position{Line: 5, Character: 21},
+			},
+			Placeholder: "test",
+		},
+	},
+	{
+		name: "pid service reference in quotes",
+		content: `
+services:
+  test:
+    image: alpine
+  test2:
+    pid: "service:test"`,
+		line:      2,
+		character: 4,
+		locations: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(false, protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			}, nil, u)
+		},
+		links: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(true, protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			}, &protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			}, u)
+		},
+		ranges: []protocol.DocumentHighlight{
+			documentHighlight(2, 2, 2, 6, protocol.DocumentHighlightKindWrite),
+			documentHighlight(5, 18, 5, 22, protocol.DocumentHighlightKindRead),
+		},
+		renameEdits: func(u protocol.DocumentUri) *protocol.WorkspaceEdit {
+			return &protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+					u: {
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 2, Character: 2},
+								End:   protocol.Position{Line: 2, Character: 6},
+							},
+						},
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 5, Character: 18},
+								End:   protocol.Position{Line: 5, Character: 22},
+							},
+						},
+					},
+				},
+			}
+		},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1036,9 +1278,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 21},
-			End:   protocol.Position{Line: 5, Character: 25},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 21},
+				End:   protocol.Position{Line: 5, Character: 25},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1092,9 +1337,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 21},
-			End:   protocol.Position{Line: 5, Character: 25},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 21},
+				End:   protocol.Position{Line: 5, Character: 25},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1148,9 +1396,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 14},
-			End:   protocol.Position{Line: 5, Character: 18},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 14},
+				End:   protocol.Position{Line: 5, Character: 18},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1205,9 +1456,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 15},
-			End:   protocol.Position{Line: 6, Character: 19},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 15},
+				End:   protocol.Position{Line: 6, Character: 19},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1262,9 +1516,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 15},
-			End:   protocol.Position{Line: 6, Character: 19},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 15},
+				End:   protocol.Position{Line: 6, Character: 19},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1319,9 +1576,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 23},
-			End:   protocol.Position{Line: 6, Character: 27},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 23},
+				End:   protocol.Position{Line: 6, Character: 27},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1376,9 +1636,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 23},
-			End:   protocol.Position{Line: 6, Character: 27},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 23},
+				End:   protocol.Position{Line: 6, Character: 27},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1466,9 +1729,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 10},
-			End:   protocol.Position{Line: 2, Character: 16},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 10},
+				End:   protocol.Position{Line: 2, Character: 16},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -1516,9 +1782,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 3, Character: 12},
-			End:   protocol.Position{Line: 3, Character: 19},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 3, Character: 12},
+				End:   protocol.Position{Line: 3, Character: 19},
+			},
+			Placeholder: "another",
 		},
 	},
 	{
@@ -1574,9 +1843,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 8},
-			End:   protocol.Position{Line: 5, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 8},
+				End:   protocol.Position{Line: 5, Character: 14},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -1632,9 +1904,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 2},
-			End:   protocol.Position{Line: 6, Character: 8},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 2},
+				End:   protocol.Position{Line: 6, Character: 8},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -1682,9 +1957,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 10},
-			End:   protocol.Position{Line: 2, Character: 16},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 10},
+				End:   protocol.Position{Line: 2, Character: 16},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -1740,9 +2018,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 15},
-			End:   protocol.Position{Line: 5, Character: 21},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 15},
+				End:   protocol.Position{Line: 5, Character: 21},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -1798,11 +2079,211 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 2},
-			End:   protocol.Position{Line: 6, Character: 8},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 2},
+				End:   protocol.Position{Line: 6, Character: 8},
+			},
+			Placeholder: "second",
 		},
 	},
+	{
+		name: "read highlight on a links service",
+		content: `
+services:
+  web:
+    links:
+      - db
+  db:
+    image: postgres`,
+		line:      4,
+		character: 9,
+		locations: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(false, protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 2},
+				End:   protocol.Position{Line: 5, Character: 4},
+			}, nil, u)
+		},
+		links: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(true, protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 2},
+				End:   protocol.Position{Line: 5, Character: 4},
+			}, &protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 10},
+			}, u)
+		},
+		ranges: []protocol.DocumentHighlight{
+			documentHighlight(4, 8, 4, 10, protocol.DocumentHighlightKindRead),
+			documentHighlight(5, 2, 5, 4, protocol.DocumentHighlightKindWrite),
+		},
+		renameEdits: func(u protocol.DocumentUri) *protocol.WorkspaceEdit {
+			return &protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+					u: {
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 4, Character: 8},
+								End:   protocol.Position{Line: 4, Character: 10},
+							},
+						},
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 5, Character: 2},
+								End:   protocol.Position{Line: 5, Character: 4},
+							},
+						},
+					},
+				},
+			}
+		},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 10},
+			},
+			Placeholder: "db",
+		},
+	},
+	{
+		name: "read highlight on the service of a links SERVICE:ALIAS entry",
+		content: `
+services:
+  web:
+    links:
+      - db:database
+  db:
+    image: postgres`,
+		line:      4,
+		character: 9,
+		locations: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(false, protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 2},
+				End:   protocol.Position{Line: 5, Character: 4},
+			}, nil, u)
+		},
+		links: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(true, protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 2},
+				End:   protocol.Position{Line: 5, Character: 4},
+			}, &protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 10},
+			}, u)
+		},
+		ranges: []protocol.DocumentHighlight{
+			documentHighlight(4, 8, 4, 10, protocol.DocumentHighlightKindRead),
+			documentHighlight(5, 2, 5, 4, protocol.DocumentHighlightKindWrite),
+		},
+		renameEdits: func(u protocol.DocumentUri) *protocol.WorkspaceEdit {
+			return &protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+					u: {
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 4, Character: 8},
+								End:   protocol.Position{Line: 4, Character: 10},
+							},
+						},
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 5, Character: 2},
+								End:   protocol.Position{Line: 5, Character: 4},
+							},
+						},
+					},
+				},
+			}
+		},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 10},
+			},
+			Placeholder: "db",
+		},
+	},
+	{
+		name: "write highlight on a service declaration that is linked to",
+		content: `
+services:
+  web:
+    links:
+      - db:database
+  db:
+    image: postgres`,
+		line:      5,
+		character: 3,
+		locations: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(false, protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 2},
+				End:   protocol.Position{Line: 5, Character: 4},
+			}, nil, u)
+		},
+		links: func(u protocol.DocumentUri) any {
+			return types.CreateDefinitionResult(true, protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 2},
+				End:   protocol.Position{Line: 5, Character: 4},
+			}, &protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 2},
+				End:   protocol.Position{Line: 5, Character: 4},
+			}, u)
+		},
+		ranges: []protocol.DocumentHighlight{
+			documentHighlight(4, 8, 4, 10, protocol.DocumentHighlightKindRead),
+			documentHighlight(5, 2, 5, 4, protocol.DocumentHighlightKindWrite),
+		},
+		renameEdits: func(u protocol.DocumentUri) *protocol.WorkspaceEdit {
+			return &protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+					u: {
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 4, Character: 8},
+								End:   protocol.Position{Line: 4, Character: 10},
+							},
+						},
+						{
+							NewText: "newName",
+							Range: protocol.Range{
+								Start: protocol.Position{Line: 5, Character: 2},
+								End:   protocol.Position{Line: 5, Character: 4},
+							},
+						},
+					},
+				},
+			}
+		},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 2},
+				End:   protocol.Position{Line: 5, Character: 4},
+			},
+			Placeholder: "db",
+		},
+	},
+	{
+		name: "alias of a links SERVICE:ALIAS entry has no references",
+		content: `
+services:
+  web:
+    links:
+      - db:database
+  db:
+    image: postgres`,
+		line:          4,
+		character:     15,
+		locations:     func(u protocol.DocumentUri) any { return nil },
+		links:         func(u protocol.DocumentUri) any { return nil },
+		ranges:        nil,
+		renameEdits:   func(u protocol.DocumentUri) *protocol.WorkspaceEdit { return nil },
+		prepareRename: nil,
+	},
 	{
 		name: "invalid services value",
 		content: `
@@ -1842,7 +2323,7 @@ var networkReferenceTestCases = []struct {
 	links         func(protocol.DocumentUri) any
 	ranges        []protocol.DocumentHighlight
 	renameEdits   func(protocol.DocumentUri) *protocol.WorkspaceEdit
-	prepareRename *protocol.Range
+	prepareRename *protocol.RangeWithPlaceholder
 }{
 	{
 		name: "write highlight on a network",
@@ -1884,9 +2365,12 @@ networks:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1929,9 +2413,12 @@ networks: &anchor
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -1963,9 +2450,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -1997,9 +2487,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2032,9 +2525,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2075,9 +2571,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2132,9 +2631,12 @@ networks:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2189,9 +2691,12 @@ networks:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 2},
-			End:   protocol.Position{Line: 6, Character: 7},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 2},
+				End:   protocol.Position{Line: 6, Character: 7},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2247,9 +2752,12 @@ networks:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2305,9 +2813,12 @@ networks:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 2},
-			End:   protocol.Position{Line: 7, Character: 7},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 2},
+				End:   protocol.Position{Line: 7, Character: 7},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2355,9 +2866,12 @@ networks:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 10},
-			End:   protocol.Position{Line: 2, Character: 16},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 10},
+				End:   protocol.Position{Line: 2, Character: 16},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -2413,9 +2927,12 @@ networks:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 8},
-			End:   protocol.Position{Line: 5, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 8},
+				End:   protocol.Position{Line: 5, Character: 14},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -2471,9 +2988,12 @@ networks:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 2},
-			End:   protocol.Position{Line: 7, Character: 8},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 2},
+				End:   protocol.Position{Line: 7, Character: 8},
+			},
+			Placeholder: "second",
 		},
 	},
 }
@@ -2500,7 +3020,7 @@ var volumeReferenceTestCases = []struct {
 	links         func(protocol.DocumentUri) any
 	ranges        []protocol.DocumentHighlight
 	renameEdits   func(protocol.DocumentUri) *protocol.WorkspaceEdit
-	prepareRename *protocol.Range
+	prepareRename *protocol.RangeWithPlaceholder
 }{
 	{
 		name: "write highlight on a volumes",
@@ -2542,9 +3062,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -2587,9 +3110,12 @@ volumes: &anchor
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -2621,9 +3147,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2655,9 +3184,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2689,9 +3221,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2723,9 +3258,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 16},
-			End:   protocol.Position{Line: 4, Character: 21},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 16},
+				End:   protocol.Position{Line: 4, Character: 21},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2757,9 +3295,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2791,9 +3332,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 9},
-			End:   protocol.Position{Line: 4, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 9},
+				End:   protocol.Position{Line: 4, Character: 14},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2859,9 +3403,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 16},
-			End:   protocol.Position{Line: 4, Character: 21},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 16},
+				End:   protocol.Position{Line: 4, Character: 21},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2893,9 +3440,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 24},
-			End:   protocol.Position{Line: 4, Character: 29},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 24},
+				End:   protocol.Position{Line: 4, Character: 29},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2927,9 +3477,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 24},
-			End:   protocol.Position{Line: 4, Character: 29},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 24},
+				End:   protocol.Position{Line: 4, Character: 29},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -2984,9 +3537,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 26},
-			End:   protocol.Position{Line: 4, Character: 29},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 26},
+				End:   protocol.Position{Line: 4, Character: 29},
+			},
+			Placeholder: "vol",
 		},
 	},
 	{
@@ -3041,9 +3597,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 16},
-			End:   protocol.Position{Line: 4, Character: 21},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 16},
+				End:   protocol.Position{Line: 4, Character: 21},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3092,9 +3651,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3126,9 +3688,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3159,9 +3724,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 3, Character: 23},
-			End:   protocol.Position{Line: 3, Character: 28},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 3, Character: 23},
+				End:   protocol.Position{Line: 3, Character: 28},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3202,9 +3770,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3259,9 +3830,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3316,9 +3890,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 2},
-			End:   protocol.Position{Line: 6, Character: 7},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 2},
+				End:   protocol.Position{Line: 6, Character: 7},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3373,9 +3950,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3423,9 +4003,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 10},
-			End:   protocol.Position{Line: 2, Character: 16},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 10},
+				End:   protocol.Position{Line: 2, Character: 16},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -3481,9 +4064,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 8},
-			End:   protocol.Position{Line: 5, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 8},
+				End:   protocol.Position{Line: 5, Character: 14},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -3539,9 +4125,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 2},
-			End:   protocol.Position{Line: 7, Character: 8},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 2},
+				End:   protocol.Position{Line: 7, Character: 8},
+			},
+			Placeholder: "second",
 		},
 	},
 }
@@ -3568,7 +4157,7 @@ var configReferenceTestCases = []struct {
 	links         func(protocol.DocumentUri) any
 	ranges        []protocol.DocumentHighlight
 	renameEdits   func(protocol.DocumentUri) *protocol.WorkspaceEdit
-	prepareRename *protocol.Range
+	prepareRename *protocol.RangeWithPlaceholder
 }{
 	{
 		name: "write highlight on a configs",
@@ -3610,9 +4199,12 @@ configs:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -3655,9 +4247,12 @@ configs: &anchor
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -3689,9 +4284,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3749,9 +4347,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3806,9 +4407,12 @@ configs:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3863,9 +4467,12 @@ configs:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 2},
-			End:   protocol.Position{Line: 6, Character: 7},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 2},
+				End:   protocol.Position{Line: 6, Character: 7},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -3913,9 +4520,12 @@ configs:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 10},
-			End:   protocol.Position{Line: 2, Character: 16},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 10},
+				End:   protocol.Position{Line: 2, Character: 16},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -3971,9 +4581,12 @@ configs:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 8},
-			End:   protocol.Position{Line: 5, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 8},
+				End:   protocol.Position{Line: 5, Character: 14},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -4029,9 +4642,12 @@ configs:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 2},
-			End:   protocol.Position{Line: 7, Character: 8},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 2},
+				End:   protocol.Position{Line: 7, Character: 8},
+			},
+			Placeholder: "second",
 		},
 	},
 }
@@ -4058,7 +4674,7 @@ var secretReferenceTestCases = []struct {
 	links         func(protocol.DocumentUri) any
 	ranges        []protocol.DocumentHighlight
 	renameEdits   func(protocol.DocumentUri) *protocol.WorkspaceEdit
-	prepareRename *protocol.Range
+	prepareRename *protocol.RangeWithPlaceholder
 }{
 	{
 		name: "write highlight on a secrets",
@@ -4100,9 +4716,12 @@ secrets:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -4145,9 +4764,12 @@ secrets: &anchor
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -4179,9 +4801,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4239,9 +4864,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4296,9 +4924,12 @@ secrets:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4353,9 +4984,12 @@ secrets:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 2},
-			End:   protocol.Position{Line: 6, Character: 7},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 2},
+				End:   protocol.Position{Line: 6, Character: 7},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4403,9 +5037,12 @@ secrets:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 10},
-			End:   protocol.Position{Line: 2, Character: 16},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 10},
+				End:   protocol.Position{Line: 2, Character: 16},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -4461,9 +5098,12 @@ secrets:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 8},
-			End:   protocol.Position{Line: 5, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 8},
+				End:   protocol.Position{Line: 5, Character: 14},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -4519,9 +5159,12 @@ secrets:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 2},
-			End:   protocol.Position{Line: 7, Character: 8},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 2},
+				End:   protocol.Position{Line: 7, Character: 8},
+			},
+			Placeholder: "second",
 		},
 	},
 }
@@ -4548,7 +5191,7 @@ var modelReferenceTestCases = []struct {
 	links         func(protocol.DocumentUri) any
 	ranges        []protocol.DocumentHighlight
 	renameEdits   func(protocol.DocumentUri) *protocol.WorkspaceEdit
-	prepareRename *protocol.Range
+	prepareRename *protocol.RangeWithPlaceholder
 }{
 	{
 		name: "write highlight on a models object",
@@ -4590,9 +5233,12 @@ models:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -4635,9 +5281,12 @@ models: &anchor
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 2},
-			End:   protocol.Position{Line: 2, Character: 6},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 2},
+				End:   protocol.Position{Line: 2, Character: 6},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -4669,9 +5318,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4703,9 +5355,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4737,9 +5392,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4771,9 +5429,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 16},
-			End:   protocol.Position{Line: 4, Character: 21},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 16},
+				End:   protocol.Position{Line: 4, Character: 21},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4805,9 +5466,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4839,9 +5503,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4882,9 +5549,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4939,9 +5609,12 @@ models:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 8},
-			End:   protocol.Position{Line: 4, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 13},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -4996,9 +5669,12 @@ models:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 2},
-			End:   protocol.Position{Line: 6, Character: 7},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 2},
+				End:   protocol.Position{Line: 6, Character: 7},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -5053,9 +5729,12 @@ models:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 6},
-			End:   protocol.Position{Line: 4, Character: 11},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 6},
+				End:   protocol.Position{Line: 4, Character: 11},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -5110,9 +5789,12 @@ models:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 2},
-			End:   protocol.Position{Line: 6, Character: 7},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 2},
+				End:   protocol.Position{Line: 6, Character: 7},
+			},
+			Placeholder: "test2",
 		},
 	},
 	{
@@ -5160,9 +5842,12 @@ models:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 10},
-			End:   protocol.Position{Line: 2, Character: 16},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 10},
+				End:   protocol.Position{Line: 2, Character: 16},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -5218,9 +5903,12 @@ models:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 8},
-			End:   protocol.Position{Line: 5, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 8},
+				End:   protocol.Position{Line: 5, Character: 14},
+			},
+			Placeholder: "second",
 		},
 	},
 	{
@@ -5276,9 +5964,12 @@ models:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 2},
-			End:   protocol.Position{Line: 7, Character: 8},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 2},
+				End:   protocol.Position{Line: 7, Character: 8},
+			},
+			Placeholder: "second",
 		},
 	},
 }
@@ -5305,7 +5996,7 @@ var fragmentTestCases = []struct {
 	links         func(protocol.DocumentUri) any
 	ranges        []protocol.DocumentHighlight
 	renameEdits   func(protocol.DocumentUri) *protocol.WorkspaceEdit
-	prepareRename *protocol.Range
+	prepareRename *protocol.RangeWithPlaceholder
 }{
 	{
 		name: "anchor with no alias",
@@ -5348,9 +6039,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 12},
-			End:   protocol.Position{Line: 2, Character: 26},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 12},
+				End:   protocol.Position{Line: 2, Character: 26},
+			},
+			Placeholder: "default-volume",
 		},
 	},
 	{
@@ -5403,9 +6097,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 12},
-			End:   protocol.Position{Line: 2, Character: 26},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 12},
+				End:   protocol.Position{Line: 2, Character: 26},
+			},
+			Placeholder: "default-volume",
 		},
 	},
 	{
@@ -5458,9 +6155,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 12},
-			End:   protocol.Position{Line: 4, Character: 26},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 12},
+				End:   protocol.Position{Line: 4, Character: 26},
+			},
+			Placeholder: "default-volume",
 		},
 	},
 	{
@@ -5522,9 +6222,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 12},
-			End:   protocol.Position{Line: 5, Character: 26},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 12},
+				End:   protocol.Position{Line: 5, Character: 26},
+			},
+			Placeholder: "default-volume",
 		},
 	},
 	{
@@ -5599,9 +6302,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 12},
-			End:   protocol.Position{Line: 5, Character: 17},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 12},
+				End:   protocol.Position{Line: 5, Character: 17},
+			},
+			Placeholder: "redis",
 		},
 	},
 	{
@@ -5659,9 +6365,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 3, Character: 12},
-			End:   protocol.Position{Line: 3, Character: 17},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 3, Character: 12},
+				End:   protocol.Position{Line: 3, Character: 17},
+			},
+			Placeholder: "redis",
 		},
 	},
 	{
@@ -5719,9 +6428,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 9, Character: 12},
-			End:   protocol.Position{Line: 9, Character: 17},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 9, Character: 12},
+				End:   protocol.Position{Line: 9, Character: 17},
+			},
+			Placeholder: "redis",
 		},
 	},
 	{
@@ -5779,9 +6491,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 12},
-			End:   protocol.Position{Line: 7, Character: 17},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 12},
+				End:   protocol.Position{Line: 7, Character: 17},
+			},
+			Placeholder: "redis",
 		},
 	},
 	{
@@ -5839,9 +6554,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 3, Character: 12},
-			End:   protocol.Position{Line: 3, Character: 18},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 3, Character: 12},
+				End:   protocol.Position{Line: 3, Character: 18},
+			},
+			Placeholder: "redis8",
 		},
 	},
 	{
@@ -5897,9 +6615,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 9},
-			End:   protocol.Position{Line: 4, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 9},
+				End:   protocol.Position{Line: 4, Character: 14},
+			},
+			Placeholder: "label",
 		},
 	},
 	{
@@ -5955,9 +6676,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 9},
-			End:   protocol.Position{Line: 7, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 9},
+				End:   protocol.Position{Line: 7, Character: 14},
+			},
+			Placeholder: "label",
 		},
 	},
 	{
@@ -6015,9 +6739,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 15},
-			End:   protocol.Position{Line: 7, Character: 25},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 15},
+				End:   protocol.Position{Line: 7, Character: 25},
+			},
+			Placeholder: "volumeType",
 		},
 	},
 	{
@@ -6070,9 +6797,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 41},
-			End:   protocol.Position{Line: 4, Character: 45},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 41},
+				End:   protocol.Position{Line: 4, Character: 45},
+			},
+			Placeholder: "keys",
 		},
 	},
 	{
@@ -6128,9 +6858,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 14},
-			End:   protocol.Position{Line: 7, Character: 18},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 14},
+				End:   protocol.Position{Line: 7, Character: 18},
+			},
+			Placeholder: "keys",
 		},
 	},
 	{
@@ -6186,9 +6919,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 14},
-			End:   protocol.Position{Line: 4, Character: 18},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 14},
+				End:   protocol.Position{Line: 4, Character: 18},
+			},
+			Placeholder: "keys",
 		},
 	},
 	{
@@ -6244,9 +6980,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 24},
-			End:   protocol.Position{Line: 4, Character: 28},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 24},
+				End:   protocol.Position{Line: 4, Character: 28},
+			},
+			Placeholder: "keys",
 		},
 	},
 	{
@@ -6302,9 +7041,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 9},
-			End:   protocol.Position{Line: 2, Character: 13},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 9},
+				End:   protocol.Position{Line: 2, Character: 13},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -6360,9 +7102,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 6, Character: 10},
-			End:   protocol.Position{Line: 6, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 6, Character: 10},
+				End:   protocol.Position{Line: 6, Character: 14},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -6418,9 +7163,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 10},
-			End:   protocol.Position{Line: 4, Character: 19},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 10},
+				End:   protocol.Position{Line: 4, Character: 19},
+			},
+			Placeholder: "testAgain",
 		},
 	},
 	{
@@ -6476,9 +7224,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 10},
-			End:   protocol.Position{Line: 7, Character: 19},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 10},
+				End:   protocol.Position{Line: 7, Character: 19},
+			},
+			Placeholder: "testAgain",
 		},
 	},
 	{
@@ -6526,9 +7277,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 10},
-			End:   protocol.Position{Line: 2, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 10},
+				End:   protocol.Position{Line: 2, Character: 14},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -6560,9 +7314,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 2, Character: 10},
-			End:   protocol.Position{Line: 2, Character: 14},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 10},
+				End:   protocol.Position{Line: 2, Character: 14},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -6594,9 +7351,12 @@ services:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 4, Character: 14},
-			End:   protocol.Position{Line: 4, Character: 18},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 14},
+				End:   protocol.Position{Line: 4, Character: 18},
+			},
+			Placeholder: "test",
 		},
 	},
 	{
@@ -6655,9 +7415,12 @@ networks:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 7, Character: 9},
-			End:   protocol.Position{Line: 7, Character: 15},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 7, Character: 9},
+				End:   protocol.Position{Line: 7, Character: 15},
+			},
+			Placeholder: "anchor",
 		},
 	},
 	{
@@ -6707,9 +7470,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 17},
-			End:   protocol.Position{Line: 5, Character: 24},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 17},
+				End:   protocol.Position{Line: 5, Character: 24},
+			},
+			Placeholder: "anchor2",
 		},
 	},
 	{
@@ -6757,9 +7523,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 9},
-			End:   protocol.Position{Line: 5, Character: 16},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 9},
+				End:   protocol.Position{Line: 5, Character: 16},
+			},
+			Placeholder: "anchor2",
 		},
 	},
 	{
@@ -6807,9 +7576,12 @@ volumes:
 				},
 			}
 		},
-		prepareRename: &protocol.Range{
-			Start: protocol.Position{Line: 5, Character: 27},
-			End:   protocol.Position{Line: 5, Character: 34},
+		prepareRename: &protocol.RangeWithPlaceholder{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 5, Character: 27},
+				End:   protocol.Position{Line: 5, Character: 34},
+			},
+			Placeholder: "anchor2",
 		},
 	},
 }
@@ -6826,3 +7598,131 @@ func TestDocumentHighlight_Fragments(t *testing.T) {
 		})
 	}
 }
+
+func TestDocumentHighlight_Interpolation(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      protocol.UInteger
+		character protocol.UInteger
+		ranges    []protocol.DocumentHighlight
+	}{
+		{
+			name: "variable referenced in two attributes of the same service",
+			content: `
+services:
+  web:
+    image: nginx:${TAG}
+    container_name: web-${TAG}`,
+			line:      3,
+			character: 20,
+			ranges: []protocol.DocumentHighlight{
+				documentHighlight(3, 19, 3, 22, protocol.DocumentHighlightKindRead),
+				documentHighlight(4, 26, 4, 29, protocol.DocumentHighlightKindRead),
+			},
+		},
+		{
+			name: "variable with a default value and its declaration in environment",
+			content: `
+services:
+  web:
+    image: "nginx:${TAG:-latest}"
+    environment:
+      TAG: 1.27`,
+			line:      3,
+			character: 21,
+			ranges: []protocol.DocumentHighlight{
+				documentHighlight(3, 20, 3, 23, protocol.DocumentHighlightKindRead),
+				documentHighlight(5, 6, 5, 9, protocol.DocumentHighlightKindWrite),
+			},
+		},
+		{
+			name: "declaration in an environment array highlights the references",
+			content: `
+services:
+  web:
+    image: nginx:$TAG
+    environment:
+      - TAG=1.27`,
+			line:      5,
+			character: 9,
+			ranges: []protocol.DocumentHighlight{
+				documentHighlight(3, 18, 3, 21, protocol.DocumentHighlightKindRead),
+				documentHighlight(5, 8, 5, 11, protocol.DocumentHighlightKindWrite),
+			},
+		},
+		{
+			name: "variable nested in the default value of another variable",
+			content: `
+services:
+  web:
+    image: ${IMAGE:-${REGISTRY}/nginx}
+    labels:
+      - registry=${REGISTRY:?registry required}`,
+			line:      3,
+			character: 25,
+			ranges: []protocol.DocumentHighlight{
+				documentHighlight(3, 22, 3, 30, protocol.DocumentHighlightKindRead),
+				documentHighlight(5, 19, 5, 27, protocol.DocumentHighlightKindRead),
+			},
+		},
+		{
+			name: "variables of other services are not highlighted",
+			content: `
+services:
+  web:
+    image: nginx:${TAG}
+  db:
+    image: postgres:${TAG}
+    environment:
+      TAG: 17`,
+			line:      3,
+			character: 20,
+			ranges: []protocol.DocumentHighlight{
+				documentHighlight(3, 19, 3, 22, protocol.DocumentHighlightKindRead),
+			},
+		},
+		{
+			name: "escaped dollar sign is not a variable",
+			content: `
+services:
+  web:
+    command: echo $${TAG}`,
+			line:      3,
+			character: 22,
+			ranges:    nil,
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	u := uri.URI(composeFileURI)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), u, 1, []byte(tc.content))
+			ranges, err := DocumentHighlight(doc, protocol.Position{Line: tc.line, Character: tc.character})
+			require.NoError(t, err)
+			require.Equal(t, tc.ranges, ranges)
+		})
+	}
+}
+
+func TestDocumentHighlight_MalformedSibling(t *testing.T) {
+	content := `
+services:
+  web:
+    image: nginx
+    depends_on:
+      - db
+  broken:
+    image: [nginx
+  db:
+    image: postgres`
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(content))
+	ranges, err := DocumentHighlight(doc, protocol.Position{Line: 5, Character: 9})
+	require.NoError(t, err)
+	require.Equal(t, []protocol.DocumentHighlight{
+		documentHighlight(5, 8, 5, 10, protocol.DocumentHighlightKindRead),
+		documentHighlight(8, 2, 8, 4, protocol.DocumentHighlightKindWrite),
+	}, ranges)
+}
@@ -568,7 +568,7 @@ services:
 services:
   test:
     image: ghcr.io:`,
-			links: nil,
+			links: []protocol.DocumentLink{},
 		},
 		{
 			name: "image: ghcr.io:tag",
@@ -700,7 +700,7 @@ services:
 services:
   test:
     image: mcr.microsoft.com:`,
-			links: nil,
+			links: []protocol.DocumentLink{},
 		},
 		{
 			name: "image: mcr.microsoft.com:tag",
@@ -798,7 +798,7 @@ services:
 services:
   test:
     image: quay.io:`,
-			links: nil,
+			links: []protocol.DocumentLink{},
 		},
 		{
 			name: "image: quay.io:tag",
@@ -0,0 +1,108 @@
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// interpolationDefault returns the default value of the given variable
+// if the variable has been referenced with the ${VAR:-default} or the
+// ${VAR-default} syntax. The default may itself reference variables.
+func interpolationDefault(value string, variable interpolationVariable) (string, bool) {
+	if variable.start < 2 || value[variable.start-1] != '{' {
+		return "", false
+	}
+	rest := value[variable.start+len(variable.name):]
+	if strings.HasPrefix(rest, ":-") {
+		rest = rest[2:]
+	} else if strings.HasPrefix(rest, "-") {
+		rest = rest[1:]
+	} else {
+		return "", false
+	}
+
+	depth := 0
+	for i := range len(rest) {
+		switch rest[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return rest[:i], true
+			}
+			depth--
+		}
+	}
+	return "", false
+}
+
+// GenerateEnvTemplate returns the content of a .env file that declares
+// every variable that is interpolated in the given Compose file in the
+// order that they are first referenced in. A variable is assigned the
+// first default value that it has been given or nothing if it has no
+// default. Variables that are already declared in the .env file next to
+// the Compose file are left out. An empty string is returned if there
+// are no variables to declare.
+func GenerateEnvTemplate(doc document.ComposeDocument) string {
+	file := doc.File()
+	if file == nil {
+		return ""
+	}
+
+	declared := []string{}
+	if documentPath, err := doc.DocumentPath(); err == nil {
+		_, envFilePath := types.Concatenate(documentPath.Folder, ".env", documentPath.WSLDollarSignHost)
+		declared = envFileVariables(envFilePath)
+	}
+
+	names := []string{}
+	defaults := map[string]string{}
+	var walk func(node ast.Node)
+	walk = func(node ast.Node) {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			walk(n.Value)
+		case *ast.MappingNode:
+			for _, child := range n.Values {
+				walk(child.Value)
+			}
+		case *ast.MappingValueNode:
+			walk(n.Value)
+		case *ast.SequenceNode:
+			for _, item := range n.Values {
+				walk(item)
+			}
+		case *ast.StringNode:
+			for _, variable := range interpolationVariables(n.Value) {
+				if slices.Contains(declared, variable.name) {
+					continue
+				}
+				if !slices.Contains(names, variable.name) {
+					names = append(names, variable.name)
+				}
+				if _, ok := defaults[variable.name]; !ok {
+					if value, ok := interpolationDefault(n.Value, variable); ok {
+						defaults[variable.name] = value
+					}
+				}
+			}
+		}
+	}
+	for _, documentNode := range file.Docs {
+		walk(documentNode.Body)
+	}
+
+	if len(names) == 0 {
+		return ""
+	}
+	lines := []string{}
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%v=%v", name, defaults[name]))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
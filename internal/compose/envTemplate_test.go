@@ -0,0 +1,105 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestGenerateEnvTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		env      string
+		template string
+	}{
+		{
+			name: "interpolation forms",
+			content: `
+services:
+  web:
+    image: $IMAGE
+    container_name: ${NAME}
+    environment:
+      - PORT=${PORT:-8080}
+      - HOST=${HOST-localhost}
+      - TOKEN=${TOKEN:?token is required}
+      - MODE=${MODE:+debug}`,
+			template: "IMAGE=\nNAME=\nPORT=8080\nHOST=localhost\nTOKEN=\nMODE=\n",
+		},
+		{
+			name: "variable referenced in a default",
+			content: `
+services:
+  web:
+    image: ${IMAGE:-${REGISTRY:-docker.io}/nginx}`,
+			template: "IMAGE=${REGISTRY:-docker.io}/nginx\nREGISTRY=docker.io\n",
+		},
+		{
+			name: "escaped dollar sign is ignored",
+			content: `
+services:
+  web:
+    command: echo $$HOME ${TAG}`,
+			template: "TAG=\n",
+		},
+		{
+			name: "variables are listed once with their first default",
+			content: `
+x-common: &common
+  image: nginx:${TAG}
+services:
+  web:
+    <<: *common
+    labels:
+      tag: ${TAG:-latest}
+  db:
+    image: postgres:${TAG:-16}`,
+			template: "TAG=latest\n",
+		},
+		{
+			name: "variables in keys are ignored",
+			content: `
+services:
+  ${NAME}:
+    image: nginx`,
+			template: "",
+		},
+		{
+			name: "variables declared in the .env file are left out",
+			content: `
+services:
+  web:
+    image: ${IMAGE}:${TAG:-latest}`,
+			env:      "IMAGE=nginx\n",
+			template: "TAG=latest\n",
+		},
+		{
+			name: "all variables declared in the .env file",
+			content: `
+services:
+  web:
+    image: ${IMAGE}`,
+			env:      "IMAGE=nginx\n",
+			template: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			folder := t.TempDir()
+			if tc.env != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(folder, ".env"), []byte(tc.env), 0644))
+			}
+			composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/")))
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			require.Equal(t, tc.template, GenerateEnvTemplate(doc))
+		})
+	}
+}
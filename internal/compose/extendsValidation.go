@@ -0,0 +1,111 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// extendedService returns the name of the service that the given
+// service extends and the token of that name. Services in other files
+// cannot be followed so an empty name is returned for them. The file
+// attribute is resolved against the document's path so that a service
+// which extends a service of the same name in another file is not
+// mistaken for a service that extends itself.
+func extendedService(documentPath *document.DocumentPath, serviceNode ast.Node) (string, *token.Token) {
+	extends := mappingValue(serviceNode, "extends")
+	if extends == nil {
+		return "", nil
+	}
+
+	var serviceNameNode ast.Node
+	switch n := resolveAnchor(extends.Value).(type) {
+	case *ast.StringNode:
+		serviceNameNode = n
+	case *ast.MappingNode:
+		if file := mappingValue(n, "file"); file != nil {
+			if documentPath == nil {
+				return "", nil
+			}
+			filePath, ok := literalValue(resolveAnchor(file.Value).GetToken().Value)
+			if !ok {
+				return "", nil
+			}
+			_, path := types.Concatenate(documentPath.Folder, filePath, documentPath.WSLDollarSignHost)
+			_, originalPath := types.Concatenate(documentPath.Folder, documentPath.FileName, documentPath.WSLDollarSignHost)
+			if !samePath(originalPath, path) {
+				return "", nil
+			}
+		}
+		if service := mappingValue(n, "service"); service != nil {
+			serviceNameNode = resolveAnchor(service.Value)
+		}
+	}
+
+	if stringNode, ok := serviceNameNode.(*ast.StringNode); ok {
+		if name, ok := literalValue(stringNode.Value); ok {
+			return name, stringNode.GetToken()
+		}
+	}
+	return "", nil
+}
+
+// validateExtendsCycles reports the services whose extends attributes
+// form a cycle as Compose will refuse to load such a file. A service
+// that extends itself is reported as a cycle of one service. Services
+// that extend a service in a cycle without being a part of it are not
+// reported.
+func validateExtendsCycles(source string, documentPath *document.DocumentPath, root *ast.MappingNode) []protocol.Diagnostic {
+	services := mappingValue(root, "services")
+	if services == nil {
+		return nil
+	}
+	servicesNode, ok := resolveAnchor(services.Value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+
+	names := []string{}
+	edges := map[string]string{}
+	tokens := map[string]*token.Token{}
+	for _, serviceNode := range servicesNode.Values {
+		name := resolveAnchor(serviceNode.Key).GetToken().Value
+		names = append(names, name)
+		if extended, t := extendedService(documentPath, serviceNode.Value); extended != "" {
+			edges[name] = extended
+			tokens[name] = t
+		}
+	}
+
+	// every service extends at most one other service so a cycle is
+	// found by following the extends chain until it returns to the
+	// service it started from
+	diagnostics := []protocol.Diagnostic{}
+	for _, name := range names {
+		cycle := []string{name}
+		next, ok := edges[name]
+		for ok && next != name && len(cycle) <= len(names) {
+			cycle = append(cycle, next)
+			next, ok = edges[next]
+		}
+		if !ok || next != name {
+			continue
+		}
+
+		t := tokens[name]
+		cycle = append(cycle, name)
+		diagnostics = append(diagnostics, createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"CircularExtends",
+			fmt.Sprintf("service %v extends itself through the cycle %v", name, strings.Join(cycle, " -> ")),
+			createRange(t, len(t.Value)),
+		))
+	}
+	return diagnostics
+}
@@ -0,0 +1,50 @@
+package compose
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+)
+
+// regionMarker checks if the line is a comment that starts with the
+// given marker. The marker must be followed by whitespace or the end
+// of the comment so that a marker of region does not match regional.
+func regionMarker(line, marker string) bool {
+	comment, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+	if !ok {
+		return false
+	}
+	rest, ok := strings.CutPrefix(strings.TrimSpace(comment), marker)
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// FoldingRange returns the regions of the Compose file that have been
+// delimited by comments that start with the given markers. Regions may
+// be nested and markers without a matching start or end are ignored.
+func FoldingRange(doc document.ComposeDocument, regionStart, regionEnd string) ([]protocol.FoldingRange, error) {
+	kind := string(protocol.FoldingRangeKindRegion)
+	ranges := []protocol.FoldingRange{}
+	starts := []int{}
+	for i, line := range strings.Split(string(doc.Input()), "\n") {
+		// the end marker is checked first in case the start marker is
+		// a prefix of it such as region and region-end
+		if regionMarker(line, regionEnd) {
+			if len(starts) > 0 {
+				ranges = append(ranges, protocol.FoldingRange{
+					StartLine: protocol.UInteger(starts[len(starts)-1]),
+					EndLine:   protocol.UInteger(i),
+					Kind:      &kind,
+				})
+				starts = starts[:len(starts)-1]
+			}
+		} else if regionMarker(line, regionStart) {
+			starts = append(starts, i)
+		}
+	}
+	slices.SortFunc(ranges, func(a, b protocol.FoldingRange) int {
+		return int(a.StartLine) - int(b.StartLine)
+	})
+	return ranges, nil
+}
@@ -0,0 +1,159 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func regionRange(startLine, endLine protocol.UInteger) protocol.FoldingRange {
+	kind := string(protocol.FoldingRangeKindRegion)
+	return protocol.FoldingRange{StartLine: startLine, EndLine: endLine, Kind: &kind}
+}
+
+func TestFoldingRange(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		regionStart string
+		regionEnd   string
+		ranges      []protocol.FoldingRange
+	}{
+		{
+			name: "no markers",
+			content: `
+services:
+  # a comment
+  web:
+    image: nginx`,
+			regionStart: "region",
+			regionEnd:   "endregion",
+			ranges:      []protocol.FoldingRange{},
+		},
+		{
+			name: "single region",
+			content: `
+services:
+  # region web
+  web:
+    image: nginx
+  # endregion`,
+			regionStart: "region",
+			regionEnd:   "endregion",
+			ranges:      []protocol.FoldingRange{regionRange(2, 5)},
+		},
+		{
+			name: "markers without a space after the hash",
+			content: `
+#region
+services:
+  web:
+    image: nginx
+#endregion`,
+			regionStart: "region",
+			regionEnd:   "endregion",
+			ranges:      []protocol.FoldingRange{regionRange(1, 5)},
+		},
+		{
+			name: "nested regions",
+			content: `
+# region services
+services:
+  # region frontend
+  web:
+    image: nginx
+  # endregion
+  # region backend
+  db:
+    image: postgres
+  # endregion
+# endregion`,
+			regionStart: "region",
+			regionEnd:   "endregion",
+			ranges: []protocol.FoldingRange{
+				regionRange(1, 11),
+				regionRange(3, 6),
+				regionRange(7, 10),
+			},
+		},
+		{
+			name: "unmatched markers are ignored",
+			content: `
+# endregion
+services:
+  # region web
+  web:
+    image: nginx
+  # region db`,
+			regionStart: "region",
+			regionEnd:   "endregion",
+			ranges:      []protocol.FoldingRange{},
+		},
+		{
+			name: "words that start with the marker are ignored",
+			content: `
+# regional settings
+services:
+  web:
+    image: nginx
+# endregion`,
+			regionStart: "region",
+			regionEnd:   "endregion",
+			ranges:      []protocol.FoldingRange{},
+		},
+		{
+			name: "markers in values are ignored",
+			content: `
+services:
+  web:
+    image: nginx
+    command: "# region"
+    entrypoint: "# endregion"`,
+			regionStart: "region",
+			regionEnd:   "endregion",
+			ranges:      []protocol.FoldingRange{},
+		},
+		{
+			name: "custom markers",
+			content: `
+# region
+# >>> services
+services:
+  web:
+    image: nginx
+# <<<`,
+			regionStart: ">>>",
+			regionEnd:   "<<<",
+			ranges:      []protocol.FoldingRange{regionRange(2, 6)},
+		},
+		{
+			name: "end marker that starts with the start marker",
+			content: `
+# section web
+services:
+  web:
+    image: nginx
+# section-end`,
+			regionStart: "section",
+			regionEnd:   "section-end",
+			ranges:      []protocol.FoldingRange{regionRange(1, 5)},
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			ranges, err := FoldingRange(doc, tc.regionStart, tc.regionEnd)
+			require.NoError(t, err)
+			require.Equal(t, tc.ranges, ranges)
+		})
+	}
+}
@@ -35,7 +35,9 @@ func indent(indentation int) string {
 
 func Formatting(doc document.ComposeDocument, options protocol.FormattingOptions) ([]protocol.TextEdit, error) {
 	file := doc.File()
-	if file == nil || len(file.Docs) == 0 {
+	// the formatting is based on YAML's indentation rules so it cannot
+	// be applied to a file that has been written in JSON
+	if file == nil || len(file.Docs) == 0 || doc.IsJSON() {
 		return nil, nil
 	}
 	tabSize, err := formattingOptionTabSize(options)
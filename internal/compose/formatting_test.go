@@ -388,3 +388,20 @@ first:
 		})
 	}
 }
+
+func TestFormatting_JSON(t *testing.T) {
+	content := `{
+    "services": {
+       "web": {
+         "image": "alpine"
+       }
+    }
+}`
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.json")), "/"))
+	doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(content))
+	edits, err := Formatting(doc, protocol.FormattingOptions{
+		protocol.FormattingOptionTabSize: float64(2),
+	})
+	require.NoError(t, err)
+	require.Nil(t, edits)
+}
@@ -3,6 +3,8 @@ package compose
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"unicode"
@@ -29,8 +31,12 @@ func Hover(ctx context.Context, params *protocol.HoverParams, doc document.Compo
 
 	for _, documentNode := range file.Docs {
 		if mappingNode, ok := documentNode.Body.(*ast.MappingNode); ok {
+			result := mergeKeyHover(mappingNode, line, character)
+			if result != nil {
+				return result, nil
+			}
 			nodePath := constructNodePath([]ast.Node{}, mappingNode, int(params.Position.Line+1), int(params.Position.Character+1))
-			result := serviceHover(doc, mappingNode, nodePath)
+			result = serviceHover(doc, mappingNode, nodePath)
 			if result != nil {
 				return result, nil
 			}
@@ -54,8 +60,19 @@ func Hover(ctx context.Context, params *protocol.HoverParams, doc document.Compo
 			if result != nil {
 				return result, nil
 			}
+			result = includeHover(doc, mappingNode, line, character)
+			if result != nil {
+				return result, nil
+			}
+			result = enumValueHover(nodePath, len(lines[params.Position.Line])+1)
+			if result != nil {
+				return result, nil
+			}
 			result = hover(composeSchema, nodePath, line, character, len(lines[params.Position.Line])+1)
 			if result != nil {
+				if len(nodePath) == 1 && nodePath[0].GetToken().Value == "name" {
+					addProjectSummary(result, mappingNode)
+				}
 				return result, nil
 			}
 
@@ -77,6 +94,290 @@ func Hover(ctx context.Context, params *protocol.HoverParams, doc document.Compo
 	return nil, nil
 }
 
+// enumValueDocumentation describes the values that can be chosen for
+// the attributes at the given paths of a Compose file. A * in a path
+// matches any name such as the name of a service.
+var enumValueDocumentation = []struct {
+	path   []string
+	values map[string]string
+}{
+	{
+		path: []string{"services", "*", "cgroup"},
+		values: map[string]string{
+			"host":    "Uses the cgroup namespace of the host.",
+			"private": "Uses a private cgroup namespace for the container.",
+		},
+	},
+	{
+		path: []string{"services", "*", "depends_on", "*", "condition"},
+		values: map[string]string{
+			"service_started":                "Waits until the dependency has been started.",
+			"service_healthy":                "Waits until the dependency's healthcheck reports that it is healthy.",
+			"service_completed_successfully": "Waits until the dependency has run to completion and exited with a zero exit code.",
+		},
+	},
+	{
+		path: []string{"services", "*", "deploy", "endpoint_mode"},
+		values: map[string]string{
+			"vip":   "Assigns the service a virtual IP that clients use to reach its replicas.",
+			"dnsrr": "Returns the IP addresses of the service's replicas in DNS round-robin order instead of using a virtual IP.",
+		},
+	},
+	{
+		path: []string{"services", "*", "deploy", "mode"},
+		values: map[string]string{
+			"global":         "Runs exactly one container on every node of the cluster.",
+			"global-job":     "Runs a one-off task on every node of the cluster until it completes.",
+			"replicated":     "Runs the number of containers given by the replicas attribute.",
+			"replicated-job": "Runs a one-off task with the number of replicas given by the replicas attribute until it completes.",
+		},
+	},
+	{
+		path: []string{"services", "*", "deploy", "rollback_config", "order"},
+		values: map[string]string{
+			"start-first": "Starts the new task before the old task is stopped so that the two briefly overlap.",
+			"stop-first":  "Stops the old task before the new task is started.",
+		},
+	},
+	{
+		path: []string{"services", "*", "deploy", "update_config", "order"},
+		values: map[string]string{
+			"start-first": "Starts the new task before the old task is stopped so that the two briefly overlap.",
+			"stop-first":  "Stops the old task before the new task is started.",
+		},
+	},
+	{
+		path: []string{"services", "*", "develop", "watch", "action"},
+		values: map[string]string{
+			"rebuild":      "Rebuilds the service's image and recreates its container when a watched file changes.",
+			"restart":      "Restarts the service's container when a watched file changes.",
+			"sync":         "Copies changed files into the service's container.",
+			"sync+exec":    "Copies changed files into the service's container and then runs the exec command in it.",
+			"sync+restart": "Copies changed files into the service's container and then restarts it.",
+		},
+	},
+	{
+		path: []string{"services", "*", "ports", "mode"},
+		values: map[string]string{
+			"host":    "Publishes the port on every node that runs a container of the service.",
+			"ingress": "Publishes the port through the load balancer of the routing mesh.",
+		},
+	},
+	{
+		path: []string{"services", "*", "ports", "protocol"},
+		values: map[string]string{
+			"tcp": "Publishes the port for TCP traffic.",
+			"udp": "Publishes the port for UDP traffic.",
+		},
+	},
+	{
+		path: []string{"services", "*", "pull_policy"},
+		values: map[string]string{
+			"always":         "Always pulls the image from the registry.",
+			"build":          "Builds the image instead of pulling it.",
+			"daily":          "Pulls the image if it has not been pulled in the last 24 hours.",
+			"if_not_present": "Pulls the image only if it is not available locally. This is the same as missing.",
+			"missing":        "Pulls the image only if it is not available locally.",
+			"never":          "Never pulls the image and fails if it is not available locally.",
+			"weekly":         "Pulls the image if it has not been pulled in the last 7 days.",
+		},
+	},
+	{
+		path: []string{"services", "*", "restart"},
+		values: map[string]string{
+			"always":         "Always restarts the container until it is removed.",
+			"no":             "Never restarts the container.",
+			"on-failure":     "Restarts the container if it exits with a non-zero exit code.",
+			"unless-stopped": "Always restarts the container unless it has been stopped.",
+		},
+	},
+	{
+		path: []string{"services", "*", "volumes", "bind", "recursive"},
+		values: map[string]string{
+			"disabled": "Does not mount the submounts of the source directory.",
+			"enabled":  "Mounts the submounts of the source directory as read-only if the mount is read-only.",
+			"readonly": "Mounts the submounts of the source directory recursively as read-only.",
+			"writable": "Mounts the submounts of the source directory as writable even if the mount is read-only.",
+		},
+	},
+	{
+		path: []string{"services", "*", "volumes", "bind", "selinux"},
+		values: map[string]string{
+			"z": "Relabels the content so that it can be shared between containers.",
+			"Z": "Relabels the content so that it is private to this container.",
+		},
+	},
+	{
+		path: []string{"services", "*", "volumes", "type"},
+		values: map[string]string{
+			"bind":    "Mounts a file or a directory of the host.",
+			"cluster": "Mounts a cluster volume.",
+			"image":   "Mounts the content of an image.",
+			"npipe":   "Mounts a named pipe of the host.",
+			"tmpfs":   "Mounts a temporary file system that is stored in memory.",
+			"volume":  "Mounts a named volume.",
+		},
+	},
+}
+
+// enumValueHover explains the value of an attribute that the cursor is
+// on if it is one of the values documented in enumValueDocumentation.
+// The documentation of the attribute itself follows the explanation.
+func enumValueHover(nodePath []ast.Node, lineLength int) *protocol.Hover {
+	if len(nodePath) < 2 {
+		return nil
+	}
+	keys := nodePath[:len(nodePath)-1]
+	value := nodePath[len(nodePath)-1].GetToken().Value
+	for _, documentation := range enumValueDocumentation {
+		if len(documentation.path) != len(keys) {
+			continue
+		}
+		matches := true
+		for i := range keys {
+			if documentation.path[i] != "*" && documentation.path[i] != keys[i].GetToken().Value {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		description, ok := documentation.values[value]
+		if !ok {
+			return nil
+		}
+
+		content := fmt.Sprintf("`%v`: %v", value, description)
+		key := keys[len(keys)-1].GetToken()
+		if result := hover(composeSchema, keys, key.Position.Line, key.Position.Column, lineLength); result != nil {
+			content = fmt.Sprintf("%v\n\n%v", content, result.Contents.(protocol.MarkupContent).Value)
+		}
+		return &protocol.Hover{
+			Contents: protocol.MarkupContent{
+				Kind:  protocol.MarkupKindMarkdown,
+				Value: content,
+			},
+		}
+	}
+	return nil
+}
+
+// summarizedAttributes are the top-level attributes whose entries are
+// counted when summarizing a Compose file.
+var summarizedAttributes = []struct{ name, singular string }{
+	{name: "services", singular: "service"},
+	{name: "networks", singular: "network"},
+	{name: "volumes", singular: "volume"},
+}
+
+func countOf(count int, singular, plural string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %v", singular)
+	}
+	return fmt.Sprintf("%v %v", count, plural)
+}
+
+// addProjectSummary adds the number of services, networks, and volumes
+// that the Compose file defines to the hover of the project's name.
+func addProjectSummary(result *protocol.Hover, mappingNode *ast.MappingNode) {
+	counts := []string{}
+	for _, attribute := range summarizedAttributes {
+		counts = append(counts, countOf(len(declaredNames(mappingNode, attribute.name)), attribute.singular, attribute.name))
+	}
+
+	summary := fmt.Sprintf("This project defines %v, %v, and %v.", counts[0], counts[1], counts[2])
+	content := result.Contents.(protocol.MarkupContent)
+	content.Value = strings.Replace(content.Value, "\n\nSchema:", fmt.Sprintf("\n\n%v\n\nSchema:", summary), 1)
+	result.Contents = content
+}
+
+// includeHover summarizes the services, networks, and volumes that are
+// defined by the included file whose path is under the cursor. The
+// file is loaded with the other included files and the hover notes
+// why if it could not be loaded.
+func includeHover(doc document.ComposeDocument, mappingNode *ast.MappingNode, line, character int) *protocol.Hover {
+	include := mappingValue(mappingNode, "include")
+	if include == nil {
+		return nil
+	}
+	sequenceNode, ok := resolveAnchor(include.Value).(*ast.SequenceNode)
+	if !ok {
+		return nil
+	}
+
+	for _, t := range includedFiles(sequenceNode.Values) {
+		if t.Position.Line != line || character < t.Position.Column || t.Position.Column+len(t.Value) < character {
+			continue
+		}
+		path, ok := literalValue(t.Value)
+		if !ok || path == "" || strings.Contains(path, "://") {
+			return nil
+		}
+		documentPath, err := doc.DocumentPath()
+		if err != nil {
+			return nil
+		}
+
+		summary := ""
+		includedPath := path
+		if !filepath.IsAbs(includedPath) {
+			includedPath = filepath.Join(documentPath.Folder, path)
+		}
+		files, resolvable := doc.IncludedFiles()
+		file := files[string(document.IncludedFileURI(documentPath.Folder, path))]
+		if !resolvable {
+			summary = fmt.Sprintf("`%v` could not be loaded as the included files include each other recursively.", path)
+		} else if file == nil {
+			if _, err := os.Stat(includedPath); err != nil {
+				summary = fmt.Sprintf("`%v` could not be found.", path)
+			} else {
+				summary = fmt.Sprintf("`%v` could not be parsed.", path)
+			}
+		} else {
+			summary = fmt.Sprintf("`%v` defines %v.", path, includedSummary(file))
+		}
+
+		r := createRange(t, len(t.Value))
+		return &protocol.Hover{
+			Contents: protocol.MarkupContent{
+				Kind:  protocol.MarkupKindMarkdown,
+				Value: summary,
+			},
+			Range: &r,
+		}
+	}
+	return nil
+}
+
+// includedSummary lists the number and the names of the services,
+// networks, and volumes that the given file defines. The names are
+// gathered the same way as the file's document symbols.
+func includedSummary(file *ast.File) string {
+	names := map[string][]string{}
+	for _, documentNode := range file.Docs {
+		if mappingNode, ok := documentNode.Body.(*ast.MappingNode); ok {
+			for _, attribute := range summarizedAttributes {
+				if n := mappingValue(mappingNode, attribute.name); n != nil {
+					for _, symbol := range findSymbols(attribute.name, n, symbolKinds) {
+						names[attribute.name] = append(names[attribute.name], fmt.Sprintf("`%v`", symbol.(*protocol.DocumentSymbol).Name))
+					}
+				}
+			}
+		}
+	}
+
+	counts := []string{}
+	for _, attribute := range summarizedAttributes {
+		count := countOf(len(names[attribute.name]), attribute.singular, attribute.name)
+		if len(names[attribute.name]) > 0 {
+			count = fmt.Sprintf("%v (%v)", count, strings.Join(names[attribute.name], ", "))
+		}
+		counts = append(counts, count)
+	}
+	return fmt.Sprintf("%v, %v, and %v", counts[0], counts[1], counts[2])
+}
+
 func createYamlHover(node ast.Node, hovered *token.Token) *protocol.Hover {
 	split := strings.Split(node.String(), "\n")
 	// remove leading empty line inserted by goccy/go-yaml if present
@@ -331,9 +632,16 @@ func hover(schema *jsonschema.Schema, nodes []ast.Node, line, column, lineLength
 				}
 			}
 
-			if match.GetToken().Position.Line == line && match.GetToken().Position.Column+len(match.GetToken().Value) >= column && property.Description != "" {
+			summary := schemaSummary(property)
+			if match.GetToken().Position.Line == line && match.GetToken().Position.Column+len(match.GetToken().Value) >= column && (property.Description != "" || summary != "") {
 				var builder strings.Builder
 				builder.WriteString(property.Description)
+				if summary != "" {
+					if property.Description != "" {
+						builder.WriteString("\n\n")
+					}
+					builder.WriteString(summary)
+				}
 				builder.WriteString("\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)")
 				switch nodes[0].GetToken().Value {
 				case "name":
@@ -392,6 +700,35 @@ func hover(schema *jsonschema.Schema, nodes []ast.Node, line, column, lineLength
 	return nil
 }
 
+// schemaSummary describes the types of the values that the given schema
+// accepts and the constraints that it places on them. An empty string
+// is returned if the schema does not declare any types.
+func schemaSummary(schema *jsonschema.Schema) string {
+	schemaTypes := *extractDetail(schema)
+	if schemaTypes == "" {
+		return ""
+	}
+
+	constraints := []string{}
+	for _, expanded := range expandSchemas([]*jsonschema.Schema{schema}) {
+		if expanded.Minimum != nil {
+			constraints = append(constraints, fmt.Sprintf("minimum `%v`", expanded.Minimum.RatString()))
+		}
+		if expanded.Maximum != nil {
+			constraints = append(constraints, fmt.Sprintf("maximum `%v`", expanded.Maximum.RatString()))
+		}
+		if expanded.Pattern != nil {
+			constraints = append(constraints, fmt.Sprintf("pattern `%v`", expanded.Pattern))
+		}
+	}
+
+	summary := fmt.Sprintf("Type: `%v`", schemaTypes)
+	if len(constraints) > 0 {
+		summary = fmt.Sprintf("%v\n\nConstraints: %v", summary, strings.Join(constraints, ", "))
+	}
+	return summary
+}
+
 func constructNodePath(matches []ast.Node, node ast.Node, line, col int) []ast.Node {
 	node = resolveAnchor(node)
 	switch n := node.(type) {
@@ -30,7 +30,7 @@ func TestHover_SchemaDocumentation(t *testing.T) {
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "declared for backward compatibility, ignored. Please remove it.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/version-and-name/)",
+					Value: "declared for backward compatibility, ignored. Please remove it.\n\nType: `string`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/version-and-name/)",
 				},
 			},
 		},
@@ -42,7 +42,19 @@ func TestHover_SchemaDocumentation(t *testing.T) {
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "define the Compose project name, until user defines one explicitly.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/version-and-name/)",
+					Value: "define the Compose project name, until user defines one explicitly.\n\nType: `string`\n\nThis project defines 0 services, 0 networks, and 0 volumes.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/version-and-name/)",
+				},
+			},
+		},
+		{
+			name:      "name description with a summary of the project",
+			content:   "name: customName\nservices:\n  web:\n    image: nginx\n  db:\n    image: postgres\nnetworks:\n  backend:\nvolumes:\n  data:\n  logs:",
+			line:      0,
+			character: 2,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "define the Compose project name, until user defines one explicitly.\n\nType: `string`\n\nThis project defines 2 services, 1 network, and 2 volumes.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/version-and-name/)",
 				},
 			},
 		},
@@ -68,7 +80,7 @@ func TestHover_SchemaDocumentation(t *testing.T) {
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "compose sub-projects to be included.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/include/)",
+					Value: "compose sub-projects to be included.\n\nType: `array`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/include/)",
 				},
 			},
 		},
@@ -81,7 +93,7 @@ func TestHover_SchemaDocumentation(t *testing.T) {
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "Path to resolve relative paths set in the Compose file\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/include/#project_directory)",
+					Value: "Path to resolve relative paths set in the Compose file\n\nType: `string`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/include/#project_directory)",
 				},
 			},
 		},
@@ -194,7 +206,7 @@ services:
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "Recursively mount the source directory.\n\nAllowed values:\n- `disabled`\n- `enabled`\n- `readonly`\n- `writable`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#volumes)",
+					Value: "`enabled`: Mounts the submounts of the source directory as read-only if the mount is read-only.\n\nRecursively mount the source directory.\n\nAllowed values:\n- `disabled`\n- `enabled`\n- `readonly`\n- `writable`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#volumes)",
 				},
 			},
 		},
@@ -212,7 +224,68 @@ services:
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "Recursively mount the source directory.\n\nAllowed values:\n- `disabled`\n- `enabled`\n- `readonly`\n- `writable`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#volumes)",
+					Value: "`enabled`: Mounts the submounts of the source directory as read-only if the mount is read-only.\n\nRecursively mount the source directory.\n\nAllowed values:\n- `disabled`\n- `enabled`\n- `readonly`\n- `writable`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#volumes)",
+				},
+			},
+		},
+		{
+			name: "pull_policy value that is not an enum in the schema",
+			content: `
+services:
+  test:
+    pull_policy: if_not_present`,
+			line:      3,
+			character: 20,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "`if_not_present`: Pulls the image only if it is not available locally. This is the same as missing.\n\nPolicy for pulling images. Options include: 'always', 'never', 'if_not_present', 'missing', 'build', or time-based refresh policies.\n\nType: `string`\n\nConstraints: pattern `always|never|build|if_not_present|missing|refresh|daily|weekly|every_([0-9]+[wdhms])+`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#pull_policy)",
+				},
+			},
+		},
+		{
+			name: "deploy mode value",
+			content: `
+services:
+  test:
+    deploy:
+      mode: global`,
+			line:      4,
+			character: 14,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "`global`: Runs exactly one container on every node of the cluster.\n\nDeployment mode for the service: 'replicated' (default) or 'global'.\n\nType: `string`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#deploy)",
+				},
+			},
+		},
+		{
+			name: "cgroup enum value",
+			content: `
+services:
+  test:
+    cgroup: host`,
+			line:      3,
+			character: 14,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "`host`: Uses the cgroup namespace of the host.\n\nSpecify the cgroup namespace to join. Use 'host' to use the host's cgroup namespace, or 'private' to use a private cgroup namespace.\n\nAllowed values:\n- `host`\n- `private`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#cgroup)",
+				},
+			},
+		},
+		{
+			name: "undocumented enum value falls back to the attribute's documentation",
+			content: `
+services:
+  test:
+    cgroup: other`,
+			line:      3,
+			character: 14,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "Specify the cgroup namespace to join. Use 'host' to use the host's cgroup namespace, or 'private' to use a private cgroup namespace.\n\nAllowed values:\n- `host`\n- `private`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#cgroup)",
 				},
 			},
 		},
@@ -311,7 +384,7 @@ services:
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "Specify the image to start the container from. Can be a repository/tag, a digest, or a local image ID.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#image)",
+					Value: "Specify the image to start the container from. Can be a repository/tag, a digest, or a local image ID.\n\nType: `string`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#image)",
 				},
 			},
 		},
@@ -368,7 +441,59 @@ services:
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "Specify a custom container name, rather than a generated default name.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#container_name)",
+					Value: "Specify a custom container name, rather than a generated default name.\n\nType: `string`\n\nConstraints: pattern `[a-zA-Z0-9][a-zA-Z0-9_.-]+`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#container_name)",
+				},
+			},
+		},
+		{
+			name: "cpu_percent attribute with a minimum and a maximum",
+			content: `
+services:
+  test:
+    cpu_percent: 50`,
+			line:      3,
+			character: 7,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "Percentage of CPU resources to use.\n\nType: `integer or string`\n\nConstraints: minimum `0`, maximum `100`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#cpu_percent)",
+				},
+			},
+		},
+		{
+			name: "deeply nested attribute of a service",
+			content: `
+services:
+  test:
+    deploy:
+      resources:
+        limits:
+          pids: 10`,
+			line:      6,
+			character: 11,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "Maximum number of PIDs available to the container.\n\nType: `integer or string`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#deploy)",
+				},
+			},
+		},
+		{
+			name: "deeply nested attribute of a volume array item",
+			content: `
+services:
+  test:
+    volumes:
+      - type: tmpfs
+        target: /data
+        tmpfs:
+          size: 1024`,
+			line:      7,
+			character: 11,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "Size of the tmpfs mount in bytes.\n\nType: `integer or string`\n\nConstraints: minimum `0`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#volumes)",
 				},
 			},
 		},
@@ -380,7 +505,7 @@ services:
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "Specify a custom container name, rather than a generated default name.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#container_name)",
+					Value: "Specify a custom container name, rather than a generated default name.\n\nType: `string`\n\nConstraints: pattern `[a-zA-Z0-9][a-zA-Z0-9_.-]+`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#container_name)",
 				},
 			},
 		},
@@ -392,7 +517,7 @@ services:
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "Specify a custom container name, rather than a generated default name.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#container_name)",
+					Value: "Specify a custom container name, rather than a generated default name.\n\nType: `string`\n\nConstraints: pattern `[a-zA-Z0-9][a-zA-Z0-9_.-]+`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#container_name)",
 				},
 			},
 		},
@@ -407,7 +532,7 @@ services:
 			result: &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.MarkupKindMarkdown,
-					Value: "Development configuration for the service, used for development workflows.\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#develop)",
+					Value: "Development configuration for the service, used for development workflows.\n\nType: `null or object`\n\nSchema: [compose-spec.json](https://raw.githubusercontent.com/compose-spec/compose-spec/master/schema/compose-spec.json)\n\n[Online documentation](https://docs.docker.com/reference/compose-file/services/#develop)",
 				},
 			},
 		},
@@ -1391,6 +1516,139 @@ services:
 	}
 }
 
+func TestHover_MergeKey(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      uint32
+		character uint32
+		result    *protocol.Hover
+	}{
+		{
+			name: "local override shadows a merged key",
+			content: `
+x-base: &base
+  image: alpine
+  restart: always
+services:
+  test:
+    <<: *base
+    restart: "no"`,
+			line:      6,
+			character: 5,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "Merged from `*base`:\n- `image`\n- `restart` (overridden locally)",
+				},
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: 6, Character: 4},
+					End:   protocol.Position{Line: 6, Character: 6},
+				},
+			},
+		},
+		{
+			name: "sequence of aliases where the earlier alias takes precedence",
+			content: `
+x-base: &base
+  image: alpine
+  restart: always
+x-other: &other
+  image: nginx
+  init: true
+services:
+  test:
+    <<: [*base, *other]
+    init: false`,
+			line:      9,
+			character: 4,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "Merged from `*base`:\n- `image`\n- `restart`\n\nMerged from `*other`:\n- `image` (overridden by `*base`)\n- `init` (overridden locally)",
+				},
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: 9, Character: 4},
+					End:   protocol.Position{Line: 9, Character: 6},
+				},
+			},
+		},
+		{
+			name: "merged mapping with its own merge key",
+			content: `
+x-common: &common
+  init: true
+x-base: &base
+  <<: *common
+  image: alpine
+services:
+  test:
+    <<: *base`,
+			line:      8,
+			character: 5,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "Merged from `*base`:\n- `image`",
+				},
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: 8, Character: 4},
+					End:   protocol.Position{Line: 8, Character: 6},
+				},
+			},
+		},
+		{
+			name: "alias that cannot be resolved",
+			content: `
+services:
+  test:
+    <<: *missing
+    image: alpine`,
+			line:      3,
+			character: 5,
+			result:    nil,
+		},
+		{
+			name: "one of the aliases of a sequence cannot be resolved",
+			content: `
+x-base: &base
+  image: alpine
+services:
+  test:
+    <<: [*base, *missing]`,
+			line:      5,
+			character: 5,
+			result:    nil,
+		},
+		{
+			name: "alias of a scalar",
+			content: `
+x-image: &image alpine
+services:
+  test:
+    <<: *image`,
+			line:      4,
+			character: 5,
+			result:    nil,
+		},
+	}
+
+	composeFile := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFile), 1, []byte(tc.content))
+			result, err := Hover(context.Background(), &protocol.HoverParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: composeFile},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, doc)
+			require.NoError(t, err)
+			require.Equal(t, tc.result, result)
+		})
+	}
+}
+
 func TestHover_InterFileSupport(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -1543,6 +1801,77 @@ volumes:
 				},
 			},
 		},
+		{
+			name: "hovering over an included file summarizes its contents",
+			content: `
+include:
+  - compose.other.yaml`,
+			otherContent: `
+services:
+  web:
+    image: nginx
+  db:
+    image: postgres
+networks:
+  front:
+volumes: {}`,
+			line:      2,
+			character: 8,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "`compose.other.yaml` defines 2 services (`web`, `db`), 1 network (`front`), and 0 volumes.",
+				},
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: 2, Character: 4},
+					End:   protocol.Position{Line: 2, Character: 22},
+				},
+			},
+		},
+		{
+			name: "hovering over an included file in the path attribute of an include object",
+			content: `
+include:
+  - path: compose.other.yaml
+    project_directory: .`,
+			otherContent: `
+services:
+  web:
+    image: nginx
+volumes:
+  data:`,
+			line:      2,
+			character: 15,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "`compose.other.yaml` defines 1 service (`web`), 0 networks, and 1 volume (`data`).",
+				},
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: 2, Character: 10},
+					End:   protocol.Position{Line: 2, Character: 28},
+				},
+			},
+		},
+		{
+			name: "hovering over an included file that is missing",
+			content: `
+include:
+  - compose.missing.yaml`,
+			otherContent: ``,
+			line:         2,
+			character:    8,
+			result: &protocol.Hover{
+				Contents: protocol.MarkupContent{
+					Kind:  protocol.MarkupKindMarkdown,
+					Value: "`compose.missing.yaml` could not be found.",
+				},
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: 2, Character: 4},
+					End:   protocol.Position{Line: 2, Character: 24},
+				},
+			},
+		},
 	}
 
 	composeFile := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
@@ -1565,3 +1894,29 @@ volumes:
 		})
 	}
 }
+
+func TestHover_UnparsableInclude(t *testing.T) {
+	folder := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "compose.broken.yaml"), []byte("services:\n  web:\n    image: [nginx"), 0644))
+
+	composeFile := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/"))
+	content := "include:\n  - compose.broken.yaml"
+	doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFile), 1, []byte(content))
+	result, err := Hover(context.Background(), &protocol.HoverParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: composeFile},
+			Position:     protocol.Position{Line: 1, Character: 8},
+		},
+	}, doc)
+	require.NoError(t, err)
+	require.Equal(t, &protocol.Hover{
+		Contents: protocol.MarkupContent{
+			Kind:  protocol.MarkupKindMarkdown,
+			Value: "`compose.broken.yaml` could not be parsed.",
+		},
+		Range: &protocol.Range{
+			Start: protocol.Position{Line: 1, Character: 4},
+			End:   protocol.Position{Line: 1, Character: 23},
+		},
+	}, result)
+}
@@ -0,0 +1,37 @@
+package compose
+
+import (
+	"fmt"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// validateImageReference reports an image or build tag that is not a
+// valid image reference. A reference may also be the ID of a local
+// image. Interpolated values are ignored as they are only known when
+// the file is loaded.
+func validateImageReference(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	s, ok := resolveAnchor(value).(*ast.StringNode)
+	if !ok {
+		return nil
+	}
+	image, ok := literalValue(s.Value)
+	if !ok || image == "" {
+		return nil
+	}
+	if _, err := reference.ParseAnyReference(image); err != nil {
+		t := s.GetToken()
+		return []protocol.Diagnostic{
+			createValidationDiagnostic(
+				source,
+				protocol.DiagnosticSeverityError,
+				"InvalidImageReference",
+				fmt.Sprintf("invalid image reference %v: %v", image, err),
+				createRange(t, len(t.Value)),
+			),
+		}
+	}
+	return nil
+}
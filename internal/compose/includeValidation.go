@@ -0,0 +1,51 @@
+package compose
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// validateIncludeCycle reports the include entry that leads into a
+// cycle of included files as Compose will refuse to load such a file.
+// The message lists the names of the files of the cycle in the order
+// that they include each other.
+func validateIncludeCycle(source string, doc document.ComposeDocument, documentPath *document.DocumentPath, root *ast.MappingNode) []protocol.Diagnostic {
+	include := mappingValue(root, "include")
+	if include == nil || documentPath == nil {
+		return nil
+	}
+	sequenceNode, ok := resolveAnchor(include.Value).(*ast.SequenceNode)
+	if !ok {
+		return nil
+	}
+	cycle := doc.IncludeCycle()
+	if len(cycle) < 2 {
+		return nil
+	}
+
+	names := []string{}
+	for _, u := range cycle {
+		names = append(names, path.Base(string(u)))
+	}
+	for _, t := range includedFiles(sequenceNode.Values) {
+		includedPath, ok := literalValue(t.Value)
+		if !ok || document.IncludedFileURI(documentPath.Folder, includedPath) != cycle[1] {
+			continue
+		}
+		return []protocol.Diagnostic{
+			createValidationDiagnostic(
+				source,
+				protocol.DiagnosticSeverityError,
+				"CircularInclude",
+				fmt.Sprintf("%v is included through the cycle %v", includedPath, strings.Join(names, " -> ")),
+				createRange(t, len(t.Value)),
+			),
+		}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package compose
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"slices"
 
 	"github.com/docker/docker-language-server/internal/pkg/document"
@@ -62,7 +64,79 @@ func hierarchyProperties(service string, serviceProps map[string]map[string]ast.
 	return chain
 }
 
-func InlayHint(doc document.ComposeDocument, rng protocol.Range) ([]protocol.InlayHint, error) {
+// resolvedPathHint returns a hint that shows the absolute path that
+// the relative path in the given node resolves to. Paths that cannot be
+// found on the file system are marked as such. Absolute, remote, and
+// interpolated paths are ignored.
+func resolvedPathHint(documentPath document.DocumentPath, node ast.Node) *protocol.InlayHint {
+	stringNode, ok := node.(*ast.StringNode)
+	if !ok {
+		return nil
+	}
+	path, ok := literalValue(stringNode.Value)
+	if !ok || path == "" || filepath.IsAbs(path) || remoteContext(path) {
+		return nil
+	}
+
+	resolved := resolvePath(documentPath.Folder, path, documentPath.WSLDollarSignHost)
+	label := fmt.Sprintf("(resolved path: %v)", resolved)
+	if _, err := os.Stat(resolved); err != nil {
+		label = fmt.Sprintf("(resolved path: %v, not found)", resolved)
+	}
+	t := stringNode.GetToken()
+	length := len(t.Value)
+	if t.Type == token.DoubleQuoteType || t.Type == token.SingleQuoteType {
+		length += 2
+	}
+	return &protocol.InlayHint{
+		Label:       label,
+		PaddingLeft: types.CreateBoolPointer(true),
+		Position: protocol.Position{
+			Line:      uint32(t.Position.Line) - 1,
+			Character: uint32(t.Position.Column + length - 1),
+		},
+	}
+}
+
+// resolvedPathHints returns hints for the relative paths of the
+// services' build contexts and env files.
+func resolvedPathHints(documentPath document.DocumentPath, root *ast.MappingNode) []protocol.InlayHint {
+	nodes := []ast.Node{}
+	matchPropertyPath([]string{"services", "*", "build"}, nil, root, func(key, value ast.Node) {
+		if context := mappingValue(value, "context"); context != nil {
+			nodes = append(nodes, context.Value)
+		} else {
+			nodes = append(nodes, value)
+		}
+	})
+	matchPropertyPath([]string{"services", "*", "env_file"}, nil, root, func(key, value ast.Node) {
+		if sequenceNode, ok := value.(*ast.SequenceNode); ok {
+			for _, item := range sequenceNode.Values {
+				if path := mappingValue(item, "path"); path != nil {
+					nodes = append(nodes, path.Value)
+				} else {
+					nodes = append(nodes, item)
+				}
+			}
+		} else {
+			nodes = append(nodes, value)
+		}
+	})
+
+	hints := []protocol.InlayHint{}
+	for _, node := range nodes {
+		if hint := resolvedPathHint(documentPath, node); hint != nil {
+			hints = append(hints, *hint)
+		}
+	}
+	return hints
+}
+
+// InlayHint returns the inlay hints for the given Compose document. The
+// parent values of overridden attributes are always included while the
+// resolved paths of relative build contexts and env files are only
+// included if resolvedPaths is true.
+func InlayHint(doc document.ComposeDocument, rng protocol.Range, resolvedPaths bool) ([]protocol.InlayHint, error) {
 	file := doc.File()
 	if file == nil || len(file.Docs) == 0 {
 		return nil, nil
@@ -71,6 +145,11 @@ func InlayHint(doc document.ComposeDocument, rng protocol.Range) ([]protocol.Inl
 	hints := []protocol.InlayHint{}
 	for _, docNode := range file.Docs {
 		if mappingNode, ok := docNode.Body.(*ast.MappingNode); ok {
+			if resolvedPaths {
+				if documentPath, err := doc.DocumentPath(); err == nil {
+					hints = append(hints, resolvedPathHints(documentPath, mappingNode)...)
+				}
+			}
 			for _, node := range mappingNode.Values {
 				if s, ok := node.Key.(*ast.StringNode); ok && s.Value == "services" {
 					serviceProps := allServiceProperties(node.Value)
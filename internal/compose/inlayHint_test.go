@@ -215,7 +215,115 @@ services:
 		u := uri.URI(composeFileURI)
 		t.Run(tc.name, func(t *testing.T) {
 			doc := document.NewComposeDocument(document.NewDocumentManager(), u, 1, []byte(tc.content))
-			inlayHints, err := InlayHint(doc, protocol.Range{})
+			inlayHints, err := InlayHint(doc, protocol.Range{}, false)
+			slices.SortFunc(inlayHints, func(a protocol.InlayHint, b protocol.InlayHint) int {
+				return int(a.Position.Line) - int(b.Position.Line)
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.inlayHints, inlayHints)
+		})
+	}
+}
+
+func TestInlayHint_ResolvedPaths(t *testing.T) {
+	folder := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(folder, "backend"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, ".env"), []byte("A=B"), 0644))
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/")))
+
+	testCases := []struct {
+		name          string
+		content       string
+		resolvedPaths bool
+		inlayHints    []protocol.InlayHint
+	}{
+		{
+			name: "disabled",
+			content: `
+services:
+  web:
+    build: ./backend`,
+			resolvedPaths: false,
+			inlayHints:    []protocol.InlayHint{},
+		},
+		{
+			name: "build string",
+			content: `
+services:
+  web:
+    build: ./backend`,
+			resolvedPaths: true,
+			inlayHints: []protocol.InlayHint{
+				{
+					Label:       fmt.Sprintf("(resolved path: %v)", filepath.Join(folder, "backend")),
+					PaddingLeft: types.CreateBoolPointer(true),
+					Position:    protocol.Position{Line: 3, Character: 20},
+				},
+			},
+		},
+		{
+			name: "build context that does not exist",
+			content: `
+services:
+  web:
+    build:
+      context: "frontend"`,
+			resolvedPaths: true,
+			inlayHints: []protocol.InlayHint{
+				{
+					Label:       fmt.Sprintf("(resolved path: %v, not found)", filepath.Join(folder, "frontend")),
+					PaddingLeft: types.CreateBoolPointer(true),
+					Position:    protocol.Position{Line: 4, Character: 25},
+				},
+			},
+		},
+		{
+			name: "env_file string, list, and path object",
+			content: `
+services:
+  web:
+    env_file: .env
+  web2:
+    env_file:
+      - .env
+      - path: other.env`,
+			resolvedPaths: true,
+			inlayHints: []protocol.InlayHint{
+				{
+					Label:       fmt.Sprintf("(resolved path: %v)", filepath.Join(folder, ".env")),
+					PaddingLeft: types.CreateBoolPointer(true),
+					Position:    protocol.Position{Line: 3, Character: 18},
+				},
+				{
+					Label:       fmt.Sprintf("(resolved path: %v)", filepath.Join(folder, ".env")),
+					PaddingLeft: types.CreateBoolPointer(true),
+					Position:    protocol.Position{Line: 6, Character: 12},
+				},
+				{
+					Label:       fmt.Sprintf("(resolved path: %v, not found)", filepath.Join(folder, "other.env")),
+					PaddingLeft: types.CreateBoolPointer(true),
+					Position:    protocol.Position{Line: 7, Character: 23},
+				},
+			},
+		},
+		{
+			name: "absolute, remote, and interpolated paths are ignored",
+			content: `
+services:
+  web:
+    build: https://github.com/docker/docker-language-server.git
+    env_file: /etc/app.env
+  web2:
+    build: ${CONTEXT}`,
+			resolvedPaths: true,
+			inlayHints:    []protocol.InlayHint{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			inlayHints, err := InlayHint(doc, protocol.Range{}, tc.resolvedPaths)
 			slices.SortFunc(inlayHints, func(a protocol.InlayHint, b protocol.InlayHint) int {
 				return int(a.Position.Line) - int(b.Position.Line)
 			})
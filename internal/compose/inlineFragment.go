@@ -0,0 +1,394 @@
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// aliasReference is an alias and the position in the document that it
+// is used in.
+type aliasReference struct {
+	alias  *ast.AliasNode
+	anchor *ast.AnchorNode
+	// entry is the mapping entry whose value is the alias
+	entry *ast.MappingValueNode
+	// mapping is the mapping that contains the entry
+	mapping *ast.MappingNode
+	// sequence is the sequence that has the alias as one of its items
+	sequence *ast.SequenceNode
+	// merge is the merge key whose sequence of aliases has the alias as
+	// one of its items
+	merge *ast.MappingValueNode
+}
+
+// aliasCollector walks a document in order so that every alias will
+// be resolved to the closest anchor with the same name that precedes
+// it.
+type aliasCollector struct {
+	anchors    map[string]*ast.AnchorNode
+	references []aliasReference
+}
+
+func (c *aliasCollector) walk(node ast.Node, reference aliasReference) {
+	switch n := node.(type) {
+	case *ast.AnchorNode:
+		c.anchors[n.Name.GetToken().Value] = n
+		c.walk(n.Value, reference)
+	case *ast.TagNode:
+		c.walk(n.Value, aliasReference{})
+	case *ast.AliasNode:
+		if anchor, ok := c.anchors[n.Value.GetToken().Value]; ok {
+			reference.alias = n
+			reference.anchor = anchor
+			c.references = append(c.references, reference)
+		}
+	case *ast.MappingNode:
+		for _, child := range n.Values {
+			c.walkEntry(child, n)
+		}
+	case *ast.MappingValueNode:
+		c.walkEntry(n, nil)
+	case *ast.SequenceNode:
+		for _, item := range n.Values {
+			c.walk(item, aliasReference{sequence: n})
+		}
+	}
+}
+
+func (c *aliasCollector) walkEntry(entry *ast.MappingValueNode, mapping *ast.MappingNode) {
+	c.walk(entry.Key, aliasReference{})
+	if _, ok := entry.Key.(*ast.MergeKeyNode); ok {
+		// merging a sequence of aliases cannot be flattened without
+		// considering the precedence of every merged mapping
+		if sequence, ok := entry.Value.(*ast.SequenceNode); ok {
+			for _, item := range sequence.Values {
+				c.walk(item, aliasReference{merge: entry, mapping: mapping})
+			}
+			return
+		}
+	}
+	c.walk(entry.Value, aliasReference{entry: entry, mapping: mapping})
+}
+
+// inlineFragmentCodeActions returns code actions that replace the
+// alias at the start of the range with a copy of the node that its
+// anchor refers to. If the alias is the only one that refers to the
+// anchor then the anchor can also be removed. An alias that is the
+// value of a merge key is replaced by the entries of the anchored
+// mapping that are not already declared in the mapping that the merge
+// key is in.
+func inlineFragmentCodeActions(lines []string, body ast.Node, params *protocol.CodeActionParams) []protocol.CodeAction {
+	collector := &aliasCollector{anchors: map[string]*ast.AnchorNode{}}
+	collector.walk(body, aliasReference{})
+
+	position := params.Range.Start
+	for _, reference := range collector.references {
+		t := reference.alias.GetToken()
+		name := reference.alias.Value.GetToken().Value
+		if int(position.Line) != t.Position.Line-1 || int(position.Character) < t.Position.Column-1 || int(position.Character) > t.Position.Column+len(name) {
+			continue
+		}
+
+		edit := inlineFragmentEdit(lines, reference)
+		if edit == nil {
+			return nil
+		}
+		actions := []protocol.CodeAction{
+			{
+				Title: "Inline fragment here",
+				Kind:  types.CreateStringPointer(protocol.CodeActionKindRefactorInline),
+				Edit: &protocol.WorkspaceEdit{
+					Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+						params.TextDocument.URI: {*edit},
+					},
+				},
+			},
+		}
+
+		aliases := 0
+		for _, other := range collector.references {
+			if other.anchor == reference.anchor {
+				aliases++
+			}
+		}
+		if aliases == 1 {
+			actions = append(actions, protocol.CodeAction{
+				Title: fmt.Sprintf("Inline fragment here and remove anchor &%v", name),
+				Kind:  types.CreateStringPointer(protocol.CodeActionKindRefactorInline),
+				Edit: &protocol.WorkspaceEdit{
+					Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+						params.TextDocument.URI: {removeAnchorEdit(lines, reference.anchor), *edit},
+					},
+				},
+			})
+		}
+		return actions
+	}
+	return nil
+}
+
+// fragment returns the text of the node that the anchor refers to. The
+// text of a block mapping or a block sequence is returned as lines
+// that are relative to the indentation of the node. Nil is returned if
+// the node cannot be copied faithfully.
+func fragment(lines []string, anchor *ast.AnchorNode) (text []string, block bool) {
+	switch n := anchor.Value.(type) {
+	case *ast.MappingNode:
+		if n.IsFlowStyle {
+			return flowFragment(lines, n.Start, n.End), false
+		}
+		if len(n.Values) == 0 {
+			return nil, false
+		}
+		return blockFragment(lines, n.Values[0].Key.GetToken(), lastLine(n)), true
+	case *ast.MappingValueNode:
+		return blockFragment(lines, n.Key.GetToken(), lastLine(n)), true
+	case *ast.SequenceNode:
+		if n.IsFlowStyle {
+			return flowFragment(lines, n.Start, n.End), false
+		}
+		return blockFragment(lines, n.Start, lastLine(n)), true
+	case *ast.StringNode, *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode, *ast.InfinityNode, *ast.NanNode, *ast.NullNode:
+		t := n.GetToken()
+		if lastLine(n) != t.Position.Line {
+			return nil, false
+		}
+		switch t.Type {
+		case token.DoubleQuoteType, token.SingleQuoteType:
+			line := lines[t.Position.Line-1]
+			return []string{line[t.Position.Column-1 : scalarEnd(line, t.Position.Column-1, false)]}, false
+		}
+		return []string{t.Value}, false
+	}
+	return nil, false
+}
+
+// flowFragment returns the text of a flow mapping or a flow sequence
+// that starts and ends on the same line.
+func flowFragment(lines []string, start, end *token.Token) []string {
+	if end == nil || start.Position.Line != end.Position.Line {
+		return nil
+	}
+	return []string{lines[start.Position.Line-1][start.Position.Column-1 : end.Position.Column]}
+}
+
+// blockFragment returns the lines of a block node that starts at the
+// given token with the node's indentation removed from them.
+func blockFragment(lines []string, start *token.Token, last int) []string {
+	indentation := start.Position.Column - 1
+	text := []string{lines[start.Position.Line-1][indentation:]}
+	for _, line := range lines[start.Position.Line:last] {
+		if strings.TrimSpace(line) == "" {
+			text = append(text, "")
+			continue
+		}
+		if len(line)-len(strings.TrimLeft(line, " ")) < indentation {
+			return nil
+		}
+		text = append(text, line[indentation:])
+	}
+	return text
+}
+
+// indentationUnit returns the number of spaces that the anchored block
+// node is indented by relative to the line that declares the anchor.
+func indentationUnit(lines []string, anchor *ast.AnchorNode, start *token.Token) int {
+	line := lines[anchor.GetToken().Position.Line-1]
+	unit := start.Position.Column - 1 - (len(line) - len(strings.TrimLeft(line, " ")))
+	if unit <= 0 {
+		return 2
+	}
+	return unit
+}
+
+// indentLines prefixes every line that is not blank with the indentation.
+func indentLines(text []string, indentation string) []string {
+	indented := make([]string, len(text))
+	for i, line := range text {
+		if line != "" {
+			indented[i] = indentation + line
+		}
+	}
+	return indented
+}
+
+// trailingComment returns the comment that follows the given index on
+// the line with a leading space. An empty string is returned if there
+// is no comment and false is returned if there is something other than
+// a comment after the index.
+func trailingComment(line string, index int) (string, bool) {
+	rest := strings.TrimSpace(line[index:])
+	if rest == "" {
+		return "", true
+	}
+	if strings.HasPrefix(rest, "#") {
+		return " " + rest, true
+	}
+	return "", false
+}
+
+func inlineFragmentEdit(lines []string, reference aliasReference) *protocol.TextEdit {
+	text, block := fragment(lines, reference.anchor)
+	if text == nil {
+		return nil
+	}
+
+	t := reference.alias.GetToken()
+	line := lines[t.Position.Line-1]
+	aliasStart := t.Position.Column - 1
+	aliasEnd := aliasStart + len(reference.alias.Value.GetToken().Value) + 1
+	aliasRange := protocol.Range{
+		Start: protocol.Position{Line: protocol.UInteger(t.Position.Line - 1), Character: protocol.UInteger(aliasStart)},
+		End:   protocol.Position{Line: protocol.UInteger(t.Position.Line - 1), Character: protocol.UInteger(aliasEnd)},
+	}
+
+	if reference.entry != nil {
+		if _, ok := reference.entry.Key.(*ast.MergeKeyNode); ok {
+			return flattenMergeEdit(lines, reference)
+		}
+	}
+	if !block {
+		return &protocol.TextEdit{NewText: text[0], Range: aliasRange}
+	}
+
+	comment, ok := trailingComment(line, aliasEnd)
+	if !ok {
+		return nil
+	}
+	before := strings.TrimRight(line[:aliasStart], " ")
+	aliasRange.End.Character = protocol.UInteger(len(line))
+	if reference.sequence != nil {
+		// the first line of the fragment follows the sequence's dash and
+		// the rest of the lines are aligned with it
+		if reference.sequence.IsFlowStyle || !strings.HasSuffix(before, "-") {
+			return nil
+		}
+		text[0] = text[0] + comment
+		return &protocol.TextEdit{
+			NewText: strings.Join(append(text[:1], indentLines(text[1:], strings.Repeat(" ", aliasStart))...), "\n"),
+			Range:   aliasRange,
+		}
+	}
+
+	if reference.entry == nil || (reference.mapping != nil && reference.mapping.IsFlowStyle) || !strings.HasSuffix(before, ":") {
+		return nil
+	}
+	var start *token.Token
+	switch n := reference.anchor.Value.(type) {
+	case *ast.MappingNode:
+		start = n.Values[0].Key.GetToken()
+	case *ast.MappingValueNode:
+		start = n.Key.GetToken()
+	case *ast.SequenceNode:
+		start = n.Start
+	}
+	keyIndentation := reference.entry.Key.GetToken().Position.Column - 1
+	indentation := strings.Repeat(" ", keyIndentation+indentationUnit(lines, reference.anchor, start))
+	aliasRange.Start.Character = protocol.UInteger(len(before))
+	return &protocol.TextEdit{
+		NewText: comment + "\n" + strings.Join(indentLines(text, indentation), "\n"),
+		Range:   aliasRange,
+	}
+}
+
+// flattenMergeEdit replaces a merge key with the entries of the mapping
+// that its alias refers to. Entries that are declared by the mapping
+// that the merge key is in take precedence over the merged entries and
+// are therefore not copied.
+func flattenMergeEdit(lines []string, reference aliasReference) *protocol.TextEdit {
+	var entries []*ast.MappingValueNode
+	switch n := reference.anchor.Value.(type) {
+	case *ast.MappingNode:
+		if n.IsFlowStyle || len(n.Values) == 0 {
+			return nil
+		}
+		entries = n.Values
+	case *ast.MappingValueNode:
+		entries = []*ast.MappingValueNode{n}
+	default:
+		return nil
+	}
+	if reference.mapping != nil && reference.mapping.IsFlowStyle {
+		return nil
+	}
+
+	keyToken := reference.entry.Key.GetToken()
+	line := lines[keyToken.Position.Line-1]
+	aliasToken := reference.alias.GetToken()
+	comment, ok := trailingComment(line, aliasToken.Position.Column+len(reference.alias.Value.GetToken().Value))
+	if !ok || aliasToken.Position.Line != keyToken.Position.Line || strings.TrimSpace(line[:keyToken.Position.Column-1]) != "" {
+		return nil
+	}
+
+	declared := []string{}
+	if reference.mapping != nil {
+		for _, child := range reference.mapping.Values {
+			if child != reference.entry {
+				declared = append(declared, resolveAnchor(child.Key).GetToken().Value)
+			}
+		}
+	}
+
+	first := entries[0].Key.GetToken()
+	text := blockFragment(lines, first, lastLine(entries[len(entries)-1]))
+	if text == nil {
+		return nil
+	}
+	merged := []string{}
+	for i, entry := range entries {
+		if slices.Contains(declared, resolveAnchor(entry.Key).GetToken().Value) {
+			continue
+		}
+		start := entry.Key.GetToken().Position.Line - first.Position.Line
+		end := len(text)
+		if i+1 < len(entries) {
+			end = entries[i+1].Key.GetToken().Position.Line - first.Position.Line
+		}
+		merged = append(merged, text[start:end]...)
+	}
+
+	rng := protocol.Range{
+		Start: protocol.Position{Line: protocol.UInteger(keyToken.Position.Line - 1)},
+		End:   protocol.Position{Line: protocol.UInteger(keyToken.Position.Line - 1), Character: protocol.UInteger(len(line))},
+	}
+	if len(merged) == 0 {
+		// every merged entry has been overridden so the merge key can
+		// simply be removed along with its line
+		if keyToken.Position.Line == len(lines) {
+			return nil
+		}
+		rng.End = protocol.Position{Line: protocol.UInteger(keyToken.Position.Line)}
+		return &protocol.TextEdit{NewText: "", Range: rng}
+	}
+	merged = indentLines(merged, strings.Repeat(" ", keyToken.Position.Column-1))
+	merged[0] = merged[0] + comment
+	return &protocol.TextEdit{NewText: strings.Join(merged, "\n"), Range: rng}
+}
+
+// removeAnchorEdit removes the anchor and the whitespace that separates
+// it from its node.
+func removeAnchorEdit(lines []string, anchor *ast.AnchorNode) protocol.TextEdit {
+	t := anchor.GetToken()
+	line := lines[t.Position.Line-1]
+	start := t.Position.Column - 1
+	end := anchor.Name.GetToken().Position.Column - 1 + len(anchor.Name.GetToken().Value)
+	if strings.TrimSpace(line[end:]) == "" {
+		start = len(strings.TrimRight(line[:start], " "))
+		end = len(line)
+	} else {
+		end = len(line) - len(strings.TrimLeft(line[end:], " "))
+	}
+	return protocol.TextEdit{
+		NewText: "",
+		Range: protocol.Range{
+			Start: protocol.Position{Line: protocol.UInteger(t.Position.Line - 1), Character: protocol.UInteger(start)},
+			End:   protocol.Position{Line: protocol.UInteger(t.Position.Line - 1), Character: protocol.UInteger(end)},
+		},
+	}
+}
@@ -0,0 +1,217 @@
+package compose
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// scalarKind describes how Compose will treat a scalar value when it
+// interpolates the Compose file.
+type scalarKind int
+
+const (
+	// scalarLiteral is a value that has no dollar signs at all.
+	scalarLiteral scalarKind = iota
+	// scalarEscaped is a value that only has $$ escapes in it which
+	// Compose will replace with a literal $.
+	scalarEscaped
+	// scalarInterpolated is a value with variables or an invalid use
+	// of a $ so its final value cannot be known.
+	scalarInterpolated
+)
+
+// classifyScalar returns how Compose will treat the given value and
+// the value after its escapes have been resolved. The value is returned
+// as is if it has been interpolated.
+func classifyScalar(value string) (scalarKind, string) {
+	if !strings.Contains(value, "$") {
+		return scalarLiteral, value
+	}
+
+	sb := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		if value[i] == '$' {
+			if i+1 < len(value) && value[i+1] == '$' {
+				sb.WriteByte('$')
+				i++
+				continue
+			}
+			return scalarInterpolated, value
+		}
+		sb.WriteByte(value[i])
+	}
+	return scalarEscaped, sb.String()
+}
+
+// literalValue returns the value that Compose will use for the given
+// string after resolving its escapes. False is returned if the value
+// has been interpolated and should not be validated.
+func literalValue(value string) (string, bool) {
+	kind, literal := classifyScalar(value)
+	return literal, kind != scalarInterpolated
+}
+
+// interpolationVariable is a variable that is referenced in a string
+// by either the $VAR or ${VAR} syntax. The start is the offset of the
+// variable's name in the string.
+type interpolationVariable struct {
+	name  string
+	start int
+}
+
+func isVariableCharacter(c byte, first bool) bool {
+	if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		return true
+	}
+	return !first && c >= '0' && c <= '9'
+}
+
+// interpolationVariables returns the variables that are referenced in
+// the given string. Variables that are nested in the default value or
+// error message of another variable such as ${A:-${B}} are included.
+// A $$ is an escaped dollar sign and does not start a variable.
+func interpolationVariables(value string) []interpolationVariable {
+	variables := []interpolationVariable{}
+	for i := 0; i < len(value)-1; i++ {
+		if value[i] != '$' {
+			continue
+		}
+		if value[i+1] == '$' {
+			i++
+			continue
+		}
+
+		start := i + 1
+		if value[start] == '{' {
+			start++
+		}
+		end := start
+		for end < len(value) && isVariableCharacter(value[end], end == start) {
+			end++
+		}
+		if end > start {
+			variables = append(variables, interpolationVariable{name: value[start:end], start: start})
+		}
+		i = end - 1
+	}
+	return variables
+}
+
+// interpolationReferences returns the tokens of the variables that
+// are interpolated in the values of the given service and the tokens
+// of the variables that the service declares in its environment
+// attribute. Aliases are not followed so that variables from another
+// service will not be included.
+func interpolationReferences(serviceNode ast.Node) (refs, decls []*token.Token) {
+	refs = []*token.Token{}
+	decls = []*token.Token{}
+	var walk func(node ast.Node)
+	walk = func(node ast.Node) {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			walk(n.Value)
+		case *ast.MappingNode:
+			for _, child := range n.Values {
+				walk(child.Value)
+			}
+		case *ast.SequenceNode:
+			for _, item := range n.Values {
+				walk(item)
+			}
+		case *ast.StringNode:
+			refs = append(refs, variableTokens(n.GetToken())...)
+		}
+	}
+	walk(serviceNode)
+
+	if environment := mappingValue(serviceNode, "environment"); environment != nil {
+		switch n := resolveAnchor(environment.Value).(type) {
+		case *ast.MappingNode:
+			for _, child := range n.Values {
+				decls = append(decls, resolveAnchor(child.Key).GetToken())
+			}
+		case *ast.SequenceNode:
+			for _, item := range n.Values {
+				if s, ok := resolveAnchor(item).(*ast.StringNode); ok {
+					t := s.GetToken()
+					name, _, _ := strings.Cut(t.Value, "=")
+					decls = append(decls, &token.Token{Type: t.Type, Value: name, Position: t.Position})
+				}
+			}
+		}
+	}
+	return refs, decls
+}
+
+// variableTokens creates a token for every variable that is referenced
+// in the given token. Strings that span multiple lines are ignored.
+func variableTokens(t *token.Token) []*token.Token {
+	if strings.Contains(t.Value, "\n") {
+		return nil
+	}
+
+	offset := 0
+	if t.Type == token.DoubleQuoteType || t.Type == token.SingleQuoteType {
+		offset = 1
+	}
+	tokens := []*token.Token{}
+	for _, variable := range interpolationVariables(t.Value) {
+		tokens = append(tokens, &token.Token{
+			Type:  token.StringType,
+			Value: variable.name,
+			Position: &token.Position{
+				Line:   t.Position.Line,
+				Column: t.Position.Column + offset + variable.start,
+			},
+		})
+	}
+	return tokens
+}
+
+// envFileVariables returns the names of the variables that are
+// declared in the given env file. Comments, blank lines, and the
+// export keyword are ignored. Nothing is returned if the file cannot
+// be read.
+func envFileVariables(path string) []string {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, _, _ := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// environmentVariableNames returns the names of the variables that
+// are declared in the .env file next to the Compose file and the names
+// of the variables that are interpolated in the Compose file itself.
+func environmentVariableNames(file *ast.File, documentPath document.DocumentPath) []string {
+	_, envFilePath := types.Concatenate(documentPath.Folder, ".env", documentPath.WSLDollarSignHost)
+	names := envFileVariables(envFilePath)
+	for _, doc := range file.Docs {
+		refs, _ := interpolationReferences(doc.Body)
+		for _, ref := range refs {
+			if !slices.Contains(names, ref.Value) {
+				names = append(names, ref.Value)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,132 @@
+package compose
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// hostGateway is the special value of an extra host that resolves to
+// the IP address of the host.
+const hostGateway = "host-gateway"
+
+// invalidAddressDiagnostic returns a diagnostic for the part of the
+// token that starts at the given offset if it is not a valid IPv4 or
+// IPv6 address. IPv6 addresses may be enclosed in brackets.
+func invalidAddressDiagnostic(source string, t *token.Token, offset int, address string) []protocol.Diagnostic {
+	if _, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")); err == nil {
+		return nil
+	}
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"InvalidIPAddress",
+			fmt.Sprintf("%v is not a valid IP address", address),
+			createRange(subToken(t, offset), len(address)),
+		),
+	}
+}
+
+// scalarToken returns the token of a scalar value if it does not use
+// interpolation.
+func scalarToken(node ast.Node) (*token.Token, bool) {
+	switch n := resolveAnchor(node).(type) {
+	case *ast.StringNode, *ast.IntegerNode, *ast.FloatNode:
+		t := n.GetToken()
+		if _, ok := literalValue(t.Value); ok && t.Value != "" {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// validateAddress checks that the value of an attribute is a valid IP
+// address.
+func validateAddress(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	t, ok := scalarToken(value)
+	if !ok {
+		return nil
+	}
+	return invalidAddressDiagnostic(source, t, 0, t.Value)
+}
+
+// validateSubnet checks that the value of an attribute is a valid
+// subnet in CIDR notation.
+func validateSubnet(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	t, ok := scalarToken(value)
+	if !ok {
+		return nil
+	}
+	if _, err := netip.ParsePrefix(t.Value); err == nil {
+		return nil
+	}
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"InvalidSubnet",
+			fmt.Sprintf("%v is not a valid subnet in CIDR notation", t.Value),
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// validateExtraHost checks the IP address of an entry of a service's
+// extra_hosts attribute. An entry in the list form separates the host
+// name from the address with either an equals sign or a colon while
+// the mapping form has one or more addresses for every host name.
+func validateExtraHost(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	if key != nil {
+		if sequenceNode, ok := resolveAnchor(value).(*ast.SequenceNode); ok {
+			diagnostics := []protocol.Diagnostic{}
+			for _, item := range sequenceNode.Values {
+				diagnostics = append(diagnostics, validateExtraHostAddress(source, item)...)
+			}
+			return diagnostics
+		}
+		return validateExtraHostAddress(source, value)
+	}
+
+	t, ok := scalarToken(value)
+	if !ok {
+		return nil
+	}
+	separator := strings.Index(t.Value, "=")
+	if separator == -1 {
+		separator = strings.Index(t.Value, ":")
+	}
+	if separator == -1 || t.Value[separator+1:] == hostGateway {
+		return nil
+	}
+	return invalidAddressDiagnostic(source, t, separator+1, t.Value[separator+1:])
+}
+
+func validateExtraHostAddress(source string, node ast.Node) []protocol.Diagnostic {
+	t, ok := scalarToken(node)
+	if !ok || t.Value == hostGateway {
+		return nil
+	}
+	return invalidAddressDiagnostic(source, t, 0, t.Value)
+}
+
+// validatePortHostIP checks the host address that an entry in the
+// short syntax of a service's ports attribute binds to.
+func validatePortHostIP(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	t, ok := scalarToken(value)
+	if !ok {
+		return nil
+	}
+	if _, isString := resolveAnchor(value).(*ast.StringNode); !isString {
+		return nil
+	}
+	mapping, ok := parsePortMapping(value)
+	if !ok || mapping.hostIP == "" {
+		return nil
+	}
+	return invalidAddressDiagnostic(source, t, 0, mapping.hostIP)
+}
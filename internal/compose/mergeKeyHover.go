@@ -0,0 +1,115 @@
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// mergeKey returns the merge key that the alias of the given reference
+// is merged in by or nil if the alias is not the value of a merge key.
+func mergeKey(reference aliasReference) *ast.MappingValueNode {
+	if reference.merge != nil {
+		return reference.merge
+	}
+	if reference.entry != nil {
+		if _, ok := reference.entry.Key.(*ast.MergeKeyNode); ok {
+			return reference.entry
+		}
+	}
+	return nil
+}
+
+// mergedEntries returns the entries of the mapping that an anchor
+// refers to or false if the anchor does not refer to a mapping.
+func mergedEntries(anchor *ast.AnchorNode) ([]*ast.MappingValueNode, bool) {
+	switch n := anchor.Value.(type) {
+	case *ast.MappingNode:
+		return n.Values, true
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}, true
+	}
+	return nil, false
+}
+
+// mergeKeyHover lists the keys that the merge key under the cursor
+// merges into its mapping from each of its aliases. A key is flagged
+// as overridden if the mapping declares it or if an earlier alias of
+// the merge key has already merged it in. Nil is returned if one of
+// the aliases cannot be resolved to an anchored mapping.
+func mergeKeyHover(body ast.Node, line, character int) *protocol.Hover {
+	collector := &aliasCollector{anchors: map[string]*ast.AnchorNode{}}
+	collector.walk(body, aliasReference{})
+
+	var merge *ast.MappingValueNode
+	var mapping *ast.MappingNode
+	references := []aliasReference{}
+	for _, reference := range collector.references {
+		entry := mergeKey(reference)
+		if entry == nil || !inToken(entry.Key.GetToken(), line, character) {
+			continue
+		}
+		merge = entry
+		mapping = reference.mapping
+		references = append(references, reference)
+	}
+	if merge == nil {
+		return nil
+	}
+
+	aliases := 1
+	if sequence, ok := merge.Value.(*ast.SequenceNode); ok {
+		aliases = len(sequence.Values)
+	}
+	if len(references) != aliases {
+		return nil
+	}
+
+	declared := []string{}
+	if mapping != nil {
+		for _, child := range mapping.Values {
+			if _, ok := child.Key.(*ast.MergeKeyNode); !ok {
+				declared = append(declared, resolveAnchor(child.Key).GetToken().Value)
+			}
+		}
+	}
+
+	merged := map[string]string{}
+	sections := []string{}
+	for _, reference := range references {
+		entries, ok := mergedEntries(reference.anchor)
+		if !ok {
+			return nil
+		}
+		alias := fmt.Sprintf("`*%v`", reference.alias.Value.GetToken().Value)
+		keys := []string{}
+		for _, entry := range entries {
+			if _, ok := entry.Key.(*ast.MergeKeyNode); ok {
+				continue
+			}
+			key := resolveAnchor(entry.Key).GetToken().Value
+			if slices.Contains(declared, key) {
+				keys = append(keys, fmt.Sprintf("- `%v` (overridden locally)", key))
+			} else if previous, ok := merged[key]; ok {
+				keys = append(keys, fmt.Sprintf("- `%v` (overridden by %v)", key, previous))
+			} else {
+				keys = append(keys, fmt.Sprintf("- `%v`", key))
+				merged[key] = alias
+			}
+		}
+		sections = append(sections, fmt.Sprintf("Merged from %v:\n%v", alias, strings.Join(keys, "\n")))
+	}
+
+	t := merge.Key.GetToken()
+	r := createRange(t, len(t.Value))
+	return &protocol.Hover{
+		Contents: protocol.MarkupContent{
+			Kind:  protocol.MarkupKindMarkdown,
+			Value: strings.Join(sections, "\n\n"),
+		},
+		Range: &r,
+	}
+}
@@ -0,0 +1,183 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// ShortForm and LongForm are the forms that the depends_on attributes
+// of the services can be normalized to.
+const (
+	ShortForm = "short"
+	LongForm  = "long"
+)
+
+// NormalizedReferences is the result of normalizing the depends_on
+// attributes of a Compose file. The edit is nil if every attribute is
+// already in the requested form. The attributes and entries that could
+// not be rewritten are listed as paths such as web.depends_on.db.
+type NormalizedReferences struct {
+	Edit    *protocol.WorkspaceEdit `json:"edit,omitempty"`
+	Skipped []string                `json:"skipped,omitempty"`
+}
+
+// defaultDependencyAttributes are the attributes of a long form
+// dependency with the values that the short form implies.
+var defaultDependencyAttributes = map[string]string{
+	"condition": "service_started",
+	"required":  "true",
+	"restart":   "false",
+}
+
+// NormalizeReferences rewrites the depends_on attributes of the
+// services of the Compose file into the given form. A long form entry
+// is only shortened if its attributes are the defaults of the short
+// form as the other attributes would otherwise be lost. Attributes that
+// have such entries, that are shared through anchors, or that have
+// comments in them are left alone and reported. Nil is returned if the
+// form is neither short nor long.
+func NormalizeReferences(doc document.ComposeDocument, form string) *NormalizedReferences {
+	if form != ShortForm && form != LongForm {
+		return nil
+	}
+	file := doc.File()
+	if file == nil {
+		return nil
+	}
+
+	result := &NormalizedReferences{}
+	edits := []protocol.TextEdit{}
+	lines := strings.Split(string(doc.Input()), "\n")
+	for _, documentNode := range file.Docs {
+		root, ok := documentNode.Body.(*ast.MappingNode)
+		if !ok {
+			continue
+		}
+		servicesNode := topLevelMapping(root, "services")
+		if servicesNode == nil {
+			continue
+		}
+		for _, serviceNode := range servicesNode.Values {
+			serviceAttributes, ok := serviceNode.Value.(*ast.MappingNode)
+			if !ok || serviceAttributes.IsFlowStyle {
+				continue
+			}
+			dependsOn := mappingValue(serviceAttributes, "depends_on")
+			if dependsOn == nil {
+				continue
+			}
+			edit, skipped := normalizeDependsOn(lines, serviceNode, dependsOn, form)
+			if edit != nil {
+				edits = append(edits, *edit)
+			}
+			result.Skipped = append(result.Skipped, skipped...)
+		}
+	}
+
+	if len(edits) > 0 {
+		result.Edit = &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+				string(doc.URI()): edits,
+			},
+		}
+	}
+	return result
+}
+
+// normalizeDependsOn returns the edit that rewrites the depends_on
+// attribute of the service into the given form. If the attribute cannot
+// be rewritten then the paths of what prevented it are returned.
+func normalizeDependsOn(lines []string, serviceNode, dependsOn *ast.MappingValueNode, form string) (*protocol.TextEdit, []string) {
+	attributePath := fmt.Sprintf("%v.depends_on", resolveAnchor(serviceNode.Key).GetToken().Value)
+	names := []string{}
+	switch value := dependsOn.Value.(type) {
+	case *ast.SequenceNode:
+		if form == ShortForm {
+			return nil, nil
+		}
+		for _, item := range value.Values {
+			s, ok := item.(*ast.StringNode)
+			if !ok {
+				return nil, []string{attributePath}
+			}
+			names = append(names, s.Value)
+		}
+	case *ast.MappingNode:
+		if form == LongForm {
+			return nil, nil
+		}
+		lossy := []string{}
+		for _, dependency := range value.Values {
+			s, ok := dependency.Key.(*ast.StringNode)
+			if !ok {
+				return nil, []string{attributePath}
+			}
+			if !defaultDependency(dependency.Value) {
+				lossy = append(lossy, fmt.Sprintf("%v.%v", attributePath, s.Value))
+			}
+			names = append(names, s.Value)
+		}
+		if len(lossy) > 0 {
+			return nil, lossy
+		}
+	case *ast.AliasNode, *ast.AnchorNode:
+		return nil, []string{attributePath}
+	default:
+		return nil, nil
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	keyLine := dependsOn.Key.GetToken().Position.Line
+	end := min(lastLine(dependsOn), len(lines))
+	for _, line := range lines[keyLine-1 : end] {
+		if strings.Contains(line, "#") {
+			return nil, []string{attributePath}
+		}
+	}
+
+	keyIndentation := dependsOn.Key.GetToken().Position.Column - 1
+	unit := keyIndentation - (serviceNode.Key.GetToken().Position.Column - 1)
+	indentation := strings.Repeat(" ", keyIndentation)
+	entryIndentation := strings.Repeat(" ", keyIndentation+unit)
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%vdepends_on:\n", indentation))
+	for _, name := range names {
+		if form == ShortForm {
+			sb.WriteString(fmt.Sprintf("%v- %v\n", entryIndentation, yamlScalar(name)))
+		} else {
+			sb.WriteString(fmt.Sprintf("%v%v:\n", entryIndentation, yamlScalar(name)))
+			sb.WriteString(fmt.Sprintf("%v%vcondition: service_started\n", entryIndentation, strings.Repeat(" ", unit)))
+		}
+	}
+	return &protocol.TextEdit{
+		NewText: sb.String(),
+		Range: protocol.Range{
+			Start: protocol.Position{Line: protocol.UInteger(keyLine - 1)},
+			End:   protocol.Position{Line: protocol.UInteger(lastLine(dependsOn))},
+		},
+	}, nil
+}
+
+// defaultDependency returns true if the value of a long form dependency
+// only sets attributes to the defaults that the short form implies.
+func defaultDependency(node ast.Node) bool {
+	switch value := node.(type) {
+	case *ast.NullNode:
+		return true
+	case *ast.MappingNode:
+		for _, attribute := range value.Values {
+			defaultValue, ok := defaultDependencyAttributes[attribute.Key.GetToken().Value]
+			if !ok || attribute.Value.GetToken().Value != defaultValue {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestNormalizeReferences(t *testing.T) {
+	mixed := `
+services:
+  web:
+    depends_on:
+      - api
+      - db
+  api:
+    depends_on:
+      db:
+        condition: service_started
+  worker:
+    depends_on:
+      db:
+        condition: service_healthy
+        restart: true
+  cron:
+    depends_on: [api]
+  db:
+    image: postgres`
+
+	testCases := []struct {
+		name    string
+		content string
+		form    string
+		result  string
+		skipped []string
+	}{
+		{
+			name:    "mixed file to the long form",
+			content: mixed,
+			form:    LongForm,
+			result: `
+services:
+  web:
+    depends_on:
+      api:
+        condition: service_started
+      db:
+        condition: service_started
+  api:
+    depends_on:
+      db:
+        condition: service_started
+  worker:
+    depends_on:
+      db:
+        condition: service_healthy
+        restart: true
+  cron:
+    depends_on:
+      api:
+        condition: service_started
+  db:
+    image: postgres`,
+		},
+		{
+			name:    "mixed file to the short form",
+			content: mixed,
+			form:    ShortForm,
+			result: `
+services:
+  web:
+    depends_on:
+      - api
+      - db
+  api:
+    depends_on:
+      - db
+  worker:
+    depends_on:
+      db:
+        condition: service_healthy
+        restart: true
+  cron:
+    depends_on: [api]
+  db:
+    image: postgres`,
+			skipped: []string{"worker.depends_on.db"},
+		},
+		{
+			name: "dependencies without attributes and with default attributes are shortened",
+			content: `
+services:
+    web:
+        depends_on:
+            db:
+            cache: {}
+            api:
+                required: true
+                restart: false`,
+			form: ShortForm,
+			result: `
+services:
+    web:
+        depends_on:
+            - db
+            - cache
+            - api
+`,
+		},
+		{
+			name: "attributes with comments or anchors are left alone",
+			content: `
+services:
+  web:
+    depends_on:
+      - db # the database
+  api:
+    depends_on: &deps
+      - db
+  db:
+    image: postgres`,
+			form:    LongForm,
+			result:  "",
+			skipped: []string{"web.depends_on", "api.depends_on"},
+		},
+		{
+			name: "attributes already in the form",
+			content: `
+services:
+  web:
+    depends_on:
+      - db`,
+			form: ShortForm,
+		},
+		{
+			name: "unknown form",
+			content: `
+services:
+  web:
+    depends_on:
+      - db`,
+			form: "medium",
+		},
+	}
+
+	apply := func(content string, edits []protocol.TextEdit) string {
+		for i := len(edits) - 1; i >= 0; i-- {
+			content = string(document.ApplyContentChange([]byte(content), edits[i].Range, edits[i].NewText))
+		}
+		return content
+	}
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(tc.content))
+			result := NormalizeReferences(doc, tc.form)
+			if tc.form != ShortForm && tc.form != LongForm {
+				require.Nil(t, result)
+				return
+			}
+			require.NotNil(t, result)
+			require.Equal(t, tc.skipped, result.Skipped)
+			if tc.result == "" {
+				require.Nil(t, result.Edit)
+				return
+			}
+			require.Equal(t, tc.result, apply(tc.content, result.Edit.Changes[composeFileURI]))
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package compose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// portRange is a range of container ports for a given protocol. A
+// single port has the same start and end.
+type portRange struct {
+	start    int
+	end      int
+	protocol string
+}
+
+func (r portRange) contains(other portRange) bool {
+	return r.protocol == other.protocol && r.start <= other.start && other.end <= r.end
+}
+
+// parsePortRange parses a port or a range of ports such as 80/udp or
+// 8000-8010. The protocol defaults to tcp if it is not specified.
+func parsePortRange(value string) (portRange, bool) {
+	ports, protocol, found := strings.Cut(value, "/")
+	if !found {
+		protocol = "tcp"
+	}
+	startValue, endValue, isRange := strings.Cut(ports, "-")
+	start, err := strconv.Atoi(startValue)
+	if err != nil {
+		return portRange{}, false
+	}
+	end := start
+	if isRange {
+		end, err = strconv.Atoi(endValue)
+		if err != nil || end < start {
+			return portRange{}, false
+		}
+	}
+	return portRange{start: start, end: end, protocol: strings.ToLower(protocol)}, true
+}
+
+// publishedContainerPorts returns the container ports that are
+// published by the entries of a service's ports attribute. Entries
+// that use interpolation or that cannot be parsed are ignored.
+func publishedContainerPorts(portsNode ast.Node) []portRange {
+	ranges := []portRange{}
+	sequenceNode, ok := resolveAnchor(portsNode).(*ast.SequenceNode)
+	if !ok {
+		return ranges
+	}
+	for _, item := range sequenceNode.Values {
+		switch n := resolveAnchor(item).(type) {
+		case *ast.IntegerNode:
+			if r, ok := parsePortRange(n.GetToken().Value); ok {
+				ranges = append(ranges, r)
+			}
+		case *ast.StringNode:
+			value, ok := literalValue(n.Value)
+			if !ok {
+				continue
+			}
+			// the container port is after the last colon, an IPv6
+			// host address is in brackets so it will come before it
+			protocol := ""
+			if idx := strings.LastIndex(value, "/"); idx != -1 {
+				protocol = value[idx:]
+				value = value[:idx]
+			}
+			if idx := strings.LastIndex(value, ":"); idx != -1 {
+				value = value[idx+1:]
+			}
+			if r, ok := parsePortRange(value + protocol); ok {
+				ranges = append(ranges, r)
+			}
+		case *ast.MappingNode:
+			target := mappingValue(n, "target")
+			if target == nil {
+				continue
+			}
+			value, ok := literalValue(resolveAnchor(target.Value).GetToken().Value)
+			if !ok {
+				continue
+			}
+			if protocol := mappingValue(n, "protocol"); protocol != nil {
+				value = fmt.Sprintf("%v/%v", value, resolveAnchor(protocol.Value).GetToken().Value)
+			}
+			if r, ok := parsePortRange(value); ok {
+				ranges = append(ranges, r)
+			}
+		}
+	}
+	return ranges
+}
+
+// validateRedundantExpose reports entries in the expose attribute of a
+// service that are already published by its ports attribute as
+// publishing a port also exposes it.
+func validateRedundantExpose(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	expose := mappingValue(value, "expose")
+	ports := mappingValue(value, "ports")
+	if expose == nil || ports == nil {
+		return nil
+	}
+	exposeNode, ok := resolveAnchor(expose.Value).(*ast.SequenceNode)
+	if !ok {
+		return nil
+	}
+
+	published := publishedContainerPorts(ports.Value)
+	diagnostics := []protocol.Diagnostic{}
+	for _, item := range exposeNode.Values {
+		item = resolveAnchor(item)
+		t := item.GetToken()
+		literal, ok := literalValue(t.Value)
+		if !ok {
+			continue
+		}
+		exposed, ok := parsePortRange(literal)
+		if !ok {
+			continue
+		}
+		for _, r := range published {
+			if r.contains(exposed) {
+				diagnostic := createValidationDiagnostic(
+					source,
+					protocol.DiagnosticSeverityHint,
+					"RedundantExpose",
+					fmt.Sprintf("port %v is already published by ports and does not need to be exposed", t.Value),
+					createRange(t, len(t.Value)),
+				)
+				diagnostic.Tags = []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary}
+				// the entry can only be removed by deleting its line if
+				// it is the only thing on it
+				if !exposeNode.IsFlowStyle {
+					diagnostic.Data = []types.NamedEdit{
+						{
+							Title: "Remove redundant expose entry",
+							Edit:  "",
+							Range: &protocol.Range{
+								Start: protocol.Position{Line: protocol.UInteger(t.Position.Line - 1)},
+								End:   protocol.Position{Line: protocol.UInteger(t.Position.Line)},
+							},
+						},
+					}
+				}
+				diagnostics = append(diagnostics, diagnostic)
+				break
+			}
+		}
+	}
+	return diagnostics
+}
+
+// portMapping is a port entry of a service's ports attribute in a form
+// that does not depend on whether the short or long syntax was used.
+type portMapping struct {
+	hostIP    string
+	published string
+	target    string
+	protocol  string
+	mode      string
+}
+
+// String returns the mapping in the short syntax.
+func (m portMapping) String() string {
+	parts := []string{}
+	if m.hostIP != "" {
+		parts = append(parts, m.hostIP)
+	}
+	if m.hostIP != "" || m.published != "" {
+		parts = append(parts, m.published)
+	}
+	parts = append(parts, m.target)
+	return fmt.Sprintf("%v/%v", strings.Join(parts, ":"), m.protocol)
+}
+
+// parsePortMapping parses an entry of a service's ports attribute.
+// Entries that use interpolation or that cannot be parsed are not
+// returned.
+func parsePortMapping(node ast.Node) (portMapping, bool) {
+	switch n := resolveAnchor(node).(type) {
+	case *ast.IntegerNode, *ast.StringNode:
+		value, ok := literalValue(n.GetToken().Value)
+		if !ok || value == "" {
+			return portMapping{}, false
+		}
+		mapping := portMapping{protocol: "tcp", mode: "ingress"}
+		if idx := strings.LastIndex(value, "/"); idx != -1 {
+			mapping.protocol = strings.ToLower(value[idx+1:])
+			value = value[:idx]
+		}
+		// the container port is after the last colon and the host
+		// address is before the colon that precedes the published port
+		if idx := strings.LastIndex(value, ":"); idx != -1 {
+			mapping.target = value[idx+1:]
+			value = value[:idx]
+			if idx := strings.LastIndex(value, ":"); idx != -1 {
+				mapping.hostIP = value[:idx]
+				value = value[idx+1:]
+			}
+			mapping.published = value
+		} else {
+			mapping.target = value
+		}
+		return mapping, true
+	case *ast.MappingNode:
+		mapping := portMapping{protocol: "tcp", mode: "ingress"}
+		attributes := map[string]*string{
+			"host_ip":   &mapping.hostIP,
+			"published": &mapping.published,
+			"target":    &mapping.target,
+			"protocol":  &mapping.protocol,
+			"mode":      &mapping.mode,
+		}
+		for name, attribute := range attributes {
+			if entry := mappingValue(n, name); entry != nil {
+				value, ok := literalValue(resolveAnchor(entry.Value).GetToken().Value)
+				if !ok {
+					return portMapping{}, false
+				}
+				*attribute = value
+			}
+		}
+		if mapping.target == "" {
+			return portMapping{}, false
+		}
+		mapping.protocol = strings.ToLower(mapping.protocol)
+		return mapping, true
+	}
+	return portMapping{}, false
+}
+
+// sequenceEntryRange returns the range of an item of a sequence such
+// as an entry of a service's ports attribute. The range of an item in
+// the long syntax starts at its first attribute and ends at the end of
+// its last value.
+func sequenceEntryRange(node ast.Node) protocol.Range {
+	if mappingNode, ok := node.(*ast.MappingNode); ok && len(mappingNode.Values) > 0 {
+		start := mappingNode.Values[0].Key.GetToken()
+		end := mappingNode.Values[len(mappingNode.Values)-1].Value.GetToken()
+		return protocol.Range{
+			Start: createRange(start, 0).Start,
+			End:   createRange(end, len(end.Value)).End,
+		}
+	}
+	t := node.GetToken()
+	return createRange(t, len(t.Value))
+}
+
+// validateDuplicatePorts reports the entries of a service's ports
+// attribute that are the same as an earlier entry of the same
+// attribute. Entries in the short and long syntax are compared with
+// each other.
+func validateDuplicatePorts(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	ports := mappingValue(value, "ports")
+	if ports == nil {
+		return nil
+	}
+	portsNode, ok := resolveAnchor(ports.Value).(*ast.SequenceNode)
+	if !ok {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	seen := map[portMapping]bool{}
+	for _, item := range portsNode.Values {
+		item = resolveAnchor(item)
+		mapping, ok := parsePortMapping(item)
+		if !ok {
+			continue
+		}
+		if !seen[mapping] {
+			seen[mapping] = true
+			continue
+		}
+
+		diagnostic := createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityWarning,
+			"DuplicatePort",
+			fmt.Sprintf("port mapping %v is already declared by this service", mapping),
+			sequenceEntryRange(item),
+		)
+		// the entry can only be removed by deleting its lines if it
+		// is the only thing on them
+		if !portsNode.IsFlowStyle {
+			diagnostic.Data = []types.NamedEdit{
+				{
+					Title: "Remove duplicate port entry",
+					Edit:  "",
+					Range: &protocol.Range{
+						Start: protocol.Position{Line: protocol.UInteger(item.GetToken().Position.Line - 1)},
+						End:   protocol.Position{Line: protocol.UInteger(lastLine(item))},
+					},
+				},
+			}
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
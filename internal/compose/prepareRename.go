@@ -5,15 +5,19 @@ import (
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
 )
 
-func PrepareRename(doc document.ComposeDocument, params *protocol.PrepareRenameParams) (*protocol.Range, error) {
-	highlights, err := DocumentHighlight(doc, params.Position)
-	if err != nil || len(highlights) == 0 {
-		return nil, err
-	}
-
-	for _, highlight := range highlights {
+// PrepareRename returns the range of the name that is at the given
+// position along with the name itself so that the client can use it
+// as the default value of the new name. The name is taken from the
+// parsed token instead of the document's text as the characters of the
+// range are not byte offsets.
+func PrepareRename(doc document.ComposeDocument, params *protocol.PrepareRenameParams) (*protocol.RangeWithPlaceholder, error) {
+	name, dependency := DocumentHighlights(doc, params.Position)
+	for _, highlight := range dependency.documentHighlights {
 		if insideRange(highlight.Range, params.Position.Line, params.Position.Character) {
-			return &highlight.Range, nil
+			return &protocol.RangeWithPlaceholder{
+				Range:       highlight.Range,
+				Placeholder: name,
+			}, nil
 		}
 	}
 	return nil, nil
@@ -120,3 +120,39 @@ func TestPrepareRename_Fragments(t *testing.T) {
 		})
 	}
 }
+
+func TestPrepareRename_MultibytePrefix(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	content := `
+services:
+  test:
+    depends_on: ["ü", web]
+  web:
+    image: alpine
+  ü:
+    image: alpine`
+	doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(content))
+	result, err := PrepareRename(doc, &protocol.PrepareRenameParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+			Position:     protocol.Position{Line: 3, Character: 23},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &protocol.RangeWithPlaceholder{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 3, Character: 22},
+			End:   protocol.Position{Line: 3, Character: 25},
+		},
+		Placeholder: "web",
+	}, result)
+
+	result, err = PrepareRename(doc, &protocol.PrepareRenameParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+			Position:     protocol.Position{Line: 3, Character: 19},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ü", result.Placeholder)
+}
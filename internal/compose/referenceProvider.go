@@ -0,0 +1,159 @@
+package compose
+
+import (
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// referenceScope is a set of names in a Compose file that refer to
+// each other. A name is only matched against the other names of the
+// scope that it was found in.
+type referenceScope struct {
+	references   []*token.Token
+	declarations []*token.Token
+}
+
+// referenceProvider finds the declarations and the uses of one
+// category of named objects in a Compose file. The providers are
+// shared by document highlights, definitions, references, and renames.
+type referenceProvider struct {
+	// dependencyType is the top-level attribute that declares the
+	// objects so that a declaration can be looked up in the included
+	// files if the file itself does not declare it.
+	dependencyType string
+	scopes         func(root *ast.MappingNode) []referenceScope
+}
+
+// topLevelMapping returns the mapping node of the named top-level
+// attribute or nil if it is not in the file or it is not a mapping.
+func topLevelMapping(root *ast.MappingNode, attributeName string) *ast.MappingNode {
+	for _, node := range root.Values {
+		name, value := convertTopLevelNode(node)
+		if name != nil && value != nil && name.Value == attributeName {
+			return value
+		}
+	}
+	return nil
+}
+
+// serviceReferenceProvider returns a provider for the top-level objects
+// of the given type that are referenced by the named attribute of the
+// services.
+func serviceReferenceProvider(dependencyType string, references func(servicesNode *ast.MappingNode) []*token.Token) referenceProvider {
+	return referenceProvider{
+		dependencyType: dependencyType,
+		scopes: func(root *ast.MappingNode) []referenceScope {
+			scope := referenceScope{}
+			if servicesNode := topLevelMapping(root, "services"); servicesNode != nil {
+				scope.references = references(servicesNode)
+			}
+			if declarationsNode := topLevelMapping(root, dependencyType); declarationsNode != nil {
+				scope.declarations = declarations(declarationsNode)
+			}
+			return []referenceScope{scope}
+		},
+	}
+}
+
+var servicesReferenceProvider = referenceProvider{
+	dependencyType: "services",
+	scopes: func(root *ast.MappingNode) []referenceScope {
+		servicesNode := topLevelMapping(root, "services")
+		if servicesNode == nil {
+			return nil
+		}
+		refs := serviceDependencyReferences(servicesNode, "depends_on", false)
+		refs = append(refs, extendedServiceReferences(servicesNode)...)
+		refs = append(refs, linkReferences(servicesNode)...)
+		refs = append(refs, additionalContextReferences(servicesNode)...)
+		refs = append(refs, sharedNamespaceReferences(servicesNode)...)
+		return []referenceScope{{references: refs, declarations: declarations(servicesNode)}}
+	},
+}
+
+// interpolationReferenceProvider treats every service as its own scope
+// as the environment of one service does not affect another service.
+var interpolationReferenceProvider = referenceProvider{
+	dependencyType: "interpolation",
+	scopes: func(root *ast.MappingNode) []referenceScope {
+		servicesNode := topLevelMapping(root, "services")
+		if servicesNode == nil {
+			return nil
+		}
+		scopes := []referenceScope{}
+		for _, service := range servicesNode.Values {
+			refs, decls := interpolationReferences(service.Value)
+			scopes = append(scopes, referenceScope{references: refs, declarations: decls})
+		}
+		return scopes
+	},
+}
+
+// projectNameReferences returns the tokens of the options of the
+// services' providers that have the same value as the project name.
+func projectNameReferences(servicesNode *ast.MappingNode, projectName string) []*token.Token {
+	tokens := []*token.Token{}
+	for _, serviceNode := range servicesNode.Values {
+		provider := mappingValue(serviceNode.Value, "provider")
+		if provider == nil {
+			continue
+		}
+		options := mappingValue(provider.Value, "options")
+		if options == nil {
+			continue
+		}
+		if optionsNode, ok := resolveAnchor(options.Value).(*ast.MappingNode); ok {
+			for _, option := range optionsNode.Values {
+				if s, ok := resolveAnchor(option.Value).(*ast.StringNode); ok && s.Value == projectName {
+					tokens = append(tokens, s.GetToken())
+				}
+			}
+		}
+	}
+	return tokens
+}
+
+// projectNameReferenceProvider links the top-level name attribute with
+// the provider options of the services that pass the project name on.
+var projectNameReferenceProvider = referenceProvider{
+	dependencyType: "name",
+	scopes: func(root *ast.MappingNode) []referenceScope {
+		name := mappingValue(root, "name")
+		if name == nil {
+			return nil
+		}
+		s, ok := resolveAnchor(name.Value).(*ast.StringNode)
+		if !ok {
+			return nil
+		}
+		if _, ok := literalValue(s.Value); !ok {
+			return nil
+		}
+		scope := referenceScope{declarations: []*token.Token{s.GetToken()}}
+		if servicesNode := topLevelMapping(root, "services"); servicesNode != nil {
+			scope.references = projectNameReferences(servicesNode, s.Value)
+		}
+		return []referenceScope{scope}
+	},
+}
+
+// referenceProviders are consulted in order and the first provider
+// with a name at the requested position is used.
+var referenceProviders = []referenceProvider{
+	servicesReferenceProvider,
+	interpolationReferenceProvider,
+	serviceReferenceProvider("networks", func(servicesNode *ast.MappingNode) []*token.Token {
+		return serviceDependencyReferences(servicesNode, "networks", false)
+	}),
+	serviceReferenceProvider("volumes", volumeReferences),
+	serviceReferenceProvider("configs", func(servicesNode *ast.MappingNode) []*token.Token {
+		return serviceDependencyReferences(servicesNode, "configs", true)
+	}),
+	serviceReferenceProvider("secrets", func(servicesNode *ast.MappingNode) []*token.Token {
+		return serviceDependencyReferences(servicesNode, "secrets", true)
+	}),
+	serviceReferenceProvider("models", func(servicesNode *ast.MappingNode) []*token.Token {
+		return serviceDependencyReferences(servicesNode, "models", false)
+	}),
+	projectNameReferenceProvider,
+}
@@ -0,0 +1,186 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestDocumentHighlight_ProjectName(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      protocol.UInteger
+		character protocol.UInteger
+		ranges    []protocol.DocumentHighlight
+	}{
+		{
+			name: "project name",
+			content: `
+name: shop
+services:
+  web:
+    provider:
+      type: model
+      options:
+        project: shop
+        model: ai/smollm2`,
+			line:      1,
+			character: 7,
+			ranges: []protocol.DocumentHighlight{
+				documentHighlight(7, 17, 7, 21, protocol.DocumentHighlightKindRead),
+				documentHighlight(1, 6, 1, 10, protocol.DocumentHighlightKindWrite),
+			},
+		},
+		{
+			name: "provider option with the project name",
+			content: `
+name: shop
+services:
+  web:
+    provider:
+      type: model
+      options:
+        project: shop`,
+			line:      7,
+			character: 19,
+			ranges: []protocol.DocumentHighlight{
+				documentHighlight(7, 17, 7, 21, protocol.DocumentHighlightKindRead),
+				documentHighlight(1, 6, 1, 10, protocol.DocumentHighlightKindWrite),
+			},
+		},
+		{
+			name: "provider option with another value",
+			content: `
+name: shop
+services:
+  web:
+    provider:
+      type: model
+      options:
+        project: store`,
+			line:      7,
+			character: 19,
+			ranges:    nil,
+		},
+		{
+			name: "interpolated project name is only highlighted as a variable",
+			content: `
+name: ${PROJECT}
+services:
+  web:
+    provider:
+      type: model
+      options:
+        project: ${PROJECT}`,
+			line:      7,
+			character: 21,
+			ranges: []protocol.DocumentHighlight{
+				documentHighlight(7, 19, 7, 26, protocol.DocumentHighlightKindRead),
+			},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(tc.content))
+			ranges, err := DocumentHighlight(doc, protocol.Position{Line: tc.line, Character: tc.character})
+			require.NoError(t, err)
+			require.Equal(t, tc.ranges, ranges)
+		})
+	}
+}
+
+// TestReferenceProviders_Registered checks that a provider that has
+// been added to the registry is used by every feature that is built on
+// top of the providers.
+func TestReferenceProviders_Registered(t *testing.T) {
+	providers := referenceProviders
+	defer func() { referenceProviders = providers }()
+	referenceProviders = append(referenceProviders, referenceProvider{
+		dependencyType: "x-hosts",
+		scopes: func(root *ast.MappingNode) []referenceScope {
+			scope := referenceScope{}
+			if servicesNode := topLevelMapping(root, "services"); servicesNode != nil {
+				for _, serviceNode := range servicesNode.Values {
+					if hostname := mappingValue(serviceNode.Value, "hostname"); hostname != nil {
+						scope.references = append(scope.references, resolveAnchor(hostname.Value).GetToken())
+					}
+				}
+			}
+			if hostsNode := topLevelMapping(root, "x-hosts"); hostsNode != nil {
+				scope.declarations = declarations(hostsNode)
+			}
+			return []referenceScope{scope}
+		},
+	})
+
+	content := `
+services:
+  web:
+    hostname: gateway
+x-hosts:
+  gateway:
+    address: 10.0.0.1`
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(content))
+	referenceRange := protocol.Range{Start: protocol.Position{Line: 3, Character: 14}, End: protocol.Position{Line: 3, Character: 21}}
+	declarationRange := protocol.Range{Start: protocol.Position{Line: 5, Character: 2}, End: protocol.Position{Line: 5, Character: 9}}
+	position := protocol.Position{Line: 3, Character: 16}
+
+	highlights, err := DocumentHighlight(doc, position)
+	require.NoError(t, err)
+	require.Equal(t, []protocol.DocumentHighlight{
+		documentHighlight(3, 14, 3, 21, protocol.DocumentHighlightKindRead),
+		documentHighlight(5, 2, 5, 9, protocol.DocumentHighlightKindWrite),
+	}, highlights)
+
+	links, err := Definition(context.Background(), true, document.NewDocumentManager(), doc, &protocol.DefinitionParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+			Position:     position,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []protocol.LocationLink{
+		{
+			OriginSelectionRange: &referenceRange,
+			TargetRange:          declarationRange,
+			TargetSelectionRange: declarationRange,
+			TargetURI:            composeFileURI,
+		},
+	}, links)
+
+	require.Equal(t, []protocol.Location{
+		{URI: composeFileURI, Range: referenceRange},
+		{URI: composeFileURI, Range: declarationRange},
+	}, references(t, composeFileURI, content, position.Line, position.Character, true))
+
+	edit, err := Rename(doc, &protocol.RenameParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+			Position:     position,
+		},
+		NewName: "proxy",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &protocol.WorkspaceEdit{
+		Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+			composeFileURI: {
+				{NewText: "proxy", Range: referenceRange},
+				{NewText: "proxy", Range: declarationRange},
+			},
+		},
+	}, edit)
+
+}
@@ -0,0 +1,102 @@
+package compose
+
+import (
+	"context"
+	"slices"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// projectDependencyTypes are the types of the named objects that are
+// shared by the file and the files that it includes.
+var projectDependencyTypes = []string{"services", "networks", "volumes", "configs", "secrets", "models"}
+
+// References returns the locations of the uses of the name at the
+// given position. The declarations of the name are only included if
+// the client has asked for them. Names of the objects of the project
+// are also looked up in the included files. The locations of every
+// file are given to the report function as soon as the file has been
+// scanned and the scan stops with the context's error if the context
+// is done before all the files have been scanned.
+func References(ctx context.Context, doc document.ComposeDocument, params *protocol.ReferenceParams, report func([]protocol.Location)) ([]protocol.Location, error) {
+	name, dependency := DocumentHighlights(doc, params.Position)
+	if len(dependency.documentHighlights) == 0 {
+		return nil, nil
+	}
+
+	locations := highlightLocations(params.TextDocument.URI, dependency.documentHighlights, params.Context.IncludeDeclaration)
+	if report != nil && len(locations) > 0 {
+		report(locations)
+	}
+
+	if slices.Contains(projectDependencyTypes, dependency.dependencyType) {
+		files, _ := doc.IncludedFiles()
+		fileURIs := []string{}
+		for u := range files {
+			fileURIs = append(fileURIs, u)
+		}
+		slices.Sort(fileURIs)
+		for _, u := range fileURIs {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			fileLocations := highlightLocations(u, includedFileHighlights(files[u], dependency.dependencyType, name), params.Context.IncludeDeclaration)
+			if report != nil && len(fileLocations) > 0 {
+				report(fileLocations)
+			}
+			locations = append(locations, fileLocations...)
+		}
+	}
+
+	if len(locations) == 0 {
+		return nil, nil
+	}
+	return locations, nil
+}
+
+// highlightLocations converts the highlights of a file into locations.
+// Declarations are skipped unless they have been asked for.
+func highlightLocations(u protocol.DocumentUri, highlights []protocol.DocumentHighlight, includeDeclaration bool) []protocol.Location {
+	locations := []protocol.Location{}
+	for _, highlight := range highlights {
+		if !includeDeclaration && *highlight.Kind == protocol.DocumentHighlightKindWrite {
+			continue
+		}
+		locations = append(locations, protocol.Location{URI: u, Range: highlight.Range})
+	}
+	return locations
+}
+
+// includedFileHighlights returns the highlights of the references to
+// and the declarations of the named object of the given type in an
+// included file.
+func includedFileHighlights(file *ast.File, dependencyType, name string) []protocol.DocumentHighlight {
+	highlights := []protocol.DocumentHighlight{}
+	if file == nil || len(file.Docs) == 0 {
+		return highlights
+	}
+	mappingNode, ok := file.Docs[0].Body.(*ast.MappingNode)
+	if !ok {
+		return highlights
+	}
+	for _, provider := range referenceProviders {
+		if provider.dependencyType != dependencyType {
+			continue
+		}
+		for _, scope := range provider.scopes(mappingNode) {
+			for _, reference := range scope.references {
+				if reference.Value == name {
+					highlights = append(highlights, documentHighlightFromToken(reference, protocol.DocumentHighlightKindRead))
+				}
+			}
+			for _, declaration := range scope.declarations {
+				if declaration.Value == name {
+					highlights = append(highlights, documentHighlightFromToken(declaration, protocol.DocumentHighlightKindWrite))
+				}
+			}
+		}
+	}
+	return highlights
+}
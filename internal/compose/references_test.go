@@ -0,0 +1,172 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+// referenceLocations converts the expected highlights of a test case
+// into the locations that the references request should return.
+func referenceLocations(u protocol.DocumentUri, ranges []protocol.DocumentHighlight, includeDeclaration bool) []protocol.Location {
+	locations := []protocol.Location{}
+	for _, highlight := range ranges {
+		if includeDeclaration || *highlight.Kind == protocol.DocumentHighlightKindRead {
+			locations = append(locations, protocol.Location{URI: u, Range: highlight.Range})
+		}
+	}
+	if len(locations) == 0 {
+		return nil
+	}
+	return locations
+}
+
+func references(t *testing.T, u protocol.DocumentUri, content string, line, character protocol.UInteger, includeDeclaration bool) []protocol.Location {
+	doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(u), 1, []byte(content))
+	locations, err := References(context.Background(), doc, &protocol.ReferenceParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: u},
+			Position:     protocol.Position{Line: line, Character: character},
+		},
+		Context: protocol.ReferenceContext{IncludeDeclaration: includeDeclaration},
+	}, nil)
+	require.NoError(t, err)
+	slices.SortFunc(locations, func(a, b protocol.Location) int {
+		return int(a.Range.Start.Line) - int(b.Range.Start.Line)
+	})
+	return locations
+}
+
+func TestReferences_Services(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range serviceReferenceTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, referenceLocations(composeFileURI, tc.ranges, true), references(t, composeFileURI, tc.content, tc.line, tc.character, true))
+			require.Equal(t, referenceLocations(composeFileURI, tc.ranges, false), references(t, composeFileURI, tc.content, tc.line, tc.character, false))
+		})
+	}
+}
+
+func TestReferences_Networks(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range networkReferenceTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, referenceLocations(composeFileURI, tc.ranges, true), references(t, composeFileURI, tc.content, tc.line, tc.character, true))
+			require.Equal(t, referenceLocations(composeFileURI, tc.ranges, false), references(t, composeFileURI, tc.content, tc.line, tc.character, false))
+		})
+	}
+}
+
+func TestReferences_Fragments(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range fragmentTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, referenceLocations(composeFileURI, tc.ranges, true), references(t, composeFileURI, tc.content, tc.line, tc.character, true))
+			require.Equal(t, referenceLocations(composeFileURI, tc.ranges, false), references(t, composeFileURI, tc.content, tc.line, tc.character, false))
+		})
+	}
+}
+
+func TestReferences_IncludedFiles(t *testing.T) {
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	otherFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.other.yaml")), "/"))
+	mgr := document.NewDocumentManager()
+	changed, err := mgr.Write(context.Background(), uri.URI(otherFileURI), protocol.DockerComposeLanguage, 1, []byte(`
+services:
+  backend:
+    depends_on:
+      - db`))
+	require.NoError(t, err)
+	require.True(t, changed)
+	doc := document.NewComposeDocument(mgr, uri.URI(composeFileURI), 1, []byte(`
+include:
+  - compose.other.yaml
+services:
+  db:
+    image: postgres`))
+
+	reported := [][]protocol.Location{}
+	locations, err := References(context.Background(), doc, &protocol.ReferenceParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+			Position:     protocol.Position{Line: 4, Character: 3},
+		},
+		Context: protocol.ReferenceContext{IncludeDeclaration: true},
+	}, func(batch []protocol.Location) {
+		reported = append(reported, batch)
+	})
+	require.NoError(t, err)
+	expected := []protocol.Location{
+		{
+			URI: composeFileURI,
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 2},
+				End:   protocol.Position{Line: 4, Character: 4},
+			},
+		},
+		{
+			URI: otherFileURI,
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 4, Character: 8},
+				End:   protocol.Position{Line: 4, Character: 10},
+			},
+		},
+	}
+	require.Equal(t, expected, locations)
+	require.Equal(t, [][]protocol.Location{expected[:1], expected[1:]}, reported)
+}
+
+func TestReferences_CancelledWhileScanningIncludedFiles(t *testing.T) {
+	folder := os.TempDir()
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/"))
+	mgr := document.NewDocumentManager()
+	for _, name := range []string{"compose.a.yaml", "compose.b.yaml", "compose.c.yaml"} {
+		u := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, name)), "/"))
+		changed, err := mgr.Write(context.Background(), uri.URI(u), protocol.DockerComposeLanguage, 1, []byte(`
+services:
+  backend:
+    depends_on:
+      - db`))
+		require.NoError(t, err)
+		require.True(t, changed)
+	}
+	doc := document.NewComposeDocument(mgr, uri.URI(composeFileURI), 1, []byte(`
+include:
+  - compose.a.yaml
+  - compose.b.yaml
+  - compose.c.yaml
+services:
+  db:
+    image: postgres`))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reported := [][]protocol.Location{}
+	locations, err := References(ctx, doc, &protocol.ReferenceParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: composeFileURI},
+			Position:     protocol.Position{Line: 6, Character: 3},
+		},
+		Context: protocol.ReferenceContext{IncludeDeclaration: true},
+	}, func(batch []protocol.Location) {
+		reported = append(reported, batch)
+		// cancel the request once the first included file has been scanned
+		if len(reported) == 2 {
+			cancel()
+		}
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, locations)
+	require.Len(t, reported, 2)
+	require.Equal(t, composeFileURI, reported[0][0].URI)
+	require.True(t, strings.HasSuffix(reported[1][0].URI, "compose.a.yaml"))
+}
@@ -3,6 +3,7 @@ package compose
 import (
 	"bytes"
 	_ "embed"
+	"encoding/json"
 	"slices"
 
 	"github.com/goccy/go-yaml/ast"
@@ -14,7 +15,13 @@ var schemaData []byte
 
 var composeSchema *jsonschema.Schema
 
+// serviceAttributeOrder is the order that the attributes of a service
+// are declared in by the schema.
+var serviceAttributeOrder []string
+
 func init() {
+	serviceAttributeOrder = propertyOrder(schemaData)
+
 	schema, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaData))
 	if err != nil {
 		return
@@ -31,6 +38,40 @@ func init() {
 	composeSchema = compiled
 }
 
+// propertyOrder returns the names of the service properties in the
+// order that they appear in the given schema. The compiled schema
+// stores its properties in a map so the order is read from the JSON.
+func propertyOrder(data []byte) []string {
+	var schema struct {
+		Definitions struct {
+			Service struct {
+				Properties json.RawMessage `json:"properties"`
+			} `json:"service"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil
+	}
+
+	names := []string{}
+	decoder := json.NewDecoder(bytes.NewReader(schema.Definitions.Service.Properties))
+	if _, err := decoder.Token(); err != nil {
+		return nil
+	}
+	for decoder.More() {
+		t, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil
+		}
+		names = append(names, t.(string))
+	}
+	return names
+}
+
 func schemaProperties() map[string]*jsonschema.Schema {
 	return composeSchema.Properties
 }
@@ -88,6 +129,16 @@ func recurseNodeProperties(nodes []*ast.MappingValueNode, line, column, nodeOffs
 						}
 					}
 				}
+				if len(schema.Properties) > 0 {
+					return recurseNodeProperties(nodes, line, column, nodeOffset+1, schema.Properties, true)
+				}
+			}
+			// an attribute such as gpus that is either a string or an
+			// array of objects
+			for _, nested := range prop.Ref.OneOf {
+				if schema, ok := nested.Items.(*jsonschema.Schema); ok && len(schema.Properties) > 0 {
+					return recurseNodeProperties(nodes, line, column, nodeOffset+1, schema.Properties, true)
+				}
 			}
 		}
 
@@ -123,6 +174,9 @@ func recurseNodeProperties(nodes []*ast.MappingValueNode, line, column, nodeOffs
 					}
 				}
 			}
+			if schema.Ref != nil && len(schema.Ref.Properties) > 0 {
+				return recurseNodeProperties(nodes, line, column, nodeOffset+1, schema.Ref.Properties, true)
+			}
 			return recurseNodeProperties(nodes, line, column, nodeOffset+1, schema.Properties, true)
 		}
 
@@ -136,3 +190,93 @@ func recurseNodeProperties(nodes []*ast.MappingValueNode, line, column, nodeOffs
 	}
 	return nodes, properties, false
 }
+
+// expandSchemas follows the references of the given schemas and
+// flattens their oneOf, anyOf, and allOf subschemas.
+func expandSchemas(schemas []*jsonschema.Schema) []*jsonschema.Schema {
+	expanded := []*jsonschema.Schema{}
+	for _, schema := range schemas {
+		if schema == nil {
+			continue
+		}
+
+		if schema.Ref != nil {
+			expanded = append(expanded, expandSchemas([]*jsonschema.Schema{schema.Ref})...)
+		} else if schema.Types == nil && (len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 || len(schema.AllOf) > 0) {
+			expanded = append(expanded, expandSchemas(schema.OneOf)...)
+			expanded = append(expanded, expandSchemas(schema.AnyOf)...)
+			expanded = append(expanded, expandSchemas(schema.AllOf)...)
+		} else {
+			expanded = append(expanded, schema)
+		}
+	}
+	return expanded
+}
+
+// childSchemas returns the schemas that may describe the value of the
+// given attribute of an object that is described by the given schemas.
+func childSchemas(schemas []*jsonschema.Schema, attributeName string) []*jsonschema.Schema {
+	children := []*jsonschema.Schema{}
+	for _, schema := range expandSchemas(schemas) {
+		if property, ok := schema.Properties[attributeName]; ok {
+			children = append(children, property)
+			continue
+		}
+
+		matched := false
+		for regexp, property := range schema.PatternProperties {
+			if regexp.MatchString(attributeName) {
+				children = append(children, property)
+				matched = true
+			}
+		}
+		if additional, ok := schema.AdditionalProperties.(*jsonschema.Schema); ok && !matched {
+			children = append(children, additional)
+		}
+	}
+	return children
+}
+
+// itemSchemas returns the schemas that may describe the items of an
+// array that is described by the given schemas.
+func itemSchemas(schemas []*jsonschema.Schema) []*jsonschema.Schema {
+	items := []*jsonschema.Schema{}
+	for _, schema := range expandSchemas(schemas) {
+		if item, ok := schema.Items.(*jsonschema.Schema); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// lookupSchemas returns the schemas that may describe the value found
+// at the given path from the root of a Compose file. An element of []
+// refers to the items of an array.
+func lookupSchemas(path ...string) []*jsonschema.Schema {
+	schemas := []*jsonschema.Schema{composeSchema}
+	for _, element := range path {
+		if element == "[]" {
+			schemas = itemSchemas(schemas)
+		} else {
+			schemas = childSchemas(schemas, element)
+		}
+	}
+	return expandSchemas(schemas)
+}
+
+// enumValues returns the sorted string values of the enums of the
+// given schemas.
+func enumValues(schemas []*jsonschema.Schema) []string {
+	values := []string{}
+	for _, schema := range schemas {
+		if schema.Enum != nil {
+			for _, value := range schema.Enum.Values {
+				if s, ok := value.(string); ok && !slices.Contains(values, s) {
+					values = append(values, s)
+				}
+			}
+		}
+	}
+	slices.Sort(values)
+	return values
+}
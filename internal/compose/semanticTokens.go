@@ -0,0 +1,119 @@
+package compose
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/docker/docker-language-server/internal/bake/hcl"
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// SemanticTokensFull returns the semantic tokens of the variables that
+// are interpolated in the values of the given Compose document. Only
+// the name of a variable is highlighted so the braces, default values,
+// and error messages around it are not. Escaped $$ dollar signs do not
+// start a variable and are not highlighted. The positions of the
+// tokens are taken from the text of the document and are counted in
+// UTF-16 code units.
+func SemanticTokensFull(doc document.ComposeDocument) *protocol.SemanticTokens {
+	file := doc.File()
+	if file == nil {
+		return nil
+	}
+
+	lines := strings.Split(string(doc.Input()), "\n")
+	variableType := hcl.SemanticTokenTypeIndex(hcl.TokenType_Variable)
+	tokens := []types.SemanticToken{}
+	var walk func(node ast.Node)
+	walk = func(node ast.Node) {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			walk(n.Value)
+		case *ast.MappingNode:
+			for _, child := range n.Values {
+				walk(child.Value)
+			}
+		case *ast.SequenceNode:
+			for _, item := range n.Values {
+				walk(item)
+			}
+		case *ast.StringNode:
+			t := n.GetToken()
+			if t.Position.Line > len(lines) {
+				return
+			}
+			line := lines[t.Position.Line-1]
+			raw, start, ok := scalarSource(line, t)
+			if !ok {
+				return
+			}
+			for _, variable := range interpolationVariables(raw) {
+				tokens = append(tokens, types.SemanticToken{
+					Line:      uint32(t.Position.Line - 1),
+					Character: uint32(utf16Length(line[:start+variable.start])),
+					Length:    uint32(utf16Length(variable.name)),
+					Type:      variableType,
+				})
+			}
+		}
+	}
+	for _, documentNode := range file.Docs {
+		walk(documentNode.Body)
+	}
+	return &protocol.SemanticTokens{Data: types.EncodeSemanticTokens(tokens)}
+}
+
+// scalarSource returns the text of the given scalar token as it was
+// written in the line along with the byte offset that the text starts
+// at. The quotes of a quoted scalar are not included but its escape
+// sequences are left as is. False is returned if the scalar does not
+// end on the line that it starts on.
+func scalarSource(line string, t *token.Token) (string, int, bool) {
+	start := 0
+	for column := 1; column < t.Position.Column; column++ {
+		_, size := utf8.DecodeRuneInString(line[start:])
+		if size == 0 {
+			return "", 0, false
+		}
+		start += size
+	}
+
+	switch t.Type {
+	case token.DoubleQuoteType, token.SingleQuoteType:
+		if start == len(line) {
+			return "", 0, false
+		}
+		quote := line[start]
+		for i := start + 1; i < len(line); i++ {
+			switch {
+			case quote == '"' && line[i] == '\\':
+				i++
+			case quote == '\'' && line[i] == '\'' && i+1 < len(line) && line[i+1] == '\'':
+				i++
+			case line[i] == quote:
+				return line[start+1 : i], start + 1, true
+			}
+		}
+		return "", 0, false
+	}
+
+	if !strings.HasPrefix(line[start:], t.Value) {
+		return "", 0, false
+	}
+	return t.Value, start, true
+}
+
+// utf16Length returns the number of UTF-16 code units that are needed
+// to encode the given string.
+func utf16Length(s string) int {
+	length := 0
+	for _, r := range s {
+		length += utf16.RuneLen(r)
+	}
+	return length
+}
@@ -0,0 +1,156 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/bake/hcl"
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestSemanticTokensFull(t *testing.T) {
+	variableType := hcl.SemanticTokenTypeIndex(hcl.TokenType_Variable)
+	testCases := []struct {
+		name    string
+		content string
+		result  *protocol.SemanticTokens
+	}{
+		{
+			name: "no variables",
+			content: `
+services:
+  web:
+    image: alpine`,
+			result: &protocol.SemanticTokens{Data: []uint32{}},
+		},
+		{
+			name: "variables with and without braces",
+			content: `
+services:
+  web:
+    image: $IMAGE
+    command: echo ${MESSAGE}`,
+			result: &protocol.SemanticTokens{
+				Data: []uint32{
+					3, 12, 5, variableType, 0,
+					1, 20, 7, variableType, 0,
+				},
+			},
+		},
+		{
+			name: "default values and error messages are not included",
+			content: `
+services:
+  web:
+    image: "${REGISTRY:-docker.io}/${IMAGE:?image is required}"`,
+			result: &protocol.SemanticTokens{
+				Data: []uint32{
+					3, 14, 8, variableType, 0,
+					0, 23, 5, variableType, 0,
+				},
+			},
+		},
+		{
+			name: "nested variable in a default value",
+			content: `
+services:
+  web:
+    image: ${IMAGE:-${DEFAULT}}`,
+			result: &protocol.SemanticTokens{
+				Data: []uint32{
+					3, 13, 5, variableType, 0,
+					0, 9, 7, variableType, 0,
+				},
+			},
+		},
+		{
+			name: "escaped dollar signs are ignored",
+			content: `
+services:
+  web:
+    command: echo $$HOME $${PATH}`,
+			result: &protocol.SemanticTokens{Data: []uint32{}},
+		},
+		{
+			name: "escape sequences in a double-quoted string",
+			content: `
+services:
+  web:
+    command: "\t\"${MESSAGE}\" é $USER"`,
+			result: &protocol.SemanticTokens{
+				Data: []uint32{
+					3, 20, 7, variableType, 0,
+					0, 14, 4, variableType, 0,
+				},
+			},
+		},
+		{
+			name: "escaped quotes in a single-quoted string",
+			content: `
+services:
+  web:
+    command: 'it''s ${MESSAGE}'`,
+			result: &protocol.SemanticTokens{
+				Data: []uint32{
+					3, 22, 7, variableType, 0,
+				},
+			},
+		},
+		{
+			name: "non-ASCII characters before a variable",
+			content: `
+services:
+  web:
+    command: échö 😀 ${MESSAGE}`,
+			result: &protocol.SemanticTokens{
+				Data: []uint32{
+					3, 23, 7, variableType, 0,
+				},
+			},
+		},
+		{
+			name: "non-ASCII characters in a key and a quoted string",
+			content: `
+services:
+  web:
+    labels:
+      été: "😀😀 $USER"`,
+			result: &protocol.SemanticTokens{
+				Data: []uint32{
+					4, 18, 4, variableType, 0,
+				},
+			},
+		},
+		{
+			name: "double-quoted string that spans multiple lines",
+			content: `
+services:
+  web:
+    command: "echo
+      ${MESSAGE}"`,
+			result: &protocol.SemanticTokens{Data: []uint32{}},
+		},
+		{
+			name: "variables in keys are ignored",
+			content: `
+services:
+  ${SERVICE}:
+    image: alpine`,
+			result: &protocol.SemanticTokens{Data: []uint32{}},
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(tc.content))
+			require.Equal(t, tc.result, SemanticTokensFull(doc))
+		})
+	}
+}
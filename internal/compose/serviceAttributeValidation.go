@@ -0,0 +1,33 @@
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// validateServiceAttributes reports the attributes of a service that
+// are not defined by the schema and that are not extensions that start
+// with x- as they are most likely misspelled. Build attributes that are
+// set next to a build string are already reported as such.
+func validateServiceAttributes(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	service, ok := resolveAnchor(value).(*ast.MappingNode)
+	if !ok || len(serviceAttributeOrder) == 0 {
+		return nil
+	}
+	misplaced := misplacedBuildAttributes(service)
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, node := range service.Values {
+		attributeKey, ok := node.Key.(*ast.StringNode)
+		if !ok || strings.HasPrefix(attributeKey.Value, "x-") || slices.Contains(serviceAttributeOrder, attributeKey.Value) || slices.Contains(misplaced, node) {
+			continue
+		}
+		description := fmt.Sprintf("attribute %v of service %v", attributeKey.Value, key.GetToken().Value)
+		diagnostics = append(diagnostics, unknownAttributeDiagnostic(source, "UnknownServiceAttribute", description, attributeKey, serviceAttributeOrder))
+	}
+	return diagnostics
+}
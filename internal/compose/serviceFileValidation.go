@@ -0,0 +1,75 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// serviceFileAttributes are the attributes of a service that declare
+// files to read the service's environment variables and labels from
+// with a description of the files for diagnostic messages. The entries
+// of both attributes are handled the same way.
+var serviceFileAttributes = []struct {
+	attribute   string
+	description string
+}{
+	{attribute: "env_file", description: "env file"},
+	{attribute: "label_file", description: "label file"},
+}
+
+// serviceFileEntries returns the entries of a service's env_file or
+// label_file attribute. The attribute is either a single path or a
+// list of entries.
+func serviceFileEntries(value ast.Node) []ast.Node {
+	if sequenceNode, ok := resolveAnchor(value).(*ast.SequenceNode); ok {
+		return sequenceNode.Values
+	}
+	return []ast.Node{value}
+}
+
+// serviceFileToken returns the token of the path of an entry of a
+// service's env_file or label_file attribute.
+func serviceFileToken(node ast.Node) *token.Token {
+	if pathNode := mappingValue(resolveAnchor(node), "path"); pathNode != nil {
+		return resolveAnchor(pathNode.Value).GetToken()
+	}
+	return resolveAnchor(node).GetToken()
+}
+
+// validateServiceFiles reports the entries of the services' env_file
+// and label_file attributes that declare a required file that does not
+// exist. Relative paths are resolved against the folder of the Compose
+// file and interpolated paths are ignored.
+func validateServiceFiles(source string, documentPath *document.DocumentPath, root *ast.MappingNode) []protocol.Diagnostic {
+	if documentPath == nil {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, serviceFile := range serviceFileAttributes {
+		matchPropertyPath([]string{"services", "*", serviceFile.attribute}, nil, root, func(key, value ast.Node) {
+			for _, entry := range serviceFileEntries(value) {
+				path, required, ok := envFileEntry(entry)
+				if !ok || !required {
+					continue
+				}
+				if _, err := os.Stat(resolvePath(documentPath.Folder, path, documentPath.WSLDollarSignHost)); os.IsNotExist(err) {
+					t := serviceFileToken(entry)
+					diagnostics = append(diagnostics, createValidationDiagnostic(
+						source,
+						protocol.DiagnosticSeverityError,
+						"FileNotFound",
+						fmt.Sprintf("%v %v could not be found", serviceFile.description, t.Value),
+						createRange(t, len(t.Value)),
+					))
+				}
+			}
+		})
+	}
+	return diagnostics
+}
@@ -0,0 +1,170 @@
+package compose
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// SortServiceKeys returns an edit that reorders the attributes of the
+// given service into the order that the schema declares them in.
+// Merge keys are kept at the top of the service and attributes that
+// are not in the schema are moved to the end in their original order.
+// Only the lines of the attributes are moved so their values and any
+// comments that directly precede them are left untouched. If the
+// attributes are already sorted then nil is returned.
+func SortServiceKeys(doc document.ComposeDocument, serviceName string) *protocol.WorkspaceEdit {
+	file := doc.File()
+	if file == nil {
+		return nil
+	}
+
+	lines := strings.Split(string(doc.Input()), "\n")
+	for _, documentNode := range file.Docs {
+		root, ok := documentNode.Body.(*ast.MappingNode)
+		if !ok {
+			continue
+		}
+		services := mappingValue(root, "services")
+		if services == nil {
+			continue
+		}
+		service := mappingValue(services.Value, serviceName)
+		if service == nil {
+			continue
+		}
+		attributes, ok := resolveAnchor(service.Value).(*ast.MappingNode)
+		if !ok || attributes.IsFlowStyle || len(attributes.Values) < 2 {
+			return nil
+		}
+		edit := sortAttributes(lines, service.Key.GetToken().Position.Line-1, attributes.Values)
+		if edit == nil {
+			return nil
+		}
+		return &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+				string(doc.URI()): {*edit},
+			},
+		}
+	}
+	return nil
+}
+
+func attributeRank(name string) int {
+	if name == "<<" {
+		return -1
+	}
+	if idx := slices.Index(serviceAttributeOrder, name); idx != -1 {
+		return idx
+	}
+	return len(serviceAttributeOrder)
+}
+
+func lineIndentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// sortAttributes splits the lines of the service into chunks that each
+// hold one attribute and then rearranges them into the schema's order.
+func sortAttributes(lines []string, serviceLine int, attributes []*ast.MappingValueNode) *protocol.TextEdit {
+	keyLines := []int{}
+	for _, attribute := range attributes {
+		keyLine := attribute.Key.GetToken().Position.Line - 1
+		if len(keyLines) > 0 && keyLine <= keyLines[len(keyLines)-1] {
+			return nil
+		}
+		keyLines = append(keyLines, keyLine)
+	}
+	indentation := attributes[0].Key.GetToken().Position.Column - 1
+
+	// comments directly above an attribute's key are moved with it
+	starts := []int{}
+	for i, keyLine := range keyLines {
+		previous := serviceLine
+		if i > 0 {
+			previous = keyLines[i-1]
+		}
+		start := keyLine
+		for start-1 > previous {
+			line := lines[start-1]
+			if !strings.HasPrefix(strings.TrimSpace(line), "#") || lineIndentation(line) != indentation {
+				break
+			}
+			start--
+		}
+		starts = append(starts, start)
+	}
+
+	// the last attribute's value ends at the first line that is not
+	// indented any further than the attribute's key
+	end := keyLines[len(keyLines)-1]
+	for i := end + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue
+		}
+		if lineIndentation(lines[i]) > indentation || (lineIndentation(lines[i]) == indentation && strings.HasPrefix(trimmed, "-")) {
+			end = i
+			continue
+		}
+		break
+	}
+
+	chunks := [][]string{}
+	separated := true
+	for i := range starts {
+		chunkEnd := end + 1
+		if i+1 < len(starts) {
+			chunkEnd = starts[i+1]
+		}
+		chunk := lines[starts[i]:chunkEnd]
+		trimmed := len(chunk)
+		for trimmed > 1 && strings.TrimSpace(chunk[trimmed-1]) == "" {
+			trimmed--
+		}
+		if i+1 < len(starts) && trimmed == len(chunk) {
+			separated = false
+		}
+		chunks = append(chunks, chunk[:trimmed])
+	}
+
+	order := make([]int, len(attributes))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return attributeRank(attributes[a].Key.GetToken().Value) - attributeRank(attributes[b].Key.GetToken().Value)
+	})
+	sorted := true
+	for i := range order {
+		if order[i] != i {
+			sorted = false
+			break
+		}
+	}
+	if sorted {
+		return nil
+	}
+
+	blankLine := ""
+	if strings.HasSuffix(lines[keyLines[0]], "\r") {
+		blankLine = "\r"
+	}
+	sortedLines := []string{}
+	for i, idx := range order {
+		if i > 0 && separated {
+			sortedLines = append(sortedLines, blankLine)
+		}
+		sortedLines = append(sortedLines, chunks[idx]...)
+	}
+	return &protocol.TextEdit{
+		NewText: strings.Join(sortedLines, "\n"),
+		Range: protocol.Range{
+			Start: protocol.Position{Line: protocol.UInteger(starts[0]), Character: 0},
+			End:   protocol.Position{Line: protocol.UInteger(end), Character: protocol.UInteger(len(lines[end]))},
+		},
+	}
+}
@@ -0,0 +1,176 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestSortServiceKeys(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		service string
+		result  *protocol.TextEdit
+	}{
+		{
+			name: "attributes are sorted into the schema's order",
+			content: `
+services:
+  web:
+    image: nginx
+    build: .
+    command: ["nginx"]`,
+			service: "web",
+			result: &protocol.TextEdit{
+				NewText: "    build: .\n    command: [\"nginx\"]\n    image: nginx",
+				Range: protocol.Range{
+					Start: protocol.Position{Line: 3, Character: 0},
+					End:   protocol.Position{Line: 5, Character: 22},
+				},
+			},
+		},
+		{
+			name: "values and comments move with their attributes",
+			content: `
+services:
+  web:
+    # the image to run
+    image: nginx # pinned later
+    environment:
+      # debug settings
+      DEBUG: "true"
+      LEVEL: info
+    volumes:
+    - data:/data
+    - logs:/logs
+  db:
+    image: postgres`,
+			service: "web",
+			result: &protocol.TextEdit{
+				NewText: "    environment:\n      # debug settings\n      DEBUG: \"true\"\n      LEVEL: info\n    # the image to run\n    image: nginx # pinned later\n    volumes:\n    - data:/data\n    - logs:/logs",
+				Range: protocol.Range{
+					Start: protocol.Position{Line: 3, Character: 0},
+					End:   protocol.Position{Line: 11, Character: 16},
+				},
+			},
+		},
+		{
+			name: "blank lines between attributes are kept",
+			content: `
+services:
+  web:
+    restart: always
+
+    image: nginx
+
+    command: ["nginx"]
+
+networks:
+  backend:`,
+			service: "web",
+			result: &protocol.TextEdit{
+				NewText: "    command: [\"nginx\"]\n\n    image: nginx\n\n    restart: always",
+				Range: protocol.Range{
+					Start: protocol.Position{Line: 3, Character: 0},
+					End:   protocol.Position{Line: 7, Character: 22},
+				},
+			},
+		},
+		{
+			name: "merge keys stay first and unknown attributes go last",
+			content: `
+services:
+  web:
+    <<: *base
+    x-custom: value
+    restart: always
+    image: nginx`,
+			service: "web",
+			result: &protocol.TextEdit{
+				NewText: "    <<: *base\n    image: nginx\n    restart: always\n    x-custom: value",
+				Range: protocol.Range{
+					Start: protocol.Position{Line: 3, Character: 0},
+					End:   protocol.Position{Line: 6, Character: 16},
+				},
+			},
+		},
+		{
+			name: "already sorted",
+			content: `
+services:
+  web:
+    build: .
+    image: nginx`,
+			service: "web",
+			result:  nil,
+		},
+		{
+			name: "only the given service is sorted",
+			content: `
+services:
+  web:
+    image: nginx
+    build: .
+  db:
+    restart: always
+    image: postgres`,
+			service: "db",
+			result: &protocol.TextEdit{
+				NewText: "    image: postgres\n    restart: always",
+				Range: protocol.Range{
+					Start: protocol.Position{Line: 6, Character: 0},
+					End:   protocol.Position{Line: 7, Character: 19},
+				},
+			},
+		},
+		{
+			name: "unknown service",
+			content: `
+services:
+  web:
+    image: nginx
+    build: .`,
+			service: "db",
+			result:  nil,
+		},
+		{
+			name: "flow style service",
+			content: `
+services:
+  web: { image: nginx, build: . }`,
+			service: "web",
+			result:  nil,
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(tc.content))
+			edit := SortServiceKeys(doc, tc.service)
+			if tc.result == nil {
+				require.Nil(t, edit)
+				return
+			}
+			require.Equal(t, &protocol.WorkspaceEdit{
+				Changes: map[protocol.DocumentUri][]protocol.TextEdit{composeFileURI: {*tc.result}},
+			}, edit)
+
+			// sorting the sorted attributes again changes nothing
+			lines := strings.Split(tc.content, "\n")
+			start := int(tc.result.Range.Start.Line)
+			end := int(tc.result.Range.End.Line)
+			sorted := strings.Join(append(append(append([]string{}, lines[:start]...), tc.result.NewText), lines[end+1:]...), "\n")
+			doc = document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 2, []byte(sorted))
+			require.Nil(t, SortServiceKeys(doc, tc.service))
+		})
+	}
+}
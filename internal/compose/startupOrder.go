@@ -0,0 +1,122 @@
+package compose
+
+import (
+	"slices"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// StartupOrder is the order that the services of a Compose file are
+// started in. If the services depend on each other in a cycle then no
+// order can be determined and the cycle is given instead with the
+// first service repeated at its end.
+type StartupOrder struct {
+	Order []string `json:"order,omitempty"`
+	Cycle []string `json:"cycle,omitempty"`
+}
+
+// serviceDependencies returns the names of the services in the order
+// that they are declared in and the services that each of them depends
+// on. Dependencies on services that are not declared in the file are
+// ignored.
+func serviceDependencies(servicesNode *ast.MappingNode) ([]string, map[string][]string) {
+	names := []string{}
+	for _, serviceNode := range servicesNode.Values {
+		names = append(names, resolveAnchor(serviceNode.Key).GetToken().Value)
+	}
+
+	dependencies := map[string][]string{}
+	for _, serviceNode := range servicesNode.Values {
+		dependsOn := mappingValue(resolveAnchor(serviceNode.Value), "depends_on")
+		if dependsOn == nil {
+			continue
+		}
+		nodes := []ast.Node{}
+		switch n := resolveAnchor(dependsOn.Value).(type) {
+		case *ast.SequenceNode:
+			nodes = n.Values
+		case *ast.MappingNode:
+			for _, dependency := range n.Values {
+				nodes = append(nodes, dependency.Key)
+			}
+		}
+
+		name := resolveAnchor(serviceNode.Key).GetToken().Value
+		for _, node := range nodes {
+			dependency := resolveAnchor(node).GetToken().Value
+			if slices.Contains(names, dependency) && !slices.Contains(dependencies[name], dependency) {
+				dependencies[name] = append(dependencies[name], dependency)
+			}
+		}
+	}
+	return names, dependencies
+}
+
+// dependencyCycle returns a cycle among the given services by following
+// the first remaining dependency of each service until a service is
+// visited again. Every remaining service has a remaining dependency
+// when no more services can be started so a cycle is always found.
+func dependencyCycle(remaining []string, dependencies map[string][]string) []string {
+	path := []string{remaining[0]}
+	for {
+		current := path[len(path)-1]
+		for _, dependency := range dependencies[current] {
+			if !slices.Contains(remaining, dependency) {
+				continue
+			}
+			if idx := slices.Index(path, dependency); idx != -1 {
+				return append(path[idx:], dependency)
+			}
+			path = append(path, dependency)
+			break
+		}
+	}
+}
+
+// ComputeStartupOrder returns the order that the services of the given
+// Compose file are started in based on their depends_on attributes. A
+// service is started after all of its dependencies and services that
+// could be started at the same time are ordered as they are declared.
+// Nil is returned if the file does not declare any services.
+func ComputeStartupOrder(doc document.ComposeDocument) *StartupOrder {
+	file := doc.File()
+	if file == nil {
+		return nil
+	}
+
+	for _, documentNode := range file.Docs {
+		root, ok := documentNode.Body.(*ast.MappingNode)
+		if !ok {
+			continue
+		}
+		services := mappingValue(root, "services")
+		if services == nil {
+			continue
+		}
+		servicesNode, ok := resolveAnchor(services.Value).(*ast.MappingNode)
+		if !ok {
+			continue
+		}
+
+		remaining, dependencies := serviceDependencies(servicesNode)
+		order := []string{}
+		for len(remaining) > 0 {
+			next := slices.IndexFunc(remaining, func(name string) bool {
+				for _, dependency := range dependencies[name] {
+					if slices.Contains(remaining, dependency) {
+						return false
+					}
+				}
+				return true
+			})
+			if next == -1 {
+				return &StartupOrder{Cycle: dependencyCycle(remaining, dependencies)}
+			}
+			order = append(order, remaining[next])
+			remaining = slices.Delete(remaining, next, next+1)
+		}
+		return &StartupOrder{Order: order}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestComputeStartupOrder(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		order   *StartupOrder
+	}{
+		{
+			name: "linear chain",
+			content: `
+services:
+  web:
+    depends_on:
+      - api
+  api:
+    depends_on:
+      - db
+  db:
+    image: postgres`,
+			order: &StartupOrder{Order: []string{"db", "api", "web"}},
+		},
+		{
+			name: "diamond",
+			content: `
+services:
+  web:
+    depends_on:
+      - cache
+      - api
+  api:
+    depends_on:
+      db:
+        condition: service_healthy
+  cache:
+    depends_on:
+      db:
+        condition: service_started
+  db:
+    image: postgres`,
+			order: &StartupOrder{Order: []string{"db", "api", "cache", "web"}},
+		},
+		{
+			name: "independent services keep their declared order",
+			content: `
+services:
+  web:
+    image: nginx
+  db:
+    image: postgres`,
+			order: &StartupOrder{Order: []string{"web", "db"}},
+		},
+		{
+			name: "dependencies on undeclared services are ignored",
+			content: `
+services:
+  web:
+    depends_on:
+      - missing
+      - db
+  db:
+    image: postgres`,
+			order: &StartupOrder{Order: []string{"db", "web"}},
+		},
+		{
+			name: "cycle",
+			content: `
+services:
+  web:
+    depends_on:
+      - db
+  api:
+    depends_on:
+      - web
+  db:
+    depends_on:
+      - api`,
+			order: &StartupOrder{Cycle: []string{"web", "db", "api", "web"}},
+		},
+		{
+			name: "cycle reached through a service outside of it",
+			content: `
+services:
+  web:
+    depends_on:
+      - api
+  api:
+    depends_on:
+      - db
+  db:
+    depends_on:
+      - api`,
+			order: &StartupOrder{Cycle: []string{"api", "db", "api"}},
+		},
+		{
+			name: "service that depends on itself",
+			content: `
+services:
+  web:
+    depends_on:
+      - web`,
+			order: &StartupOrder{Cycle: []string{"web", "web"}},
+		},
+		{
+			name:    "no services",
+			content: "networks:\n  front:",
+			order:   nil,
+		},
+	}
+
+	composeFileURI := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/")))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), composeFileURI, 1, []byte(tc.content))
+			require.Equal(t, tc.order, ComputeStartupOrder(doc))
+		})
+	}
+}
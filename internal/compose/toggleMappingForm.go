@@ -0,0 +1,250 @@
+package compose
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// toggleableAttributes are the attributes that can be written either
+// as a list of KEY=value strings or as a mapping of keys to values.
+var toggleableAttributes = []string{"annotations", "environment", "labels", "sysctls"}
+
+// ToggleMappingForm returns an edit that rewrites the environment,
+// labels, annotations, or sysctls attribute at the given position from
+// its list form into its mapping form or the other way around. The
+// order of the entries and any comments between them are preserved.
+// If there is no such attribute at the position or if the attribute
+// cannot be rewritten safely then nil is returned.
+func ToggleMappingForm(doc document.ComposeDocument, position protocol.Position) *protocol.WorkspaceEdit {
+	file := doc.File()
+	if file == nil {
+		return nil
+	}
+
+	line := int(position.Line) + 1
+	for _, documentNode := range file.Docs {
+		attribute := toggleableAttribute(documentNode.Body, line)
+		if attribute == nil {
+			continue
+		}
+
+		lines := strings.Split(string(doc.Input()), "\n")
+		var edit *protocol.TextEdit
+		switch n := resolveAnchor(attribute.Value).(type) {
+		case *ast.SequenceNode:
+			edit = toMappingForm(lines, attribute, n)
+		case *ast.MappingNode:
+			edit = toListForm(lines, attribute, n)
+		}
+		if edit == nil {
+			return nil
+		}
+		return &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+				string(doc.URI()): {*edit},
+			},
+		}
+	}
+	return nil
+}
+
+// toggleableAttribute returns the toggleable attribute whose key or
+// value is on the given line.
+func toggleableAttribute(node ast.Node, line int) *ast.MappingValueNode {
+	switch n := resolveAnchor(node).(type) {
+	case *ast.MappingNode:
+		for _, child := range n.Values {
+			if child.Key.GetToken().Position.Line > line || lastLine(child) < line {
+				continue
+			}
+			if slices.Contains(toggleableAttributes, resolveAnchor(child.Key).GetToken().Value) {
+				switch resolveAnchor(child.Value).(type) {
+				case *ast.SequenceNode, *ast.MappingNode:
+					return child
+				}
+			}
+			return toggleableAttribute(child.Value, line)
+		}
+	case *ast.SequenceNode:
+		for _, item := range n.Values {
+			if attribute := toggleableAttribute(item, line); attribute != nil {
+				return attribute
+			}
+		}
+	}
+	return nil
+}
+
+// scalarEnd returns the index in the line that the scalar starting at
+// the given index ends at. A plain scalar that is a mapping's key ends
+// at the colon that follows it while other plain scalars end at a
+// trailing comment or at the end of the line.
+func scalarEnd(line string, start int, key bool) int {
+	switch line[start] {
+	case '"':
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+			} else if line[i] == '"' {
+				return i + 1
+			}
+		}
+		return len(line)
+	case '\'':
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+				} else {
+					return i + 1
+				}
+			}
+		}
+		return len(line)
+	}
+
+	end := len(line)
+	for i := start; i < len(line); i++ {
+		if key && line[i] == ':' && (i+1 == len(line) || line[i+1] == ' ') {
+			end = i
+			break
+		}
+		if line[i] == '#' && (line[i-1] == ' ' || line[i-1] == '\t') {
+			end = i
+			break
+		}
+	}
+	return start + len(strings.TrimRight(line[start:end], " \t"))
+}
+
+// yamlScalar returns the given value as a YAML scalar that is quoted
+// if it would otherwise not be read back as the same string.
+func yamlScalar(value string) string {
+	if strings.Contains(value, "\n") {
+		return strconv.Quote(value)
+	}
+	b, err := yaml.Marshal(value)
+	if err != nil {
+		return strconv.Quote(value)
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// replaceLines creates an edit that replaces the given lines of the
+// document with the rewritten lines.
+func replaceLines(lines []string, start, end int, rewritten []string) *protocol.TextEdit {
+	return &protocol.TextEdit{
+		NewText: strings.Join(rewritten, "\n"),
+		Range: protocol.Range{
+			Start: protocol.Position{Line: protocol.UInteger(start)},
+			End:   protocol.Position{Line: protocol.UInteger(end), Character: protocol.UInteger(len(lines[end]))},
+		},
+	}
+}
+
+// toMappingForm rewrites the KEY=value strings of a list into KEY:
+// value entries of a mapping. An entry without an equals sign becomes
+// a key without a value.
+func toMappingForm(lines []string, attribute *ast.MappingValueNode, sequenceNode *ast.SequenceNode) *protocol.TextEdit {
+	if sequenceNode.IsFlowStyle || len(sequenceNode.Values) == 0 {
+		return nil
+	}
+
+	start := sequenceNode.Values[0].GetToken().Position.Line - 1
+	end := lastLine(sequenceNode) - 1
+	rewritten := slices.Clone(lines[start : end+1])
+	indentation := sequenceNode.Start.Position.Column - 1
+	// a list may be at the same indentation as its attribute's key but
+	// the entries of a mapping must be indented further
+	if keyIndentation := attribute.Key.GetToken().Position.Column - 1; indentation <= keyIndentation {
+		indentation = keyIndentation + 2
+	}
+
+	for _, item := range sequenceNode.Values {
+		stringNode, ok := resolveAnchor(item).(*ast.StringNode)
+		if !ok || item != ast.Node(stringNode) {
+			return nil
+		}
+		t := stringNode.GetToken()
+		if lastLine(item) != t.Position.Line {
+			return nil
+		}
+		line := lines[t.Position.Line-1]
+		if !strings.HasPrefix(strings.TrimLeft(line, " \t"), "-") {
+			return nil
+		}
+
+		entry := yamlScalar(stringNode.Value) + ":"
+		if key, value, found := strings.Cut(stringNode.Value, "="); found {
+			entry = yamlScalar(key) + ": " + yamlScalar(value)
+		}
+		itemEnd := scalarEnd(line, t.Position.Column-1, false)
+		rewritten[t.Position.Line-1-start] = strings.Repeat(" ", indentation) + entry + line[itemEnd:]
+	}
+	return replaceLines(lines, start, end, rewritten)
+}
+
+// toListForm rewrites the KEY: value entries of a mapping into KEY=value
+// strings of a list. A key without a value becomes a bare KEY entry for
+// environment variables as their values are then taken from the shell
+// and a KEY= entry for everything else.
+func toListForm(lines []string, attribute *ast.MappingValueNode, mappingNode *ast.MappingNode) *protocol.TextEdit {
+	if mappingNode.IsFlowStyle || len(mappingNode.Values) == 0 {
+		return nil
+	}
+
+	start := mappingNode.Values[0].Key.GetToken().Position.Line - 1
+	end := lastLine(mappingNode) - 1
+	rewritten := slices.Clone(lines[start : end+1])
+	environment := resolveAnchor(attribute.Key).GetToken().Value == "environment"
+
+	for _, child := range mappingNode.Values {
+		keyNode, ok := child.Key.(*ast.StringNode)
+		if !ok {
+			return nil
+		}
+		t := keyNode.GetToken()
+		line := lines[t.Position.Line-1]
+		if lastLine(child) != t.Position.Line {
+			return nil
+		}
+
+		keyStart := t.Position.Column - 1
+		keyEnd := scalarEnd(line, keyStart, true)
+		colon := strings.Index(line[keyEnd:], ":")
+		if colon == -1 {
+			return nil
+		}
+		itemEnd := keyEnd + colon + 1
+
+		var entry string
+		switch value := child.Value.(type) {
+		case *ast.NullNode:
+			entry = keyNode.Value + "="
+			if environment {
+				entry = keyNode.Value
+			}
+			// an explicit null or ~ is written after the colon
+			valueStart := value.GetToken().Position.Column - 1
+			if valueStart >= itemEnd && valueStart < len(line) && strings.HasPrefix(line[valueStart:], value.GetToken().Value) {
+				itemEnd = scalarEnd(line, valueStart, false)
+			}
+		case *ast.StringNode:
+			entry = keyNode.Value + "=" + value.Value
+			itemEnd = scalarEnd(line, value.GetToken().Position.Column-1, false)
+		case *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode:
+			entry = keyNode.Value + "=" + value.GetToken().Value
+			itemEnd = scalarEnd(line, value.GetToken().Position.Column-1, false)
+		default:
+			return nil
+		}
+		rewritten[t.Position.Line-1-start] = line[:keyStart] + "- " + yamlScalar(entry) + line[itemEnd:]
+	}
+	return replaceLines(lines, start, end, rewritten)
+}
@@ -0,0 +1,178 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestToggleMappingForm(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      protocol.UInteger
+		result    string
+		roundTrip bool
+	}{
+		{
+			name: "environment list to mapping",
+			content: `
+services:
+  web:
+    environment:
+      - KEY=value
+      # comment
+      - EQUALS=a=b # trailing
+      - PASSTHROUGH
+      - PORT=8080
+      - "MESSAGE=hello: world"`,
+			line: 3,
+			result: `
+services:
+  web:
+    environment:
+      KEY: value
+      # comment
+      EQUALS: a=b # trailing
+      PASSTHROUGH:
+      PORT: "8080"
+      MESSAGE: "hello: world"`,
+			roundTrip: true,
+		},
+		{
+			name: "labels mapping to list",
+			content: `
+services:
+  web:
+    labels:
+      com.example.name: web
+      com.example.empty:
+      com.example.port: 8080
+      com.example.quoted: "a=b" # trailing`,
+			line: 5,
+			result: `
+services:
+  web:
+    labels:
+      - com.example.name=web
+      - com.example.empty=
+      - com.example.port=8080
+      - com.example.quoted=a=b # trailing`,
+			roundTrip: false,
+		},
+		{
+			name: "environment mapping without a value becomes a bare key",
+			content: `
+services:
+  web:
+    environment:
+      PASSTHROUGH:
+      EXPLICIT: null # comment
+      KEY: value`,
+			line: 4,
+			result: `
+services:
+  web:
+    environment:
+      - PASSTHROUGH
+      - EXPLICIT # comment
+      - KEY=value`,
+			roundTrip: false,
+		},
+		{
+			name: "list at the same indentation as its key",
+			content: `
+services:
+  web:
+    sysctls:
+    - net.core.somaxconn=1024`,
+			line: 4,
+			result: `
+services:
+  web:
+    sysctls:
+      net.core.somaxconn: "1024"`,
+			roundTrip: false,
+		},
+		{
+			name: "build labels",
+			content: `
+services:
+  web:
+    build:
+      context: .
+      labels:
+        - a=b`,
+			line: 6,
+			result: `
+services:
+  web:
+    build:
+      context: .
+      labels:
+        a: b`,
+			roundTrip: true,
+		},
+		{
+			name: "flow style is not rewritten",
+			content: `
+services:
+  web:
+    environment: [KEY=value]`,
+			line: 3,
+		},
+		{
+			name: "position outside of a toggleable attribute",
+			content: `
+services:
+  web:
+    image: alpine
+    environment:
+      - KEY=value`,
+			line: 3,
+		},
+		{
+			name: "nested values are not rewritten",
+			content: `
+services:
+  web:
+    annotations:
+      key:
+        nested: value`,
+			line: 3,
+		},
+	}
+
+	composeFileURI := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), "compose.yaml")), "/"))
+	apply := func(content string, edit *protocol.WorkspaceEdit) string {
+		textEdit := edit.Changes[composeFileURI][0]
+		return string(document.ApplyContentChange([]byte(content), textEdit.Range, textEdit.NewText))
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 1, []byte(tc.content))
+			edit := ToggleMappingForm(doc, protocol.Position{Line: tc.line})
+			if tc.result == "" {
+				require.Nil(t, edit)
+				return
+			}
+			require.NotNil(t, edit)
+			result := apply(tc.content, edit)
+			require.Equal(t, tc.result, result)
+
+			if tc.roundTrip {
+				doc = document.NewComposeDocument(document.NewDocumentManager(), uri.URI(composeFileURI), 2, []byte(result))
+				edit = ToggleMappingForm(doc, protocol.Position{Line: tc.line})
+				require.NotNil(t, edit)
+				require.Equal(t, tc.content, apply(result, edit))
+			}
+		})
+	}
+}
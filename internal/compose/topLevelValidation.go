@@ -0,0 +1,127 @@
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// maximumSuggestionDistance is the largest number of edits that a
+// misspelled name may be away from the name that is suggested for it.
+const maximumSuggestionDistance = 2
+
+// editDistance returns the Levenshtein distance between the two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// closestName returns the name that the given name is most likely a
+// misspelling of. If only one of the names starts with the given name
+// then the given name is taken to be an abbreviation of it. Otherwise
+// the name that is the fewest edits away is returned if it is close
+// enough with names that are equally close decided alphabetically.
+func closestName(name string, names []string) (string, bool) {
+	sorted := slices.Clone(names)
+	slices.Sort(sorted)
+	prefixed := slices.DeleteFunc(slices.Clone(sorted), func(candidate string) bool {
+		return !strings.HasPrefix(candidate, name)
+	})
+	if len(name) > 1 && len(prefixed) == 1 {
+		return prefixed[0], true
+	}
+
+	closest := ""
+	closestDistance := maximumSuggestionDistance + 1
+	for _, candidate := range sorted {
+		if distance := editDistance(name, candidate); distance < closestDistance {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+	return closest, closest != ""
+}
+
+// schemaTopLevelAttributes returns the names of the top-level
+// attributes that are defined by the schema.
+func schemaTopLevelAttributes() []string {
+	if composeSchema == nil {
+		return nil
+	}
+	names := []string{}
+	for name := range schemaProperties() {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// unknownAttributeDiagnostic creates a diagnostic for the given key of
+// an attribute that is not one of the known attributes. If a known
+// attribute is close to it then it is suggested in the message and a
+// quick fix to rename the key is offered.
+func unknownAttributeDiagnostic(source, code, description string, key *ast.StringNode, known []string) protocol.Diagnostic {
+	t := key.GetToken()
+	suggestion, found := closestName(key.Value, known)
+	message := fmt.Sprintf("unknown %v, extension attributes must start with x-", description)
+	if found {
+		message = fmt.Sprintf("unknown %v, did you mean %v?", description, suggestion)
+	}
+	diagnostic := createValidationDiagnostic(
+		source,
+		protocol.DiagnosticSeverityWarning,
+		code,
+		message,
+		createRange(t, len(t.Value)),
+	)
+	if found {
+		diagnostic.Data = []types.NamedEdit{
+			{
+				Title: fmt.Sprintf("Change attribute name to %v", suggestion),
+				Edit:  suggestion,
+			},
+		}
+	}
+	return diagnostic
+}
+
+// validateTopLevelAttributes reports the top-level attributes that are
+// not defined by the schema and that are not extensions that start
+// with x- as they are most likely misspelled. If a known attribute is
+// close to the unknown one then a quick fix to rename it is offered.
+func validateTopLevelAttributes(source string, root *ast.MappingNode) []protocol.Diagnostic {
+	known := schemaTopLevelAttributes()
+	if len(known) == 0 {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, node := range root.Values {
+		key, ok := node.Key.(*ast.StringNode)
+		if !ok || strings.HasPrefix(key.Value, "x-") || slices.Contains(known, key.Value) {
+			continue
+		}
+		description := fmt.Sprintf("top-level attribute %v", key.Value)
+		diagnostics = append(diagnostics, unknownAttributeDiagnostic(source, "UnknownTopLevelAttribute", description, key, known))
+	}
+	return diagnostics
+}
@@ -0,0 +1,1193 @@
+package compose
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/containerd/platforms"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// propertyValidator checks the nodes found at the given path of a
+// Compose file. Each element of the path is matched against the keys
+// of a mapping node with * matching any key. An element of [] matches
+// every item of a sequence node. The root of the document is provided
+// for validators that need to look up other parts of the file.
+type propertyValidator struct {
+	path     []string
+	validate func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic
+}
+
+var propertyValidators = []propertyValidator{
+	{
+		path:     []string{"services", "*"},
+		validate: resourceNameValidator("service"),
+	},
+	{
+		path:     []string{"networks", "*"},
+		validate: resourceNameValidator("network"),
+	},
+	{
+		path:     []string{"volumes", "*"},
+		validate: resourceNameValidator("volume"),
+	},
+	{
+		path:     []string{"configs", "*"},
+		validate: resourceNameValidator("config"),
+	},
+	{
+		path:     []string{"secrets", "*"},
+		validate: resourceNameValidator("secret"),
+	},
+	{
+		path:     []string{"models", "*"},
+		validate: resourceNameValidator("model"),
+	},
+	{
+		path:     []string{"configs", "*"},
+		validate: resourceSourceValidator("config", []string{"file", "environment", "content", "external"}),
+	},
+	{
+		path:     []string{"secrets", "*"},
+		validate: resourceSourceValidator("secret", []string{"file", "environment", "external"}),
+	},
+	{
+		path:     []string{"services", "*"},
+		validate: validateRedundantExpose,
+	},
+	{
+		path:     []string{"services", "*"},
+		validate: validatePrivilegedCapabilities,
+	},
+	{
+		path:     []string{"services", "*", "depends_on"},
+		validate: validateHealthyDependencies,
+	},
+	{
+		path:     []string{"services", "*", "healthcheck"},
+		validate: validateDisabledHealthcheck,
+	},
+	{
+		path:     []string{"services", "*", "restart"},
+		validate: validateRestart,
+	},
+	{
+		path:     []string{"services", "*", "scale"},
+		validate: integerRangeValidator("scale", 0, math.MaxInt64),
+	},
+	{
+		path:     []string{"services", "*", "scale"},
+		validate: validateDeprecatedScale,
+	},
+	{
+		path:     []string{"services", "*", "credential_spec"},
+		validate: validateCredentialSpec,
+	},
+	{
+		path:     []string{"services", "*", "pids_limit"},
+		validate: integerRangeValidator("pids_limit", -1, math.MaxInt64),
+	},
+	{
+		path:     []string{"services", "*", "oom_score_adj"},
+		validate: integerRangeValidator("oom_score_adj", -1000, 1000),
+	},
+	{
+		path:     []string{"services", "*", "deploy", "replicas"},
+		validate: integerRangeValidator("replicas", 0, math.MaxInt64),
+	},
+	{
+		path:     []string{"services", "*", "deploy"},
+		validate: validateGlobalReplicas,
+	},
+	{
+		path:     []string{"services", "*"},
+		validate: validateBuildPlatforms,
+	},
+	{
+		path:     []string{"services", "*"},
+		validate: validateBuildString,
+	},
+	{
+		path:     []string{"services", "*"},
+		validate: validateServiceAttributes,
+	},
+	{
+		path:     []string{"services", "*", "deploy", "restart_policy", "max_attempts"},
+		validate: integerRangeValidator("max_attempts", 0, math.MaxInt64),
+	},
+	{
+		path:     []string{"services", "*", "volumes", "[]"},
+		validate: validateMountSource,
+	},
+	{
+		path:     []string{"services", "*", "volumes", "[]"},
+		validate: validateMountOptions,
+	},
+	{
+		path:     []string{"services", "*", "volumes", "[]"},
+		validate: validateVolumeMode,
+	},
+	{
+		path:     []string{"services", "*", "volumes", "[]", "read_only"},
+		validate: enumValidator("read_only", []string{"true", "false"}),
+	},
+	{
+		path:     []string{"services", "*", "volumes", "[]", "bind", "propagation"},
+		validate: enumValidator("propagation", []string{"shared", "slave", "private", "rshared", "rslave", "rprivate"}),
+	},
+	{
+		path:     []string{"services", "*", "volumes", "[]", "tmpfs", "mode"},
+		validate: validateFileMode,
+	},
+	{
+		path:     []string{"services", "*", "configs", "[]", "mode"},
+		validate: validateFileMode,
+	},
+	{
+		path:     []string{"services", "*", "configs", "[]", "uid"},
+		validate: integerRangeValidator("uid", 0, math.MaxInt64),
+	},
+	{
+		path:     []string{"services", "*", "configs", "[]", "gid"},
+		validate: integerRangeValidator("gid", 0, math.MaxInt64),
+	},
+	{
+		path:     []string{"services", "*", "secrets", "[]", "mode"},
+		validate: validateFileMode,
+	},
+	{
+		path:     []string{"services", "*", "secrets", "[]", "uid"},
+		validate: integerRangeValidator("uid", 0, math.MaxInt64),
+	},
+	{
+		path:     []string{"services", "*", "secrets", "[]", "gid"},
+		validate: integerRangeValidator("gid", 0, math.MaxInt64),
+	},
+	{
+		path:     []string{"services", "*", "image"},
+		validate: validateImageReference,
+	},
+	{
+		path:     []string{"services", "*", "build", "tags", "[]"},
+		validate: validateImageReference,
+	},
+	{
+		path:     []string{"services", "*", "profiles", "[]"},
+		validate: validateProfile,
+	},
+	{
+		path:     []string{"services", "*", "container_name"},
+		validate: validateContainerName,
+	},
+	{
+		path:     []string{"services", "*", "device_cgroup_rules", "[]"},
+		validate: validateDeviceCgroupRule,
+	},
+	{
+		path:     []string{"services", "*", "env_file"},
+		validate: validateEnvFiles,
+	},
+	{
+		path:     []string{"services", "*", "environment", "[]"},
+		validate: validateEnvironmentEntry,
+	},
+	{
+		path:     []string{"services", "*", "links", "[]"},
+		validate: validateLink,
+	},
+	{
+		path:     []string{"services", "*", "build", "additional_contexts", "*"},
+		validate: validateAdditionalContext,
+	},
+	{
+		path:     []string{"services", "*", "build", "additional_contexts", "[]"},
+		validate: validateAdditionalContext,
+	},
+	{
+		path:     []string{"services", "*", "ipc"},
+		validate: validateSharedNamespace,
+	},
+	{
+		path:     []string{"services", "*", "network_mode"},
+		validate: validateSharedNamespace,
+	},
+	{
+		path:     []string{"services", "*", "pid"},
+		validate: validateSharedNamespace,
+	},
+	{
+		path:     []string{"services", "*", "extra_hosts", "[]"},
+		validate: validateExtraHost,
+	},
+	{
+		path:     []string{"services", "*", "extra_hosts", "*"},
+		validate: validateExtraHost,
+	},
+	{
+		path:     []string{"services", "*", "ports", "[]"},
+		validate: validatePortHostIP,
+	},
+	{
+		path:     []string{"services", "*", "ports", "[]", "host_ip"},
+		validate: validateAddress,
+	},
+	{
+		path:     []string{"networks", "*", "ipam", "config", "[]", "subnet"},
+		validate: validateSubnet,
+	},
+	{
+		path:     []string{"networks", "*", "ipam", "config", "[]", "ip_range"},
+		validate: validateSubnet,
+	},
+	{
+		path:     []string{"networks", "*", "ipam", "config", "[]", "gateway"},
+		validate: validateAddress,
+	},
+	{
+		path:     []string{"networks", "*", "ipam", "config", "[]", "aux_addresses", "*"},
+		validate: validateAddress,
+	},
+	{
+		path:     []string{"services", "*", "build", "cache_from", "[]"},
+		validate: cacheSpecValidator(false),
+	},
+	{
+		path:     []string{"services", "*", "build", "cache_to", "[]"},
+		validate: cacheSpecValidator(true),
+	},
+}
+
+// resourceNameRegexp is the pattern that the names of services and
+// top-level resources must match.
+var resourceNameRegexp = regexp.MustCompile("^[a-zA-Z0-9._-]+$")
+
+// resourceNameValidator returns a validator that checks that the name
+// of a service or top-level resource only contains characters that
+// Compose accepts.
+func resourceNameValidator(resourceType string) func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	return func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+		if _, ok := key.(*ast.MergeKeyNode); ok {
+			return nil
+		}
+
+		return invalidNameDiagnostics(source, resourceType, key.GetToken())
+	}
+}
+
+// invalidNameDiagnostics returns an error if the name in the given
+// token does not match the pattern of resource names.
+func invalidNameDiagnostics(source, resourceType string, t *token.Token) []protocol.Diagnostic {
+	if resourceNameRegexp.MatchString(t.Value) {
+		return nil
+	}
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"InvalidResourceName",
+			fmt.Sprintf("%v name %q must only contain letters, digits, periods, underscores, and hyphens", resourceType, t.Value),
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// validateProfile checks that a profile of a service has a valid name
+// and warns if a service has the same name as the profile as it is
+// then unclear what a --profile flag refers to. Interpolated profiles
+// are ignored.
+func validateProfile(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	s, ok := resolveAnchor(value).(*ast.StringNode)
+	if !ok {
+		return nil
+	}
+	if _, ok := literalValue(s.Value); !ok {
+		return nil
+	}
+
+	t := s.GetToken()
+	if diagnostics := invalidNameDiagnostics(source, "profile", t); diagnostics != nil {
+		return diagnostics
+	}
+	if !slices.Contains(declaredNames(root, "services"), t.Value) {
+		return nil
+	}
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityWarning,
+			"ProfileNameConflict",
+			fmt.Sprintf("profile %v has the same name as a service", t.Value),
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// resourceSourceValidator returns a validator that checks that a
+// top-level config or secret declares where its value comes from with
+// one of the given attributes. Resources with merge keys are ignored
+// as the attribute may have been merged in.
+func resourceSourceValidator(resourceType string, sources []string) func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	return func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+		if _, ok := key.(*ast.MergeKeyNode); ok {
+			return nil
+		}
+		switch n := resolveAnchor(value).(type) {
+		case *ast.NullNode:
+		case *ast.MappingNode:
+			for _, attribute := range append([]string{"<<"}, sources...) {
+				if mappingValue(n, attribute) != nil {
+					return nil
+				}
+			}
+		default:
+			return nil
+		}
+
+		t := key.GetToken()
+		return []protocol.Diagnostic{
+			createValidationDiagnostic(
+				source,
+				protocol.DiagnosticSeverityError,
+				"MissingResourceSource",
+				fmt.Sprintf("%v %v must set one of %v", resourceType, t.Value, strings.Join(sources, ", ")),
+				createRange(t, len(t.Value)),
+			),
+		}
+	}
+}
+
+// matchPropertyPath walks down the given node and calls fn with every
+// key and value pair that matches the path. The key will be nil if
+// the value is an item of a sequence.
+func matchPropertyPath(path []string, key, value ast.Node, fn func(key, value ast.Node)) {
+	if len(path) == 0 {
+		fn(key, value)
+		return
+	}
+
+	value = resolveAnchor(value)
+	if path[0] == "[]" {
+		if sequenceNode, ok := value.(*ast.SequenceNode); ok {
+			for _, item := range sequenceNode.Values {
+				matchPropertyPath(path[1:], nil, item, fn)
+			}
+		}
+		return
+	}
+
+	if mappingNode, ok := value.(*ast.MappingNode); ok {
+		for _, child := range mappingNode.Values {
+			childKey := resolveAnchor(child.Key)
+			if path[0] == "*" || childKey.GetToken().Value == path[0] {
+				matchPropertyPath(path[1:], childKey, child.Value, fn)
+			}
+		}
+	}
+}
+
+// mappingValue returns the entry of the mapping node with the given
+// key or nil if the key cannot be found.
+func mappingValue(node ast.Node, key string) *ast.MappingValueNode {
+	if mappingNode, ok := resolveAnchor(node).(*ast.MappingNode); ok {
+		for _, child := range mappingNode.Values {
+			if resolveAnchor(child.Key).GetToken().Value == key {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// declaredNames returns the names of the objects that are declared
+// under the given top-level attribute such as services or volumes.
+func declaredNames(root *ast.MappingNode, attributeName string) []string {
+	names := []string{}
+	if declarations := mappingValue(root, attributeName); declarations != nil {
+		if mappingNode, ok := resolveAnchor(declarations.Value).(*ast.MappingNode); ok {
+			for _, declaration := range mappingNode.Values {
+				names = append(names, resolveAnchor(declaration.Key).GetToken().Value)
+			}
+		}
+	}
+	return names
+}
+
+func createValidationDiagnostic(source string, severity protocol.DiagnosticSeverity, code, message string, rng protocol.Range) protocol.Diagnostic {
+	return protocol.Diagnostic{
+		Message:  message,
+		Code:     &protocol.IntegerOrString{Value: code},
+		Source:   types.CreateStringPointer(source),
+		Severity: types.CreateDiagnosticSeverityPointer(severity),
+		Range:    rng,
+	}
+}
+
+// keyValuePair is an entry of a comma-separated list of key=value
+// pairs such as type=registry,ref=user/app:cache. The offsets are
+// relative to the start of the list.
+type keyValuePair struct {
+	key   string
+	value string
+	start int
+	end   int
+}
+
+// parseKeyValuePairs splits a comma-separated list of key=value pairs
+// as used by BuildKit for its cache and output specifications. An
+// entry without an = will have an empty value.
+func parseKeyValuePairs(list string) []keyValuePair {
+	pairs := []keyValuePair{}
+	offset := 0
+	for _, field := range strings.Split(list, ",") {
+		key, value, _ := strings.Cut(field, "=")
+		pairs = append(pairs, keyValuePair{key: strings.TrimSpace(key), value: value, start: offset, end: offset + len(field)})
+		offset += len(field) + 1
+	}
+	return pairs
+}
+
+// integerRangeValidator creates a validator that checks that a node's
+// value is an integer between min and max inclusive.
+func integerRangeValidator(attributeName string, min, max int64) func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	return func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+		value = resolveAnchor(value)
+		var number int64
+		switch n := value.(type) {
+		case *ast.IntegerNode:
+			parsed, err := strconv.ParseInt(n.GetToken().Value, 0, 64)
+			if err != nil {
+				return []protocol.Diagnostic{integerRangeDiagnostic(source, attributeName, min, max, value)}
+			}
+			number = parsed
+		case *ast.StringNode:
+			literal, ok := literalValue(n.Value)
+			if !ok {
+				return nil
+			}
+			parsed, err := strconv.ParseInt(strings.TrimSpace(literal), 10, 64)
+			if err != nil {
+				return []protocol.Diagnostic{integerRangeDiagnostic(source, attributeName, min, max, value)}
+			}
+			number = parsed
+		case *ast.FloatNode:
+			return []protocol.Diagnostic{integerRangeDiagnostic(source, attributeName, min, max, value)}
+		default:
+			return nil
+		}
+
+		if number < min || number > max {
+			return []protocol.Diagnostic{integerRangeDiagnostic(source, attributeName, min, max, value)}
+		}
+		return nil
+	}
+}
+
+func integerRangeDiagnostic(source, attributeName string, min, max int64, value ast.Node) protocol.Diagnostic {
+	message := fmt.Sprintf("%v must be an integer between %v and %v", attributeName, min, max)
+	if max == math.MaxInt64 {
+		if min == 0 {
+			message = fmt.Sprintf("%v must be a non-negative integer", attributeName)
+		} else {
+			message = fmt.Sprintf("%v must be an integer greater than or equal to %v", attributeName, min)
+		}
+	}
+	t := value.GetToken()
+	return createValidationDiagnostic(source, protocol.DiagnosticSeverityError, "InvalidIntegerValue", message, createRange(t, len(t.Value)))
+}
+
+// maxFileMode is the largest permission mode that can be set on a file
+// that is mounted into a container including the setuid, setgid, and
+// sticky bits.
+const maxFileMode = 07777
+
+// validateFileMode checks that the mode of a mounted file is an octal
+// permission mode. Integers are parsed as YAML would so a leading zero
+// makes them octal whereas strings are always treated as octal.
+func validateFileMode(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	value = resolveAnchor(value)
+	var mode int64
+	var err error
+	switch n := value.(type) {
+	case *ast.IntegerNode:
+		mode, err = strconv.ParseInt(n.GetToken().Value, 0, 64)
+	case *ast.StringNode:
+		literal, ok := literalValue(n.Value)
+		if !ok {
+			return nil
+		}
+		mode, err = strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(literal), "0o"), 8, 64)
+	case *ast.FloatNode:
+		err = strconv.ErrSyntax
+	default:
+		return nil
+	}
+
+	if err != nil || mode < 0 || mode > maxFileMode {
+		t := value.GetToken()
+		return []protocol.Diagnostic{
+			createValidationDiagnostic(
+				source,
+				protocol.DiagnosticSeverityWarning,
+				"InvalidFileMode",
+				fmt.Sprintf("mode must be an octal permission mode between 0000 and %04o (found %v)", maxFileMode, t.Value),
+				createRange(t, len(t.Value)),
+			),
+		}
+	}
+	return nil
+}
+
+// enumValidator creates a validator that checks that a string node's
+// value is one of the given values. Interpolated values are ignored.
+func enumValidator(attributeName string, values []string) func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	return func(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+		s, ok := resolveAnchor(value).(*ast.StringNode)
+		if !ok {
+			return nil
+		}
+		literal, ok := literalValue(s.Value)
+		if !ok || slices.Contains(values, literal) {
+			return nil
+		}
+
+		t := s.GetToken()
+		return []protocol.Diagnostic{
+			createValidationDiagnostic(
+				source,
+				protocol.DiagnosticSeverityWarning,
+				"InvalidEnumValue",
+				fmt.Sprintf("invalid %v value %v (expected one of: %v)", attributeName, literal, strings.Join(values, ", ")),
+				createRange(t, len(t.Value)),
+			),
+		}
+	}
+}
+
+// validateContainerName checks that no other service in the file uses
+// the same container name as containers must have unique names.
+// Interpolated names cannot be compared and are ignored.
+func validateContainerName(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	s, ok := resolveAnchor(value).(*ast.StringNode)
+	if !ok {
+		return nil
+	}
+	name, ok := literalValue(s.Value)
+	if !ok || name == "" {
+		return nil
+	}
+	services := mappingValue(root, "services")
+	if services == nil {
+		return nil
+	}
+	servicesNode, ok := resolveAnchor(services.Value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+
+	others := []string{}
+	for _, serviceNode := range servicesNode.Values {
+		containerName := mappingValue(serviceNode.Value, "container_name")
+		if containerName == nil || containerName.Value == value {
+			continue
+		}
+		if other, ok := resolveAnchor(containerName.Value).(*ast.StringNode); ok {
+			if otherName, ok := literalValue(other.Value); ok && otherName == name {
+				others = append(others, resolveAnchor(serviceNode.Key).GetToken().Value)
+			}
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+
+	t := s.GetToken()
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityWarning,
+			"DuplicateContainerName",
+			fmt.Sprintf("container name %v is also used by %v", name, strings.Join(others, ", ")),
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// deviceNumbersRegexp is the pattern that the MAJOR:MINOR part of a
+// device cgroup rule must match.
+var deviceNumbersRegexp = regexp.MustCompile(`^([0-9]+|\*):([0-9]+|\*)$`)
+
+// devicePermissionsRegexp is the pattern that the permissions of a
+// device cgroup rule must match.
+var devicePermissionsRegexp = regexp.MustCompile(`^[rwm]{1,3}$`)
+
+// validateDeviceCgroupRule checks that an entry of a service's
+// device_cgroup_rules attribute is in the TYPE MAJOR:MINOR PERMISSIONS
+// form that the container runtime expects.
+func validateDeviceCgroupRule(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	s, ok := resolveAnchor(value).(*ast.StringNode)
+	if !ok {
+		return nil
+	}
+	rule, ok := literalValue(s.Value)
+	if !ok {
+		return nil
+	}
+
+	message := ""
+	fields := strings.Fields(rule)
+	if len(fields) != 3 {
+		message = fmt.Sprintf("device cgroup rule %v must be in the form TYPE MAJOR:MINOR PERMISSIONS", rule)
+	} else if fields[0] != "a" && fields[0] != "b" && fields[0] != "c" {
+		message = fmt.Sprintf("invalid device type %v in device cgroup rule (expected one of: a, b, c)", fields[0])
+	} else if !deviceNumbersRegexp.MatchString(fields[1]) {
+		message = fmt.Sprintf("invalid device numbers %v in device cgroup rule (expected MAJOR:MINOR with numbers or *)", fields[1])
+	} else if !devicePermissionsRegexp.MatchString(fields[2]) {
+		message = fmt.Sprintf("invalid device permissions %v in device cgroup rule (expected a combination of r, w, m)", fields[2])
+	}
+	if message == "" {
+		return nil
+	}
+
+	t := s.GetToken()
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"InvalidDeviceCgroupRule",
+			message,
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// validateEnvironmentEntry checks an entry of the list form of a
+// service's environment attribute. An entry is either a KEY=value pair
+// or a bare KEY whose value is taken from the shell. Entries that are
+// empty, that have no variable name, or whose variable name contains
+// whitespace are most likely typos and are reported.
+func validateEnvironmentEntry(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	s, ok := resolveAnchor(value).(*ast.StringNode)
+	if !ok {
+		return nil
+	}
+
+	message := ""
+	name, _, hasValue := strings.Cut(s.Value, "=")
+	if strings.TrimSpace(s.Value) == "" {
+		message = "environment entry must not be empty"
+	} else if strings.TrimSpace(name) == "" {
+		message = fmt.Sprintf("environment entry %v is missing a variable name", s.Value)
+	} else if strings.ContainsFunc(name, unicode.IsSpace) {
+		if hasValue {
+			message = fmt.Sprintf("environment variable name %v must not contain whitespace", name)
+		} else {
+			message = fmt.Sprintf("environment entry %v contains whitespace but no equals sign (expected KEY=value or KEY)", s.Value)
+		}
+	}
+	if message == "" {
+		return nil
+	}
+
+	t := s.GetToken()
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityWarning,
+			"MalformedEnvironmentEntry",
+			message,
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// envFileEntry returns the path of an entry of a service's env_file
+// attribute and whether the file is required. An entry is either a
+// path or an object with a path and a required flag that defaults to
+// true. False is returned if the entry uses interpolation.
+func envFileEntry(node ast.Node) (path string, required bool, ok bool) {
+	switch n := resolveAnchor(node).(type) {
+	case *ast.StringNode:
+		path, ok = literalValue(n.Value)
+		return filepath.Clean(path), true, ok && path != ""
+	case *ast.MappingNode:
+		pathNode := mappingValue(n, "path")
+		if pathNode == nil {
+			return "", false, false
+		}
+		path, ok = literalValue(resolveAnchor(pathNode.Value).GetToken().Value)
+		if !ok || path == "" {
+			return "", false, false
+		}
+		required = true
+		if requiredNode := mappingValue(n, "required"); requiredNode != nil {
+			required, ok = boolValue(resolveAnchor(requiredNode.Value))
+			if !ok {
+				return "", false, false
+			}
+		}
+		return filepath.Clean(path), required, true
+	}
+	return "", false, false
+}
+
+// validateEnvFiles reports the entries of a service's env_file
+// attribute that declare a file that an earlier entry has already
+// declared with a different required flag.
+func validateEnvFiles(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	sequenceNode, ok := resolveAnchor(value).(*ast.SequenceNode)
+	if !ok {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	declared := map[string]bool{}
+	for _, item := range sequenceNode.Values {
+		path, required, ok := envFileEntry(item)
+		if !ok {
+			continue
+		}
+		earlier, found := declared[path]
+		if !found {
+			declared[path] = required
+			continue
+		}
+		if earlier != required {
+			diagnostics = append(diagnostics, createValidationDiagnostic(
+				source,
+				protocol.DiagnosticSeverityWarning,
+				"ConflictingEnvFile",
+				fmt.Sprintf("env file %v is declared with required: %v but an earlier entry declares it with required: %v", path, required, earlier),
+				sequenceEntryRange(resolveAnchor(item)),
+			))
+		}
+	}
+	return diagnostics
+}
+
+// validateRestart checks that the retry count of an on-failure restart
+// policy is a non-negative integer.
+func validateRestart(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	s, ok := resolveAnchor(value).(*ast.StringNode)
+	if !ok {
+		return nil
+	}
+	literal, ok := literalValue(s.Value)
+	if !ok || !strings.HasPrefix(literal, "on-failure:") {
+		return nil
+	}
+
+	count := literal[len("on-failure:"):]
+	if parsed, err := strconv.ParseInt(count, 10, 64); err != nil || parsed < 0 {
+		t := s.GetToken()
+		return []protocol.Diagnostic{
+			createValidationDiagnostic(
+				source,
+				protocol.DiagnosticSeverityError,
+				"InvalidIntegerValue",
+				fmt.Sprintf("on-failure retry count must be a non-negative integer (found %v)", count),
+				createRange(t, len(t.Value)),
+			),
+		}
+	}
+	return nil
+}
+
+// validateLink checks that the service of a SERVICE or SERVICE:ALIAS
+// entry in a service's links has been declared. Services from included
+// files cannot be seen so nothing is reported if the file includes
+// other files.
+func validateLink(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	s, ok := resolveAnchor(value).(*ast.StringNode)
+	if !ok || mappingValue(root, "include") != nil {
+		return nil
+	}
+	literal, ok := literalValue(s.Value)
+	if !ok {
+		return nil
+	}
+
+	t := linkToken(s.GetToken())
+	service, _, _ := strings.Cut(literal, ":")
+	if service == "" || slices.Contains(declaredNames(root, "services"), service) {
+		return nil
+	}
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"UndefinedService",
+			fmt.Sprintf("linked service %v could not be found in this file", service),
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// validateAdditionalContext checks that the service of an additional
+// build context with the service: prefix has been declared. Services
+// from included files cannot be seen so nothing is reported if the
+// file includes other files.
+func validateAdditionalContext(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	if _, ok := resolveAnchor(value).(*ast.StringNode); !ok || mappingValue(root, "include") != nil {
+		return nil
+	}
+	t := serviceReferenceToken(additionalContextToken(value, key == nil))
+	if t == nil {
+		return nil
+	}
+	service, ok := literalValue(t.Value)
+	if !ok || service == "" || slices.Contains(declaredNames(root, "services"), service) {
+		return nil
+	}
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"UndefinedService",
+			fmt.Sprintf("additional context service %v could not be found in this file", service),
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// validateSharedNamespace checks that the service whose namespace is
+// joined with the service: prefix of an ipc, network_mode, or pid
+// attribute has been declared. Services from included files cannot be
+// seen so nothing is reported if the file includes other files.
+func validateSharedNamespace(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	stringNode, ok := resolveAnchor(value).(*ast.StringNode)
+	if !ok || mappingValue(root, "include") != nil {
+		return nil
+	}
+	t := serviceReferenceToken(stringNode.GetToken())
+	if t == nil {
+		return nil
+	}
+	service, ok := literalValue(t.Value)
+	if !ok || service == "" || slices.Contains(declaredNames(root, "services"), service) {
+		return nil
+	}
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"UndefinedService",
+			fmt.Sprintf("%v service %v could not be found in this file", key.GetToken().Value, service),
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// lastLineVisitor finds the last line of the document that a node's
+// tokens are on.
+type lastLineVisitor struct {
+	line int
+}
+
+func (v *lastLineVisitor) Visit(node ast.Node) ast.Visitor {
+	if _, ok := node.(*ast.CommentGroupNode); ok {
+		return nil
+	}
+	if t := node.GetToken(); t != nil {
+		// the origin includes the whitespace around the token so it is
+		// trimmed to find the lines that the value itself spans
+		line := t.Position.Line + strings.Count(strings.TrimSpace(t.Origin), "\n")
+		v.line = max(v.line, line)
+	}
+	return v
+}
+
+// lastLine returns the last line of the document that the given node
+// spans.
+func lastLine(node ast.Node) int {
+	v := &lastLineVisitor{}
+	ast.Walk(v, node)
+	return v.line
+}
+
+// ignoredAttributeDiagnostics reports the attributes of the mapping node
+// that are ignored by Compose because of the presence of another
+// attribute. Attributes in the kept list and merge keys are not
+// reported. If an edit is given then it is offered to the user as a
+// way to fix every ignored attribute at once.
+func ignoredAttributeDiagnostics(source, reason string, mappingNode *ast.MappingNode, kept []string, edit *types.NamedEdit) []protocol.Diagnostic {
+	diagnostics := []protocol.Diagnostic{}
+	for _, child := range mappingNode.Values {
+		if _, ok := child.Key.(*ast.MergeKeyNode); ok {
+			continue
+		}
+		t := child.Key.GetToken()
+		if slices.Contains(kept, t.Value) {
+			continue
+		}
+		diagnostic := createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityWarning,
+			"IgnoredAttribute",
+			fmt.Sprintf("%v is ignored %v", t.Value, reason),
+			createRange(t, len(t.Value)),
+		)
+		diagnostic.Tags = []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary}
+		if edit != nil {
+			diagnostic.Data = []types.NamedEdit{*edit}
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// disabledHealthcheck returns the attributes of the healthcheck if it
+// has been disabled and nil otherwise.
+func disabledHealthcheck(value ast.Node) *ast.MappingNode {
+	healthcheck, ok := resolveAnchor(value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+	disable := mappingValue(healthcheck, "disable")
+	if disable == nil {
+		return nil
+	}
+	if b, ok := resolveAnchor(disable.Value).(*ast.BoolNode); !ok || !b.Value {
+		return nil
+	}
+	return healthcheck
+}
+
+// validateDisabledHealthcheck reports the attributes of a healthcheck
+// that are ignored because the healthcheck has been disabled. The code
+// action that removes them is offered by CodeAction.
+func validateDisabledHealthcheck(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	healthcheck := disabledHealthcheck(value)
+	if healthcheck == nil {
+		return nil
+	}
+	return ignoredAttributeDiagnostics(source, "because the healthcheck is disabled", healthcheck, []string{"disable"}, nil)
+}
+
+// boolValue returns the value of a boolean or of a string that can be
+// parsed as one. False is returned for ok if the node is neither or if
+// it uses interpolation.
+func boolValue(node ast.Node) (value bool, ok bool) {
+	switch n := node.(type) {
+	case *ast.BoolNode:
+		return n.Value, true
+	case *ast.StringNode:
+		literal, ok := literalValue(n.Value)
+		if !ok {
+			return false, false
+		}
+		b, err := strconv.ParseBool(literal)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// validatePrivilegedCapabilities reports the cap_add and cap_drop
+// attributes of a privileged service as a privileged container is
+// granted every capability regardless of them. The privileged flag may
+// be a boolean or a string that can be parsed as one.
+func validatePrivilegedCapabilities(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	service, ok := resolveAnchor(value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+	privileged := mappingValue(service, "privileged")
+	if privileged == nil {
+		return nil
+	}
+	if b, ok := boolValue(resolveAnchor(privileged.Value)); !ok || !b {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, attribute := range []string{"cap_add", "cap_drop"} {
+		capabilities := mappingValue(service, attribute)
+		if capabilities == nil {
+			continue
+		}
+		t := capabilities.Key.GetToken()
+		diagnostic := createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityHint,
+			"RedundantCapabilities",
+			fmt.Sprintf("%v has no effect as privileged already grants all capabilities to the container", attribute),
+			createRange(t, len(t.Value)),
+		)
+		diagnostic.Tags = []protocol.DiagnosticTag{protocol.DiagnosticTagUnnecessary}
+		// the attribute can only be removed by deleting its lines if
+		// it does not share them with the other attributes
+		if !service.IsFlowStyle {
+			diagnostic.Data = []types.NamedEdit{
+				{
+					Title: fmt.Sprintf("Remove redundant %v", attribute),
+					Edit:  "",
+					Range: &protocol.Range{
+						Start: protocol.Position{Line: protocol.UInteger(t.Position.Line - 1)},
+						End:   protocol.Position{Line: protocol.UInteger(lastLine(capabilities))},
+					},
+				},
+			}
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// validateGlobalReplicas reports the replicas attribute of a deploy
+// block that runs the service in the global mode as the global mode
+// always runs exactly one task on every node.
+func validateGlobalReplicas(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	deploy, ok := resolveAnchor(value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+	mode := mappingValue(deploy, "mode")
+	replicas := mappingValue(deploy, "replicas")
+	if mode == nil || replicas == nil {
+		return nil
+	}
+	if s, ok := resolveAnchor(mode.Value).(*ast.StringNode); !ok || s.Value != "global" {
+		return nil
+	}
+
+	t := replicas.Key.GetToken()
+	diagnostic := createValidationDiagnostic(
+		source,
+		protocol.DiagnosticSeverityError,
+		"GlobalModeReplicas",
+		"replicas cannot be set when the deploy mode is global",
+		createRange(t, len(t.Value)),
+	)
+	if !deploy.IsFlowStyle {
+		diagnostic.Data = []types.NamedEdit{
+			{
+				Title: "Remove replicas",
+				Edit:  "",
+				Range: &protocol.Range{
+					Start: protocol.Position{Line: protocol.UInteger(t.Position.Line - 1)},
+					End:   protocol.Position{Line: protocol.UInteger(lastLine(replicas))},
+				},
+			},
+		}
+	}
+	return []protocol.Diagnostic{diagnostic}
+}
+
+// normalizedPlatform returns the normalized form of the platform in the
+// given node. False is returned if the node is not a string, if it has
+// been interpolated, or if it cannot be parsed as a platform.
+func normalizedPlatform(node ast.Node) (string, bool) {
+	s, ok := resolveAnchor(node).(*ast.StringNode)
+	if !ok {
+		return "", false
+	}
+	value, ok := literalValue(s.Value)
+	if !ok {
+		return "", false
+	}
+	platform, err := platforms.Parse(value)
+	if err != nil {
+		return "", false
+	}
+	return platforms.Format(platforms.Normalize(platform)), true
+}
+
+// validateBuildPlatforms reports the platform of a service if the image
+// of the service is built for other platforms as the container would
+// then not be able to run the image that was built for it.
+func validateBuildPlatforms(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	service, ok := resolveAnchor(value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+	platform := mappingValue(service, "platform")
+	build := mappingValue(service, "build")
+	if platform == nil || build == nil {
+		return nil
+	}
+	buildPlatforms := mappingValue(build.Value, "platforms")
+	if buildPlatforms == nil {
+		return nil
+	}
+	sequence, ok := resolveAnchor(buildPlatforms.Value).(*ast.SequenceNode)
+	if !ok {
+		return nil
+	}
+	runtimePlatform, ok := normalizedPlatform(platform.Value)
+	if !ok {
+		return nil
+	}
+	for _, item := range sequence.Values {
+		builtPlatform, ok := normalizedPlatform(item)
+		if !ok || builtPlatform == runtimePlatform {
+			return nil
+		}
+	}
+
+	t := resolveAnchor(platform.Value).GetToken()
+	return []protocol.Diagnostic{
+		createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityWarning,
+			"PlatformNotBuilt",
+			fmt.Sprintf("platform %v is not one of the platforms in build.platforms", t.Value),
+			createRange(t, len(t.Value)),
+		),
+	}
+}
+
+// validateDeprecatedScale reports the scale attribute of a service as
+// it has been deprecated in favor of deploy.replicas. The attribute can
+// be moved with a code action.
+func validateDeprecatedScale(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	t := key.GetToken()
+	diagnostic := createValidationDiagnostic(
+		source,
+		protocol.DiagnosticSeverityInformation,
+		"DeprecatedScale",
+		"scale is deprecated, use deploy.replicas instead",
+		createRange(t, len(t.Value)),
+	)
+	diagnostic.Tags = []protocol.DiagnosticTag{protocol.DiagnosticTagDeprecated}
+	return []protocol.Diagnostic{diagnostic}
+}
+
+// validateCredentialSpec reports the attributes of a credential_spec
+// if more than one of the mutually exclusive sources has been set.
+func validateCredentialSpec(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	credentialSpec, ok := resolveAnchor(value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+	group := exclusiveAttributes["credential_spec"]
+	sources := []*ast.MappingValueNode{}
+	for _, node := range credentialSpec.Values {
+		if slices.Contains(group, resolveAnchor(node.Key).GetToken().Value) {
+			sources = append(sources, node)
+		}
+	}
+	if len(sources) < 2 {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, node := range sources {
+		t := node.Key.GetToken()
+		diagnostics = append(diagnostics, createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityError,
+			"ConflictingCredentialSpec",
+			fmt.Sprintf("only one of %v can be set in credential_spec", strings.Join(group, ", ")),
+			createRange(t, len(t.Value)),
+		))
+	}
+	return diagnostics
+}
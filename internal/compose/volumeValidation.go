@@ -0,0 +1,197 @@
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// hostPath returns true if the source of a mount is a path on the
+// host instead of the name of a volume.
+func hostPath(source string) bool {
+	return strings.ContainsAny(source, "/\\") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~")
+}
+
+// longFormMountType returns the type of a long-form service volume. If
+// the type is missing or interpolated then false is returned.
+func longFormMountType(value ast.Node) (*ast.StringNode, string, bool) {
+	typeNode := mappingValue(value, "type")
+	if typeNode == nil {
+		return nil, "", false
+	}
+	mountType, ok := resolveAnchor(typeNode.Value).(*ast.StringNode)
+	if !ok {
+		return nil, "", false
+	}
+	mountTypeValue, ok := literalValue(mountType.Value)
+	if !ok {
+		return nil, "", false
+	}
+	return mountType, mountTypeValue, true
+}
+
+// validateMountSource checks that the source of a long-form service
+// volume is compatible with the type of the mount.
+func validateMountSource(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	mountType, mountTypeValue, ok := longFormMountType(value)
+	if !ok {
+		return nil
+	}
+
+	sourceNode := mappingValue(value, "source")
+	var sourceValue *ast.StringNode
+	var sourceLiteral string
+	if sourceNode != nil {
+		if s, ok := resolveAnchor(sourceNode.Value).(*ast.StringNode); ok {
+			if literal, ok := literalValue(s.Value); ok {
+				sourceValue = s
+				sourceLiteral = literal
+			}
+		}
+	}
+
+	switch mountTypeValue {
+	case "volume":
+		if sourceValue != nil && hostPath(sourceLiteral) {
+			return []protocol.Diagnostic{mountSourceDiagnostic(source, sourceValue, fmt.Sprintf("source of a volume mount must be a named volume but %v is a path", sourceLiteral))}
+		}
+	case "bind":
+		if sourceNode == nil {
+			return []protocol.Diagnostic{mountSourceDiagnostic(source, mountType, "bind mount requires a source path on the host")}
+		}
+		if sourceValue != nil && !hostPath(sourceLiteral) && slices.Contains(declaredNames(root, "volumes"), sourceLiteral) {
+			return []protocol.Diagnostic{mountSourceDiagnostic(source, sourceValue, fmt.Sprintf("source of a bind mount must be a path on the host but %v is a named volume", sourceLiteral))}
+		}
+	case "tmpfs":
+		if sourceNode != nil {
+			t := resolveAnchor(sourceNode.Key).GetToken()
+			return []protocol.Diagnostic{
+				createValidationDiagnostic(source, protocol.DiagnosticSeverityError, "MountSourceMismatch", "tmpfs mount must not have a source", createRange(t, len(t.Value))),
+			}
+		}
+	}
+	return nil
+}
+
+func mountSourceDiagnostic(source string, node *ast.StringNode, message string) protocol.Diagnostic {
+	t := node.GetToken()
+	return createValidationDiagnostic(source, protocol.DiagnosticSeverityError, "MountSourceMismatch", message, createRange(t, len(t.Value)))
+}
+
+// mountOptionTypes are the types of mounts that have an attribute of
+// the same name for the options that only apply to that type.
+var mountOptionTypes = []string{"bind", "volume", "tmpfs", "image"}
+
+// validateMountOptions reports the options blocks of a long-form
+// service volume that do not apply to the type of the mount such as
+// bind options on a volume mount. A quick fix to remove the block is
+// offered if the volume is not in flow style.
+func validateMountOptions(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	_, mountTypeValue, ok := longFormMountType(value)
+	if !ok || !slices.Contains(mountOptionTypes, mountTypeValue) {
+		return nil
+	}
+	volume, ok := resolveAnchor(value).(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	for _, attribute := range volume.Values {
+		t := resolveAnchor(attribute.Key).GetToken()
+		if t.Value == mountTypeValue || !slices.Contains(mountOptionTypes, t.Value) {
+			continue
+		}
+		diagnostic := createValidationDiagnostic(
+			source,
+			protocol.DiagnosticSeverityWarning,
+			"MismatchedMountOptions",
+			fmt.Sprintf("%v options only apply to %v mounts and are ignored by this %v mount", t.Value, t.Value, mountTypeValue),
+			createRange(t, len(t.Value)),
+		)
+		if !volume.IsFlowStyle {
+			diagnostic.Data = []types.NamedEdit{
+				{
+					Title: fmt.Sprintf("Remove %v options", t.Value),
+					Edit:  "",
+					Range: &protocol.Range{
+						Start: protocol.Position{Line: protocol.UInteger(attribute.Key.GetToken().Position.Line - 1)},
+						End:   protocol.Position{Line: protocol.UInteger(lastLine(attribute))},
+					},
+				},
+			}
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// shortSyntaxVolumeModes are the flags that may follow the target of a
+// volume in the short syntax. Multiple flags are separated by commas.
+var shortSyntaxVolumeModes = []string{"ro", "rw", "z", "Z", "nocopy", "cached", "delegated", "consistent", "shared", "rshared", "slave", "rslave", "private", "rprivate"}
+
+// shortSyntaxVolumeParts splits a short syntax volume into its
+// colon-separated parts and returns the offset of every part in the
+// volume. A part that starts with a Windows drive letter such as
+// C:\data keeps the colon after the drive letter.
+func shortSyntaxVolumeParts(volume string) ([]string, []int) {
+	parts := []string{}
+	offsets := []int{}
+	offset := 0
+	for {
+		searchStart := offset
+		if len(volume)-offset >= 3 && unicode.IsLetter(rune(volume[offset])) && volume[offset+1] == ':' && (volume[offset+2] == '\\' || volume[offset+2] == '/') {
+			searchStart += 2
+		}
+		idx := strings.Index(volume[searchStart:], ":")
+		if idx == -1 {
+			parts = append(parts, volume[offset:])
+			offsets = append(offsets, offset)
+			return parts, offsets
+		}
+		parts = append(parts, volume[offset:searchStart+idx])
+		offsets = append(offsets, offset)
+		offset = searchStart + idx + 1
+	}
+}
+
+// validateVolumeMode checks the flags at the end of a short syntax
+// volume and reports every flag that Compose does not recognize.
+// Interpolated volumes are ignored.
+func validateVolumeMode(source string, root *ast.MappingNode, key, value ast.Node) []protocol.Diagnostic {
+	s, ok := resolveAnchor(value).(*ast.StringNode)
+	if !ok {
+		return nil
+	}
+	if _, ok := literalValue(s.Value); !ok {
+		return nil
+	}
+
+	t := s.GetToken()
+	parts, offsets := shortSyntaxVolumeParts(t.Value)
+	if len(parts) != 3 {
+		return nil
+	}
+
+	diagnostics := []protocol.Diagnostic{}
+	offset := offsets[2]
+	for _, mode := range strings.Split(parts[2], ",") {
+		if mode != "" && !slices.Contains(shortSyntaxVolumeModes, mode) {
+			modeToken := subToken(t, offset)
+			diagnostics = append(diagnostics, createValidationDiagnostic(
+				source,
+				protocol.DiagnosticSeverityError,
+				"InvalidVolumeMode",
+				fmt.Sprintf("unknown volume mode %q", mode),
+				createRange(modeToken, len(mode)),
+			))
+		}
+		offset += len(mode) + 1
+	}
+	return diagnostics
+}
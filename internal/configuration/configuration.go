@@ -15,6 +15,15 @@ const (
 	ConfigExperimentalScoutNotPinnedDigest             = "docker.lsp.experimental.scout.notPinnedDigest"
 	ConfigExperimentalScoutRecommendedTag              = "docker.lsp.experimental.scout.recommendedTag"
 	ConfigExperimentalScoutVulnerabilities             = "docker.lsp.experimental.scout.vulnerabilities"
+
+	ConfigFoldingRegionStart = "docker.lsp.folding.regionStart"
+	ConfigFoldingRegionEnd   = "docker.lsp.folding.regionEnd"
+
+	ConfigInlayHintsResolvedPaths = "docker.lsp.inlayHints.resolvedPaths"
+
+	ConfigComposeImages = "docker.lsp.compose.images"
+
+	ConfigDiagnostics = "docker.lsp.diagnostics"
 )
 
 type TelemetrySetting string
@@ -25,10 +34,69 @@ const (
 	TelemetrySettingAll   TelemetrySetting = "all"
 )
 
+// DiagnosticSetting is the severity that the diagnostics of a rule are
+// reported with or off if the rule's diagnostics should not be
+// reported at all.
+type DiagnosticSetting string
+
+const (
+	DiagnosticSettingOff         DiagnosticSetting = "off"
+	DiagnosticSettingHint        DiagnosticSetting = "hint"
+	DiagnosticSettingInformation DiagnosticSetting = "info"
+	DiagnosticSettingWarning     DiagnosticSetting = "warning"
+	DiagnosticSettingError       DiagnosticSetting = "error"
+)
+
+var diagnosticSeverities = map[DiagnosticSetting]protocol.DiagnosticSeverity{
+	DiagnosticSettingHint:        protocol.DiagnosticSeverityHint,
+	DiagnosticSettingInformation: protocol.DiagnosticSeverityInformation,
+	DiagnosticSettingWarning:     protocol.DiagnosticSeverityWarning,
+	DiagnosticSettingError:       protocol.DiagnosticSeverityError,
+}
+
 type Configuration struct {
 	// docker.lsp.telemetry
 	Telemetry    TelemetrySetting `json:"telemetry,omitempty"`
 	Experimental Experimental     `json:"experimental"`
+	// docker.lsp.folding
+	Folding Folding `json:"folding"`
+	// docker.lsp.inlayHints
+	InlayHints InlayHints `json:"inlayHints"`
+	// docker.lsp.compose
+	Compose Compose `json:"compose"`
+	// docker.lsp.diagnostics maps the code of a rule to its setting
+	Diagnostics map[string]DiagnosticSetting `json:"diagnostics,omitempty"`
+}
+
+type Compose struct {
+	// docker.lsp.compose.images
+	Images []string `json:"images,omitempty"`
+}
+
+type InlayHints struct {
+	// docker.lsp.inlayHints.resolvedPaths
+	ResolvedPaths bool `json:"resolvedPaths"`
+}
+
+type Folding struct {
+	// docker.lsp.folding.regionStart
+	RegionStart string `json:"regionStart,omitempty"`
+	// docker.lsp.folding.regionEnd
+	RegionEnd string `json:"regionEnd,omitempty"`
+}
+
+// RegionMarkers returns the text that a comment must start with to
+// start or end a folding region. The markers default to region and
+// endregion if they have not been configured.
+func (f Folding) RegionMarkers() (start, end string) {
+	start, end = f.RegionStart, f.RegionEnd
+	if start == "" {
+		start = defaultConfiguration.Folding.RegionStart
+	}
+	if end == "" {
+		end = defaultConfiguration.Folding.RegionEnd
+	}
+	return start, end
 }
 
 type Experimental struct {
@@ -46,6 +114,7 @@ type Scout struct {
 }
 
 var configurations = make(map[protocol.DocumentUri]Configuration)
+var initializationDiagnostics = make(map[string]DiagnosticSetting)
 var lock = sync.RWMutex{}
 var defaultConfiguration = Configuration{
 	Telemetry: TelemetrySettingAll,
@@ -58,6 +127,10 @@ var defaultConfiguration = Configuration{
 			Vulnerabilites:              true,
 		},
 	},
+	Folding: Folding{
+		RegionStart: "region",
+		RegionEnd:   "endregion",
+	},
 }
 
 func Documents() []protocol.DocumentUri {
@@ -92,3 +165,48 @@ func Remove(document protocol.DocumentUri) {
 	defer lock.Unlock()
 	delete(configurations, document)
 }
+
+// StoreInitializationDiagnostics stores the diagnostic settings that
+// were sent in the initialization options. They apply to the rules
+// that the configuration of a document does not have a setting for.
+func StoreInitializationDiagnostics(settings map[string]DiagnosticSetting) {
+	lock.Lock()
+	defer lock.Unlock()
+	initializationDiagnostics = settings
+}
+
+// ApplyDiagnosticSettings changes the severity of the given diagnostics
+// to the severity that has been configured for their rules. A rule is
+// identified by the code of its diagnostics. The diagnostics of rules
+// that have been turned off are removed and diagnostics without a code
+// or with an unknown setting are left unchanged.
+func ApplyDiagnosticSettings(document protocol.DocumentUri, diagnostics []protocol.Diagnostic) []protocol.Diagnostic {
+	settings := Get(document).Diagnostics
+	lock.RLock()
+	defaults := initializationDiagnostics
+	lock.RUnlock()
+	if len(settings) == 0 && len(defaults) == 0 {
+		return diagnostics
+	}
+
+	filtered := []protocol.Diagnostic{}
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Code == nil {
+			filtered = append(filtered, diagnostic)
+			continue
+		}
+		code, _ := diagnostic.Code.Value.(string)
+		setting, ok := settings[code]
+		if !ok {
+			setting = defaults[code]
+		}
+		if setting == DiagnosticSettingOff {
+			continue
+		}
+		if severity, ok := diagnosticSeverities[setting]; ok {
+			diagnostic.Severity = &severity
+		}
+		filtered = append(filtered, diagnostic)
+	}
+	return filtered
+}
@@ -0,0 +1,102 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func diagnostic(code string, severity protocol.DiagnosticSeverity) protocol.Diagnostic {
+	d := protocol.Diagnostic{Message: "message", Severity: &severity}
+	if code != "" {
+		d.Code = &protocol.IntegerOrString{Value: code}
+	}
+	return d
+}
+
+func TestApplyDiagnosticSettings(t *testing.T) {
+	documentURI := protocol.DocumentUri("file:///compose.yaml")
+	testCases := []struct {
+		name                   string
+		settings               map[string]DiagnosticSetting
+		initializationSettings map[string]DiagnosticSetting
+		diagnostics            []protocol.Diagnostic
+		expectedDiagnostics    []protocol.Diagnostic
+	}{
+		{
+			name:                "no settings",
+			diagnostics:         []protocol.Diagnostic{diagnostic("FileNotFound", protocol.DiagnosticSeverityError)},
+			expectedDiagnostics: []protocol.Diagnostic{diagnostic("FileNotFound", protocol.DiagnosticSeverityError)},
+		},
+		{
+			name:                "error lowered to a warning",
+			settings:            map[string]DiagnosticSetting{"FileNotFound": DiagnosticSettingWarning},
+			diagnostics:         []protocol.Diagnostic{diagnostic("FileNotFound", protocol.DiagnosticSeverityError)},
+			expectedDiagnostics: []protocol.Diagnostic{diagnostic("FileNotFound", protocol.DiagnosticSeverityWarning)},
+		},
+		{
+			name:                "warning raised to an error",
+			settings:            map[string]DiagnosticSetting{"MismatchedMountOptions": DiagnosticSettingError},
+			diagnostics:         []protocol.Diagnostic{diagnostic("MismatchedMountOptions", protocol.DiagnosticSeverityWarning)},
+			expectedDiagnostics: []protocol.Diagnostic{diagnostic("MismatchedMountOptions", protocol.DiagnosticSeverityError)},
+		},
+		{
+			name:     "hint and information",
+			settings: map[string]DiagnosticSetting{"A": DiagnosticSettingHint, "B": DiagnosticSettingInformation},
+			diagnostics: []protocol.Diagnostic{
+				diagnostic("A", protocol.DiagnosticSeverityError),
+				diagnostic("B", protocol.DiagnosticSeverityError),
+			},
+			expectedDiagnostics: []protocol.Diagnostic{
+				diagnostic("A", protocol.DiagnosticSeverityHint),
+				diagnostic("B", protocol.DiagnosticSeverityInformation),
+			},
+		},
+		{
+			name:     "rule turned off",
+			settings: map[string]DiagnosticSetting{"FileNotFound": DiagnosticSettingOff},
+			diagnostics: []protocol.Diagnostic{
+				diagnostic("FileNotFound", protocol.DiagnosticSeverityError),
+				diagnostic("MismatchedMountOptions", protocol.DiagnosticSeverityWarning),
+			},
+			expectedDiagnostics: []protocol.Diagnostic{diagnostic("MismatchedMountOptions", protocol.DiagnosticSeverityWarning)},
+		},
+		{
+			name:                "unknown setting is ignored",
+			settings:            map[string]DiagnosticSetting{"FileNotFound": "critical"},
+			diagnostics:         []protocol.Diagnostic{diagnostic("FileNotFound", protocol.DiagnosticSeverityError)},
+			expectedDiagnostics: []protocol.Diagnostic{diagnostic("FileNotFound", protocol.DiagnosticSeverityError)},
+		},
+		{
+			name:                "diagnostic without a code is left unchanged",
+			settings:            map[string]DiagnosticSetting{"": DiagnosticSettingOff},
+			diagnostics:         []protocol.Diagnostic{diagnostic("", protocol.DiagnosticSeverityError)},
+			expectedDiagnostics: []protocol.Diagnostic{diagnostic("", protocol.DiagnosticSeverityError)},
+		},
+		{
+			name:                   "initialization setting",
+			initializationSettings: map[string]DiagnosticSetting{"FileNotFound": DiagnosticSettingOff},
+			diagnostics:            []protocol.Diagnostic{diagnostic("FileNotFound", protocol.DiagnosticSeverityError)},
+			expectedDiagnostics:    []protocol.Diagnostic{},
+		},
+		{
+			name:                   "document setting takes precedence over the initialization setting",
+			settings:               map[string]DiagnosticSetting{"FileNotFound": DiagnosticSettingHint},
+			initializationSettings: map[string]DiagnosticSetting{"FileNotFound": DiagnosticSettingOff},
+			diagnostics:            []protocol.Diagnostic{diagnostic("FileNotFound", protocol.DiagnosticSeverityError)},
+			expectedDiagnostics:    []protocol.Diagnostic{diagnostic("FileNotFound", protocol.DiagnosticSeverityHint)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			Store(documentURI, Configuration{Diagnostics: tc.settings})
+			StoreInitializationDiagnostics(tc.initializationSettings)
+			defer Remove(documentURI)
+			defer StoreInitializationDiagnostics(nil)
+
+			require.Equal(t, tc.expectedDiagnostics, ApplyDiagnosticSettings(documentURI, tc.diagnostics))
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package dockerfile
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+var cacheSharingValues = []flagValue{
+	{name: "shared", documentation: "The cache mount can be used concurrently by multiple writers."},
+	{name: "private", documentation: "A new mount is created if there are multiple writers."},
+	{name: "locked", documentation: "The second writer is paused until the first one releases the mount."},
+}
+
+func Completion(ctx context.Context, params *protocol.CompletionParams, doc document.DockerfileDocument) (*protocol.CompletionList, error) {
+	instruction := doc.Instruction(params.Position)
+	if instruction == nil {
+		return nil, nil
+	}
+
+	lines := strings.Split(string(doc.Input()), "\n")
+	if int(params.Position.Line) >= len(lines) {
+		return nil, nil
+	}
+	line := strings.TrimSuffix(lines[params.Position.Line], "\r")
+	character := int(params.Position.Character)
+	if character > len(line) {
+		return nil, nil
+	}
+
+	if prefix, ok := variablePrefix(line[:character]); ok {
+		items := variableCompletionItems(variablesInScope(doc.Nodes(), instruction), params.Position, len(prefix))
+		if len(items) == 0 {
+			return nil, nil
+		}
+		return &protocol.CompletionList{Items: items}, nil
+	}
+
+	if !strings.EqualFold(instruction.Value, "RUN") {
+		return nil, nil
+	}
+
+	keyword := ""
+	if instruction.StartLine == int(params.Position.Line)+1 {
+		keyword = instruction.Value
+	}
+	for _, flag := range parseFlags(line, keyword) {
+		if flag.name == "--mount" && flag.start+len("--mount=") <= character && character <= flag.end {
+			items := mountCompletionItems(doc.Nodes(), instruction, flag, params.Position, line[flag.start+len("--mount="):character])
+			if len(items) == 0 {
+				return nil, nil
+			}
+			return &protocol.CompletionList{Items: items}, nil
+		}
+	}
+	return nil, nil
+}
+
+// mountCompletionItems suggests the options of a --mount flag or the
+// values of the option that is being typed. The prefix is the content
+// of the flag's value up to the cursor.
+func mountCompletionItems(nodes []*parser.Node, instruction *parser.Node, flag instructionFlag, position protocol.Position, prefix string) []protocol.CompletionItem {
+	field := prefix[strings.LastIndex(prefix, ",")+1:]
+	mountType, typed := flag.option("type")
+	if !typed {
+		mountType = "bind"
+	}
+
+	if key, value, ok := strings.Cut(field, "="); ok {
+		switch key {
+		case "type":
+			values := []flagValue{}
+			for _, mountType := range mountTypes {
+				values = append(values, flagValue{name: mountType, documentation: mountTypeDocumentations[mountType].documentation})
+			}
+			return valueCompletionItems(values, position, len(value))
+		case "from":
+			values := []flagValue{}
+			for _, stage := range stageNames(nodes, instruction) {
+				values = append(values, flagValue{name: stage})
+			}
+			return valueCompletionItems(values, position, len(value))
+		case "sharing":
+			if mountType == "cache" {
+				return valueCompletionItems(cacheSharingValues, position, len(value))
+			}
+		}
+		return nil
+	}
+
+	items := []protocol.CompletionItem{}
+	if !typed {
+		items = append(items, optionCompletionItem(flagValue{name: "type", documentation: "The type of the mount: `bind`, `cache`, `tmpfs`, `secret`, or `ssh`. Defaults to `bind`."}, position, len(field)))
+	}
+	for _, option := range mountTypeDocumentations[mountType].options {
+		if !optionUsed(flag, option) {
+			items = append(items, optionCompletionItem(option, position, len(field)))
+		}
+	}
+	return items
+}
+
+// variablePrefix returns the partially typed name of the variable that
+// the content ends with if it is a $NAME or ${NAME} reference.
+func variablePrefix(content string) (string, bool) {
+	idx := len(content)
+	for idx > 0 && isVariableCharacter(content[idx-1]) {
+		idx--
+	}
+	prefix := content[idx:]
+	content = strings.TrimSuffix(content[:idx], "{")
+	if !strings.HasSuffix(content, "$") || strings.HasSuffix(content, "\\$") {
+		return "", false
+	}
+	return prefix, true
+}
+
+func isVariableCharacter(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func variableCompletionItems(variables []variable, position protocol.Position, prefixLength int) []protocol.CompletionItem {
+	items := []protocol.CompletionItem{}
+	for _, v := range variables {
+		item := protocol.CompletionItem{
+			Label:    v.name,
+			Kind:     types.CreateCompletionItemKindPointer(protocol.CompletionItemKindVariable),
+			TextEdit: completionTextEdit(v.name, position, prefixLength),
+		}
+		if v.value != nil {
+			item.Detail = types.CreateStringPointer(*v.value)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func optionUsed(flag instructionFlag, option flagValue) bool {
+	for _, used := range flag.options {
+		if used.key == option.name || slices.Contains(option.aliases, used.key) {
+			if strings.Contains(flag.raw[used.start-flag.start:used.end-flag.start], "=") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func optionCompletionItem(option flagValue, position protocol.Position, prefixLength int) protocol.CompletionItem {
+	return protocol.CompletionItem{
+		Label:         option.name,
+		Documentation: option.documentation,
+		Kind:          types.CreateCompletionItemKindPointer(protocol.CompletionItemKindProperty),
+		TextEdit:      completionTextEdit(option.name+"=", position, prefixLength),
+	}
+}
+
+func valueCompletionItems(values []flagValue, position protocol.Position, prefixLength int) []protocol.CompletionItem {
+	items := []protocol.CompletionItem{}
+	for _, value := range values {
+		item := protocol.CompletionItem{
+			Label:    value.name,
+			Kind:     types.CreateCompletionItemKindPointer(protocol.CompletionItemKindEnumMember),
+			TextEdit: completionTextEdit(value.name, position, prefixLength),
+		}
+		if value.documentation != "" {
+			item.Documentation = value.documentation
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func completionTextEdit(newText string, position protocol.Position, prefixLength int) protocol.TextEdit {
+	return protocol.TextEdit{
+		NewText: newText,
+		Range: protocol.Range{
+			Start: protocol.Position{Line: position.Line, Character: position.Character - protocol.UInteger(prefixLength)},
+			End:   position,
+		},
+	}
+}
+
+// stageNames returns the names of the build stages that have been
+// declared before the given instruction.
+func stageNames(nodes []*parser.Node, instruction *parser.Node) []string {
+	names := []string{}
+	for _, node := range nodes {
+		if node.StartLine >= instruction.StartLine {
+			break
+		}
+		if strings.EqualFold(node.Value, "FROM") && node.Next != nil && node.Next.Next != nil && strings.EqualFold(node.Next.Next.Value, "AS") && node.Next.Next.Next != nil {
+			names = append(names, node.Next.Next.Next.Value)
+		}
+	}
+	return names
+}
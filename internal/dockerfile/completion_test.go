@@ -0,0 +1,292 @@
+package dockerfile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestCompletion(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      protocol.UInteger
+		character protocol.UInteger
+		list      *protocol.CompletionList
+	}{
+		{
+			name:      "type suggested for an empty --mount flag",
+			content:   "FROM scratch\nRUN --mount=",
+			line:      1,
+			character: 12,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					optionItem("type", "The type of the mount: `bind`, `cache`, `tmpfs`, `secret`, or `ssh`. Defaults to `bind`.", 1, 12, 0),
+					optionItem("target", "Mount path.", 1, 12, 0),
+					optionItem("source", "Source path in the `from`. Defaults to the root of the `from`.", 1, 12, 0),
+					optionItem("from", "Build stage, context, or image name for the root of the source. Defaults to the build context.", 1, 12, 0),
+					optionItem("rw", "Allow writes on the mount. Written data will be discarded.", 1, 12, 0),
+				},
+			},
+		},
+		{
+			name:      "mount types",
+			content:   "FROM scratch\nRUN --mount=type=c",
+			line:      1,
+			character: 18,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					valueItem("bind", mountTypeDocumentations["bind"].documentation, 1, 18, 1),
+					valueItem("cache", mountTypeDocumentations["cache"].documentation, 1, 18, 1),
+					valueItem("tmpfs", mountTypeDocumentations["tmpfs"].documentation, 1, 18, 1),
+					valueItem("secret", mountTypeDocumentations["secret"].documentation, 1, 18, 1),
+					valueItem("ssh", mountTypeDocumentations["ssh"].documentation, 1, 18, 1),
+				},
+			},
+		},
+		{
+			name:      "cache options exclude the ones already set",
+			content:   "FROM scratch\nRUN --mount=type=cache,target=/root/.cache,",
+			line:      1,
+			character: 43,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					optionItem("id", "Optional ID to identify separate caches. Defaults to the value of `target`.", 1, 43, 0),
+					optionItem("ro", "Read-only if set.", 1, 43, 0),
+					optionItem("sharing", mountTypeDocumentations["cache"].options[3].documentation, 1, 43, 0),
+					optionItem("from", "Build stage, context, or image name to use as a base of the cache mount. Defaults to an empty directory.", 1, 43, 0),
+					optionItem("source", "Subpath in the `from` to mount. Defaults to the root of the `from`.", 1, 43, 0),
+					optionItem("mode", "File mode for a new cache directory in octal. Defaults to `0755`.", 1, 43, 0),
+					optionItem("uid", "User ID for a new cache directory. Defaults to `0`.", 1, 43, 0),
+					optionItem("gid", "Group ID for a new cache directory. Defaults to `0`.", 1, 43, 0),
+				},
+			},
+		},
+		{
+			name:      "options consider the typed prefix and a type set after the cursor",
+			content:   "FROM scratch\nRUN --mount=si,type=tmpfs",
+			line:      1,
+			character: 14,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					optionItem("target", "Mount path.", 1, 14, 2),
+					optionItem("size", "Upper limit on the size of the filesystem.", 1, 14, 2),
+				},
+			},
+		},
+		{
+			name:      "sharing values of a cache mount",
+			content:   "FROM scratch\nRUN --mount=type=cache,sharing=",
+			line:      1,
+			character: 31,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					valueItem("shared", "The cache mount can be used concurrently by multiple writers.", 1, 31, 0),
+					valueItem("private", "A new mount is created if there are multiple writers.", 1, 31, 0),
+					valueItem("locked", "The second writer is paused until the first one releases the mount.", 1, 31, 0),
+				},
+			},
+		},
+		{
+			name:      "from suggests the stages declared before the instruction",
+			content:   "FROM alpine AS base\nFROM scratch AS deps\nRUN --mount=from=\nFROM scratch AS later",
+			line:      2,
+			character: 17,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					valueItem("base", "", 2, 17, 0),
+					valueItem("deps", "", 2, 17, 0),
+				},
+			},
+		},
+		{
+			name:      "from without any named stages",
+			content:   "FROM scratch\nRUN --mount=from=",
+			line:      1,
+			character: 17,
+			list:      nil,
+		},
+		{
+			name:      "--mount flag on a continuation line",
+			content:   "FROM scratch\nRUN --network=none \\\n  --mount=type=ssh,",
+			line:      2,
+			character: 19,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					optionItem("id", "ID of the SSH agent socket or key. Defaults to `default`.", 2, 19, 0),
+					optionItem("target", "SSH agent socket path. Defaults to `/run/buildkit/ssh_agent.${N}`.", 2, 19, 0),
+					optionItem("required", "If set to `true`, the instruction errors out when the key is unavailable. Defaults to `false`.", 2, 19, 0),
+					optionItem("mode", "File mode for the socket in octal. Defaults to `0600`.", 2, 19, 0),
+					optionItem("uid", "User ID for the socket. Defaults to `0`.", 2, 19, 0),
+					optionItem("gid", "Group ID for the socket. Defaults to `0`.", 2, 19, 0),
+				},
+			},
+		},
+		{
+			name:      "inside the flag's name",
+			content:   "FROM scratch\nRUN --mount=",
+			line:      1,
+			character: 8,
+			list:      nil,
+		},
+		{
+			name:      "arguments of the command",
+			content:   "FROM scratch\nRUN echo --mount=",
+			line:      1,
+			character: 17,
+			list:      nil,
+		},
+		{
+			name:      "other instructions",
+			content:   "FROM scratch\nCOPY --mount= . .",
+			line:      1,
+			character: 13,
+			list:      nil,
+		},
+		{
+			name:      "variables declared earlier in the stage",
+			content:   "FROM scratch\nARG VERSION=1.0\nENV FOO=bar BAZ=\"q z\"\nRUN echo $",
+			line:      3,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					variableItem("VERSION", "1.0", 3, 10, 0),
+					variableItem("FOO", "bar", 3, 10, 0),
+					variableItem("BAZ", "q z", 3, 10, 0),
+				},
+			},
+		},
+		{
+			name:      "braced variable with a prefix",
+			content:   "FROM scratch\nENV FOO=bar\nWORKDIR /${FO",
+			line:      2,
+			character: 13,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					variableItem("FOO", "bar", 2, 13, 2),
+				},
+			},
+		},
+		{
+			name:      "variable on a continuation line of an ENV instruction",
+			content:   "FROM scratch\nENV FOO=bar\nENV BAZ=qux \\\n  OTHER=$",
+			line:      3,
+			character: 9,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					variableItem("FOO", "bar", 3, 9, 0),
+				},
+			},
+		},
+		{
+			name:      "redeclared variables use the last value",
+			content:   "FROM scratch\nENV FOO=bar\nENV FOO=baz\nRUN echo $",
+			line:      3,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					variableItem("FOO", "baz", 3, 10, 0),
+				},
+			},
+		},
+		{
+			name:      "global build arguments in FROM instructions",
+			content:   "ARG IMAGE=alpine\nFROM scratch\nENV FOO=bar\nFROM $",
+			line:      3,
+			character: 6,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					variableItem("IMAGE", "alpine", 3, 6, 0),
+				},
+			},
+		},
+		{
+			name:      "global build argument redeclared in a stage inherits the default",
+			content:   "ARG IMAGE=alpine\nFROM scratch\nARG IMAGE\nARG OTHER\nRUN echo $",
+			line:      4,
+			character: 10,
+			list: &protocol.CompletionList{
+				Items: []protocol.CompletionItem{
+					variableItem("IMAGE", "alpine", 4, 10, 0),
+					variableItem("OTHER", "", 4, 10, 0),
+				},
+			},
+		},
+		{
+			name:      "variables of other stages are not in scope",
+			content:   "FROM scratch\nENV FOO=bar\nFROM scratch\nRUN echo $",
+			line:      3,
+			character: 10,
+			list:      nil,
+		},
+		{
+			name:      "escaped dollar sign",
+			content:   "FROM scratch\nENV FOO=bar\nRUN echo \\$",
+			line:      2,
+			character: 11,
+			list:      nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewDockerfileDocument(uri.URI("file:///tmp/Dockerfile"), 1, []byte(tc.content))
+			list, err := Completion(context.Background(), &protocol.CompletionParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: "file:///tmp/Dockerfile"},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, doc)
+			require.NoError(t, err)
+			require.Equal(t, tc.list, list)
+		})
+	}
+}
+
+func optionItem(label, documentation string, line, character, prefixLength protocol.UInteger) protocol.CompletionItem {
+	return protocol.CompletionItem{
+		Label:         label,
+		Documentation: documentation,
+		Kind:          types.CreateCompletionItemKindPointer(protocol.CompletionItemKindProperty),
+		TextEdit:      textEdit(label+"=", line, character, prefixLength),
+	}
+}
+
+func valueItem(label, documentation string, line, character, prefixLength protocol.UInteger) protocol.CompletionItem {
+	item := protocol.CompletionItem{
+		Label:    label,
+		Kind:     types.CreateCompletionItemKindPointer(protocol.CompletionItemKindEnumMember),
+		TextEdit: textEdit(label, line, character, prefixLength),
+	}
+	if documentation != "" {
+		item.Documentation = documentation
+	}
+	return item
+}
+
+func textEdit(newText string, line, character, prefixLength protocol.UInteger) protocol.TextEdit {
+	return protocol.TextEdit{
+		NewText: newText,
+		Range: protocol.Range{
+			Start: protocol.Position{Line: line, Character: character - prefixLength},
+			End:   protocol.Position{Line: line, Character: character},
+		},
+	}
+}
+
+func variableItem(label, detail string, line, character, prefixLength protocol.UInteger) protocol.CompletionItem {
+	item := protocol.CompletionItem{
+		Label:    label,
+		Kind:     types.CreateCompletionItemKindPointer(protocol.CompletionItemKindVariable),
+		TextEdit: textEdit(label, line, character, prefixLength),
+	}
+	if detail != "" {
+		item.Detail = types.CreateStringPointer(detail)
+	}
+	return item
+}
@@ -0,0 +1,130 @@
+package dockerfile
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// stageReference is a word in a Dockerfile that may refer to the name
+// of a build stage such as the value of a COPY instruction's --from
+// flag. The offsets are relative to the start of the line that the
+// reference is on.
+type stageReference struct {
+	name  string
+	line  int
+	start int
+	end   int
+}
+
+// stageDeclaration returns the name of the build stage that the given
+// FROM instruction declares.
+func stageDeclaration(lines []string, instruction *parser.Node) (instructionArgument, bool) {
+	if !strings.EqualFold(instruction.Value, "FROM") {
+		return instructionArgument{}, false
+	}
+	arguments := instructionArguments(lines, instruction)
+	if len(arguments) < 3 || !strings.EqualFold(arguments[1].value, "AS") {
+		return instructionArgument{}, false
+	}
+	return arguments[2], true
+}
+
+// stageReferences returns the words of the instruction that may refer
+// to a build stage. These are the image of a FROM instruction, the
+// --from flag of a COPY instruction, and the from option of a RUN
+// instruction's --mount flag. The instruction that is wrapped by an
+// ONBUILD instruction is also considered.
+func stageReferences(lines []string, instruction *parser.Node) []stageReference {
+	references := []stageReference{}
+	trigger := triggerInstruction(instruction)
+	if strings.EqualFold(trigger.Value, "FROM") {
+		if arguments := instructionArguments(lines, instruction); len(arguments) > 0 {
+			argument := arguments[0]
+			references = append(references, stageReference{name: argument.value, line: argument.line, start: argument.start, end: argument.end})
+		}
+		return references
+	}
+
+	instructionFlags(lines, instruction, func(line int, flag instructionFlag) {
+		if flag.name == "--from" && strings.EqualFold(trigger.Value, "COPY") {
+			references = append(references, stageReference{name: flag.value, line: line, start: flag.end - len(flag.value), end: flag.end})
+		} else if flag.name == "--mount" && strings.EqualFold(trigger.Value, "RUN") {
+			for _, option := range flag.options {
+				if option.key == "from" {
+					references = append(references, stageReference{name: option.value, line: line, start: option.end - len(option.value), end: option.end})
+				}
+			}
+		}
+	})
+	return references
+}
+
+// Definition returns the location of the build stage or the variable
+// that is referenced at the given position. A stage can only be
+// referenced by instructions that come after it and a variable
+// reference resolves to the latest declaration of the variable in the
+// same scope that precedes it.
+func Definition(ctx context.Context, definitionLinkSupport bool, doc document.DockerfileDocument, position protocol.Position) (any, error) {
+	lines := strings.Split(string(doc.Input()), "\n")
+	stages := map[string]instructionArgument{}
+	for _, node := range doc.Nodes() {
+		if node.StartLine < 1 || node.EndLine > len(lines) {
+			continue
+		}
+		if int(position.Line) >= node.StartLine-1 && int(position.Line) < node.EndLine {
+			for _, reference := range stageReferences(lines, node) {
+				if reference.line != int(position.Line) || int(position.Character) < reference.start || reference.end < int(position.Character) {
+					continue
+				}
+				if stage, ok := stages[strings.ToLower(reference.name)]; ok {
+					originSelectionRange := wordRange(reference.line, reference.start, reference.end)
+					return types.CreateDefinitionResult(
+						definitionLinkSupport,
+						wordRange(stage.line, stage.start, stage.end),
+						&originSelectionRange,
+						protocol.URI(doc.URI()),
+					), nil
+				}
+			}
+		}
+		if stage, ok := stageDeclaration(lines, node); ok {
+			stages[strings.ToLower(stage.value)] = stage
+		}
+	}
+	return variableDefinition(definitionLinkSupport, doc, position), nil
+}
+
+// variableDefinition returns the location of the declaration that the
+// variable reference at the given position resolves to.
+func variableDefinition(definitionLinkSupport bool, doc document.DockerfileDocument, position protocol.Position) any {
+	occurrences := variableOccurrences(doc)
+	for _, reference := range occurrences {
+		if reference.declaration || reference.rng.Start.Line != position.Line || position.Character < reference.rng.Start.Character || reference.rng.End.Character < position.Character {
+			continue
+		}
+
+		var declaration *variableOccurrence
+		for _, occurrence := range occurrences {
+			if occurrence.declaration && occurrence.name == reference.name && occurrence.scope == reference.scope && occurrence.rng.Start.Line <= reference.rng.Start.Line {
+				declaration = &occurrence
+			}
+		}
+		if declaration == nil {
+			return nil
+		}
+		return types.CreateDefinitionResult(definitionLinkSupport, declaration.rng, &reference.rng, protocol.URI(doc.URI()))
+	}
+	return nil
+}
+
+func wordRange(line, start, end int) protocol.Range {
+	return protocol.Range{
+		Start: protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(start)},
+		End:   protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(end)},
+	}
+}
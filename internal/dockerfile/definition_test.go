@@ -0,0 +1,171 @@
+package dockerfile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func definitionLink(originLine, originStart, originEnd, targetLine, targetStart, targetEnd protocol.UInteger) []protocol.LocationLink {
+	return []protocol.LocationLink{
+		{
+			OriginSelectionRange: &protocol.Range{
+				Start: protocol.Position{Line: originLine, Character: originStart},
+				End:   protocol.Position{Line: originLine, Character: originEnd},
+			},
+			TargetRange: protocol.Range{
+				Start: protocol.Position{Line: targetLine, Character: targetStart},
+				End:   protocol.Position{Line: targetLine, Character: targetEnd},
+			},
+			TargetSelectionRange: protocol.Range{
+				Start: protocol.Position{Line: targetLine, Character: targetStart},
+				End:   protocol.Position{Line: targetLine, Character: targetEnd},
+			},
+			TargetURI: "file:///tmp/Dockerfile",
+		},
+	}
+}
+
+func TestDefinition(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      protocol.UInteger
+		character protocol.UInteger
+		links     any
+	}{
+		{
+			name:      "COPY --from stage",
+			content:   "FROM alpine AS base\nFROM scratch\nCOPY --from=base /a /b",
+			line:      2,
+			character: 14,
+			links:     definitionLink(2, 12, 16, 0, 15, 19),
+		},
+		{
+			name:      "COPY --from stage with a different case",
+			content:   "FROM alpine AS Base\nFROM scratch\nCOPY --from=bASE /a /b",
+			line:      2,
+			character: 12,
+			links:     definitionLink(2, 12, 16, 0, 15, 19),
+		},
+		{
+			name:      "RUN --mount from stage",
+			content:   "FROM alpine AS base\nFROM alpine\nRUN --mount=type=bind,from=base,target=/x ls",
+			line:      2,
+			character: 30,
+			links:     definitionLink(2, 27, 31, 0, 15, 19),
+		},
+		{
+			name:      "FROM stage",
+			content:   "FROM alpine AS base\nFROM --platform=linux/amd64 base AS child",
+			line:      1,
+			character: 29,
+			links:     definitionLink(1, 28, 32, 0, 15, 19),
+		},
+		{
+			name:      "ONBUILD COPY --from stage",
+			content:   "FROM alpine AS base\nFROM scratch\nONBUILD COPY --from=base --chown=app /a /b",
+			line:      2,
+			character: 22,
+			links:     definitionLink(2, 20, 24, 0, 15, 19),
+		},
+		{
+			name:      "ONBUILD RUN --mount from stage",
+			content:   "FROM alpine AS base\nFROM alpine\nONBUILD RUN --mount=type=cache,from=base,target=/x ls",
+			line:      2,
+			character: 37,
+			links:     definitionLink(2, 36, 40, 0, 15, 19),
+		},
+		{
+			name:      "ONBUILD instruction that spans multiple lines",
+			content:   "FROM alpine AS base\nFROM scratch\nONBUILD COPY \\\n  --from=base /a /b",
+			line:      3,
+			character: 10,
+			links:     definitionLink(3, 9, 13, 0, 15, 19),
+		},
+		{
+			name:      "ONBUILD keyword is not a reference",
+			content:   "FROM alpine AS base\nFROM scratch\nONBUILD COPY --from=base /a /b",
+			line:      2,
+			character: 3,
+			links:     nil,
+		},
+		{
+			name:      "stages declared later cannot be referenced",
+			content:   "FROM scratch\nCOPY --from=base /a /b\nFROM alpine AS base",
+			line:      1,
+			character: 14,
+			links:     nil,
+		},
+		{
+			name:      "images are not stages",
+			content:   "FROM scratch\nCOPY --from=alpine /a /b",
+			line:      1,
+			character: 14,
+			links:     nil,
+		},
+		{
+			name:      "ARG referenced in a stage",
+			content:   "FROM alpine\nARG VERSION=1\nRUN echo $VERSION",
+			line:      2,
+			character: 12,
+			links:     definitionLink(2, 10, 17, 1, 4, 11),
+		},
+		{
+			name:      "ENV referenced in an ONBUILD instruction",
+			content:   "FROM alpine\nENV DIR=/app\nONBUILD COPY --from=base . ${DIR}",
+			line:      2,
+			character: 30,
+			links:     definitionLink(2, 29, 32, 1, 4, 7),
+		},
+		{
+			name:      "variable reference resolves to the latest declaration",
+			content:   "FROM alpine\nARG NAME=a\nARG NAME=b\nRUN echo $NAME",
+			line:      3,
+			character: 11,
+			links:     definitionLink(3, 10, 14, 2, 4, 8),
+		},
+		{
+			name:      "global ARG referenced by FROM",
+			content:   "ARG IMAGE=alpine\nFROM $IMAGE",
+			line:      1,
+			character: 7,
+			links:     definitionLink(1, 6, 11, 0, 4, 9),
+		},
+		{
+			name:      "undeclared variable",
+			content:   "FROM alpine\nRUN echo $HOME",
+			line:      1,
+			character: 11,
+			links:     nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewDockerfileDocument(uri.URI("file:///tmp/Dockerfile"), 1, []byte(tc.content))
+			links, err := Definition(context.Background(), true, doc, protocol.Position{Line: tc.line, Character: tc.character})
+			require.NoError(t, err)
+			require.Equal(t, tc.links, links)
+		})
+	}
+}
+
+func TestDefinition_Locations(t *testing.T) {
+	doc := document.NewDockerfileDocument(uri.URI("file:///tmp/Dockerfile"), 1, []byte("FROM alpine AS base\nFROM scratch\nONBUILD COPY --from=base /a /b"))
+	locations, err := Definition(context.Background(), false, doc, protocol.Position{Line: 2, Character: 22})
+	require.NoError(t, err)
+	require.Equal(t, []protocol.Location{
+		{
+			URI: "file:///tmp/Dockerfile",
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 0, Character: 15},
+				End:   protocol.Position{Line: 0, Character: 19},
+			},
+		},
+	}, locations)
+}
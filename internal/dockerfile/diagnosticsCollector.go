@@ -0,0 +1,278 @@
+package dockerfile
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/buildkit"
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/pkg/lsp/textdocument"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+type DockerfileDiagnosticsCollector struct {
+}
+
+func NewDockerfileDiagnosticsCollector() textdocument.DiagnosticsCollector {
+	return &DockerfileDiagnosticsCollector{}
+}
+
+func (c *DockerfileDiagnosticsCollector) SupportsLanguageIdentifier(languageIdentifier protocol.LanguageIdentifier) bool {
+	return languageIdentifier == protocol.DockerfileLanguage
+}
+
+func (c *DockerfileDiagnosticsCollector) CollectDiagnostics(source, workspaceFolder string, doc document.Document, text string) []protocol.Diagnostic {
+	dockerfileDoc := doc.(document.DockerfileDocument)
+	lines := strings.Split(string(doc.Input()), "\n")
+	diagnostics := []protocol.Diagnostic{}
+	for _, node := range dockerfileDoc.Nodes() {
+		trigger := triggerInstruction(node)
+		if strings.EqualFold(trigger.Value, "ADD") || strings.EqualFold(trigger.Value, "COPY") {
+			instructionFlags(lines, node, func(line int, flag instructionFlag) {
+				if flag.name == "--chown" {
+					if diagnostic := validateChown(source, line, flag); diagnostic != nil {
+						diagnostics = append(diagnostics, *diagnostic)
+					}
+				}
+			})
+		} else if strings.EqualFold(trigger.Value, "EXPOSE") {
+			for _, argument := range instructionArguments(lines, node) {
+				if diagnostic := validateExposedPort(source, argument); diagnostic != nil {
+					diagnostics = append(diagnostics, *diagnostic)
+				}
+			}
+		} else if strings.EqualFold(trigger.Value, "STOPSIGNAL") {
+			arguments := instructionArguments(lines, node)
+			if len(arguments) == 1 {
+				if diagnostic := validateStopSignal(source, arguments[0]); diagnostic != nil {
+					diagnostics = append(diagnostics, *diagnostic)
+				}
+			}
+		}
+	}
+	if buildkit.RemoveOverlappingIssues {
+		diagnostics = slices.DeleteFunc(diagnostics, overlapsWithDockerfileUtils)
+	}
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	return diagnostics
+}
+
+// overlapsWithDockerfileUtils returns true if the diagnostic is also
+// reported by dockerfile-utils so that it is not reported twice when
+// the client runs it alongside this server.
+func overlapsWithDockerfileUtils(diagnostic protocol.Diagnostic) bool {
+	if diagnostic.Code != nil {
+		if value, ok := diagnostic.Code.Value.(string); ok {
+			switch value {
+			case "InvalidChownFlag":
+				return true
+			case "InvalidExposedPort":
+				return true
+			case "InvalidStopSignal":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// triggerInstruction returns the instruction that is wrapped by the
+// given ONBUILD instruction. Any other instruction is returned as is.
+func triggerInstruction(instruction *parser.Node) *parser.Node {
+	if strings.EqualFold(instruction.Value, "ONBUILD") && instruction.Next != nil && len(instruction.Next.Children) > 0 {
+		return instruction.Next.Children[0]
+	}
+	return instruction
+}
+
+// instructionKeyword returns the keyword that the instruction starts
+// with. The keyword of an ONBUILD instruction also includes the keyword
+// of the instruction that it wraps.
+func instructionKeyword(instruction *parser.Node) string {
+	if trigger := triggerInstruction(instruction); trigger != instruction {
+		return instruction.Value + " " + trigger.Value
+	}
+	return instruction.Value
+}
+
+// instructionFlags calls fn with every flag of the instruction and the
+// zero-based line that it is on. Words that the parser did not consider
+// to be a flag of the instruction are ignored. The flags of an ONBUILD
+// instruction are the flags of the instruction that it wraps.
+func instructionFlags(lines []string, instruction *parser.Node, fn func(line int, flag instructionFlag)) {
+	keyword := instructionKeyword(instruction)
+	flags := triggerInstruction(instruction).Flags
+	for line := instruction.StartLine - 1; line < instruction.EndLine && line < len(lines); line++ {
+		if line != instruction.StartLine-1 {
+			keyword = ""
+		}
+		for _, flag := range parseFlags(strings.TrimSuffix(lines[line], "\r"), keyword) {
+			if slices.Contains(flags, flag.raw) {
+				fn(line, flag)
+			}
+		}
+	}
+}
+
+// validateChown checks that the value of a --chown flag is in the form
+// of user[:group]. The user and group cannot be checked as they are
+// only resolved at build time so only malformed values are reported.
+func validateChown(source string, line int, flag instructionFlag) *protocol.Diagnostic {
+	if strings.Contains(flag.value, "$") {
+		return nil
+	}
+
+	parts := strings.Split(flag.value, ":")
+	if len(parts) <= 2 && !slices.Contains(parts, "") {
+		return nil
+	}
+
+	start := flag.start
+	if flag.value != "" {
+		start = flag.end - len(flag.value)
+	}
+	return &protocol.Diagnostic{
+		Message:  fmt.Sprintf("--chown value must be in the form user[:group] (found %q)", flag.value),
+		Code:     &protocol.IntegerOrString{Value: "InvalidChownFlag"},
+		Source:   types.CreateStringPointer(source),
+		Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityWarning),
+		Range: protocol.Range{
+			Start: protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(start)},
+			End:   protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(flag.end)},
+		},
+	}
+}
+
+// instructionArgument is a whitespace delimited word of an
+// instruction's arguments. The offsets are relative to the start of
+// the line that the word is on.
+type instructionArgument struct {
+	value string
+	line  int
+	start int
+	end   int
+}
+
+// instructionArguments returns the words of the instruction that come
+// after its keyword and its flags. Line continuation characters and
+// comments between the lines of the instruction are skipped.
+func instructionArguments(lines []string, instruction *parser.Node) []instructionArgument {
+	keywords := len(strings.Fields(instructionKeyword(instruction)))
+	flags := triggerInstruction(instruction).Flags
+	arguments := []instructionArgument{}
+	for line := instruction.StartLine - 1; line < instruction.EndLine && line < len(lines); line++ {
+		text := strings.TrimSuffix(lines[line], "\r")
+		if line != instruction.StartLine-1 && strings.HasPrefix(strings.TrimSpace(text), "#") {
+			continue
+		}
+		words := splitWords(text)
+		if line == instruction.StartLine-1 {
+			words = words[min(keywords, len(words)):]
+		}
+		for _, word := range words {
+			value := text[word[0]:word[1]]
+			if value == "\\" || (len(arguments) == 0 && slices.Contains(flags, value)) {
+				continue
+			}
+			value = strings.TrimSuffix(value, "\\")
+			arguments = append(arguments, instructionArgument{value: value, line: line, start: word[0], end: word[0] + len(value)})
+		}
+	}
+	return arguments
+}
+
+func argumentDiagnostic(source, code, message string, argument instructionArgument) *protocol.Diagnostic {
+	return &protocol.Diagnostic{
+		Message:  message,
+		Code:     &protocol.IntegerOrString{Value: code},
+		Source:   types.CreateStringPointer(source),
+		Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
+		Range: protocol.Range{
+			Start: protocol.Position{Line: protocol.UInteger(argument.line), Character: protocol.UInteger(argument.start)},
+			End:   protocol.Position{Line: protocol.UInteger(argument.line), Character: protocol.UInteger(argument.end)},
+		},
+	}
+}
+
+// validateExposedPort checks that an argument of an EXPOSE instruction
+// is a port or a range of ports with an optional protocol such as
+// 80/tcp or 8000-8010. Arguments that use variables are ignored.
+func validateExposedPort(source string, argument instructionArgument) *protocol.Diagnostic {
+	if strings.Contains(argument.value, "$") {
+		return nil
+	}
+
+	ports, protocol, found := strings.Cut(argument.value, "/")
+	if found && !slices.Contains([]string{"tcp", "udp", "sctp"}, strings.ToLower(protocol)) {
+		return argumentDiagnostic(source, "InvalidExposedPort", fmt.Sprintf("invalid protocol %v in EXPOSE (expected one of: tcp, udp, sctp)", protocol), argument)
+	}
+
+	start, end, isRange := strings.Cut(ports, "-")
+	bounds := []string{start}
+	if isRange {
+		bounds = append(bounds, end)
+	}
+	for _, port := range bounds {
+		number, err := strconv.Atoi(port)
+		if err != nil {
+			return argumentDiagnostic(source, "InvalidExposedPort", fmt.Sprintf("invalid port %v in EXPOSE (expected a number or a range of numbers)", ports), argument)
+		}
+		if number < 1 || number > 65535 {
+			return argumentDiagnostic(source, "InvalidExposedPort", fmt.Sprintf("port %v in EXPOSE is out of range (expected 1-65535)", port), argument)
+		}
+	}
+	if isRange {
+		first, _ := strconv.Atoi(start)
+		last, _ := strconv.Atoi(end)
+		if first > last {
+			return argumentDiagnostic(source, "InvalidExposedPort", fmt.Sprintf("invalid port range %v in EXPOSE (the start must not be greater than the end)", ports), argument)
+		}
+	}
+	return nil
+}
+
+// signals are the names of the Linux signals without their SIG prefix.
+var signals = []string{
+	"ABRT", "ALRM", "BUS", "CHLD", "CLD", "CONT", "FPE", "HUP", "ILL", "INT", "IO", "IOT", "KILL",
+	"PIPE", "POLL", "PROF", "PWR", "QUIT", "SEGV", "STKFLT", "STOP", "SYS", "TERM", "TRAP", "TSTP",
+	"TTIN", "TTOU", "URG", "USR1", "USR2", "VTALRM", "WINCH", "XCPU", "XFSZ",
+}
+
+// validStopSignal returns true if the signal is a signal number or the
+// name of a Linux signal with or without the SIG prefix. The real-time
+// signals can also be given relative to RTMIN and RTMAX.
+func validStopSignal(signal string) bool {
+	if number, err := strconv.Atoi(signal); err == nil {
+		return number >= 1 && number <= 64
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	if slices.Contains(signals, name) || name == "RTMIN" || name == "RTMAX" {
+		return true
+	}
+	if offset, ok := strings.CutPrefix(name, "RTMIN+"); ok {
+		number, err := strconv.Atoi(offset)
+		return err == nil && number >= 1 && number <= 15
+	}
+	if offset, ok := strings.CutPrefix(name, "RTMAX-"); ok {
+		number, err := strconv.Atoi(offset)
+		return err == nil && number >= 1 && number <= 14
+	}
+	return false
+}
+
+// validateStopSignal checks that the argument of a STOPSIGNAL
+// instruction is a signal that the container can be stopped with.
+// Arguments that use variables are ignored.
+func validateStopSignal(source string, argument instructionArgument) *protocol.Diagnostic {
+	if strings.Contains(argument.value, "$") || validStopSignal(argument.value) {
+		return nil
+	}
+	return argumentDiagnostic(source, "InvalidStopSignal", fmt.Sprintf("unknown signal %v in STOPSIGNAL (expected a signal name such as SIGTERM or a signal number)", argument.value), argument)
+}
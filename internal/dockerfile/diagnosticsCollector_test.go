@@ -0,0 +1,198 @@
+package dockerfile
+
+import (
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/buildkit"
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestCollectDiagnostics(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		diagnostics []protocol.Diagnostic
+	}{
+		{
+			name:        "user only",
+			content:     "FROM scratch\nCOPY --chown=app . /app",
+			diagnostics: nil,
+		},
+		{
+			name:        "user and group",
+			content:     "FROM scratch\nCOPY --chown=app:staff . /app",
+			diagnostics: nil,
+		},
+		{
+			name:        "numeric uid and gid",
+			content:     "FROM scratch\nADD --chown=1000:1000 . /app",
+			diagnostics: nil,
+		},
+		{
+			name:        "variables are ignored",
+			content:     "FROM scratch\nCOPY --chown=${UID}: . /app",
+			diagnostics: nil,
+		},
+		{
+			name:    "empty value",
+			content: "FROM scratch\nCOPY --chown= . /app",
+			diagnostics: []protocol.Diagnostic{
+				chownDiagnostic(`--chown value must be in the form user[:group] (found "")`, 1, 5, 13),
+			},
+		},
+		{
+			name:    "trailing colon",
+			content: "FROM scratch\nCOPY --chown=app: . /app",
+			diagnostics: []protocol.Diagnostic{
+				chownDiagnostic(`--chown value must be in the form user[:group] (found "app:")`, 1, 13, 17),
+			},
+		},
+		{
+			name:    "leading colon",
+			content: "FROM scratch\ncopy --chown=:staff . /app",
+			diagnostics: []protocol.Diagnostic{
+				chownDiagnostic(`--chown value must be in the form user[:group] (found ":staff")`, 1, 13, 19),
+			},
+		},
+		{
+			name:    "too many colons",
+			content: "FROM scratch\nCOPY --chown=a:b:c . /app",
+			diagnostics: []protocol.Diagnostic{
+				chownDiagnostic(`--chown value must be in the form user[:group] (found "a:b:c")`, 1, 13, 18),
+			},
+		},
+		{
+			name:    "flag on a continuation line",
+			content: "FROM scratch\nCOPY --link \\\n  --chown=app: . /app",
+			diagnostics: []protocol.Diagnostic{
+				chownDiagnostic(`--chown value must be in the form user[:group] (found "app:")`, 2, 10, 14),
+			},
+		},
+		{
+			name:        "not a flag of the instruction",
+			content:     "FROM scratch\nCOPY . --chown=app: /app",
+			diagnostics: nil,
+		},
+		{
+			name:        "other instructions are ignored",
+			content:     "FROM scratch\nRUN --chown=app: ls",
+			diagnostics: nil,
+		},
+		{
+			name:        "valid exposed ports",
+			content:     "FROM scratch\nEXPOSE 80 443/tcp 53/UDP 8000-8010/udp \\\n  # comment\n  9000/sctp ${PORT}/tcp",
+			diagnostics: nil,
+		},
+		{
+			name:    "non-numeric exposed port",
+			content: "FROM scratch\nEXPOSE http 80",
+			diagnostics: []protocol.Diagnostic{
+				errorDiagnostic("InvalidExposedPort", "invalid port http in EXPOSE (expected a number or a range of numbers)", 1, 7, 11),
+			},
+		},
+		{
+			name:    "exposed port out of range",
+			content: "FROM scratch\nexpose 80 \\\n  65536/tcp",
+			diagnostics: []protocol.Diagnostic{
+				errorDiagnostic("InvalidExposedPort", "port 65536 in EXPOSE is out of range (expected 1-65535)", 2, 2, 11),
+			},
+		},
+		{
+			name:    "exposed port with an unknown protocol",
+			content: "FROM scratch\nEXPOSE 80/http",
+			diagnostics: []protocol.Diagnostic{
+				errorDiagnostic("InvalidExposedPort", "invalid protocol http in EXPOSE (expected one of: tcp, udp, sctp)", 1, 7, 14),
+			},
+		},
+		{
+			name:    "exposed port range in reverse",
+			content: "FROM scratch\nEXPOSE 8010-8000",
+			diagnostics: []protocol.Diagnostic{
+				errorDiagnostic("InvalidExposedPort", "invalid port range 8010-8000 in EXPOSE (the start must not be greater than the end)", 1, 7, 16),
+			},
+		},
+		{
+			name:        "signal names and numbers",
+			content:     "FROM scratch\nSTOPSIGNAL SIGTERM\nSTOPSIGNAL kill\nSTOPSIGNAL 9\nSTOPSIGNAL SIGRTMIN+3\nSTOPSIGNAL $SIGNAL",
+			diagnostics: nil,
+		},
+		{
+			name:    "unknown signal name",
+			content: "FROM scratch\nSTOPSIGNAL SIGTERMINATE",
+			diagnostics: []protocol.Diagnostic{
+				errorDiagnostic("InvalidStopSignal", "unknown signal SIGTERMINATE in STOPSIGNAL (expected a signal name such as SIGTERM or a signal number)", 1, 11, 23),
+			},
+		},
+		{
+			name:    "signal number out of range",
+			content: "FROM scratch\nSTOPSIGNAL 0",
+			diagnostics: []protocol.Diagnostic{
+				errorDiagnostic("InvalidStopSignal", "unknown signal 0 in STOPSIGNAL (expected a signal name such as SIGTERM or a signal number)", 1, 11, 12),
+			},
+		},
+		{
+			name:    "ONBUILD COPY with an invalid --chown flag",
+			content: "FROM scratch\nONBUILD COPY --from=base --chown=:staff . /app",
+			diagnostics: []protocol.Diagnostic{
+				chownDiagnostic(`--chown value must be in the form user[:group] (found ":staff")`, 1, 33, 39),
+			},
+		},
+		{
+			name:    "ONBUILD EXPOSE with an invalid port",
+			content: "FROM scratch\nONBUILD EXPOSE 80 70000",
+			diagnostics: []protocol.Diagnostic{
+				errorDiagnostic("InvalidExposedPort", "port 70000 in EXPOSE is out of range (expected 1-65535)", 1, 18, 23),
+			},
+		},
+		{
+			name:        "ONBUILD RUN with a mount from a stage",
+			content:     "FROM alpine AS base\nFROM alpine\nONBUILD RUN --mount=type=bind,from=base,target=/x ls",
+			diagnostics: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewDockerfileDocument(uri.URI("file:///tmp/Dockerfile"), 1, []byte(tc.content))
+			collector := NewDockerfileDiagnosticsCollector()
+			buildkit.RemoveOverlappingIssues = false
+			diagnostics := collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Equal(t, tc.diagnostics, diagnostics)
+
+			buildkit.RemoveOverlappingIssues = true
+			defer func() { buildkit.RemoveOverlappingIssues = false }()
+			diagnostics = collector.CollectDiagnostics("docker-language-server", "", doc, "")
+			require.Nil(t, diagnostics, "dockerfile-utils reports the same problems")
+		})
+	}
+}
+
+func chownDiagnostic(message string, line, start, end protocol.UInteger) protocol.Diagnostic {
+	return protocol.Diagnostic{
+		Message:  message,
+		Code:     &protocol.IntegerOrString{Value: "InvalidChownFlag"},
+		Source:   types.CreateStringPointer("docker-language-server"),
+		Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityWarning),
+		Range: protocol.Range{
+			Start: protocol.Position{Line: line, Character: start},
+			End:   protocol.Position{Line: line, Character: end},
+		},
+	}
+}
+
+func errorDiagnostic(code, message string, line, start, end protocol.UInteger) protocol.Diagnostic {
+	return protocol.Diagnostic{
+		Message:  message,
+		Code:     &protocol.IntegerOrString{Value: code},
+		Source:   types.CreateStringPointer("docker-language-server"),
+		Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
+		Range: protocol.Range{
+			Start: protocol.Position{Line: line, Character: start},
+			End:   protocol.Position{Line: line, Character: end},
+		},
+	}
+}
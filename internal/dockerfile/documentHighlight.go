@@ -0,0 +1,201 @@
+package dockerfile
+
+import (
+	"strings"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// globalScope is the scope of the build arguments that are declared
+// before the first FROM instruction. The FROM instructions themselves
+// can only reference variables from this scope.
+const globalScope = -1
+
+// variableOccurrence is a declaration of or a reference to a variable
+// in a Dockerfile. The scope is the index of the build stage that the
+// occurrence is in or globalScope.
+type variableOccurrence struct {
+	name        string
+	scope       int
+	declaration bool
+	rng         protocol.Range
+}
+
+type scopedName struct {
+	name  string
+	scope int
+}
+
+// variableOccurrences returns every declaration of and reference to a
+// variable in the given Dockerfile. A build stage that redeclares a
+// global build argument inherits its value so the scope of all of its
+// occurrences in that stage is changed to globalScope to treat them as
+// the same variable.
+func variableOccurrences(doc document.DockerfileDocument) []variableOccurrence {
+	lines := strings.Split(string(doc.Input()), "\n")
+	occurrences := []variableOccurrence{}
+	redeclared := map[scopedName]bool{}
+	scope := globalScope
+	for _, node := range doc.Nodes() {
+		if node.StartLine < 1 || node.EndLine > len(lines) {
+			continue
+		}
+		instructionScope := scope
+		if strings.EqualFold(node.Value, "FROM") {
+			instructionScope = globalScope
+			scope++
+		}
+
+		line := node.StartLine - 1
+		start := len(lines[line]) - len(strings.TrimLeft(lines[line], " \t")) + len(node.Value)
+		for _, occurrence := range declarationOccurrences(lines, node, start) {
+			occurrence.scope = instructionScope
+			occurrences = append(occurrences, occurrence)
+			if instructionScope != globalScope && strings.EqualFold(node.Value, "ARG") {
+				redeclared[scopedName{name: occurrence.name, scope: instructionScope}] = true
+			}
+		}
+		for i := line; i < node.EndLine; i++ {
+			if i != line && strings.HasPrefix(strings.TrimSpace(lines[i]), "#") {
+				continue
+			}
+			if i != line {
+				start = 0
+			}
+			for _, occurrence := range referenceOccurrences(lines[i], i, start) {
+				occurrence.scope = instructionScope
+				occurrences = append(occurrences, occurrence)
+			}
+		}
+	}
+
+	globals := map[string]bool{}
+	for _, occurrence := range occurrences {
+		if occurrence.declaration && occurrence.scope == globalScope {
+			globals[occurrence.name] = true
+		}
+	}
+	for i := range occurrences {
+		if globals[occurrences[i].name] && redeclared[scopedName{name: occurrences[i].name, scope: occurrences[i].scope}] {
+			occurrences[i].scope = globalScope
+		}
+	}
+	return occurrences
+}
+
+// declarationOccurrences finds the names of the variables declared by
+// the given ARG or ENV instruction in the lines of the instruction.
+// Each name is searched for after the previous one so that names that
+// appear in the values of other variables are not matched.
+func declarationOccurrences(lines []string, node *parser.Node, start int) []variableOccurrence {
+	occurrences := []variableOccurrence{}
+	line := node.StartLine - 1
+	for _, v := range declaredVariables(node) {
+		for line < node.EndLine {
+			if idx := declarationIndex(lines[line], v.name, start); idx != -1 {
+				occurrences = append(occurrences, variableOccurrence{
+					name:        v.name,
+					declaration: true,
+					rng:         variableRange(line, idx, v.name),
+				})
+				start = idx + len(v.name)
+				break
+			}
+			line++
+			start = 0
+		}
+	}
+	return occurrences
+}
+
+// declarationIndex returns the offset of the given name in the line if
+// it is the name of a variable being declared or -1 if it is not found.
+func declarationIndex(line, name string, start int) int {
+	for start < len(line) {
+		idx := strings.Index(line[start:], name)
+		if idx == -1 {
+			return -1
+		}
+		idx += start
+		end := idx + len(name)
+		if (idx == 0 || line[idx-1] == ' ' || line[idx-1] == '\t') && (end == len(line) || strings.ContainsRune("= \t\r", rune(line[end]))) {
+			return idx
+		}
+		start = end
+	}
+	return -1
+}
+
+// referenceOccurrences finds the $VAR and ${VAR} references in the line
+// that come after the given offset.
+func referenceOccurrences(line string, lineNumber, start int) []variableOccurrence {
+	occurrences := []variableOccurrence{}
+	for i := start; i < len(line)-1; i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if line[i] != '$' {
+			continue
+		}
+
+		nameStart := i + 1
+		if line[nameStart] == '{' {
+			nameStart++
+		}
+		nameEnd := nameStart
+		for nameEnd < len(line) && isVariableCharacter(line[nameEnd]) {
+			nameEnd++
+		}
+		if nameEnd > nameStart && (line[nameStart] < '0' || line[nameStart] > '9') {
+			name := line[nameStart:nameEnd]
+			occurrences = append(occurrences, variableOccurrence{name: name, rng: variableRange(lineNumber, nameStart, name)})
+		}
+		i = nameEnd - 1
+	}
+	return occurrences
+}
+
+func variableRange(line, character int, name string) protocol.Range {
+	return protocol.Range{
+		Start: protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(character)},
+		End:   protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(character + len(name))},
+	}
+}
+
+// DocumentHighlight returns the declarations of and the references to
+// the ARG or ENV variable at the given position. Occurrences of a
+// variable in other build stages are not included unless they have
+// all redeclared the same global build argument. Nothing is returned
+// for variables that are not declared by the Dockerfile.
+func DocumentHighlight(doc document.DockerfileDocument, position protocol.Position) ([]protocol.DocumentHighlight, error) {
+	occurrences := variableOccurrences(doc)
+	for _, occurrence := range occurrences {
+		if occurrence.rng.Start.Line == position.Line && occurrence.rng.Start.Character <= position.Character && position.Character <= occurrence.rng.End.Character {
+			return variableHighlights(occurrences, occurrence.name, occurrence.scope), nil
+		}
+	}
+	return nil, nil
+}
+
+func variableHighlights(occurrences []variableOccurrence, name string, scope int) []protocol.DocumentHighlight {
+	declared := false
+	highlights := []protocol.DocumentHighlight{}
+	for _, occurrence := range occurrences {
+		if occurrence.name != name || occurrence.scope != scope {
+			continue
+		}
+		kind := protocol.DocumentHighlightKindRead
+		if occurrence.declaration {
+			kind = protocol.DocumentHighlightKindWrite
+			declared = true
+		}
+		highlights = append(highlights, protocol.DocumentHighlight{Range: occurrence.rng, Kind: &kind})
+	}
+	if !declared {
+		return nil
+	}
+	return highlights
+}
@@ -0,0 +1,155 @@
+package dockerfile
+
+import (
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func documentHighlight(kind protocol.DocumentHighlightKind, line, start, end protocol.UInteger) protocol.DocumentHighlight {
+	return protocol.DocumentHighlight{
+		Kind: &kind,
+		Range: protocol.Range{
+			Start: protocol.Position{Line: line, Character: start},
+			End:   protocol.Position{Line: line, Character: end},
+		},
+	}
+}
+
+func TestDocumentHighlight(t *testing.T) {
+	testCases := []struct {
+		name       string
+		content    string
+		line       protocol.UInteger
+		character  protocol.UInteger
+		highlights []protocol.DocumentHighlight
+	}{
+		{
+			name:      "ARG declared and referenced in a stage",
+			content:   "FROM alpine\nARG VERSION=1\nRUN echo $VERSION ${VERSION}",
+			line:      2,
+			character: 11,
+			highlights: []protocol.DocumentHighlight{
+				documentHighlight(protocol.DocumentHighlightKindWrite, 1, 4, 11),
+				documentHighlight(protocol.DocumentHighlightKindRead, 2, 10, 17),
+				documentHighlight(protocol.DocumentHighlightKindRead, 2, 20, 27),
+			},
+		},
+		{
+			name:      "cursor on the declaration",
+			content:   "FROM alpine\nARG VERSION=1\nRUN echo $VERSION ${VERSION}",
+			line:      1,
+			character: 4,
+			highlights: []protocol.DocumentHighlight{
+				documentHighlight(protocol.DocumentHighlightKindWrite, 1, 4, 11),
+				documentHighlight(protocol.DocumentHighlightKindRead, 2, 10, 17),
+				documentHighlight(protocol.DocumentHighlightKindRead, 2, 20, 27),
+			},
+		},
+		{
+			name:      "variables of other stages are not included",
+			content:   "FROM alpine AS a\nARG NAME=a\nRUN echo $NAME\nFROM alpine AS b\nARG NAME=b\nRUN echo $NAME",
+			line:      5,
+			character: 11,
+			highlights: []protocol.DocumentHighlight{
+				documentHighlight(protocol.DocumentHighlightKindWrite, 4, 4, 8),
+				documentHighlight(protocol.DocumentHighlightKindRead, 5, 10, 14),
+			},
+		},
+		{
+			name:      "global ARG redeclared in a stage",
+			content:   "ARG BASE=alpine\nARG VERSION=1\nFROM ${BASE}\nARG VERSION\nRUN echo $VERSION\nFROM ${BASE}\nRUN echo $VERSION",
+			line:      4,
+			character: 11,
+			highlights: []protocol.DocumentHighlight{
+				documentHighlight(protocol.DocumentHighlightKindWrite, 1, 4, 11),
+				documentHighlight(protocol.DocumentHighlightKindWrite, 3, 4, 11),
+				documentHighlight(protocol.DocumentHighlightKindRead, 4, 10, 17),
+			},
+		},
+		{
+			name:      "global ARG referenced by FROM instructions",
+			content:   "ARG BASE=alpine\nARG VERSION=1\nFROM ${BASE}\nARG VERSION\nRUN echo $VERSION\nFROM ${BASE}\nRUN echo $VERSION",
+			line:      2,
+			character: 8,
+			highlights: []protocol.DocumentHighlight{
+				documentHighlight(protocol.DocumentHighlightKindWrite, 0, 4, 8),
+				documentHighlight(protocol.DocumentHighlightKindRead, 2, 7, 11),
+				documentHighlight(protocol.DocumentHighlightKindRead, 5, 7, 11),
+			},
+		},
+		{
+			name:       "global ARG that has not been redeclared is out of scope",
+			content:    "ARG BASE=alpine\nARG VERSION=1\nFROM ${BASE}\nARG VERSION\nRUN echo $VERSION\nFROM ${BASE}\nRUN echo $VERSION",
+			line:       6,
+			character:  11,
+			highlights: nil,
+		},
+		{
+			name:      "ENV with multiple variables",
+			content:   "FROM alpine\nENV A=1 B=$A\nRUN echo $B",
+			line:      1,
+			character: 8,
+			highlights: []protocol.DocumentHighlight{
+				documentHighlight(protocol.DocumentHighlightKindWrite, 1, 8, 9),
+				documentHighlight(protocol.DocumentHighlightKindRead, 2, 10, 11),
+			},
+		},
+		{
+			name:      "ENV referencing another variable in its value",
+			content:   "FROM alpine\nENV A=1 B=$A\nRUN echo $B",
+			line:      1,
+			character: 11,
+			highlights: []protocol.DocumentHighlight{
+				documentHighlight(protocol.DocumentHighlightKindWrite, 1, 4, 5),
+				documentHighlight(protocol.DocumentHighlightKindRead, 1, 11, 12),
+			},
+		},
+		{
+			name:      "escaped dollar sign is not a reference",
+			content:   "FROM alpine\nARG A\nRUN echo \\$A $A",
+			line:      2,
+			character: 14,
+			highlights: []protocol.DocumentHighlight{
+				documentHighlight(protocol.DocumentHighlightKindWrite, 1, 4, 5),
+				documentHighlight(protocol.DocumentHighlightKindRead, 2, 14, 15),
+			},
+		},
+		{
+			name:      "ARG spanning multiple lines",
+			content:   "FROM alpine\nARG A=1 \\\n    B=2\nRUN echo $B",
+			line:      3,
+			character: 10,
+			highlights: []protocol.DocumentHighlight{
+				documentHighlight(protocol.DocumentHighlightKindWrite, 2, 4, 5),
+				documentHighlight(protocol.DocumentHighlightKindRead, 3, 10, 11),
+			},
+		},
+		{
+			name:       "undeclared variable",
+			content:    "FROM alpine\nRUN echo $HOME",
+			line:       1,
+			character:  11,
+			highlights: nil,
+		},
+		{
+			name:       "cursor not on a variable",
+			content:    "FROM alpine\nARG A\nRUN echo $A",
+			line:       2,
+			character:  2,
+			highlights: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewDockerfileDocument(uri.URI("file:///tmp/Dockerfile"), 1, []byte(tc.content))
+			highlights, err := DocumentHighlight(doc, protocol.Position{Line: tc.line, Character: tc.character})
+			require.NoError(t, err)
+			require.Equal(t, tc.highlights, highlights)
+		})
+	}
+}
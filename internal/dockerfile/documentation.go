@@ -0,0 +1,105 @@
+package dockerfile
+
+// flagValue describes an allowed value or an option of a flag.
+type flagValue struct {
+	name          string
+	aliases       []string
+	documentation string
+}
+
+type flagDocumentation struct {
+	documentation string
+	anchor        string
+	values        []flagValue
+}
+
+type mountTypeDocumentation struct {
+	documentation string
+	options       []flagValue
+}
+
+const onlineDocumentation = "https://docs.docker.com/reference/dockerfile/"
+
+var runFlags = map[string]flagDocumentation{
+	"--mount": {
+		documentation: "Creates a filesystem mount that the build step can access.",
+		anchor:        "run---mount",
+	},
+	"--network": {
+		documentation: "Controls which networking environment the command is run in.",
+		anchor:        "run---network",
+		values: []flagValue{
+			{name: "default", documentation: "Run in the default network."},
+			{name: "none", documentation: "Run with no network access."},
+			{name: "host", documentation: "Run in the host's network environment."},
+		},
+	},
+	"--security": {
+		documentation: "Controls the security mode that the command is run in.",
+		anchor:        "run---security",
+		values: []flagValue{
+			{name: "sandbox", documentation: "Run in the default sandbox."},
+			{name: "insecure", documentation: "Run without the sandbox, equivalent to a privileged container. Requires the `security.insecure` entitlement."},
+		},
+	},
+}
+
+// mountTypes lists the types of mounts that can be created by a RUN
+// instruction's --mount flag in the order that they are documented.
+var mountTypes = []string{"bind", "cache", "tmpfs", "secret", "ssh"}
+
+var mountTypeDocumentations = map[string]mountTypeDocumentation{
+	"bind": {
+		documentation: "Bind-mounts files or directories from the build context or another stage. This is the default mount type.",
+		options: []flagValue{
+			{name: "target", aliases: []string{"dst", "destination"}, documentation: "Mount path."},
+			{name: "source", documentation: "Source path in the `from`. Defaults to the root of the `from`."},
+			{name: "from", documentation: "Build stage, context, or image name for the root of the source. Defaults to the build context."},
+			{name: "rw", aliases: []string{"readwrite"}, documentation: "Allow writes on the mount. Written data will be discarded."},
+		},
+	},
+	"cache": {
+		documentation: "Mounts a directory that persists between builds to cache content for compilers and package managers.",
+		options: []flagValue{
+			{name: "id", documentation: "Optional ID to identify separate caches. Defaults to the value of `target`."},
+			{name: "target", aliases: []string{"dst", "destination"}, documentation: "Mount path."},
+			{name: "ro", aliases: []string{"readonly"}, documentation: "Read-only if set."},
+			{name: "sharing", documentation: "One of `shared`, `private`, or `locked`. Defaults to `shared`. A `shared` cache mount can be used concurrently by multiple writers. `private` creates a new mount if there are multiple writers. `locked` pauses the second writer until the first one releases the mount."},
+			{name: "from", documentation: "Build stage, context, or image name to use as a base of the cache mount. Defaults to an empty directory."},
+			{name: "source", documentation: "Subpath in the `from` to mount. Defaults to the root of the `from`."},
+			{name: "mode", documentation: "File mode for a new cache directory in octal. Defaults to `0755`."},
+			{name: "uid", documentation: "User ID for a new cache directory. Defaults to `0`."},
+			{name: "gid", documentation: "Group ID for a new cache directory. Defaults to `0`."},
+		},
+	},
+	"tmpfs": {
+		documentation: "Mounts a `tmpfs` filesystem in the build container.",
+		options: []flagValue{
+			{name: "target", aliases: []string{"dst", "destination"}, documentation: "Mount path."},
+			{name: "size", documentation: "Upper limit on the size of the filesystem."},
+		},
+	},
+	"secret": {
+		documentation: "Gives the build container access to secure files such as private keys without baking them into the image or build cache.",
+		options: []flagValue{
+			{name: "id", documentation: "ID of the secret. Defaults to the basename of the target path."},
+			{name: "target", aliases: []string{"dst", "destination"}, documentation: "Mount the secret to the specified path. Defaults to `/run/secrets/` + `id` if unset and if `env` is also unset."},
+			{name: "env", documentation: "Mount the secret to an environment variable instead of a file, or both."},
+			{name: "required", documentation: "If set to `true`, the instruction errors out when the secret is unavailable. Defaults to `false`."},
+			{name: "mode", documentation: "File mode for the secret file in octal. Defaults to `0400`."},
+			{name: "uid", documentation: "User ID for the secret file. Defaults to `0`."},
+			{name: "gid", documentation: "Group ID for the secret file. Defaults to `0`."},
+		},
+	},
+	"ssh": {
+		documentation: "Gives the build container access to SSH keys through SSH agents, with support for passphrases.",
+		options: []flagValue{
+			{name: "id", documentation: "ID of the SSH agent socket or key. Defaults to `default`."},
+			{name: "target", aliases: []string{"dst", "destination"}, documentation: "SSH agent socket path. Defaults to `/run/buildkit/ssh_agent.${N}`."},
+			{name: "required", documentation: "If set to `true`, the instruction errors out when the key is unavailable. Defaults to `false`."},
+			{name: "mode", documentation: "File mode for the socket in octal. Defaults to `0600`."},
+			{name: "uid", documentation: "User ID for the socket. Defaults to `0`."},
+			{name: "gid", documentation: "Group ID for the socket. Defaults to `0`."},
+		},
+	},
+}
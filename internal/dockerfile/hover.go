@@ -0,0 +1,165 @@
+package dockerfile
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+)
+
+func Hover(ctx context.Context, params *protocol.HoverParams, doc document.DockerfileDocument) (*protocol.Hover, error) {
+	instruction := doc.Instruction(params.Position)
+	if instruction == nil || !strings.EqualFold(instruction.Value, "RUN") {
+		return nil, nil
+	}
+
+	lines := strings.Split(string(doc.Input()), "\n")
+	if int(params.Position.Line) >= len(lines) {
+		return nil, nil
+	}
+
+	keyword := ""
+	if instruction.StartLine == int(params.Position.Line)+1 {
+		keyword = instruction.Value
+	}
+	text := strings.TrimSuffix(lines[params.Position.Line], "\r")
+	character := protocol.Position{Character: params.Position.Character}.IndexIn(text)
+	for _, flag := range parseFlags(text, keyword) {
+		if flag.start <= character && character < flag.end && slices.Contains(instruction.Flags, flag.raw) {
+			return flagHover(params.Position.Line, text, flag, character), nil
+		}
+	}
+	return nil, nil
+}
+
+func flagHover(line protocol.UInteger, text string, flag instructionFlag, character int) *protocol.Hover {
+	documentation, ok := runFlags[flag.name]
+	if !ok {
+		return nil
+	}
+
+	if flag.name == "--mount" {
+		return mountFlagHover(line, text, flag, character, documentation)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("**%v**\n\n%v\n\nAllowed values:\n", flag.name, documentation.documentation))
+	for _, value := range documentation.values {
+		builder.WriteString(fmt.Sprintf("- `%v`: %v\n", value.name, value.documentation))
+	}
+	builder.WriteString(fmt.Sprintf("\n[Online documentation](%v#%v)", onlineDocumentation, documentation.anchor))
+	return createHover(builder.String(), line, text, flag.start, flag.end)
+}
+
+// mountFlagHover describes the option of the --mount flag under the
+// cursor or the flag itself along with the options of its mount type.
+func mountFlagHover(line protocol.UInteger, text string, flag instructionFlag, character int, documentation flagDocumentation) *protocol.Hover {
+	mountType, ok := flag.option("type")
+	if !ok {
+		mountType = "bind"
+	}
+
+	for _, option := range flag.options {
+		if option.start <= character && character < option.end {
+			if option.key == "type" {
+				if _, ok := mountTypeDocumentations[option.value]; ok {
+					return createHover(mountTypeMarkdown(option.value)+mountTypeLink(option.value), line, text, option.start, option.end)
+				}
+				return createHover(mountTypesMarkdown()+mountTypeLink(""), line, text, option.start, option.end)
+			}
+			if value := mountOption(mountType, option.key); value != nil {
+				return createHover(mountOptionMarkdown(mountType, *value)+mountTypeLink(mountType), line, text, option.start, option.end)
+			}
+			return nil
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("**%v**\n\n%v\n\n", flag.name, documentation.documentation))
+	builder.WriteString(mountTypesMarkdown())
+	if _, ok := mountTypeDocumentations[mountType]; ok {
+		builder.WriteString("\n")
+		builder.WriteString(mountTypeMarkdown(mountType))
+	}
+	builder.WriteString(mountTypeLink(""))
+	return createHover(builder.String(), line, text, flag.start, flag.end)
+}
+
+func mountOption(mountType, key string) *flagValue {
+	if documentation, ok := mountTypeDocumentations[mountType]; ok {
+		for i := range documentation.options {
+			if documentation.options[i].name == key || slices.Contains(documentation.options[i].aliases, key) {
+				return &documentation.options[i]
+			}
+		}
+	}
+	return nil
+}
+
+func mountTypesMarkdown() string {
+	var builder strings.Builder
+	builder.WriteString("Mount types:\n")
+	for _, mountType := range mountTypes {
+		builder.WriteString(fmt.Sprintf("- `%v`: %v\n", mountType, mountTypeDocumentations[mountType].documentation))
+	}
+	return builder.String()
+}
+
+func mountTypeMarkdown(mountType string) string {
+	documentation := mountTypeDocumentations[mountType]
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("**type=%v**\n\n%v\n\nOptions:\n", mountType, documentation.documentation))
+	for _, option := range documentation.options {
+		builder.WriteString(fmt.Sprintf("- %v: %v\n", optionNames(option), option.documentation))
+	}
+	return builder.String()
+}
+
+func mountOptionMarkdown(mountType string, option flagValue) string {
+	return fmt.Sprintf("**%v** (`type=%v`)\n\n%v\n", option.name, mountType, option.documentation)
+}
+
+// mountTypeLink returns a link to the online documentation of the
+// given mount type or of the --mount flag if no type is specified.
+func mountTypeLink(mountType string) string {
+	if mountType == "" {
+		return fmt.Sprintf("\n[Online documentation](%v#run---mount)", onlineDocumentation)
+	}
+	return fmt.Sprintf("\n[Online documentation](%v#run---mounttype%v)", onlineDocumentation, mountType)
+}
+
+func optionNames(option flagValue) string {
+	names := []string{fmt.Sprintf("`%v`", option.name)}
+	for _, alias := range option.aliases {
+		names = append(names, fmt.Sprintf("`%v`", alias))
+	}
+	return strings.Join(names, ", ")
+}
+
+// createHover creates a hover for the given byte offsets of the line's
+// text. The offsets are converted into UTF-16 code units as the LSP
+// specification requires.
+func createHover(value string, line protocol.UInteger, text string, start, end int) *protocol.Hover {
+	return &protocol.Hover{
+		Contents: protocol.MarkupContent{
+			Kind:  protocol.MarkupKindMarkdown,
+			Value: value,
+		},
+		Range: &protocol.Range{
+			Start: protocol.Position{Line: line, Character: utf16Length(text[:start])},
+			End:   protocol.Position{Line: line, Character: utf16Length(text[:end])},
+		},
+	}
+}
+
+func utf16Length(s string) protocol.UInteger {
+	length := protocol.UInteger(0)
+	for _, r := range s {
+		length += protocol.UInteger(utf16.RuneLen(r))
+	}
+	return length
+}
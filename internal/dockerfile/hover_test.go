@@ -0,0 +1,167 @@
+package dockerfile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestHover(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      protocol.UInteger
+		character protocol.UInteger
+		result    *protocol.Hover
+	}{
+		{
+			name:      "--network flag",
+			content:   "FROM scratch\nRUN --network=none ls",
+			line:      1,
+			character: 6,
+			result: hover(
+				"**--network**\n\nControls which networking environment the command is run in.\n\nAllowed values:\n- `default`: Run in the default network.\n- `none`: Run with no network access.\n- `host`: Run in the host's network environment.\n\n[Online documentation](https://docs.docker.com/reference/dockerfile/#run---network)",
+				1, 4, 18,
+			),
+		},
+		{
+			name:      "--security flag",
+			content:   "FROM scratch\nrun --security=insecure ls",
+			line:      1,
+			character: 20,
+			result: hover(
+				"**--security**\n\nControls the security mode that the command is run in.\n\nAllowed values:\n- `sandbox`: Run in the default sandbox.\n- `insecure`: Run without the sandbox, equivalent to a privileged container. Requires the `security.insecure` entitlement.\n\n[Online documentation](https://docs.docker.com/reference/dockerfile/#run---security)",
+				1, 4, 23,
+			),
+		},
+		{
+			name:      "--mount flag name without a type defaults to bind",
+			content:   "FROM scratch\nRUN --mount=target=/src ls",
+			line:      1,
+			character: 5,
+			result: hover(
+				"**--mount**\n\nCreates a filesystem mount that the build step can access.\n\n"+mountTypesMarkdown()+"\n"+mountTypeMarkdown("bind")+"\n[Online documentation](https://docs.docker.com/reference/dockerfile/#run---mount)",
+				1, 4, 23,
+			),
+		},
+		{
+			name:      "--mount type option",
+			content:   "FROM scratch\nRUN --mount=type=cache,target=/root/.cache ls",
+			line:      1,
+			character: 14,
+			result: hover(
+				"**type=cache**\n\nMounts a directory that persists between builds to cache content for compilers and package managers.\n\nOptions:\n- `id`: Optional ID to identify separate caches. Defaults to the value of `target`.\n- `target`, `dst`, `destination`: Mount path.\n- `ro`, `readonly`: Read-only if set.\n- `sharing`: One of `shared`, `private`, or `locked`. Defaults to `shared`. A `shared` cache mount can be used concurrently by multiple writers. `private` creates a new mount if there are multiple writers. `locked` pauses the second writer until the first one releases the mount.\n- `from`: Build stage, context, or image name to use as a base of the cache mount. Defaults to an empty directory.\n- `source`: Subpath in the `from` to mount. Defaults to the root of the `from`.\n- `mode`: File mode for a new cache directory in octal. Defaults to `0755`.\n- `uid`: User ID for a new cache directory. Defaults to `0`.\n- `gid`: Group ID for a new cache directory. Defaults to `0`.\n\n[Online documentation](https://docs.docker.com/reference/dockerfile/#run---mounttypecache)",
+				1, 12, 22,
+			),
+		},
+		{
+			name:      "--mount option uses the mount type's documentation",
+			content:   "FROM scratch\nRUN --mount=type=cache,sharing=locked ls",
+			line:      1,
+			character: 25,
+			result: hover(
+				"**sharing** (`type=cache`)\n\nOne of `shared`, `private`, or `locked`. Defaults to `shared`. A `shared` cache mount can be used concurrently by multiple writers. `private` creates a new mount if there are multiple writers. `locked` pauses the second writer until the first one releases the mount.\n\n[Online documentation](https://docs.docker.com/reference/dockerfile/#run---mounttypecache)",
+				1, 23, 37,
+			),
+		},
+		{
+			name:      "--mount option alias",
+			content:   "FROM scratch\nRUN --mount=type=secret,id=npmrc,dst=/root/.npmrc ls",
+			line:      1,
+			character: 34,
+			result: hover(
+				"**target** (`type=secret`)\n\nMount the secret to the specified path. Defaults to `/run/secrets/` + `id` if unset and if `env` is also unset.\n\n[Online documentation](https://docs.docker.com/reference/dockerfile/#run---mounttypesecret)",
+				1, 33, 49,
+			),
+		},
+		{
+			name:      "unknown option of a mount type",
+			content:   "FROM scratch\nRUN --mount=type=tmpfs,sharing=locked ls",
+			line:      1,
+			character: 25,
+			result:    nil,
+		},
+		{
+			name:      "flag on a continuation line",
+			content:   "FROM scratch\nRUN --mount=type=ssh \\\n  --network=host ls",
+			line:      2,
+			character: 4,
+			result: hover(
+				"**--network**\n\nControls which networking environment the command is run in.\n\nAllowed values:\n- `default`: Run in the default network.\n- `none`: Run with no network access.\n- `host`: Run in the host's network environment.\n\n[Online documentation](https://docs.docker.com/reference/dockerfile/#run---network)",
+				2, 2, 16,
+			),
+		},
+		{
+			name:      "arguments of the command are ignored",
+			content:   "FROM scratch\nRUN ls --network=none",
+			line:      1,
+			character: 10,
+			result:    nil,
+		},
+		{
+			name:      "flags of other instructions are ignored",
+			content:   "FROM --platform=linux/amd64 scratch",
+			line:      0,
+			character: 8,
+			result:    nil,
+		},
+		{
+			name:      "non-ASCII characters before a flag",
+			content:   "FROM scratch\nRUN --mount=target=/café --network=none ls",
+			line:      1,
+			character: 26,
+			result: hover(
+				"**--network**\n\nControls which networking environment the command is run in.\n\nAllowed values:\n- `default`: Run in the default network.\n- `none`: Run with no network access.\n- `host`: Run in the host's network environment.\n\n[Online documentation](https://docs.docker.com/reference/dockerfile/#run---network)",
+				1, 25, 39,
+			),
+		},
+		{
+			name:      "surrogate pairs before a flag",
+			content:   "FROM scratch\nRUN --mount=target=/😀 --network=none ls",
+			line:      1,
+			character: 23,
+			result: hover(
+				"**--network**\n\nControls which networking environment the command is run in.\n\nAllowed values:\n- `default`: Run in the default network.\n- `none`: Run with no network access.\n- `host`: Run in the host's network environment.\n\n[Online documentation](https://docs.docker.com/reference/dockerfile/#run---network)",
+				1, 23, 37,
+			),
+		},
+		{
+			name:      "RUN keyword",
+			content:   "FROM scratch\nRUN --network=none ls",
+			line:      1,
+			character: 1,
+			result:    nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewDockerfileDocument(uri.URI("file:///tmp/Dockerfile"), 1, []byte(tc.content))
+			result, err := Hover(context.Background(), &protocol.HoverParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: "file:///tmp/Dockerfile"},
+					Position:     protocol.Position{Line: tc.line, Character: tc.character},
+				},
+			}, doc)
+			require.NoError(t, err)
+			require.Equal(t, tc.result, result)
+		})
+	}
+}
+
+func hover(value string, line protocol.UInteger, start, end protocol.UInteger) *protocol.Hover {
+	return &protocol.Hover{
+		Contents: protocol.MarkupContent{
+			Kind:  protocol.MarkupKindMarkdown,
+			Value: value,
+		},
+		Range: &protocol.Range{
+			Start: protocol.Position{Line: line, Character: start},
+			End:   protocol.Position{Line: line, Character: end},
+		},
+	}
+}
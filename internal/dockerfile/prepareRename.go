@@ -0,0 +1,21 @@
+package dockerfile
+
+import (
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+)
+
+func PrepareRename(doc document.DockerfileDocument, params *protocol.PrepareRenameParams) (*protocol.Range, error) {
+	highlights, err := DocumentHighlight(doc, params.Position)
+	if err != nil || len(highlights) == 0 {
+		return nil, err
+	}
+
+	for _, highlight := range highlights {
+		r := highlight.Range
+		if r.Start.Line == params.Position.Line && r.Start.Character <= params.Position.Character && params.Position.Character <= r.End.Character {
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
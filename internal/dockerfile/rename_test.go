@@ -0,0 +1,93 @@
+package dockerfile
+
+import (
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+func TestRename(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		line      protocol.UInteger
+		character protocol.UInteger
+		prepare   *protocol.Range
+		edits     []protocol.TextEdit
+	}{
+		{
+			name:      "global ARG redeclared in multiple stages",
+			content:   "ARG VERSION=1\nFROM alpine\nARG VERSION\nRUN echo $VERSION\nFROM alpine\nARG VERSION\nRUN echo ${VERSION}",
+			line:      3,
+			character: 12,
+			prepare: &protocol.Range{
+				Start: protocol.Position{Line: 3, Character: 10},
+				End:   protocol.Position{Line: 3, Character: 17},
+			},
+			edits: []protocol.TextEdit{
+				{NewText: "RENAMED", Range: variableRange(0, 4, "VERSION")},
+				{NewText: "RENAMED", Range: variableRange(2, 4, "VERSION")},
+				{NewText: "RENAMED", Range: variableRange(3, 10, "VERSION")},
+				{NewText: "RENAMED", Range: variableRange(5, 4, "VERSION")},
+				{NewText: "RENAMED", Range: variableRange(6, 11, "VERSION")},
+			},
+		},
+		{
+			name:      "stage variable with the same name as another stage's",
+			content:   "FROM alpine AS a\nENV NAME=a\nRUN echo $NAME\nFROM alpine AS b\nENV NAME=b\nRUN echo $NAME",
+			line:      1,
+			character: 6,
+			prepare: &protocol.Range{
+				Start: protocol.Position{Line: 1, Character: 4},
+				End:   protocol.Position{Line: 1, Character: 8},
+			},
+			edits: []protocol.TextEdit{
+				{NewText: "RENAMED", Range: variableRange(1, 4, "NAME")},
+				{NewText: "RENAMED", Range: variableRange(2, 10, "NAME")},
+			},
+		},
+		{
+			name:      "undeclared variable cannot be renamed",
+			content:   "FROM alpine\nRUN echo $HOME",
+			line:      1,
+			character: 11,
+			prepare:   nil,
+			edits:     nil,
+		},
+	}
+
+	u := "file:///tmp/Dockerfile"
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := document.NewDockerfileDocument(uri.URI(u), 1, []byte(tc.content))
+			position := protocol.Position{Line: tc.line, Character: tc.character}
+			rng, err := PrepareRename(doc, &protocol.PrepareRenameParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: u},
+					Position:     position,
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.prepare, rng)
+
+			edit, err := Rename(doc, &protocol.RenameParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: u},
+					Position:     position,
+				},
+				NewName: "RENAMED",
+			})
+			require.NoError(t, err)
+			if tc.edits == nil {
+				require.Nil(t, edit)
+			} else {
+				require.Equal(t, &protocol.WorkspaceEdit{
+					Changes: map[protocol.DocumentUri][]protocol.TextEdit{u: tc.edits},
+				}, edit)
+			}
+		})
+	}
+}
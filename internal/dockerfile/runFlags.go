@@ -0,0 +1,110 @@
+package dockerfile
+
+import (
+	"strings"
+	"unicode"
+)
+
+// flagOption is a key=value pair in the value of a flag such as the
+// type=cache in --mount=type=cache,target=/root/.cache. The offsets are
+// relative to the start of the line that the flag is on.
+type flagOption struct {
+	key   string
+	value string
+	start int
+	end   int
+}
+
+// instructionFlag is a flag of an instruction such as
+// --mount=type=cache,target=/root/.cache. The offsets are relative to
+// the start of the line that the flag is on.
+type instructionFlag struct {
+	raw     string
+	name    string
+	value   string
+	start   int
+	end     int
+	options []flagOption
+}
+
+// parseFlags returns the flags found on the given line of an
+// instruction. If keyword is not empty then the line is expected to
+// start with the instruction's keyword which may be made up of several
+// words such as ONBUILD COPY. Parsing stops at the first word that is
+// not a flag.
+func parseFlags(line, keyword string) []instructionFlag {
+	flags := []instructionFlag{}
+	words := splitWords(line)
+	for _, k := range strings.Fields(keyword) {
+		if len(words) == 0 || !strings.EqualFold(line[words[0][0]:words[0][1]], k) {
+			return flags
+		}
+		words = words[1:]
+	}
+
+	for _, word := range words {
+		text := line[word[0]:word[1]]
+		if text == "\\" {
+			continue
+		}
+		if !strings.HasPrefix(text, "--") {
+			break
+		}
+		flags = append(flags, parseFlag(text, word[0]))
+	}
+	return flags
+}
+
+func parseFlag(text string, start int) instructionFlag {
+	flag := instructionFlag{raw: text, name: text, start: start, end: start + len(text)}
+	idx := strings.Index(text, "=")
+	if idx == -1 {
+		return flag
+	}
+
+	flag.name = text[:idx]
+	flag.value = text[idx+1:]
+	offset := start + idx + 1
+	for _, field := range strings.Split(flag.value, ",") {
+		option := flagOption{key: field, start: offset, end: offset + len(field)}
+		if keyValue := strings.SplitN(field, "=", 2); len(keyValue) == 2 {
+			option.key = keyValue[0]
+			option.value = keyValue[1]
+		}
+		flag.options = append(flag.options, option)
+		offset += len(field) + 1
+	}
+	return flag
+}
+
+// splitWords returns the start and end offsets of every whitespace
+// delimited word in the line.
+func splitWords(line string) [][2]int {
+	words := [][2]int{}
+	start := -1
+	for i, r := range line {
+		if unicode.IsSpace(r) {
+			if start != -1 {
+				words = append(words, [2]int{start, i})
+				start = -1
+			}
+		} else if start == -1 {
+			start = i
+		}
+	}
+	if start != -1 {
+		words = append(words, [2]int{start, len(line)})
+	}
+	return words
+}
+
+// option returns the value of the named option and whether it was
+// set.
+func (f *instructionFlag) option(key string) (string, bool) {
+	for _, option := range f.options {
+		if option.key == key {
+			return option.value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,110 @@
+package dockerfile
+
+import (
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// variable is a build argument or an environment variable that has
+// been declared by an ARG or ENV instruction. The value will be nil if
+// the ARG instruction did not specify a default value.
+type variable struct {
+	name        string
+	value       *string
+	instruction *parser.Node
+}
+
+// declaredVariables returns the variables declared by the given ARG or
+// ENV instruction in the order that they were declared.
+func declaredVariables(node *parser.Node) []variable {
+	variables := []variable{}
+	switch strings.ToUpper(node.Value) {
+	case "ARG":
+		for next := node.Next; next != nil; next = next.Next {
+			if name, value, ok := strings.Cut(next.Value, "="); ok {
+				value = unquote(value)
+				variables = append(variables, variable{name: name, value: &value, instruction: node})
+			} else {
+				variables = append(variables, variable{name: name, instruction: node})
+			}
+		}
+	case "ENV":
+		// the parser creates a name, value, and separator node for
+		// each environment variable
+		next := node.Next
+		for next != nil && next.Next != nil {
+			value := unquote(next.Next.Value)
+			variables = append(variables, variable{name: next.Value, value: &value, instruction: node})
+			if next.Next.Next == nil {
+				break
+			}
+			next = next.Next.Next.Next
+		}
+	}
+	return variables
+}
+
+// variablesInScope returns the variables that can be referenced by the
+// given instruction. Global build arguments declared before the first
+// FROM instruction are only in scope for FROM instructions while the
+// variables of a build stage are only in scope for the instructions
+// that follow them in the same stage. A build argument that is
+// redeclared in a stage without a value inherits the global default.
+// If a variable has been declared more than once then only the last
+// declaration is returned.
+func variablesInScope(nodes []*parser.Node, instruction *parser.Node) []variable {
+	global := []variable{}
+	stage := []variable{}
+	inStage := false
+	for _, node := range nodes {
+		if node.StartLine >= instruction.StartLine {
+			break
+		}
+		if strings.EqualFold(node.Value, "FROM") {
+			inStage = true
+			stage = []variable{}
+			continue
+		}
+		if inStage {
+			for _, v := range declaredVariables(node) {
+				if v.value == nil {
+					for _, g := range global {
+						if g.name == v.name {
+							v.value = g.value
+						}
+					}
+				}
+				stage = append(stage, v)
+			}
+		} else if strings.EqualFold(node.Value, "ARG") {
+			global = append(global, declaredVariables(node)...)
+		}
+	}
+
+	if !inStage || strings.EqualFold(instruction.Value, "FROM") {
+		return latestDeclarations(global)
+	}
+	return latestDeclarations(stage)
+}
+
+func latestDeclarations(variables []variable) []variable {
+	latest := []variable{}
+	indices := map[string]int{}
+	for _, v := range variables {
+		if idx, ok := indices[v.name]; ok {
+			latest[idx] = v
+		} else {
+			indices[v.name] = len(latest)
+			latest = append(latest, v)
+		}
+	}
+	return latest
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -250,6 +250,7 @@ func lintWithBuildKitBinary(contextPath, source string, doc document.DockerfileD
 	if output.BuildError != nil && shouldReport(output.BuildError.Message) {
 		diagnostic := protocol.Diagnostic{
 			Range:    createRange(lines, &output.BuildError.Location),
+			Code:     &protocol.IntegerOrString{Value: "BuildError"},
 			Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 			Message:  output.BuildError.Message,
 			Source:   types.CreateStringPointer(source),
@@ -354,6 +355,12 @@ func (c *BuildKitDiagnosticsCollector) CollectDiagnostics(source, workspaceFolde
 	return diagnostics
 }
 
+// Deferrable returns true as linting requires running a build with
+// Buildx.
+func (c *BuildKitDiagnosticsCollector) Deferrable() bool {
+	return true
+}
+
 func (c *BuildKitDiagnosticsCollector) SupportsLanguageIdentifier(languageIdentifier protocol.LanguageIdentifier) bool {
 	return languageIdentifier == protocol.DockerfileLanguage
 }
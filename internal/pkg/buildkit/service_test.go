@@ -40,6 +40,7 @@ func TestParse(t *testing.T) {
 						End:   protocol.Position{Line: 0, Character: 0},
 					},
 					Message:  "the Dockerfile cannot be empty",
+					Code:     &protocol.IntegerOrString{Value: "BuildError"},
 					Source:   types.CreateStringPointer("buildkit-testing-source"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 				},
@@ -56,6 +57,7 @@ func TestParse(t *testing.T) {
 						End:   protocol.Position{Line: 1, Character: 11},
 					},
 					Message:  "dockerfile parse error on line 2: unknown instruction: UNKNOWN",
+					Code:     &protocol.IntegerOrString{Value: "BuildError"},
 					Source:   types.CreateStringPointer("buildkit-testing-source"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 				},
@@ -72,6 +74,7 @@ func TestParse(t *testing.T) {
 						End:   protocol.Position{Line: 0, Character: 36},
 					},
 					Message:  "dockerfile parse error on line 1: unknown flag: --platform2 (did you mean platform?)",
+					Code:     &protocol.IntegerOrString{Value: "BuildError"},
 					Source:   types.CreateStringPointer("buildkit-testing-source"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Data: []types.NamedEdit{
@@ -94,6 +97,7 @@ func TestParse(t *testing.T) {
 						End:   protocol.Position{Line: 0, Character: 30},
 					},
 					Message:  "dockerfile parse error on line 1: unknown flag: --abc",
+					Code:     &protocol.IntegerOrString{Value: "BuildError"},
 					Source:   types.CreateStringPointer("buildkit-testing-source"),
 					Severity: types.CreateDiagnosticSeverityPointer(protocol.DiagnosticSeverityError),
 					Data: []types.NamedEdit{
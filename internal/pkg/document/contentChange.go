@@ -0,0 +1,58 @@
+package document
+
+import (
+	"unicode/utf8"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+)
+
+// ApplyContentChange replaces the text within the given range of the
+// input with the provided text. Positions that extend beyond the end
+// of a line or the end of the input are clamped.
+func ApplyContentChange(input []byte, rng protocol.Range, text string) []byte {
+	start := offset(input, rng.Start)
+	end := offset(input, rng.End)
+	if end < start {
+		start, end = end, start
+	}
+
+	result := make([]byte, 0, len(input)-(end-start)+len(text))
+	result = append(result, input[:start]...)
+	result = append(result, text...)
+	return append(result, input[end:]...)
+}
+
+// offset converts an LSP position into a byte offset of the input. The
+// character of the position is interpreted as a count of UTF-16 code
+// units as required by the specification.
+func offset(input []byte, position protocol.Position) int {
+	idx := 0
+	for line := protocol.UInteger(0); line < position.Line; line++ {
+		newline := -1
+		for i := idx; i < len(input); i++ {
+			if input[i] == '\n' {
+				newline = i
+				break
+			}
+		}
+		if newline == -1 {
+			return len(input)
+		}
+		idx = newline + 1
+	}
+
+	units := protocol.UInteger(0)
+	for idx < len(input) && units < position.Character {
+		r, size := utf8.DecodeRune(input[idx:])
+		if r == '\n' || (r == '\r' && idx+1 < len(input) && input[idx+1] == '\n') {
+			break
+		}
+		if r >= 0x10000 {
+			units += 2
+		} else {
+			units++
+		}
+		idx += size
+	}
+	return idx
+}
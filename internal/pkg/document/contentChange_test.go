@@ -0,0 +1,75 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyContentChange(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		rng    protocol.Range
+		text   string
+		result string
+	}{
+		{
+			name:   "insert at the start",
+			input:  "FROM alpine",
+			rng:    protocol.Range{Start: protocol.Position{Line: 0, Character: 0}, End: protocol.Position{Line: 0, Character: 0}},
+			text:   "# comment\n",
+			result: "# comment\nFROM alpine",
+		},
+		{
+			name:   "replace a word",
+			input:  "FROM alpine\nRUN ls",
+			rng:    protocol.Range{Start: protocol.Position{Line: 1, Character: 4}, End: protocol.Position{Line: 1, Character: 6}},
+			text:   "pwd",
+			result: "FROM alpine\nRUN pwd",
+		},
+		{
+			name:   "delete across lines",
+			input:  "FROM alpine\nRUN ls\nRUN pwd",
+			rng:    protocol.Range{Start: protocol.Position{Line: 0, Character: 11}, End: protocol.Position{Line: 1, Character: 6}},
+			text:   "",
+			result: "FROM alpine\nRUN pwd",
+		},
+		{
+			name:   "character beyond the end of the line is clamped",
+			input:  "FROM alpine\nRUN ls",
+			rng:    protocol.Range{Start: protocol.Position{Line: 0, Character: 100}, End: protocol.Position{Line: 0, Character: 100}},
+			text:   " AS base",
+			result: "FROM alpine AS base\nRUN ls",
+		},
+		{
+			name:   "line beyond the end of the input is clamped",
+			input:  "FROM alpine",
+			rng:    protocol.Range{Start: protocol.Position{Line: 5, Character: 0}, End: protocol.Position{Line: 5, Character: 0}},
+			text:   "\nRUN ls",
+			result: "FROM alpine\nRUN ls",
+		},
+		{
+			name:   "CRLF line endings",
+			input:  "FROM alpine\r\nRUN ls",
+			rng:    protocol.Range{Start: protocol.Position{Line: 0, Character: 11}, End: protocol.Position{Line: 1, Character: 0}},
+			text:   "",
+			result: "FROM alpineRUN ls",
+		},
+		{
+			name:   "characters are counted in UTF-16 code units",
+			input:  "LABEL a=\"\U0001F433é\" b=c",
+			rng:    protocol.Range{Start: protocol.Position{Line: 0, Character: 14}, End: protocol.Position{Line: 0, Character: 17}},
+			text:   "d=e",
+			result: "LABEL a=\"\U0001F433é\" d=e",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ApplyContentChange([]byte(tc.input), tc.rng, tc.text)
+			require.Equal(t, tc.result, string(result))
+		})
+	}
+}
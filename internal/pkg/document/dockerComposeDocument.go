@@ -2,6 +2,7 @@ package document
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"slices"
@@ -9,6 +10,7 @@ import (
 	"sync"
 
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml"
 	"github.com/goccy/go-yaml/ast"
 	"github.com/goccy/go-yaml/parser"
 	"go.lsp.dev/uri"
@@ -18,7 +20,20 @@ type ComposeDocument interface {
 	Document
 	File() *ast.File
 	ParsingError() error
+	// IgnoredLines returns the zero-based lines of the document that
+	// were ignored so that the rest of it could be parsed in spite of
+	// its syntax errors.
+	IgnoredLines() []int
 	IncludedFiles() (map[string]*ast.File, bool)
+	// IncludeCycle returns the chain of files from this document to the
+	// included file that includes a file of the chain again or nil if
+	// the included files do not form a cycle.
+	IncludeCycle() []uri.URI
+	// IsJSON returns true if the file is a JSON file instead of a YAML
+	// file as determined by its .json extension. JSON is parsed into the
+	// same YAML nodes as a YAML file that uses flow mappings so the
+	// content itself cannot tell them apart.
+	IsJSON() bool
 }
 
 type composeDocument struct {
@@ -27,9 +42,17 @@ type composeDocument struct {
 	mgr          *Manager
 	file         *ast.File
 	parsingError error
+	ignoredLines []int
 }
 
 func NewComposeDocument(mgr *Manager, u uri.URI, version int32, input []byte) ComposeDocument {
+	doc := newComposeDocument(mgr, u, version, input)
+	doc.document.parseFn(true)
+	return doc
+}
+
+// newComposeDocument creates a document that has not been parsed yet.
+func newComposeDocument(mgr *Manager, u uri.URI, version int32, input []byte) *composeDocument {
 	doc := &composeDocument{
 		document: document{
 			uri:        u,
@@ -44,7 +67,6 @@ func NewComposeDocument(mgr *Manager, u uri.URI, version int32, input []byte) Co
 	}
 	doc.document.copyFn = doc.copy
 	doc.document.parseFn = doc.parse
-	doc.document.parseFn(true)
 	return doc
 }
 
@@ -53,11 +75,97 @@ func (d *composeDocument) parse(_ bool) bool {
 	defer d.mutex.Unlock()
 
 	d.file, d.parsingError = parser.ParseBytes(d.input, parser.ParseComments)
+	d.ignoredLines = nil
+	if d.parsingError != nil {
+		d.file, d.ignoredLines = partialFile(d.input, d.parsingError)
+	}
 	return true
 }
 
+// recoveryParses is the number of times that a document may be parsed
+// when recovering its partial node tree. A large document with many
+// syntax errors would otherwise be parsed over and over again on every
+// change.
+const recoveryParses = 20
+
+// recoveryLookBehind is the number of lines before the line of a syntax
+// error that may also be the cause of it. The parser often only
+// reports an error on the line that follows the malformed one such as
+// when a flow sequence is not closed.
+const recoveryLookBehind = 3
+
+// partialFile parses the given input with the lines that cause syntax
+// errors blanked out so that the rest of the document can still be
+// used. Blanking a line keeps the positions of the other lines intact.
+// The line of an error and the few lines before it are blanked one at
+// a time and if none of them fixes the error then the error's line is
+// left blanked while the next error is searched for. The blanked lines
+// are returned with the node tree. Nil is returned if the document
+// could not be recovered within the allowed number of parses.
+func partialFile(input []byte, err error) (*ast.File, []int) {
+	lines := strings.Split(string(input), "\n")
+	blankedLines := []int{}
+	parses := 0
+	for parses < recoveryParses {
+		var syntaxError *yaml.SyntaxError
+		if !errors.As(err, &syntaxError) || syntaxError.Token == nil {
+			return nil, nil
+		}
+		errorLine := syntaxError.Token.Position.Line - 1
+		if errorLine < 0 || errorLine >= len(lines) {
+			return nil, nil
+		}
+
+		// the error of the input with only the error's line blanked is
+		// the next error to recover from if no line fixes this one
+		err = nil
+		for line := errorLine; line >= 0 && line >= errorLine-recoveryLookBehind && parses < recoveryParses; line-- {
+			blanked := slices.Clone(lines)
+			blanked[line] = ""
+			file, lineErr := parser.ParseBytes([]byte(strings.Join(blanked, "\n")), parser.ParseComments)
+			parses++
+			if lineErr == nil {
+				return file, append(blankedLines, line)
+			}
+			if line == errorLine {
+				err = lineErr
+			}
+		}
+		lines[errorLine] = ""
+		blankedLines = append(blankedLines, errorLine)
+	}
+	return nil, nil
+}
+
+// blankLines returns the input with the given zero-based lines emptied.
+func blankLines(input []byte, lineNumbers []int) []byte {
+	lines := strings.Split(string(input), "\n")
+	for _, line := range lineNumbers {
+		if line < len(lines) {
+			lines[line] = ""
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func (d *composeDocument) IsJSON() bool {
+	return strings.HasSuffix(strings.ToLower(string(d.uri)), ".json")
+}
+
 func (d *composeDocument) copy() Document {
-	return NewComposeDocument(d.mgr, d.uri, d.version, d.input)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.file == nil || len(d.ignoredLines) == 0 {
+		return NewComposeDocument(d.mgr, d.uri, d.version, d.input)
+	}
+	// the lines that had to be blanked out of this version are already
+	// known so the partial node tree is not recovered all over again
+	doc := newComposeDocument(d.mgr, d.uri, d.version, d.input)
+	doc.file, _ = parser.ParseBytes(blankLines(d.input, d.ignoredLines), parser.ParseComments)
+	doc.parsingError = d.parsingError
+	doc.ignoredLines = slices.Clone(d.ignoredLines)
+	return doc
 }
 
 func (d *composeDocument) File() *ast.File {
@@ -68,6 +176,10 @@ func (d *composeDocument) ParsingError() error {
 	return d.parsingError
 }
 
+func (d *composeDocument) IgnoredLines() []int {
+	return d.ignoredLines
+}
+
 func isPath(path string) bool {
 	prefixes := []string{"git://", "http://", "https://", "oci://"}
 	for _, prefix := range prefixes {
@@ -78,30 +190,46 @@ func isPath(path string) bool {
 	return true
 }
 
-func searchForIncludedFiles(searched []uri.URI, d *composeDocument) (map[string]*ast.File, bool) {
+// IncludedFileURI returns the URI of the file that an include entry of
+// a Compose file in the given folder refers to with the given path.
+func IncludedFileURI(folder, path string) uri.URI {
+	includedPath := filepath.Join(folder, path)
+	return uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(includedPath), "/")))
+}
+
+// searchForIncludedFiles returns the files that the document includes
+// either directly or through the files that it includes. Files in
+// another workspace folder are not included. The searched URIs are the
+// chain of files that led to the document. If a file includes a file
+// that is already in the chain then no files are returned and the
+// chain that closes the cycle is returned instead.
+func searchForIncludedFiles(searched []uri.URI, d *composeDocument) (map[string]*ast.File, []uri.URI) {
 	documentPath, err := d.document.DocumentPath()
 	if err != nil {
-		return nil, true
+		return nil, nil
 	}
 
 	files := map[string]*ast.File{}
 	for _, path := range d.includedPaths() {
 		if isPath(path) {
-			includedPath := filepath.Join(documentPath.Folder, path)
-			uriString := fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(includedPath), "/"))
-			pathURI := uri.URI(uriString)
+			pathURI := IncludedFileURI(documentPath.Folder, path)
+			if !d.mgr.SameWorkspaceFolder(d.uri, pathURI) {
+				continue
+			}
+			chain := append(slices.Clone(searched), pathURI)
 			if slices.Contains(searched, pathURI) {
-				return nil, false
+				return nil, chain
 			}
-			doc, err := d.mgr.tryReading(context.Background(), pathURI, false)
+			doc, err := d.mgr.tryReading(context.Background(), pathURI, protocol.DockerComposeLanguage, false)
 			if err == nil {
-				if c, ok := doc.(*composeDocument); ok && c.file != nil {
-					searched = append(searched, pathURI)
-					next, resolvable := searchForIncludedFiles(searched, c)
-					if !resolvable {
-						return nil, false
+				// the partial node tree of a file with syntax errors is
+				// not used for the files that it is included by
+				if c, ok := doc.(*composeDocument); ok && c.file != nil && c.parsingError == nil {
+					next, cycle := searchForIncludedFiles(chain, c)
+					if cycle != nil {
+						return nil, cycle
 					}
-					files[uriString] = c.file
+					files[string(pathURI)] = c.file
 					for u, f := range next {
 						files[u] = f
 					}
@@ -109,11 +237,17 @@ func searchForIncludedFiles(searched []uri.URI, d *composeDocument) (map[string]
 			}
 		}
 	}
-	return files, true
+	return files, nil
 }
 
 func (d *composeDocument) IncludedFiles() (map[string]*ast.File, bool) {
-	return searchForIncludedFiles([]uri.URI{d.uri}, d)
+	files, cycle := searchForIncludedFiles([]uri.URI{d.uri}, d)
+	return files, cycle == nil
+}
+
+func (d *composeDocument) IncludeCycle() []uri.URI {
+	_, cycle := searchForIncludedFiles([]uri.URI{d.uri}, d)
+	return cycle
 }
 
 func (d *composeDocument) includedPaths() []string {
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/goccy/go-yaml/ast"
 	"github.com/stretchr/testify/require"
 	"go.lsp.dev/uri"
 )
@@ -85,6 +86,23 @@ include:
 				"second.yaml": "name: second",
 			},
 		},
+		{
+			name: "file included by two other files",
+			content: `
+include:
+  - first.yaml
+  - second.yaml`,
+			resolved: true,
+			externalContent: map[string]string{
+				"first.yaml": `
+include:
+  - shared.yaml`,
+				"second.yaml": `
+include:
+  - shared.yaml`,
+				"shared.yaml": "name: shared",
+			},
+		},
 		{
 			name: "two-way self recursion",
 			content: `
@@ -112,6 +130,16 @@ include:
   - compose.yaml`,
 			},
 		},
+		{
+			name: "included JSON file",
+			content: `
+include:
+  - first.json`,
+			resolved: true,
+			externalContent: map[string]string{
+				"first.json": `{"name": "first"}`,
+			},
+		},
 		{
 			name:            "include node is invalid",
 			content:         "include:",
@@ -145,6 +173,131 @@ include:
 	}
 }
 
+func TestPartialFile(t *testing.T) {
+	testCases := []struct {
+		name         string
+		content      string
+		services     []string
+		ignoredLines []int
+	}{
+		{
+			name:         "valid file",
+			content:      "services:\n  web:\n    image: nginx",
+			services:     []string{"web"},
+			ignoredLines: nil,
+		},
+		{
+			name:         "malformed service between valid services",
+			content:      "services:\n  web:\n    image: nginx\n  broken:\n    image: [nginx\n  db:\n    image: postgres",
+			services:     []string{"web", "broken", "db"},
+			ignoredLines: []int{4},
+		},
+		{
+			name:         "tab indented service",
+			content:      "services:\n  web:\n    image: nginx\n\tdb:\n  db:\n    image: postgres",
+			services:     []string{"web", "db"},
+			ignoredLines: []int{3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := NewComposeDocument(NewDocumentManager(), uri.URI(fileURI(os.TempDir(), "compose.yaml")), 1, []byte(tc.content))
+			require.Equal(t, tc.ignoredLines, doc.IgnoredLines())
+			services := []string{}
+			root := doc.File().Docs[0].Body.(*ast.MappingNode)
+			for _, service := range root.Values[0].Value.(*ast.MappingNode).Values {
+				services = append(services, service.Key.GetToken().Value)
+			}
+			require.Equal(t, tc.services, services)
+		})
+	}
+}
+
+func TestIsJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fileName string
+		content  string
+		json     bool
+	}{
+		{
+			name:     "YAML file",
+			fileName: "compose.yaml",
+			content:  "services:\n  web:\n    image: nginx",
+			json:     false,
+		},
+		{
+			name:     "YAML file with a flow mapping",
+			fileName: "compose.yaml",
+			content:  `{"services": {"web": {"image": "nginx"}}}`,
+			json:     false,
+		},
+		{
+			name:     "JSON file",
+			fileName: "compose.json",
+			content:  `{"services": {"web": {"image": "nginx"}}}`,
+			json:     true,
+		},
+		{
+			name:     "JSON file with an uppercase extension",
+			fileName: "compose.JSON",
+			content:  "\n  {\n    \"services\": {}\n  }",
+			json:     true,
+		},
+		{
+			name:     "empty JSON file",
+			fileName: "compose.json",
+			content:  "",
+			json:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := NewComposeDocument(NewDocumentManager(), uri.URI(fileURI(os.TempDir(), tc.fileName)), 1, []byte(tc.content))
+			require.Equal(t, tc.json, doc.IsJSON())
+		})
+	}
+}
+
 func fileURI(folder, name string) string {
 	return fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, name)), "/"))
 }
+
+func TestPartialFile_LargeFile(t *testing.T) {
+	sb := strings.Builder{}
+	sb.WriteString("services:\n  broken:\n    image: [nginx\n")
+	for i := range 2000 {
+		sb.WriteString(fmt.Sprintf("  service%v:\n    image: nginx\n    ports:\n      - %v:80\n", i, 8000+i))
+	}
+
+	doc := NewComposeDocument(NewDocumentManager(), uri.URI(fileURI(os.TempDir(), "compose.yaml")), 1, []byte(sb.String()))
+	require.Error(t, doc.ParsingError())
+	require.Equal(t, []int{2}, doc.IgnoredLines())
+	require.Len(t, doc.File().Docs[0].Body.(*ast.MappingNode).Values[0].Value.(*ast.MappingNode).Values, 2001)
+
+	// the copy reuses the lines that were blanked out
+	copied := doc.Copy().(ComposeDocument)
+	require.Equal(t, doc.ParsingError(), copied.ParsingError())
+	require.Equal(t, []int{2}, copied.IgnoredLines())
+	require.Len(t, copied.File().Docs[0].Body.(*ast.MappingNode).Values[0].Value.(*ast.MappingNode).Values, 2001)
+}
+
+func TestPartialFile_TooManyErrors(t *testing.T) {
+	// the recovery gives up on a large file instead of parsing it again
+	// for every one of its errors
+	sb := strings.Builder{}
+	sb.WriteString("services:\n")
+	for i := range 2000 {
+		if i%300 == 0 {
+			sb.WriteString(fmt.Sprintf("  broken%v:\n    image: [nginx\n", i))
+		}
+		sb.WriteString(fmt.Sprintf("  service%v:\n    image: nginx\n", i))
+	}
+
+	doc := NewComposeDocument(NewDocumentManager(), uri.URI(fileURI(os.TempDir(), "compose.yaml")), 1, []byte(sb.String()))
+	require.Error(t, doc.ParsingError())
+	require.Nil(t, doc.IgnoredLines())
+	require.Nil(t, doc.File())
+}
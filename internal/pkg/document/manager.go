@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/bep/debounce"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 	"go.lsp.dev/uri"
 )
@@ -27,11 +30,34 @@ type Manager struct {
 	diagnosticsProcessing map[uri.URI]*documentLock
 	newDocFunc            NewDocumentFunc
 	readDocFunc           ReadDocumentFunc
+
+	// workspaceFolders are the folders of the client's workspace. The
+	// files of one folder are not resolved from the files of another.
+	workspaceFolders []string
+	foldersMutex     sync.RWMutex
 }
 
 type documentLock struct {
 	mu    sync.Mutex
 	queue func(func())
+
+	// deferredDiagnostics are the diagnostics from the last time the
+	// document was checked by the collectors that only run on save.
+	deferredDiagnostics []protocol.Diagnostic
+
+	// pulledDiagnostics are the diagnostics that were last reported
+	// to a client that pulls them or nil if they have to be collected
+	// again.
+	pulledDiagnostics *PulledDiagnostics
+}
+
+// PulledDiagnostics are the diagnostics of a version of a document
+// that were reported to a client that pulls them along with the result
+// ID that they were reported with.
+type PulledDiagnostics struct {
+	Version     int32
+	ResultID    string
+	Diagnostics []protocol.Diagnostic
 }
 
 func parseDockerfile(dockerfilePath string) ([]byte, *parser.Result, error) {
@@ -57,6 +83,22 @@ func OpenDockerfile(ctx context.Context, manager *Manager, documentURI, path str
 	return dockerfileBytes, result.AST.Children
 }
 
+// OpenComposeFile returns the Compose file at the given URI. The
+// manager's copy is returned if the file has been opened and the file
+// is read from the file system otherwise without being added to the
+// manager. Nil is returned if the file cannot be read.
+func OpenComposeFile(ctx context.Context, manager *Manager, documentURI uri.URI) ComposeDocument {
+	if doc := manager.Get(ctx, documentURI); doc != nil {
+		composeDocument, _ := doc.(ComposeDocument)
+		return composeDocument
+	}
+	contents, err := manager.readDocFunc(documentURI)
+	if err != nil {
+		return nil
+	}
+	return NewComposeDocument(manager, documentURI, 1, contents)
+}
+
 func NewDocumentManager(opts ...ManagerOpt) *Manager {
 	m := Manager{
 		docs:                  make(DocumentMap),
@@ -72,6 +114,24 @@ func NewDocumentManager(opts ...ManagerOpt) *Manager {
 	return &m
 }
 
+// SetWorkspaceFolders replaces the folders of the client's workspace.
+func (m *Manager) SetWorkspaceFolders(workspaceFolders []string) {
+	m.foldersMutex.Lock()
+	defer m.foldersMutex.Unlock()
+	m.workspaceFolders = slices.Clone(workspaceFolders)
+}
+
+// SameWorkspaceFolder returns false if the two URIs are in different
+// workspace folders. A file that is not in any workspace folder may be
+// referred to from any folder.
+func (m *Manager) SameWorkspaceFolder(a, b uri.URI) bool {
+	m.foldersMutex.RLock()
+	defer m.foldersMutex.RUnlock()
+	folder, _, _ := types.WorkspaceFolder(protocol.DocumentUri(a), m.workspaceFolders)
+	other, _, _ := types.WorkspaceFolder(protocol.DocumentUri(b), m.workspaceFolders)
+	return folder == "" || other == "" || folder == other
+}
+
 func WithReadDocumentFunc(readDocFunc ReadDocumentFunc) ManagerOpt {
 	return func(manager *Manager) {
 		manager.readDocFunc = readDocFunc
@@ -113,14 +173,15 @@ func (m *Manager) Version(ctx context.Context, u uri.URI) (int32, error) {
 // If no file exists at the path or the URI is of an invalid type, an error is
 // returned.
 func (m *Manager) Read(ctx context.Context, u uri.URI) (doc Document, err error) {
-	return m.tryReading(ctx, u, true)
+	return m.tryReading(ctx, u, languageIdentifier(u), true)
 }
 
-// Read returns the contents of the file for the given URI.
+// Read returns the contents of the file for the given URI. A file that
+// is not being managed is parsed as the given language.
 //
 // If no file exists at the path or the URI is of an invalid type, an error is
 // returned.
-func (m *Manager) tryReading(ctx context.Context, u uri.URI, create bool) (doc Document, err error) {
+func (m *Manager) tryReading(ctx context.Context, u uri.URI, identifier protocol.LanguageIdentifier, create bool) (doc Document, err error) {
 	m.mu.Lock()
 	defer func() {
 		if err == nil {
@@ -133,7 +194,7 @@ func (m *Manager) tryReading(ctx context.Context, u uri.URI, create bool) (doc D
 	// TODO(siegs): check staleness for files read from disk?
 	var found bool
 	if doc, found = m.docs[u]; !found {
-		_, err = m.readAndParse(ctx, u)
+		_, err = m.readAndParse(ctx, u, identifier)
 		doc = m.docs[u]
 		if !create {
 			delete(m.docs, u)
@@ -203,6 +264,39 @@ func (m *Manager) Overwrite(ctx context.Context, u uri.URI, version int32, input
 	return m.write(ctx, u, identifier, version, input)
 }
 
+// ApplyChanges applies the given content changes to the document's
+// current contents in the order that they are provided and then
+// reparses the result. A TextDocumentContentChangeEvent replaces the
+// text within its range whereas a TextDocumentContentChangeEventWhole
+// replaces the entire document. True will be returned if the
+// document's syntax tree has changed.
+func (m *Manager) ApplyChanges(ctx context.Context, u uri.URI, version int32, changes []any) (bool, error) {
+	// the lock is held until the result has been written so that the
+	// changes of one call are not applied to contents that another call
+	// is about to replace
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	doc, found := m.docs[u]
+	if !found {
+		return false, errors.New("document not managed")
+	}
+
+	input := doc.Input()
+	for _, change := range changes {
+		switch event := change.(type) {
+		case protocol.TextDocumentContentChangeEvent:
+			if event.Range == nil {
+				input = []byte(event.Text)
+			} else {
+				input = ApplyContentChange(input, *event.Range, event.Text)
+			}
+		case protocol.TextDocumentContentChangeEventWhole:
+			input = []byte(event.Text)
+		}
+	}
+	return m.write(ctx, u, doc.LanguageIdentifier(), version, input)
+}
+
 func (m *Manager) Remove(u uri.URI) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -219,14 +313,18 @@ func (m *Manager) Keys() []uri.URI {
 	return keys
 }
 
-func (m *Manager) readAndParse(ctx context.Context, u uri.URI) (bool, error) {
-	identifier := protocol.DockerfileLanguage
+// languageIdentifier returns the language of a file that has not been
+// opened by the client based on its name.
+func languageIdentifier(u uri.URI) protocol.LanguageIdentifier {
 	if strings.HasSuffix(string(u), "hcl") {
-		identifier = protocol.DockerBakeLanguage
-	} else if strings.HasSuffix(string(u), "yml") || strings.HasSuffix(string(u), "yaml") {
-		identifier = protocol.DockerComposeLanguage
+		return protocol.DockerBakeLanguage
+	} else if strings.HasSuffix(string(u), "yml") || strings.HasSuffix(string(u), "yaml") || composeJSONFile(u) {
+		return protocol.DockerComposeLanguage
 	}
+	return protocol.DockerfileLanguage
+}
 
+func (m *Manager) readAndParse(ctx context.Context, u uri.URI, identifier protocol.LanguageIdentifier) (bool, error) {
 	if _, found := m.docs[u]; !found {
 		contents, err := m.readDocFunc(u)
 		if err != nil {
@@ -237,6 +335,18 @@ func (m *Manager) readAndParse(ctx context.Context, u uri.URI) (bool, error) {
 	return m.parse(ctx, u, identifier, 1, nil)
 }
 
+// composeJSONFile returns true if the URI is a JSON file that is named
+// like a Compose file such as compose.json or docker-compose.dev.json.
+// Other JSON files are only Compose files if the client opens them as
+// such or if they are included by a Compose file.
+func composeJSONFile(u uri.URI) bool {
+	name := strings.ToLower(path.Base(string(u)))
+	if !strings.HasSuffix(name, ".json") {
+		return false
+	}
+	return strings.HasPrefix(name, "compose.") || strings.HasPrefix(name, "docker-compose.")
+}
+
 func (m *Manager) parse(_ context.Context, uri uri.URI, identifier protocol.LanguageIdentifier, version int32, input []byte) (bool, error) {
 	doc, loaded := m.docs[uri]
 	changed := true
@@ -268,6 +378,61 @@ func (m *Manager) UnlockDocument(uri uri.URI) {
 	}
 }
 
+// SetDeferredDiagnostics stores the diagnostics of the checks that are
+// deferred until the document is saved so that they can be published
+// alongside the diagnostics of any changes that follow.
+func (m *Manager) SetDeferredDiagnostics(u uri.URI, diagnostics []protocol.Diagnostic) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lock, ok := m.diagnosticsProcessing[u]; ok {
+		lock.deferredDiagnostics = diagnostics
+	}
+}
+
+// DeferredDiagnostics returns the diagnostics that were last stored
+// with SetDeferredDiagnostics for the given document.
+func (m *Manager) DeferredDiagnostics(u uri.URI) []protocol.Diagnostic {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lock, ok := m.diagnosticsProcessing[u]; ok {
+		return lock.deferredDiagnostics
+	}
+	return nil
+}
+
+// SetPulledDiagnostics stores the diagnostics that were reported to a
+// client that pulls them so that they can be reported again without
+// collecting them until the document or its settings change.
+func (m *Manager) SetPulledDiagnostics(u uri.URI, pulled PulledDiagnostics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lock, ok := m.diagnosticsProcessing[u]; ok {
+		lock.pulledDiagnostics = &pulled
+	}
+}
+
+// PulledDiagnostics returns the diagnostics that were last stored with
+// SetPulledDiagnostics for the given document. False is returned if
+// nothing has been stored since they were last invalidated.
+func (m *Manager) PulledDiagnostics(u uri.URI) (PulledDiagnostics, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lock, ok := m.diagnosticsProcessing[u]; ok && lock.pulledDiagnostics != nil {
+		return *lock.pulledDiagnostics, true
+	}
+	return PulledDiagnostics{}, false
+}
+
+// InvalidatePulledDiagnostics discards the diagnostics that were stored
+// with SetPulledDiagnostics for the given document.
+func (m *Manager) InvalidatePulledDiagnostics(u uri.URI) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lock, ok := m.diagnosticsProcessing[u]; ok {
+		lock.pulledDiagnostics = nil
+	}
+}
+
 // removeAndCleanup removes a Document and frees associated resources.
 func (m *Manager) removeAndCleanup(uri uri.URI) {
 	if existing, ok := m.docs[uri]; ok {
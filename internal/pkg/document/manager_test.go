@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -115,3 +116,161 @@ func TestWrite(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyChanges(t *testing.T) {
+	testCases := []struct {
+		name       string
+		identifier protocol.LanguageIdentifier
+		u          uri.URI
+		content    string
+		changes    []any
+		result     string
+	}{
+		{
+			name:       "sequence of ranged changes to a Dockerfile",
+			identifier: protocol.DockerfileLanguage,
+			u:          "file:///tmp/Dockerfile",
+			content:    "FROM alpine\nRUN ls",
+			changes: []any{
+				protocol.TextDocumentContentChangeEvent{
+					Range: &protocol.Range{Start: protocol.Position{Line: 0, Character: 11}, End: protocol.Position{Line: 0, Character: 11}},
+					Text:  ":3.21",
+				},
+				protocol.TextDocumentContentChangeEvent{
+					Range: &protocol.Range{Start: protocol.Position{Line: 1, Character: 6}, End: protocol.Position{Line: 1, Character: 6}},
+					Text:  "\nRUN pwd",
+				},
+				protocol.TextDocumentContentChangeEvent{
+					Range: &protocol.Range{Start: protocol.Position{Line: 1, Character: 4}, End: protocol.Position{Line: 1, Character: 6}},
+					Text:  "echo",
+				},
+			},
+			result: "FROM alpine:3.21\nRUN echo\nRUN pwd",
+		},
+		{
+			name:       "ranged changes after a full replacement",
+			identifier: protocol.DockerComposeLanguage,
+			u:          "file:///tmp/compose.yaml",
+			content:    "services:\n  web:\n    image: nginx",
+			changes: []any{
+				protocol.TextDocumentContentChangeEventWhole{
+					Text: "services:\n  test:\n    image: alpine",
+				},
+				protocol.TextDocumentContentChangeEvent{
+					Range: &protocol.Range{Start: protocol.Position{Line: 1, Character: 2}, End: protocol.Position{Line: 1, Character: 6}},
+					Text:  "backend",
+				},
+			},
+			result: "services:\n  backend:\n    image: alpine",
+		},
+		{
+			name:       "ranged changes to a Bake file",
+			identifier: protocol.DockerBakeLanguage,
+			u:          "file:///tmp/docker-bake.hcl",
+			content:    "target \"t\" {\n}",
+			changes: []any{
+				protocol.TextDocumentContentChangeEvent{
+					Range: &protocol.Range{Start: protocol.Position{Line: 0, Character: 12}, End: protocol.Position{Line: 0, Character: 12}},
+					Text:  "\n  context = \".\"",
+				},
+			},
+			result: "target \"t\" {\n  context = \".\"\n}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			incremental := NewDocumentManager()
+			defer incremental.Remove(tc.u)
+			full := NewDocumentManager()
+			defer full.Remove(tc.u)
+
+			_, err := incremental.Write(context.Background(), tc.u, tc.identifier, 1, []byte(tc.content))
+			require.NoError(t, err)
+			_, err = incremental.ApplyChanges(context.Background(), tc.u, 2, tc.changes)
+			require.NoError(t, err)
+
+			_, err = full.Write(context.Background(), tc.u, tc.identifier, 1, []byte(tc.content))
+			require.NoError(t, err)
+			_, err = full.Overwrite(context.Background(), tc.u, 2, []byte(tc.result))
+			require.NoError(t, err)
+
+			incrementalDoc := incremental.Get(context.Background(), tc.u)
+			fullDoc := full.Get(context.Background(), tc.u)
+			require.Equal(t, tc.result, string(incrementalDoc.Input()))
+			require.Equal(t, string(fullDoc.Input()), string(incrementalDoc.Input()))
+			require.Equal(t, fullDoc.Version(), incrementalDoc.Version())
+			require.Equal(t, fullDoc.LanguageIdentifier(), incrementalDoc.LanguageIdentifier())
+		})
+	}
+}
+
+func TestApplyChanges_Concurrent(t *testing.T) {
+	manager := NewDocumentManager()
+	u := uri.URI("file:///tmp/compose.yaml")
+	sb := strings.Builder{}
+	sb.WriteString("services:\n")
+	for i := range 2000 {
+		sb.WriteString(fmt.Sprintf("  web%v:\n    image: nginx\n", i))
+	}
+	content := sb.String()
+	_, err := manager.Write(context.Background(), u, protocol.DockerComposeLanguage, 1, []byte(content))
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := manager.ApplyChanges(context.Background(), u, int32(i+2), []any{
+				protocol.TextDocumentContentChangeEvent{
+					Range: &protocol.Range{Start: protocol.Position{Line: 0, Character: 0}, End: protocol.Position{Line: 0, Character: 0}},
+					Text:  "#\n",
+				},
+			})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	doc := manager.Get(context.Background(), u)
+	require.Equal(t, strings.Repeat("#\n", 10)+content, string(doc.Input()))
+}
+
+func TestApplyChanges_UnmanagedDocument(t *testing.T) {
+	manager := NewDocumentManager()
+	changed, err := manager.ApplyChanges(context.Background(), "file:///tmp/Dockerfile", 1, []any{
+		protocol.TextDocumentContentChangeEventWhole{Text: "FROM alpine"},
+	})
+	require.Error(t, err)
+	require.False(t, changed)
+}
+
+func TestRead_LanguageIdentifier(t *testing.T) {
+	testCases := []struct {
+		name       string
+		fileName   string
+		identifier protocol.LanguageIdentifier
+	}{
+		{name: "compose.yaml", fileName: "compose.yaml", identifier: protocol.DockerComposeLanguage},
+		{name: "compose.json", fileName: "compose.json", identifier: protocol.DockerComposeLanguage},
+		{name: "docker-compose.json", fileName: "docker-compose.json", identifier: protocol.DockerComposeLanguage},
+		{name: "compose.override.json", fileName: "compose.override.json", identifier: protocol.DockerComposeLanguage},
+		{name: "package.json is not a Compose file", fileName: "package.json", identifier: protocol.DockerfileLanguage},
+		{name: "compose-schema.json is not a Compose file", fileName: "compose-schema.json", identifier: protocol.DockerfileLanguage},
+		{name: "docker-bake.hcl", fileName: "docker-bake.hcl", identifier: protocol.DockerBakeLanguage},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := NewDocumentManager(WithReadDocumentFunc(func(uri.URI) ([]byte, error) {
+				return []byte("{}"), nil
+			}))
+			u := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(os.TempDir(), tc.fileName)), "/")))
+			doc, err := manager.Read(context.Background(), u)
+			require.NoError(t, err)
+			defer doc.Close()
+			require.Equal(t, tc.identifier, doc.LanguageIdentifier())
+		})
+	}
+}
@@ -9,3 +9,13 @@ type DiagnosticsCollector interface {
 	CollectDiagnostics(source, workspaceFolder string, doc document.Document, text string) []protocol.Diagnostic
 	SupportsLanguageIdentifier(languageIdentifier protocol.LanguageIdentifier) bool
 }
+
+// DeferrableDiagnosticsCollector is implemented by collectors whose
+// checks are too expensive to run on every change such as ones that
+// call out to an external process or a registry. If the server has
+// been configured to validate on save then these collectors will only
+// be run when a document is opened or saved.
+type DeferrableDiagnosticsCollector interface {
+	DiagnosticsCollector
+	Deferrable() bool
+}
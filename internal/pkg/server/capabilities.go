@@ -0,0 +1,96 @@
+package server
+
+import (
+	"slices"
+
+	"github.com/docker/docker-language-server/internal/bake/hcl"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+)
+
+// dynamicRegistration returns whether the client supports registering
+// the formatting and rename providers dynamically.
+func dynamicRegistration(params *protocol.InitializeParams) (formatting bool, rename bool) {
+	if params.Capabilities.TextDocument != nil {
+		if params.Capabilities.TextDocument.Formatting != nil &&
+			params.Capabilities.TextDocument.Formatting.DynamicRegistration != nil {
+			formatting = *params.Capabilities.TextDocument.Formatting.DynamicRegistration
+		}
+		if params.Capabilities.TextDocument.Rename != nil &&
+			params.Capabilities.TextDocument.Rename.DynamicRegistration != nil {
+			rename = *params.Capabilities.TextDocument.Rename.DynamicRegistration
+		}
+	}
+	return formatting, rename
+}
+
+// serverCapabilities returns the capabilities that the server should
+// advertise in its initialize response. Providers that depend on the
+// client or the server's settings are only included if they have been
+// enabled. The formatting and rename providers are omitted if they
+// will be registered dynamically instead.
+func (s *Server) serverCapabilities(dynamicFormatting, dynamicRename bool) protocol.ServerCapabilities {
+	syncKind := protocol.TextDocumentSyncKindIncremental
+	textDocumentSync := protocol.TextDocumentSyncOptions{
+		OpenClose: &protocol.True,
+		Change:    &syncKind,
+	}
+	// save notifications are only needed if the expensive checks have
+	// been deferred until a document is saved
+	if s.validateOnSave {
+		textDocumentSync.Save = &protocol.True
+	}
+	capabilities := protocol.ServerCapabilities{
+		CodeActionProvider: protocol.CodeActionOptions{},
+		CompletionProvider: &protocol.CompletionOptions{
+			TriggerCharacters: []string{"/", ":", " ", "-", "$"},
+		},
+		DefinitionProvider:        protocol.DefinitionOptions{},
+		DocumentHighlightProvider: protocol.DocumentHighlightOptions{},
+		DocumentLinkProvider:      &protocol.DocumentLinkOptions{},
+		DocumentSymbolProvider:    protocol.DocumentSymbolOptions{},
+		ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
+			Commands: []string{types.TelemetryCallbackCommandId, types.SortServiceKeysCommandId, types.ToggleMappingFormCommandId, types.StartupOrderCommandId, types.GenerateEnvTemplateCommandId, types.NormalizeReferencesCommandId},
+		},
+		FoldingRangeProvider:     protocol.FoldingRangeOptions{},
+		HoverProvider:            protocol.HoverOptions{},
+		InlayHintProvider:        protocol.InlayHintOptions{},
+		InlineCompletionProvider: protocol.InlineCompletionOptions{},
+		ReferencesProvider:       protocol.ReferenceOptions{},
+		SemanticTokensProvider: protocol.SemanticTokensOptions{
+			Legend: protocol.SemanticTokensLegend{
+				TokenModifiers: []string{},
+				TokenTypes:     hcl.SemanticTokenTypes,
+			},
+			Full:  true,
+			Range: false,
+		},
+		TextDocumentSync: textDocumentSync,
+		Workspace: &protocol.ServerCapabilitiesWorkspace{
+			WorkspaceFolders: &protocol.WorkspaceFoldersServerCapabilities{
+				Supported:           &protocol.True,
+				ChangeNotifications: &protocol.BoolOrString{Value: true},
+			},
+		},
+	}
+
+	// code lenses are only created for running Bake builds which
+	// requires the client to be able to handle the command
+	if s.capabilities != nil && slices.Contains(s.capabilities.Capabilities.Commands, types.BakeBuildCommandId) {
+		capabilities.CodeLensProvider = &protocol.CodeLensOptions{}
+	}
+	// diagnostics are only pulled by clients that support it, the other
+	// clients will have them published instead
+	if s.pullDiagnostics {
+		capabilities.DiagnosticProvider = protocol.DiagnosticOptions{WorkspaceDiagnostics: true}
+	}
+	if !dynamicFormatting {
+		capabilities.DocumentFormattingProvider = protocol.DocumentFormattingOptions{}
+	}
+	if !dynamicRename {
+		capabilities.RenameProvider = protocol.RenameOptions{
+			PrepareProvider: types.CreateBoolPointer(true),
+		}
+	}
+	return capabilities
+}
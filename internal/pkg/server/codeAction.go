@@ -3,10 +3,13 @@ package server
 import (
 	"encoding/json"
 
+	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/telemetry"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
 	"github.com/docker/docker-language-server/internal/types"
+	"go.lsp.dev/uri"
 )
 
 func (s *Server) TextDocumentCodeAction(ctx *glsp.Context, params *protocol.CodeActionParams) (any, error) {
@@ -59,5 +62,13 @@ func (s *Server) TextDocumentCodeAction(ctx *glsp.Context, params *protocol.Code
 		}
 	}
 
+	doc, err := s.docs.Read(ctx.Context, uri.URI(params.TextDocument.URI))
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
+		actions = append(actions, compose.CodeAction(doc.(document.ComposeDocument), params)...)
+	}
 	return actions, nil
 }
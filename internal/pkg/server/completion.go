@@ -1,14 +1,29 @@
 package server
 
 import (
+	"slices"
+
 	"github.com/docker/docker-language-server/internal/bake/hcl"
 	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/configuration"
+	"github.com/docker/docker-language-server/internal/dockerfile"
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
 	"go.lsp.dev/uri"
 )
 
+// triggeredBy returns true if completion was not triggered by a
+// character or if it was triggered by one of the given characters. The
+// trigger characters that the server advertises are mostly for Compose
+// files so the other languages only respond to the ones they handle.
+func triggeredBy(params *protocol.CompletionParams, characters ...string) bool {
+	if params.Context == nil || params.Context.TriggerKind != protocol.CompletionTriggerKindTriggerCharacter || params.Context.TriggerCharacter == nil {
+		return true
+	}
+	return slices.Contains(characters, *params.Context.TriggerCharacter)
+}
+
 func (s *Server) TextDocumentCompletion(ctx *glsp.Context, params *protocol.CompletionParams) (any, error) {
 	doc, err := s.docs.Read(ctx.Context, uri.URI(params.TextDocument.URI))
 	if err != nil {
@@ -17,9 +32,18 @@ func (s *Server) TextDocumentCompletion(ctx *glsp.Context, params *protocol.Comp
 	defer doc.Close()
 
 	if doc.LanguageIdentifier() == protocol.DockerBakeLanguage {
+		if !triggeredBy(params, "/") {
+			return nil, nil
+		}
 		return hcl.Completion(ctx.Context, params, s.docs, doc.(document.BakeHCLDocument))
 	} else if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport && s.composeCompletion {
-		return compose.Completion(ctx.Context, params, s.docs, doc.(document.ComposeDocument))
+		images := configuration.Get(params.TextDocument.URI).Compose.Images
+		return compose.Completion(ctx.Context, params, s.docs, doc.(document.ComposeDocument), images)
+	} else if doc.LanguageIdentifier() == protocol.DockerfileLanguage {
+		if !triggeredBy(params, "/", "$") {
+			return nil, nil
+		}
+		return dockerfile.Completion(ctx.Context, params, doc.(document.DockerfileDocument))
 	}
 	return nil, nil
 }
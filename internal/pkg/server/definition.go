@@ -3,6 +3,7 @@ package server
 import (
 	"github.com/docker/docker-language-server/internal/bake/hcl"
 	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/dockerfile"
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -19,7 +20,9 @@ func (s *Server) TextDocumentDefinition(ctx *glsp.Context, params *protocol.Defi
 	if doc.LanguageIdentifier() == protocol.DockerBakeLanguage {
 		return hcl.Definition(ctx.Context, s.definitionLinkSupport, s.docs, uri.URI(params.TextDocument.URI), doc.(document.BakeHCLDocument), params.Position)
 	} else if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
-		return compose.Definition(ctx.Context, s.definitionLinkSupport, doc.(document.ComposeDocument), params)
+		return compose.Definition(ctx.Context, s.definitionLinkSupport, s.docs, doc.(document.ComposeDocument), params)
+	} else if doc.LanguageIdentifier() == protocol.DockerfileLanguage {
+		return dockerfile.Definition(ctx.Context, s.definitionLinkSupport, doc.(document.DockerfileDocument), params.Position)
 	}
 	return nil, nil
 }
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/sourcegraph/jsonrpc2"
+	"go.lsp.dev/uri"
+)
+
+// serverCancelled is the error code that is returned for a diagnostic
+// request that the server could not complete. The client is asked to
+// send the request again.
+const serverCancelled = -32802
+
+// TextDocumentDiagnostic returns the diagnostics of a document to a
+// client that pulls them instead of waiting for them to be published.
+// An unchanged report is returned if the client already has the
+// diagnostics that would have been reported.
+func (s *Server) TextDocumentDiagnostic(ctx *glsp.Context, params *protocol.DocumentDiagnosticParams) (any, error) {
+	requestContext := ctx.Context
+	if requestContext == nil {
+		requestContext = context.Background()
+	}
+	// reading the document loads it if it has not been opened
+	doc, err := s.docs.Read(requestContext, uri.URI(params.TextDocument.URI))
+	if err != nil {
+		return nil, err
+	}
+	doc.Close()
+
+	pulled, err := s.pullDocumentDiagnostics(requestContext, params.TextDocument.URI)
+	if err != nil {
+		return nil, diagnosticRequestError(err)
+	}
+	if params.PreviousResultId != nil && *params.PreviousResultId == pulled.ResultID {
+		return protocol.RelatedUnchangedDocumentDiagnosticReport{
+			UnchangedDocumentDiagnosticReport: protocol.UnchangedDocumentDiagnosticReport{
+				Kind:     string(protocol.DocumentDiagnosticReportKindUnchanged),
+				ResultID: pulled.ResultID,
+			},
+		}, nil
+	}
+	return protocol.RelatedFullDocumentDiagnosticReport{
+		FullDocumentDiagnosticReport: protocol.FullDocumentDiagnosticReport{
+			Kind:     string(protocol.DocumentDiagnosticReportKindFull),
+			ResultID: &pulled.ResultID,
+			Items:    pulled.Diagnostics,
+		},
+	}, nil
+}
+
+// WorkspaceDiagnostic returns the diagnostics of every document that
+// the server knows about. Each document has its own result ID so an
+// unchanged report is returned for the documents whose diagnostics the
+// client already has. Documents that are closed while the report is
+// being put together are left out of it.
+func (s *Server) WorkspaceDiagnostic(ctx *glsp.Context, params *protocol.WorkspaceDiagnosticParams) (*protocol.WorkspaceDiagnosticReport, error) {
+	requestContext := ctx.Context
+	if requestContext == nil {
+		requestContext = context.Background()
+	}
+	previousResultIDs := map[protocol.DocumentUri]string{}
+	for _, previous := range params.PreviousResultIds {
+		previousResultIDs[previous.URI] = previous.Value
+	}
+
+	documentURIs := s.docs.Keys()
+	slices.Sort(documentURIs)
+	report := &protocol.WorkspaceDiagnosticReport{Items: []protocol.WorkspaceDocumentDiagnosticReport{}}
+	for _, u := range documentURIs {
+		documentURI := protocol.DocumentUri(u)
+		pulled, err := s.pullDocumentDiagnostics(requestContext, documentURI)
+		if errors.Is(err, errDocumentNotManaged) {
+			continue
+		} else if err != nil {
+			return nil, diagnosticRequestError(err)
+		}
+		if previousResultIDs[documentURI] == pulled.ResultID {
+			report.Items = append(report.Items, protocol.WorkspaceUnchangedDocumentDiagnosticReport{
+				UnchangedDocumentDiagnosticReport: protocol.UnchangedDocumentDiagnosticReport{
+					Kind:     string(protocol.DocumentDiagnosticReportKindUnchanged),
+					ResultID: pulled.ResultID,
+				},
+				URI:     documentURI,
+				Version: &pulled.Version,
+			})
+		} else {
+			report.Items = append(report.Items, protocol.WorkspaceFullDocumentDiagnosticReport{
+				FullDocumentDiagnosticReport: protocol.FullDocumentDiagnosticReport{
+					Kind:     string(protocol.DocumentDiagnosticReportKindFull),
+					ResultID: &pulled.ResultID,
+					Items:    pulled.Diagnostics,
+				},
+				URI:     documentURI,
+				Version: &pulled.Version,
+			})
+		}
+	}
+	return report, nil
+}
+
+// errDocumentNotManaged is returned when the diagnostics of a document
+// are pulled after the document has been closed.
+var errDocumentNotManaged = errors.New("document is not managed")
+
+// diagnosticRequestError converts an error from pulling the diagnostics
+// of a document into the error that is returned to the client.
+func diagnosticRequestError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return &jsonrpc2.Error{Code: requestCancelled, Message: "Request cancelled"}
+	}
+	if errors.Is(err, errDocumentNotManaged) {
+		rpcError := &jsonrpc2.Error{Code: serverCancelled, Message: err.Error()}
+		rpcError.SetError(protocol.DiagnosticServerCancellationData{RetriggerRequest: true})
+		return rpcError
+	}
+	return err
+}
+
+// pullDocumentDiagnostics returns the diagnostics of the given document
+// for a client that pulls them. The diagnostics that were last pulled
+// are returned if the document and its settings have not changed since.
+// Otherwise, the diagnostics are collected with the document locked the
+// same way that it is when its diagnostics are published so that they
+// are collected from a version of the document that is not changing.
+func (s *Server) pullDocumentDiagnostics(ctx context.Context, documentURI protocol.DocumentUri) (document.PulledDiagnostics, error) {
+	if err := ctx.Err(); err != nil {
+		return document.PulledDiagnostics{}, err
+	}
+	if !s.docs.LockDocument(uri.URI(documentURI)) {
+		return document.PulledDiagnostics{}, errDocumentNotManaged
+	}
+	defer s.docs.UnlockDocument(uri.URI(documentURI))
+	// the lock may have been waited on for a while
+	if err := ctx.Err(); err != nil {
+		return document.PulledDiagnostics{}, err
+	}
+	doc := s.docs.Get(ctx, uri.URI(documentURI))
+	if doc == nil {
+		return document.PulledDiagnostics{}, errDocumentNotManaged
+	}
+	if pulled, ok := s.docs.PulledDiagnostics(uri.URI(documentURI)); ok && pulled.Version == doc.Version() {
+		return pulled, nil
+	}
+	doc = doc.Copy()
+	defer doc.Close()
+
+	diagnostics := []protocol.Diagnostic{}
+	if doc.LanguageIdentifier() != protocol.DockerComposeLanguage || s.composeSupport {
+		folder, _, _, _ := s.workspaceFolder(documentURI)
+		diagnostics = s.collectDiagnostics(documentURI, folder, doc, false)
+	}
+	pulled := document.PulledDiagnostics{
+		Version:     doc.Version(),
+		ResultID:    diagnosticsResultID(doc.Version(), diagnostics),
+		Diagnostics: diagnostics,
+	}
+	s.docs.SetPulledDiagnostics(uri.URI(documentURI), pulled)
+	return pulled, nil
+}
+
+// diagnosticsResultID identifies the diagnostics of a version of a
+// document. The diagnostics are hashed as the diagnostic settings or
+// the deferred diagnostics may change without the document changing.
+func diagnosticsResultID(version int32, diagnostics []protocol.Diagnostic) string {
+	hasher := fnv.New32a()
+	content, _ := json.Marshal(diagnostics)
+	hasher.Write(content)
+	return fmt.Sprintf("%v-%x", version, hasher.Sum32())
+}
@@ -0,0 +1,345 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/docker/docker-language-server/internal/configuration"
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/pkg/lsp/textdocument"
+	"github.com/docker/docker-language-server/internal/tliron/glsp"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+type staticCollector struct {
+	messages []string
+	calls    int
+}
+
+func (c *staticCollector) CollectDiagnostics(source, workspaceFolder string, doc document.Document, text string) []protocol.Diagnostic {
+	c.calls++
+	diagnostics := []protocol.Diagnostic{}
+	for _, message := range c.messages {
+		diagnostics = append(diagnostics, protocol.Diagnostic{Message: message})
+	}
+	return diagnostics
+}
+
+func (c *staticCollector) SupportsLanguageIdentifier(languageIdentifier protocol.LanguageIdentifier) bool {
+	return true
+}
+
+func TestTextDocumentDiagnostic(t *testing.T) {
+	ctx := context.Background()
+	u := uri.URI("file:///tmp/Dockerfile")
+	collector := &staticCollector{messages: []string{"first"}}
+	s := NewServer(document.NewDocumentManager())
+	s.updateTelemetrySetting("off")
+	s.diagnosticsCollectors = []textdocument.DiagnosticsCollector{collector}
+
+	pull := func(previousResultID *string) any {
+		report, err := s.TextDocumentDiagnostic(&glsp.Context{Context: ctx}, &protocol.DocumentDiagnosticParams{
+			TextDocument:     protocol.TextDocumentIdentifier{URI: protocol.DocumentUri(u)},
+			PreviousResultId: previousResultID,
+		})
+		require.NoError(t, err)
+		return report
+	}
+
+	_, err := s.docs.Write(ctx, u, protocol.DockerfileLanguage, 1, []byte("FROM alpine"))
+	require.NoError(t, err)
+	report := pull(nil)
+	require.IsType(t, protocol.RelatedFullDocumentDiagnosticReport{}, report)
+	full := report.(protocol.RelatedFullDocumentDiagnosticReport)
+	require.Equal(t, string(protocol.DocumentDiagnosticReportKindFull), full.Kind)
+	require.Equal(t, []protocol.Diagnostic{{Message: "first"}}, full.Items)
+	require.NotNil(t, full.ResultID)
+	resultID := *full.ResultID
+
+	// nothing has changed so the client's diagnostics are still current
+	// and they are not collected again
+	unchanged := protocol.RelatedUnchangedDocumentDiagnosticReport{
+		UnchangedDocumentDiagnosticReport: protocol.UnchangedDocumentDiagnosticReport{
+			Kind:     string(protocol.DocumentDiagnosticReportKindUnchanged),
+			ResultID: resultID,
+		},
+	}
+	require.Equal(t, unchanged, pull(&resultID))
+	require.Equal(t, 1, collector.calls)
+
+	// a stale result ID gets a full report from what was last pulled
+	stale := "stale"
+	require.IsType(t, protocol.RelatedFullDocumentDiagnosticReport{}, pull(&stale))
+	require.Equal(t, 1, collector.calls)
+
+	// the document has a new version
+	_, err = s.docs.Write(ctx, u, protocol.DockerfileLanguage, 2, []byte("FROM alpine:3.21"))
+	require.NoError(t, err)
+	report = pull(&resultID)
+	require.IsType(t, protocol.RelatedFullDocumentDiagnosticReport{}, report)
+	full = report.(protocol.RelatedFullDocumentDiagnosticReport)
+	require.NotEqual(t, resultID, *full.ResultID)
+	resultID = *full.ResultID
+
+	// the diagnostics are only collected again once the settings change
+	collector.messages = []string{"first", "second"}
+	require.IsType(t, protocol.RelatedUnchangedDocumentDiagnosticReport{}, pull(&resultID))
+	s.recomputeDiagnostics()
+	report = pull(&resultID)
+	require.IsType(t, protocol.RelatedFullDocumentDiagnosticReport{}, report)
+	full = report.(protocol.RelatedFullDocumentDiagnosticReport)
+	require.Equal(t, []protocol.Diagnostic{{Message: "first"}, {Message: "second"}}, full.Items)
+	require.NotEqual(t, resultID, *full.ResultID)
+	resultID = *full.ResultID
+
+	// saving the document collects its diagnostics again
+	calls := collector.calls
+	require.NoError(t, s.TextDocumentDidSave(&glsp.Context{Context: ctx}, &protocol.DidSaveTextDocumentParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: protocol.DocumentUri(u)},
+	}))
+	require.IsType(t, protocol.RelatedUnchangedDocumentDiagnosticReport{}, pull(&resultID))
+	require.Equal(t, calls+1, collector.calls)
+}
+
+func TestWorkspaceDiagnostic(t *testing.T) {
+	ctx := context.Background()
+	dockerfileURI := uri.URI("file:///tmp/Dockerfile")
+	bakeURI := uri.URI("file:///tmp/docker-bake.hcl")
+	collector := &staticCollector{messages: []string{"first"}}
+	s := NewServer(document.NewDocumentManager())
+	s.updateTelemetrySetting("off")
+	s.diagnosticsCollectors = []textdocument.DiagnosticsCollector{collector}
+
+	_, err := s.docs.Write(ctx, dockerfileURI, protocol.DockerfileLanguage, 1, []byte("FROM alpine"))
+	require.NoError(t, err)
+	_, err = s.docs.Write(ctx, bakeURI, protocol.DockerBakeLanguage, 3, []byte("target {}"))
+	require.NoError(t, err)
+
+	pull := func(previousResultIDs []protocol.PreviousResultId) []protocol.WorkspaceDocumentDiagnosticReport {
+		report, err := s.WorkspaceDiagnostic(&glsp.Context{Context: ctx}, &protocol.WorkspaceDiagnosticParams{PreviousResultIds: previousResultIDs})
+		require.NoError(t, err)
+		return report.Items
+	}
+
+	items := pull(nil)
+	require.Len(t, items, 2)
+	previousResultIDs := []protocol.PreviousResultId{}
+	for i, documentURI := range []uri.URI{dockerfileURI, bakeURI} {
+		require.IsType(t, protocol.WorkspaceFullDocumentDiagnosticReport{}, items[i])
+		full := items[i].(protocol.WorkspaceFullDocumentDiagnosticReport)
+		require.Equal(t, protocol.DocumentUri(documentURI), full.URI)
+		require.Equal(t, []protocol.Diagnostic{{Message: "first"}}, full.Items)
+		previousResultIDs = append(previousResultIDs, protocol.PreviousResultId{URI: full.URI, Value: *full.ResultID})
+	}
+	require.Equal(t, int32(3), *items[1].(protocol.WorkspaceFullDocumentDiagnosticReport).Version)
+	require.NotEqual(t, previousResultIDs[0].Value, previousResultIDs[1].Value)
+
+	// only the document that changed gets a full report
+	_, err = s.docs.Write(ctx, dockerfileURI, protocol.DockerfileLanguage, 2, []byte("FROM alpine:3.21"))
+	require.NoError(t, err)
+	items = pull(previousResultIDs)
+	require.Len(t, items, 2)
+	require.IsType(t, protocol.WorkspaceFullDocumentDiagnosticReport{}, items[0])
+	full := items[0].(protocol.WorkspaceFullDocumentDiagnosticReport)
+	require.Equal(t, protocol.DocumentUri(dockerfileURI), full.URI)
+	require.Equal(t, int32(2), *full.Version)
+	require.NotEqual(t, previousResultIDs[0].Value, *full.ResultID)
+	version := int32(3)
+	require.Equal(t, protocol.WorkspaceUnchangedDocumentDiagnosticReport{
+		UnchangedDocumentDiagnosticReport: protocol.UnchangedDocumentDiagnosticReport{
+			Kind:     string(protocol.DocumentDiagnosticReportKindUnchanged),
+			ResultID: previousResultIDs[1].Value,
+		},
+		URI:     protocol.DocumentUri(bakeURI),
+		Version: &version,
+	}, items[1])
+}
+
+func TestPullDiagnostics_Capabilities(t *testing.T) {
+	testCases := []struct {
+		name            string
+		textDocument    *protocol.TextDocumentClientCapabilities
+		pullDiagnostics bool
+	}{
+		{
+			name:            "no text document capabilities",
+			textDocument:    nil,
+			pullDiagnostics: false,
+		},
+		{
+			name:            "no diagnostic capabilities",
+			textDocument:    &protocol.TextDocumentClientCapabilities{},
+			pullDiagnostics: false,
+		},
+		{
+			name:            "diagnostic capabilities",
+			textDocument:    &protocol.TextDocumentClientCapabilities{Diagnostic: &protocol.DiagnosticClientCapabilities{}},
+			pullDiagnostics: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewServer(document.NewDocumentManager())
+			s.toggleSupportedFeatures(&protocol.InitializeParams{
+				Capabilities: protocol.ClientCapabilities{TextDocument: tc.textDocument},
+			})
+			require.Equal(t, tc.pullDiagnostics, s.pullDiagnostics)
+			capabilities := s.serverCapabilities(false, false)
+			if tc.pullDiagnostics {
+				require.Equal(t, protocol.DiagnosticOptions{WorkspaceDiagnostics: true}, capabilities.DiagnosticProvider)
+			} else {
+				require.Nil(t, capabilities.DiagnosticProvider)
+			}
+		})
+	}
+}
+
+func TestTextDocumentDiagnostic_Cancelled(t *testing.T) {
+	u := uri.URI("file:///tmp/Dockerfile")
+	collector := &staticCollector{messages: []string{"first"}}
+	s := NewServer(document.NewDocumentManager())
+	s.updateTelemetrySetting("off")
+	s.diagnosticsCollectors = []textdocument.DiagnosticsCollector{collector}
+	_, err := s.docs.Write(context.Background(), u, protocol.DockerfileLanguage, 1, []byte("FROM alpine"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.TextDocumentDiagnostic(&glsp.Context{Context: ctx}, &protocol.DocumentDiagnosticParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: protocol.DocumentUri(u)},
+	})
+	require.Equal(t, &jsonrpc2.Error{Code: requestCancelled, Message: "Request cancelled"}, err)
+	_, err = s.WorkspaceDiagnostic(&glsp.Context{Context: ctx}, &protocol.WorkspaceDiagnosticParams{})
+	require.Equal(t, &jsonrpc2.Error{Code: requestCancelled, Message: "Request cancelled"}, err)
+	require.Equal(t, 0, collector.calls)
+}
+
+func TestWorkspaceDidChangeConfiguration_DiagnosticRefresh(t *testing.T) {
+	testCases := []struct {
+		name         string
+		capabilities string
+		setting      string
+		refreshed    bool
+	}{
+		{
+			name:         "diagnostics setting changed",
+			capabilities: `{"textDocument":{"diagnostic":{}},"workspace":{"diagnostics":{"refreshSupport":true}}}`,
+			setting:      configuration.ConfigDiagnostics,
+			refreshed:    true,
+		},
+		{
+			name:         "Scout setting changed",
+			capabilities: `{"textDocument":{"diagnostic":{}},"workspace":{"diagnostics":{"refreshSupport":true}}}`,
+			setting:      configuration.ConfigExperimentalScoutVulnerabilities,
+			refreshed:    true,
+		},
+		{
+			name:         "setting that does not affect diagnostics changed",
+			capabilities: `{"textDocument":{"diagnostic":{}},"workspace":{"diagnostics":{"refreshSupport":true}}}`,
+			setting:      configuration.ConfigFoldingRegionStart,
+			refreshed:    false,
+		},
+		{
+			name:         "client does not support refreshes",
+			capabilities: `{"textDocument":{"diagnostic":{}},"workspace":{"diagnostics":{"refreshSupport":false}}}`,
+			setting:      configuration.ConfigDiagnostics,
+			refreshed:    false,
+		},
+		{
+			name:         "client does not declare workspace diagnostic capabilities",
+			capabilities: `{"textDocument":{"diagnostic":{}},"workspace":{}}`,
+			setting:      configuration.ConfigDiagnostics,
+			refreshed:    false,
+		},
+		{
+			name:         "client does not pull diagnostics",
+			capabilities: `{"workspace":{"diagnostics":{"refreshSupport":true}}}`,
+			setting:      configuration.ConfigDiagnostics,
+			refreshed:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			u := uri.URI("file:///tmp/Dockerfile")
+			collector := &staticCollector{messages: []string{"first"}}
+			s := NewServer(document.NewDocumentManager())
+			s.updateTelemetrySetting("off")
+			s.diagnosticsCollectors = []textdocument.DiagnosticsCollector{collector}
+			params := &protocol.InitializeParams{}
+			require.NoError(t, json.Unmarshal([]byte(tc.capabilities), &params.Capabilities))
+			s.toggleSupportedFeatures(params)
+
+			refreshed := make(chan struct{}, 1)
+			s.client = &LanguageClient{
+				call: func(ctx context.Context, method string, params any, result any) {
+					if method == string(protocol.ServerWorkspaceDiagnosticRefresh) {
+						refreshed <- struct{}{}
+					}
+				},
+				notify: func(ctx context.Context, method string, params any) {},
+			}
+
+			_, err := s.docs.Write(ctx, u, protocol.DockerfileLanguage, 1, []byte("FROM alpine"))
+			require.NoError(t, err)
+			configuration.Store(protocol.DocumentUri(u), configuration.Configuration{})
+			defer configuration.Remove(protocol.DocumentUri(u))
+			if s.pullDiagnostics {
+				_, err = s.pullDocumentDiagnostics(ctx, protocol.DocumentUri(u))
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, s.WorkspaceDidChangeConfiguration(&glsp.Context{Context: ctx}, &protocol.DidChangeConfigurationParams{
+				Settings: []any{tc.setting},
+			}))
+			if tc.refreshed {
+				select {
+				case <-refreshed:
+				case <-time.After(5 * time.Second):
+					t.Fatal("workspace/diagnostic/refresh was not sent")
+				}
+				// the diagnostics that the client pulls again are collected again
+				_, ok := s.docs.PulledDiagnostics(u)
+				require.False(t, ok)
+			} else {
+				select {
+				case <-refreshed:
+					t.Fatal("unexpected workspace/diagnostic/refresh")
+				case <-time.After(200 * time.Millisecond):
+				}
+			}
+		})
+	}
+}
+
+func TestPullDiagnostics_NothingPublished(t *testing.T) {
+	ctx := context.Background()
+	u := uri.URI("file:///tmp/Dockerfile")
+	published := make(chan any, 1)
+	s := NewServer(document.NewDocumentManager())
+	s.updateTelemetrySetting("off")
+	s.pullDiagnostics = true
+	s.diagnosticsCollectors = []textdocument.DiagnosticsCollector{&staticCollector{messages: []string{"first"}}}
+	s.client = &LanguageClient{notify: func(ctx context.Context, method string, params any) {
+		published <- params
+	}}
+
+	_, err := s.docs.Write(ctx, u, protocol.DockerfileLanguage, 1, []byte("FROM alpine"))
+	require.NoError(t, err)
+	s.computeDiagnostics(ctx, protocol.DocumentUri(u), true)
+	require.NoError(t, s.TextDocumentDidClose(&glsp.Context{Context: ctx}, &protocol.DidCloseTextDocumentParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: protocol.DocumentUri(u)},
+	}))
+	select {
+	case params := <-published:
+		t.Fatalf("unexpected diagnostics published: %v", params)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
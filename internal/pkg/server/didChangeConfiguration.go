@@ -9,6 +9,10 @@ import (
 func (s *Server) WorkspaceDidChangeConfiguration(ctx *glsp.Context, params *protocol.DidChangeConfigurationParams) error {
 	changedSettings, _ := params.Settings.([]any)
 	scoutConfigurationChanged := false
+	foldingConfigurationChanged := false
+	inlayHintsConfigurationChanged := false
+	composeConfigurationChanged := false
+	diagnosticsConfigurationChanged := false
 	for _, setting := range changedSettings {
 		config := setting.(string)
 		switch config {
@@ -24,17 +28,32 @@ func (s *Server) WorkspaceDidChangeConfiguration(ctx *glsp.Context, params *prot
 			fallthrough
 		case configuration.ConfigExperimentalScoutVulnerabilities:
 			scoutConfigurationChanged = true
+		case configuration.ConfigFoldingRegionStart:
+			fallthrough
+		case configuration.ConfigFoldingRegionEnd:
+			foldingConfigurationChanged = true
+		case configuration.ConfigInlayHintsResolvedPaths:
+			inlayHintsConfigurationChanged = true
+		case configuration.ConfigComposeImages:
+			composeConfigurationChanged = true
+		case configuration.ConfigDiagnostics:
+			diagnosticsConfigurationChanged = true
 		}
 	}
 
-	if scoutConfigurationChanged {
+	if scoutConfigurationChanged || foldingConfigurationChanged || inlayHintsConfigurationChanged || composeConfigurationChanged || diagnosticsConfigurationChanged {
 		scopes := configuration.Documents()
 		if len(scopes) > 0 {
 			go func() {
 				defer s.handlePanic("WorkspaceDidChangeConfiguration")
 
 				s.FetchConfigurations(scopes)
-				s.recomputeDiagnostics()
+				// the folding markers, inlay hint, and image settings are
+				// only read when the ranges, hints, or completion items
+				// are requested so the diagnostics do not need to change
+				if scoutConfigurationChanged || diagnosticsConfigurationChanged {
+					s.recomputeDiagnostics()
+				}
 			}()
 		}
 	}
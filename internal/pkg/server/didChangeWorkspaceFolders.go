@@ -0,0 +1,58 @@
+package server
+
+import (
+	"slices"
+
+	"github.com/docker/docker-language-server/internal/tliron/glsp"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/go-git/go-git/v5"
+)
+
+func (s *Server) WorkspaceDidChangeWorkspaceFolders(ctx *glsp.Context, params *protocol.DidChangeWorkspaceFoldersParams) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// the documents' references are resolved within their folders
+	defer func() { s.docs.SetWorkspaceFolders(s.workspaceFolders) }()
+
+	for _, workspaceFolder := range params.Event.Removed {
+		path, err := urlPath(workspaceFolder.URI)
+		if err != nil {
+			return err
+		}
+		s.workspaceFolders = slices.DeleteFunc(s.workspaceFolders, func(folder string) bool {
+			return folder == path
+		})
+		delete(s.gitRemotes, path)
+	}
+
+	for _, workspaceFolder := range params.Event.Added {
+		path, err := urlPath(workspaceFolder.URI)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(s.workspaceFolders, path) {
+			s.workspaceFolders = append(s.workspaceFolders, path)
+			s.recordGitRemote(path)
+		}
+	}
+	return nil
+}
+
+// recordGitRemote remembers the repository of the origin remote of the
+// given workspace folder if it is a Git repository. The caller must
+// hold the server's mutex.
+func (s *Server) recordGitRemote(workspaceFolder string) {
+	r, err := git.PlainOpen(workspaceFolder)
+	if err != nil {
+		return
+	}
+	remote, err := r.Remote("origin")
+	if err != nil {
+		return
+	}
+	config := remote.Config()
+	if config != nil && len(config.URLs) > 0 {
+		s.gitRemotes[workspaceFolder] = types.GitRepository(config.URLs[0])
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/docker/docker-language-server/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceDidChangeWorkspaceFolders(t *testing.T) {
+	s := NewServer(document.NewDocumentManager())
+	s.updateTelemetrySetting("off")
+	s.workspaceFolders = []string{"/workspace/a"}
+
+	err := s.WorkspaceDidChangeWorkspaceFolders(nil, &protocol.DidChangeWorkspaceFoldersParams{
+		Event: protocol.WorkspaceFoldersChangeEvent{
+			Added: []protocol.WorkspaceFolder{{URI: "file:///workspace/b", Name: "b"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"/workspace/a", "/workspace/b"}, s.workspaceFolders)
+
+	folder, _, relativePath := types.WorkspaceFolder("file:///workspace/b/compose.yaml", s.workspaceFolders)
+	require.Equal(t, "/workspace/b", folder)
+	require.Equal(t, "compose.yaml", relativePath)
+	require.False(t, s.docs.SameWorkspaceFolder("file:///workspace/a/compose.yaml", "file:///workspace/b/compose.yaml"))
+
+	err = s.WorkspaceDidChangeWorkspaceFolders(nil, &protocol.DidChangeWorkspaceFoldersParams{
+		Event: protocol.WorkspaceFoldersChangeEvent{
+			Removed: []protocol.WorkspaceFolder{{URI: "file:///workspace/a", Name: "a"}},
+			Added:   []protocol.WorkspaceFolder{{URI: "file:///workspace/b", Name: "b"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"/workspace/b"}, s.workspaceFolders)
+
+	folder, _, _ = types.WorkspaceFolder("file:///workspace/a/compose.yaml", s.workspaceFolders)
+	require.Equal(t, "", folder)
+	require.True(t, s.docs.SameWorkspaceFolder("file:///workspace/a/compose.yaml", "file:///workspace/b/compose.yaml"))
+}
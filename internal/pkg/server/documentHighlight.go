@@ -3,6 +3,7 @@ package server
 import (
 	"github.com/docker/docker-language-server/internal/bake/hcl"
 	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/dockerfile"
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -19,6 +20,8 @@ func (s *Server) TextDocumentDocumentHighlight(ctx *glsp.Context, params *protoc
 		return hcl.DocumentHighlight(doc.(document.BakeHCLDocument), params.Position)
 	} else if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
 		return compose.DocumentHighlight(doc.(document.ComposeDocument), params.Position)
+	} else if doc.LanguageIdentifier() == protocol.DockerfileLanguage {
+		return dockerfile.DocumentHighlight(doc.(document.DockerfileDocument), params.Position)
 	}
 	return nil, nil
 }
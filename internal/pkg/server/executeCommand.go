@@ -1,9 +1,12 @@
 package server
 
 import (
+	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
 	"github.com/docker/docker-language-server/internal/types"
+	"go.lsp.dev/uri"
 )
 
 func (s *Server) WorkspaceExecuteCommand(context *glsp.Context, params *protocol.ExecuteCommandParams) (any, error) {
@@ -13,6 +16,135 @@ func (s *Server) WorkspaceExecuteCommand(context *glsp.Context, params *protocol
 				s.Enqueue(event, properties)
 			}
 		}
+	} else if params.Command == types.SortServiceKeysCommandId && len(params.Arguments) == 2 {
+		documentURI, ok := params.Arguments[0].(string)
+		if !ok {
+			return nil, nil
+		}
+		serviceName, ok := params.Arguments[1].(string)
+		if !ok {
+			return nil, nil
+		}
+		return s.sortServiceKeys(context, documentURI, serviceName)
+	} else if params.Command == types.ToggleMappingFormCommandId && len(params.Arguments) == 2 {
+		documentURI, ok := params.Arguments[0].(string)
+		if !ok {
+			return nil, nil
+		}
+		position, ok := params.Arguments[1].(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		line, ok := position["line"].(float64)
+		if !ok {
+			return nil, nil
+		}
+		character, _ := position["character"].(float64)
+		return s.toggleMappingForm(context, documentURI, protocol.Position{Line: protocol.UInteger(line), Character: protocol.UInteger(character)})
+	} else if params.Command == types.StartupOrderCommandId && len(params.Arguments) == 1 {
+		documentURI, ok := params.Arguments[0].(string)
+		if !ok {
+			return nil, nil
+		}
+		return s.startupOrder(context, documentURI)
+	} else if params.Command == types.GenerateEnvTemplateCommandId && len(params.Arguments) == 1 {
+		documentURI, ok := params.Arguments[0].(string)
+		if !ok {
+			return nil, nil
+		}
+		return s.generateEnvTemplate(context, documentURI)
+	} else if params.Command == types.NormalizeReferencesCommandId && len(params.Arguments) == 2 {
+		documentURI, ok := params.Arguments[0].(string)
+		if !ok {
+			return nil, nil
+		}
+		form, ok := params.Arguments[1].(string)
+		if !ok {
+			return nil, nil
+		}
+		return s.normalizeReferences(context, documentURI, form)
+	}
+	return nil, nil
+}
+
+// sortServiceKeys returns a workspace edit that sorts the attributes
+// of the named service in the given Compose file.
+func (s *Server) sortServiceKeys(context *glsp.Context, documentURI, serviceName string) (any, error) {
+	doc, err := s.docs.Read(context.Context, uri.URI(documentURI))
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
+		if edit := compose.SortServiceKeys(doc.(document.ComposeDocument), serviceName); edit != nil {
+			return edit, nil
+		}
+	}
+	return nil, nil
+}
+
+// toggleMappingForm returns a workspace edit that rewrites the
+// attribute at the given position of the Compose file between its list
+// and mapping forms.
+func (s *Server) toggleMappingForm(context *glsp.Context, documentURI string, position protocol.Position) (any, error) {
+	doc, err := s.docs.Read(context.Context, uri.URI(documentURI))
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
+		if edit := compose.ToggleMappingForm(doc.(document.ComposeDocument), position); edit != nil {
+			return edit, nil
+		}
+	}
+	return nil, nil
+}
+
+// startupOrder returns the order that the services of the Compose file
+// are started in or the cycle that prevents them from being ordered.
+func (s *Server) startupOrder(context *glsp.Context, documentURI string) (any, error) {
+	doc, err := s.docs.Read(context.Context, uri.URI(documentURI))
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
+		if order := compose.ComputeStartupOrder(doc.(document.ComposeDocument)); order != nil {
+			return order, nil
+		}
+	}
+	return nil, nil
+}
+
+// generateEnvTemplate returns the content of a .env file that declares
+// the variables that are interpolated in the Compose file.
+func (s *Server) generateEnvTemplate(context *glsp.Context, documentURI string) (any, error) {
+	doc, err := s.docs.Read(context.Context, uri.URI(documentURI))
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
+		if template := compose.GenerateEnvTemplate(doc.(document.ComposeDocument)); template != "" {
+			return template, nil
+		}
+	}
+	return nil, nil
+}
+
+// normalizeReferences returns a workspace edit that rewrites the
+// depends_on attributes of the Compose file into the given form and
+// the attributes that could not be rewritten.
+func (s *Server) normalizeReferences(context *glsp.Context, documentURI, form string) (any, error) {
+	doc, err := s.docs.Read(context.Context, uri.URI(documentURI))
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
+		if result := compose.NormalizeReferences(doc.(document.ComposeDocument), form); result != nil {
+			return result, nil
+		}
 	}
 	return nil, nil
 }
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/configuration"
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"go.lsp.dev/uri"
+)
+
+func (s *Server) TextDocumentFoldingRange(ctx *glsp.Context, params *protocol.FoldingRangeParams) ([]protocol.FoldingRange, error) {
+	doc, err := s.docs.Read(ctx.Context, uri.URI(params.TextDocument.URI))
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
+		regionStart, regionEnd := configuration.Get(params.TextDocument.URI).Folding.RegionMarkers()
+		return compose.FoldingRange(doc.(document.ComposeDocument), regionStart, regionEnd)
+	}
+	return nil, nil
+}
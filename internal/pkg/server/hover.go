@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/docker-language-server/internal/bake/hcl"
 	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/dockerfile"
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -27,6 +28,10 @@ func (s *Server) TextDocumentHover(ctx *glsp.Context, params *protocol.HoverPara
 		}
 		return nil, nil
 	case protocol.DockerfileLanguage:
+		result, err := dockerfile.Hover(ctx.Context, params, doc.(document.DockerfileDocument))
+		if result != nil || err != nil {
+			return result, err
+		}
 		instruction := doc.(document.DockerfileDocument).Instruction(params.Position)
 		if instruction != nil && strings.EqualFold(instruction.Value, "FROM") && instruction.Next != nil {
 			return s.scoutService.Hover(ctx.Context, params.TextDocument.URI, instruction.Next.Value)
@@ -5,17 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
-	"slices"
 	"strings"
 
-	"github.com/docker/docker-language-server/internal/bake/hcl"
+	"github.com/docker/docker-language-server/internal/configuration"
 	"github.com/docker/docker-language-server/internal/pkg/buildkit"
 	"github.com/docker/docker-language-server/internal/pkg/cli/metadata"
 	"github.com/docker/docker-language-server/internal/telemetry"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
-	"github.com/docker/docker-language-server/internal/types"
-	"github.com/go-git/go-git/v5"
 	"github.com/sourcegraph/jsonrpc2"
 )
 
@@ -86,105 +83,64 @@ func (s *Server) Initialize(ctx *glsp.Context, params *protocol.InitializeParams
 			}
 		}
 
+		if value, ok := clientConfig["validateOnSave"].(bool); ok {
+			s.validateOnSave = value
+		}
+
 		if value, ok := clientConfig["telemetry"].(string); ok {
 			s.updateTelemetrySetting(value)
 		}
-	}
 
-	if len(workspaceFolders) > 0 {
-		s.workspaceFolders = workspaceFolders
-	} else if params.RootURI != nil {
-		path, err := urlPath(*params.RootURI)
-		if err != nil {
-			return nil, err
+		if settings, ok := clientConfig["diagnostics"].(map[string]any); ok {
+			diagnostics := map[string]configuration.DiagnosticSetting{}
+			for code, setting := range settings {
+				if value, ok := setting.(string); ok {
+					diagnostics[code] = configuration.DiagnosticSetting(value)
+				}
+			}
+			configuration.StoreInitializationDiagnostics(diagnostics)
 		}
-		s.workspaceFolders = []string{path}
-	} else if params.RootPath != nil {
-		s.workspaceFolders = []string{*params.RootPath}
 	}
 
-	if len(s.workspaceFolders) > 0 {
-		for i := range s.workspaceFolders {
-			r, err := git.PlainOpen(s.workspaceFolders[i])
-			if err == nil {
-				remote, err := r.Remote("origin")
-				if err == nil {
-					config := remote.Config()
-					if config != nil && len(config.URLs) > 0 {
-						s.gitRemotes[s.workspaceFolders[i]] = types.GitRepository(config.URLs[0])
-					}
-				}
+	if len(workspaceFolders) == 0 {
+		if params.RootURI != nil {
+			path, err := urlPath(*params.RootURI)
+			if err != nil {
+				return nil, err
 			}
+			workspaceFolders = []string{path}
+		} else if params.RootPath != nil {
+			workspaceFolders = []string{*params.RootPath}
 		}
 	}
 
-	var codeLensProvider *protocol.CodeLensOptions
-	if s.capabilities != nil && slices.Contains(s.capabilities.Capabilities.Commands, types.BakeBuildCommandId) {
-		codeLensProvider = &protocol.CodeLensOptions{}
+	s.mutex.Lock()
+	if len(workspaceFolders) > 0 {
+		s.workspaceFolders = workspaceFolders
+	}
+	for i := range s.workspaceFolders {
+		s.recordGitRemote(s.workspaceFolders[i])
 	}
+	s.docs.SetWorkspaceFolders(s.workspaceFolders)
+	s.mutex.Unlock()
 
 	s.toggleSupportedFeatures(params)
 
-	syncKind := protocol.TextDocumentSyncKindFull
+	dynamicFormatting, dynamicRename := dynamicRegistration(params)
+	if dynamicFormatting {
+		s.registerFormattingCapability()
+	}
+	if dynamicRename {
+		s.registerRenameCapability()
+	}
+
 	result := protocol.InitializeResult{
-		Capabilities: protocol.ServerCapabilities{
-			CodeActionProvider: protocol.CodeActionOptions{},
-			CodeLensProvider:   codeLensProvider,
-			CompletionProvider: &protocol.CompletionOptions{
-				TriggerCharacters: []string{"/"},
-			},
-			DefinitionProvider:        protocol.DefinitionOptions{},
-			DocumentHighlightProvider: protocol.DocumentHighlightOptions{},
-			DocumentLinkProvider:      &protocol.DocumentLinkOptions{},
-			DocumentSymbolProvider:    protocol.DocumentSymbolOptions{},
-			ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
-				Commands: []string{types.TelemetryCallbackCommandId},
-			},
-			HoverProvider:            protocol.HoverOptions{},
-			InlayHintProvider:        protocol.InlayHintOptions{},
-			InlineCompletionProvider: protocol.InlineCompletionOptions{},
-			SemanticTokensProvider: protocol.SemanticTokensOptions{
-				Legend: protocol.SemanticTokensLegend{
-					TokenModifiers: []string{},
-					TokenTypes:     hcl.SemanticTokenTypes,
-				},
-				Full:  true,
-				Range: false,
-			},
-			TextDocumentSync: protocol.TextDocumentSyncOptions{
-				OpenClose: &protocol.True,
-				Change:    &syncKind,
-			},
-		},
+		Capabilities: s.serverCapabilities(dynamicFormatting, dynamicRename),
 		ServerInfo: &protocol.InitializeResultServerInfo{
 			Name:    "docker-language-server",
 			Version: &metadata.Version,
 		},
 	}
-	dynamicFormatting := false
-	dynamicRename := false
-	if params.Capabilities.TextDocument != nil {
-		if params.Capabilities.TextDocument.Formatting != nil &&
-			params.Capabilities.TextDocument.Formatting.DynamicRegistration != nil &&
-			*params.Capabilities.TextDocument.Formatting.DynamicRegistration {
-			dynamicFormatting = true
-			s.registerFormattingCapability()
-		}
-		if params.Capabilities.TextDocument.Rename != nil &&
-			params.Capabilities.TextDocument.Rename.DynamicRegistration != nil &&
-			*params.Capabilities.TextDocument.Rename.DynamicRegistration {
-			dynamicRename = true
-			s.registerRenameCapability()
-		}
-	}
-	if !dynamicFormatting {
-		result.Capabilities.DocumentFormattingProvider = protocol.DocumentFormattingOptions{}
-	}
-	if !dynamicRename {
-		result.Capabilities.RenameProvider = protocol.RenameOptions{
-			PrepareProvider: types.CreateBoolPointer(true),
-		}
-	}
 	return result, nil
 }
 
@@ -195,6 +151,13 @@ func (s *Server) toggleSupportedFeatures(params *protocol.InitializeParams) {
 				s.definitionLinkSupport = *params.Capabilities.TextDocument.Definition.LinkSupport
 			}
 		}
+		s.pullDiagnostics = params.Capabilities.TextDocument.Diagnostic != nil
+	}
+
+	if params.Capabilities.Workspace != nil && params.Capabilities.Workspace.Diagnostics != nil {
+		if params.Capabilities.Workspace.Diagnostics.RefreshSupport != nil {
+			s.diagnosticRefreshSupport = *params.Capabilities.Workspace.Diagnostics.RefreshSupport
+		}
 	}
 
 	if params.Capabilities.Window != nil {
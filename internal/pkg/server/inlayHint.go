@@ -3,6 +3,7 @@ package server
 import (
 	"github.com/docker/docker-language-server/internal/bake/hcl"
 	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/configuration"
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -16,7 +17,8 @@ func (s *Server) TextDocumentInlayHint(ctx *glsp.Context, params *protocol.Inlay
 	}
 	defer doc.Close()
 	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
-		return compose.InlayHint(doc.(document.ComposeDocument), params.Range)
+		config := configuration.Get(params.TextDocument.URI)
+		return compose.InlayHint(doc.(document.ComposeDocument), params.Range, config.InlayHints.ResolvedPaths)
 	} else if doc.LanguageIdentifier() == protocol.DockerBakeLanguage {
 		return hcl.InlayHint(s.docs, doc.(document.BakeHCLDocument), params.Range)
 	}
@@ -40,6 +40,10 @@ func (c *LanguageClient) WorkspaceSemanticTokensRefresh(ctx context.Context) {
 	c.call(ctx, protocol.MethodWorkspaceSemanticTokensRefresh, nil, nil)
 }
 
+func (c *LanguageClient) WorkspaceDiagnosticRefresh(ctx context.Context) {
+	c.call(ctx, protocol.ServerWorkspaceDiagnosticRefresh, nil, nil)
+}
+
 func (c *LanguageClient) PublishDiagnostics(ctx context.Context, params protocol.PublishDiagnosticsParams) {
 	c.notify(ctx, protocol.ServerTextDocumentPublishDiagnostics, params)
 }
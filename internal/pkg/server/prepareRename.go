@@ -2,6 +2,7 @@ package server
 
 import (
 	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/dockerfile"
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -16,6 +17,8 @@ func (s *Server) TextDocumentPrepareRename(ctx *glsp.Context, params *protocol.P
 	defer doc.Close()
 	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
 		return compose.PrepareRename(doc.(document.ComposeDocument), params)
+	} else if doc.LanguageIdentifier() == protocol.DockerfileLanguage {
+		return dockerfile.PrepareRename(doc.(document.DockerfileDocument), params)
 	}
 	return nil, nil
 }
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/tliron/glsp"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/sourcegraph/jsonrpc2"
+	"go.lsp.dev/uri"
+)
+
+// requestCancelled is the error code that is returned for a request
+// that the client cancelled while it was being handled.
+const requestCancelled = -32800
+
+func (s *Server) TextDocumentReferences(ctx *glsp.Context, params *protocol.ReferenceParams) ([]protocol.Location, error) {
+	requestContext := ctx.Context
+	if requestContext == nil {
+		requestContext = context.Background()
+	}
+	doc, err := s.docs.Read(requestContext, uri.URI(params.TextDocument.URI))
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if doc.LanguageIdentifier() != protocol.DockerComposeLanguage || !s.composeSupport {
+		return nil, nil
+	}
+
+	notify := func(token *protocol.ProgressToken, value any) {
+		if token != nil && ctx.Notify != nil {
+			ctx.Notify(requestContext, string(protocol.MethodProgress), &protocol.ProgressParams{Token: *token, Value: value})
+		}
+	}
+	notify(params.WorkDoneToken, protocol.WorkDoneProgressBegin{Kind: "begin", Title: "Finding references"})
+	defer notify(params.WorkDoneToken, protocol.WorkDoneProgressEnd{Kind: "end"})
+
+	files := 0
+	var report func([]protocol.Location)
+	if params.WorkDoneToken != nil || params.PartialResultToken != nil {
+		report = func(locations []protocol.Location) {
+			files++
+			message := fmt.Sprintf("Found references in %v file(s)", files)
+			notify(params.WorkDoneToken, protocol.WorkDoneProgressReport{Kind: "report", Message: &message})
+			notify(params.PartialResultToken, locations)
+		}
+	}
+	locations, err := compose.References(requestContext, doc.(document.ComposeDocument), params, report)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, &jsonrpc2.Error{Code: requestCancelled, Message: "Request cancelled"}
+		}
+		return nil, err
+	}
+	if params.PartialResultToken != nil {
+		// every location has already been sent as a partial result
+		return []protocol.Location{}, nil
+	}
+	return locations, nil
+}
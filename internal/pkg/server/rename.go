@@ -2,6 +2,7 @@ package server
 
 import (
 	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/dockerfile"
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -16,6 +17,8 @@ func (s *Server) TextDocumentRename(ctx *glsp.Context, params *protocol.RenamePa
 	defer doc.Close()
 	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
 		return compose.Rename(doc.(document.ComposeDocument), params)
+	} else if doc.LanguageIdentifier() == protocol.DockerfileLanguage {
+		return dockerfile.Rename(doc.(document.DockerfileDocument), params)
 	}
 	return nil, nil
 }
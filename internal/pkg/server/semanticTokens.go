@@ -2,6 +2,7 @@ package server
 
 import (
 	"github.com/docker/docker-language-server/internal/bake/hcl"
+	"github.com/docker/docker-language-server/internal/compose"
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -20,6 +21,8 @@ func (s *Server) TextDocumentSemanticTokensFull(ctx *glsp.Context, params *proto
 			return nil, err
 		}
 		return &protocol.SemanticTokens{Data: result.Data}, nil
+	} else if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && s.composeSupport {
+		return compose.SemanticTokensFull(doc.(document.ComposeDocument)), nil
 	}
 
 	return nil, nil
@@ -5,6 +5,7 @@ import (
 	"io"
 	"runtime"
 	"runtime/debug"
+	"slices"
 	"sync"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/docker/docker-language-server/internal/bake/hcl"
 	"github.com/docker/docker-language-server/internal/compose"
 	"github.com/docker/docker-language-server/internal/configuration"
+	"github.com/docker/docker-language-server/internal/dockerfile"
 	"github.com/docker/docker-language-server/internal/pkg/buildkit"
 	"github.com/docker/docker-language-server/internal/pkg/cli/metadata"
 	"github.com/docker/docker-language-server/internal/pkg/document"
@@ -71,11 +73,30 @@ type Server struct {
 	composeSupport    bool
 	composeCompletion bool
 
+	// validateOnSave defers the diagnostics collectors that are
+	// expensive to run until a document has been saved.
+	validateOnSave bool
+
+	// pullDiagnostics is true if the client pulls the diagnostics of
+	// its documents so they should not be published to it.
+	pullDiagnostics bool
+
+	// diagnosticRefreshSupport is true if the client can be asked to
+	// pull the diagnostics of its documents again.
+	diagnosticRefreshSupport bool
+
 	mutex sync.RWMutex
+
+	// ctx is cancelled when the server is shutting down so that any
+	// lookups and background processes that are still running will
+	// stop instead of outliving the server.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func NewServer(docManager *document.Manager) *Server {
-	scoutService := scout.NewService()
+	ctx, cancel := context.WithCancel(context.Background())
+	scoutService := scout.NewService(ctx)
 	handler := protocol.Handler{}
 	sessionTelemetryProperties := make(map[string]string)
 	sessionTelemetryProperties["server_session"] = identity.NewID()
@@ -92,41 +113,61 @@ func NewServer(docManager *document.Manager) *Server {
 		sessionTelemetryProperties: sessionTelemetryProperties,
 		composeSupport:             true,
 		composeCompletion:          true,
+		ctx:                        ctx,
+		cancel:                     cancel,
 		diagnosticsCollectors: []textdocument.DiagnosticsCollector{
 			buildkit.NewBuildKitDiagnosticsCollector(),
+			dockerfile.NewDockerfileDiagnosticsCollector(),
 			scoutService,
 			compose.NewComposeDiagnosticsCollector(),
-			hcl.NewBakeHCLDiagnosticsCollector(docManager, scoutService),
+			compose.NewComposeDeferrableDiagnosticsCollector(),
+			hcl.NewBakeHCLDiagnosticsCollector(docManager),
+			hcl.NewBakeScoutDiagnosticsCollector(scoutService),
 		},
 	}
 
 	handler.Initialize = s.Initialize
 	handler.Initialized = s.Initialized
 	handler.Shutdown = s.shutdown
+	handler.Exit = s.exit
 	handler.SetTrace = s.setTrace
 
 	handler.TextDocumentCodeAction = s.TextDocumentCodeAction
 	handler.TextDocumentCodeLens = s.TextDocumentCodeLens
 	handler.TextDocumentCompletion = s.TextDocumentCompletion
 	handler.TextDocumentDefinition = s.TextDocumentDefinition
+	handler.TextDocumentDiagnostic = s.TextDocumentDiagnostic
 	handler.TextDocumentFormatting = s.TextDocumentFormatting
 	handler.TextDocumentDocumentHighlight = s.TextDocumentDocumentHighlight
 	handler.TextDocumentDocumentLink = s.TextDocumentDocumentLink
 	handler.TextDocumentDocumentSymbol = s.TextDocumentDocumentSymbol
+	handler.TextDocumentFoldingRange = s.TextDocumentFoldingRange
 	handler.TextDocumentHover = s.TextDocumentHover
 	handler.TextDocumentInlayHint = s.TextDocumentInlayHint
 	handler.TextDocumentInlineCompletion = s.TextDocumentInlineCompletion
 	handler.TextDocumentPrepareRename = s.TextDocumentPrepareRename
+	handler.TextDocumentReferences = s.TextDocumentReferences
 	handler.TextDocumentRename = s.TextDocumentRename
 	handler.TextDocumentSemanticTokensFull = s.TextDocumentSemanticTokensFull
 
 	handler.TextDocumentDidOpen = s.TextDocumentDidOpen
 	handler.TextDocumentDidChange = s.TextDocumentDidChange
+	handler.TextDocumentDidSave = s.TextDocumentDidSave
 	handler.TextDocumentDidClose = s.TextDocumentDidClose
 
 	handler.WorkspaceDidChangeConfiguration = s.WorkspaceDidChangeConfiguration
+	handler.WorkspaceDidChangeWorkspaceFolders = s.WorkspaceDidChangeWorkspaceFolders
+	handler.WorkspaceDiagnostic = s.WorkspaceDiagnostic
 	handler.WorkspaceExecuteCommand = s.WorkspaceExecuteCommand
 
+	// references are searched for in the included files so a client
+	// may want to cancel them while they are being searched for
+	s.gs.AsyncMethods = []string{
+		string(protocol.MethodTextDocumentReferences),
+		string(protocol.MethodTextDocumentDiagnostic),
+		string(protocol.MethodWorkspaceDiagnostic),
+	}
+
 	handler.Recover = func(method string, recovered interface{}) error {
 		if s.handleRecovered(method, recovered) {
 			return &jsonrpc2.Error{Code: -32803, Message: "Internal server error"}
@@ -154,28 +195,60 @@ func (s *Server) Initialized(context *glsp.Context, params *protocol.Initialized
 	return nil
 }
 
+// shutdown cancels any lookups that are still in progress and then
+// flushes the telemetry events that have not been published yet.
 func (s *Server) shutdown(ctx *glsp.Context) error {
+	s.cancel()
 	_, _ = s.telemetry.Publish(context.Background())
 	protocol.SetTraceValue(protocol.TraceValueOff)
 	return nil
 }
 
+// exit cancels the server's context in case the client exits without
+// shutting the server down first.
+func (s *Server) exit(ctx *glsp.Context) error {
+	s.cancel()
+	return nil
+}
+
 func (s *Server) setTrace(context *glsp.Context, params *protocol.SetTraceParams) error {
 	protocol.SetTraceValue(params.Value)
 	return nil
 }
 
 func (s *Server) WorkspaceFolders() []string {
-	return s.workspaceFolders
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return slices.Clone(s.workspaceFolders)
+}
+
+// workspaceFolder returns the workspace folder of the given document
+// and the Git remote of that folder along with the document's absolute
+// path and its path relative to the folder.
+func (s *Server) workspaceFolder(documentURI protocol.DocumentUri) (folder, remote, absolutePath, relativePath string) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	folder, absolutePath, relativePath = types.WorkspaceFolder(documentURI, s.workspaceFolders)
+	return folder, s.gitRemotes[folder], absolutePath, relativePath
 }
 
 func (s *Server) recomputeDiagnostics() {
 	for _, uri := range s.docs.Keys() {
-		doc := s.docs.Get(context.Background(), uri)
+		s.docs.InvalidatePulledDiagnostics(uri)
+		doc := s.docs.Get(s.ctx, uri)
 		if doc != nil {
-			s.computeDiagnostics(context.Background(), string(uri))
+			s.computeDiagnostics(s.ctx, string(uri), true)
 		}
 	}
+	s.refreshPulledDiagnostics()
+}
+
+// refreshPulledDiagnostics asks a client that pulls diagnostics to pull
+// them again if it supports being asked to.
+func (s *Server) refreshPulledDiagnostics() {
+	if s.pullDiagnostics && s.diagnosticRefreshSupport {
+		s.client.WorkspaceDiagnosticRefresh(s.ctx)
+	}
 }
 
 func (s *Server) StartBackgrondProcesses(ctx context.Context) {
@@ -186,12 +259,15 @@ func (s *Server) publishTelemetry(ctx context.Context) {
 	go func() {
 		defer s.handlePanic("publishTelemetry")
 
+		ticker := time.NewTicker(time.Second * 60)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			default:
-				time.Sleep(time.Second * 60)
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
 				_, _ = s.telemetry.Publish(ctx)
 			}
 		}
@@ -298,16 +374,28 @@ func (s *Server) registerFormattingCapability() {
 }
 
 func (s *Server) registerRenameCapability() {
+	dockerfileLanguage := string(protocol.DockerfileLanguage)
 	dockercomposeLanguage := string(protocol.DockerComposeLanguage)
-	dockercomposeDocumentSelctor := protocol.DocumentSelector{protocol.DocumentFilter{Language: &dockercomposeLanguage}}
 	s.registerCapability(
 		[]protocol.Registration{
+			{
+				ID:     "docker.lsp.dockerfile.textDocument.rename",
+				Method: "textDocument/rename",
+				RegisterOptions: protocol.RenameRegistrationOptions{
+					TextDocumentRegistrationOptions: protocol.TextDocumentRegistrationOptions{
+						DocumentSelector: &protocol.DocumentSelector{protocol.DocumentFilter{Language: &dockerfileLanguage}},
+					},
+					RenameOptions: protocol.RenameOptions{
+						PrepareProvider: types.CreateBoolPointer(true),
+					},
+				},
+			},
 			{
 				ID:     "docker.lsp.dockercompose.textDocument.rename",
 				Method: "textDocument/rename",
 				RegisterOptions: protocol.RenameRegistrationOptions{
 					TextDocumentRegistrationOptions: protocol.TextDocumentRegistrationOptions{
-						DocumentSelector: &dockercomposeDocumentSelctor,
+						DocumentSelector: &protocol.DocumentSelector{protocol.DocumentFilter{Language: &dockercomposeLanguage}},
 					},
 					RenameOptions: protocol.RenameOptions{
 						PrepareProvider: types.CreateBoolPointer(true),
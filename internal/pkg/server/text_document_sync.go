@@ -6,6 +6,8 @@ import (
 	"os"
 
 	"github.com/docker/docker-language-server/internal/configuration"
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/pkg/lsp/textdocument"
 	"github.com/docker/docker-language-server/internal/telemetry"
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -19,7 +21,7 @@ func (s *Server) TextDocumentDidOpen(ctx *glsp.Context, params *protocol.DidOpen
 		defer s.handlePanic("TextDocumentDidOpen")
 
 		s.FetchConfigurations([]protocol.DocumentUri{params.TextDocument.URI})
-		s.computeDiagnostics(ctx.Context, params.TextDocument.URI)
+		s.computeDiagnostics(ctx.Context, params.TextDocument.URI, true)
 	}()
 	return nil
 }
@@ -29,16 +31,18 @@ func (s *Server) TextDocumentDidChange(ctx *glsp.Context, params *protocol.DidCh
 		return nil
 	}
 
-	if changeEvent, ok := params.ContentChanges[0].(protocol.TextDocumentContentChangeEvent); ok {
-		changed, _ := s.docs.Overwrite(ctx.Context, uri.URI(params.TextDocument.URI), params.TextDocument.Version, []byte(changeEvent.Text))
-		if changed {
-			s.computeDiagnostics(ctx.Context, params.TextDocument.URI)
-		}
-	} else if changeEventWhole, ok := params.ContentChanges[0].(protocol.TextDocumentContentChangeEventWhole); ok {
-		changed, _ := s.docs.Overwrite(ctx.Context, uri.URI(params.TextDocument.URI), params.TextDocument.Version, []byte(changeEventWhole.Text))
-		if changed {
-			s.computeDiagnostics(ctx.Context, params.TextDocument.URI)
-		}
+	changed, _ := s.docs.ApplyChanges(ctx.Context, uri.URI(params.TextDocument.URI), params.TextDocument.Version, params.ContentChanges)
+	s.docs.InvalidatePulledDiagnostics(uri.URI(params.TextDocument.URI))
+	if changed {
+		s.computeDiagnostics(ctx.Context, params.TextDocument.URI, false)
+	}
+	return nil
+}
+
+func (s *Server) TextDocumentDidSave(ctx *glsp.Context, params *protocol.DidSaveTextDocumentParams) error {
+	s.docs.InvalidatePulledDiagnostics(uri.URI(params.TextDocument.URI))
+	if s.validateOnSave {
+		s.computeDiagnostics(ctx.Context, params.TextDocument.URI, true)
 	}
 	return nil
 }
@@ -46,6 +50,9 @@ func (s *Server) TextDocumentDidChange(ctx *glsp.Context, params *protocol.DidCh
 func (s *Server) TextDocumentDidClose(ctx *glsp.Context, params *protocol.DidCloseTextDocumentParams) error {
 	s.docs.Remove(uri.URI(params.TextDocument.URI))
 	configuration.Remove(params.TextDocument.URI)
+	if s.pullDiagnostics {
+		return nil
+	}
 	// clear out all existing diagnostics when the editor has been closed
 	s.client.PublishDiagnostics(context.Background(), protocol.PublishDiagnosticsParams{
 		URI:         params.TextDocument.URI,
@@ -54,17 +61,23 @@ func (s *Server) TextDocumentDidClose(ctx *glsp.Context, params *protocol.DidClo
 	return nil
 }
 
-func (s *Server) computeDiagnostics(ctx context.Context, documentURI protocol.DocumentUri) {
+// computeDiagnostics collects and publishes the diagnostics of the
+// given document. If the server has been configured to validate on
+// save then the deferrable collectors will only be run if runDeferred
+// is true. Otherwise, the diagnostics that they last reported will be
+// published again. Nothing is published to a client that pulls
+// diagnostics, the deferrable collectors are only run for it so that
+// their diagnostics can be included in what it pulls.
+func (s *Server) computeDiagnostics(ctx context.Context, documentURI protocol.DocumentUri, runDeferred bool) {
 	doc := s.docs.Get(ctx, uri.URI(documentURI))
 	if doc == nil {
 		return
 	}
 
-	folder, absolutePath, relativePath := types.WorkspaceFolder(documentURI, s.workspaceFolders)
+	folder, remote, absolutePath, relativePath := s.workspaceFolder(documentURI)
 	if folder == "" {
 		s.recordAnalysis(doc.LanguageIdentifier(), "unversioned", absolutePath)
 	} else {
-		remote := s.gitRemotes[folder]
 		if remote == "" {
 			s.recordAnalysis(doc.LanguageIdentifier(), "unversioned", absolutePath)
 		} else {
@@ -75,6 +88,9 @@ func (s *Server) computeDiagnostics(ctx context.Context, documentURI protocol.Do
 	if doc.LanguageIdentifier() == protocol.DockerComposeLanguage && !s.composeSupport {
 		return
 	}
+	if s.pullDiagnostics && (!s.validateOnSave || !runDeferred) {
+		return
+	}
 
 	s.docs.Queue(ctx, uri.URI(documentURI), func() {
 		defer s.handlePanic("computeDiagnostics")
@@ -89,18 +105,14 @@ func (s *Server) computeDiagnostics(ctx context.Context, documentURI protocol.Do
 		doc = doc.Copy()
 		defer doc.Close()
 
-		folder = types.StripLeadingSlash(folder)
-		diagnostics := []protocol.Diagnostic{}
-		for _, collector := range s.diagnosticsCollectors {
-			if collector.SupportsLanguageIdentifier(doc.LanguageIdentifier()) {
-				if folder == "" {
-					diagnostics = append(diagnostics, collector.CollectDiagnostics("docker-language-server", os.TempDir(), doc, string(doc.Input()))...)
-				} else {
-					diagnostics = append(diagnostics, collector.CollectDiagnostics("docker-language-server", folder, doc, string(doc.Input()))...)
-				}
-			}
+		diagnostics := s.collectDiagnostics(documentURI, folder, doc, runDeferred)
+		if s.pullDiagnostics {
+			// the deferred diagnostics have changed so what the client
+			// last pulled is out of date
+			s.docs.InvalidatePulledDiagnostics(uri.URI(documentURI))
+			s.refreshPulledDiagnostics()
+			return
 		}
-
 		version := doc.Version()
 		s.client.PublishDiagnostics(context.Background(), protocol.PublishDiagnosticsParams{
 			URI:         documentURI,
@@ -110,6 +122,45 @@ func (s *Server) computeDiagnostics(ctx context.Context, documentURI protocol.Do
 	})
 }
 
+// collectDiagnostics runs the collectors that support the given
+// document and applies the diagnostic settings to what they report.
+// If the server has been configured to validate on save then the
+// deferrable collectors will only be run if runDeferred is true.
+// Otherwise, the diagnostics that they last reported will be used.
+func (s *Server) collectDiagnostics(documentURI protocol.DocumentUri, folder string, doc document.Document, runDeferred bool) []protocol.Diagnostic {
+	folder = types.StripLeadingSlash(folder)
+	if folder == "" {
+		folder = os.TempDir()
+	}
+	diagnostics := []protocol.Diagnostic{}
+	deferredDiagnostics := []protocol.Diagnostic{}
+	for _, collector := range s.diagnosticsCollectors {
+		if collector.SupportsLanguageIdentifier(doc.LanguageIdentifier()) {
+			if s.validateOnSave && deferrable(collector) {
+				if runDeferred {
+					deferredDiagnostics = append(deferredDiagnostics, collector.CollectDiagnostics("docker-language-server", folder, doc, string(doc.Input()))...)
+				}
+			} else {
+				diagnostics = append(diagnostics, collector.CollectDiagnostics("docker-language-server", folder, doc, string(doc.Input()))...)
+			}
+		}
+	}
+	if s.validateOnSave {
+		if runDeferred {
+			s.docs.SetDeferredDiagnostics(uri.URI(documentURI), deferredDiagnostics)
+		}
+		diagnostics = append(diagnostics, s.docs.DeferredDiagnostics(uri.URI(documentURI))...)
+	}
+	return configuration.ApplyDiagnosticSettings(documentURI, diagnostics)
+}
+
+func deferrable(collector textdocument.DiagnosticsCollector) bool {
+	if c, ok := collector.(textdocument.DeferrableDiagnosticsCollector); ok {
+		return c.Deferrable()
+	}
+	return false
+}
+
 // recordAnalysis queues a telemetry event to record that the given path
 // under the specified Git remote has been analyzed. gitRemote and path
 // will be hashed before it is sent to the telemetry backend.
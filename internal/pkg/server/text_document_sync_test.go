@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker-language-server/internal/compose"
+	"github.com/docker/docker-language-server/internal/pkg/document"
+	"github.com/docker/docker-language-server/internal/pkg/lsp/textdocument"
+	"github.com/docker/docker-language-server/internal/tliron/glsp"
+	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/uri"
+)
+
+type countingCollector struct {
+	name       string
+	deferrable bool
+	calls      atomic.Int32
+}
+
+func (c *countingCollector) CollectDiagnostics(source, workspaceFolder string, doc document.Document, text string) []protocol.Diagnostic {
+	calls := c.calls.Add(1)
+	return []protocol.Diagnostic{{Message: fmt.Sprintf("%v %v", c.name, calls)}}
+}
+
+func (c *countingCollector) SupportsLanguageIdentifier(languageIdentifier protocol.LanguageIdentifier) bool {
+	return true
+}
+
+func (c *countingCollector) Deferrable() bool {
+	return c.deferrable
+}
+
+func diagnosticMessages(params protocol.PublishDiagnosticsParams) []string {
+	messages := []string{}
+	for _, diagnostic := range params.Diagnostics {
+		messages = append(messages, diagnostic.Message)
+	}
+	return messages
+}
+
+func TestComputeDiagnostics_ValidateOnSave(t *testing.T) {
+	testCases := []struct {
+		name           string
+		validateOnSave bool
+		opened         []string
+		changed        []string
+		saved          []string
+	}{
+		{
+			name:           "validateOnSave disabled",
+			validateOnSave: false,
+			opened:         []string{"live 1", "deferred 1"},
+			changed:        []string{"live 2", "deferred 2"},
+			saved:          nil,
+		},
+		{
+			name:           "validateOnSave enabled",
+			validateOnSave: true,
+			opened:         []string{"live 1", "deferred 1"},
+			changed:        []string{"live 2", "deferred 1"},
+			saved:          []string{"live 3", "deferred 2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			u := uri.URI("file:///tmp/Dockerfile")
+			published := make(chan protocol.PublishDiagnosticsParams, 1)
+			s := NewServer(document.NewDocumentManager())
+			s.updateTelemetrySetting("off")
+			s.validateOnSave = tc.validateOnSave
+			s.diagnosticsCollectors = []textdocument.DiagnosticsCollector{
+				&countingCollector{name: "live"},
+				&countingCollector{name: "deferred", deferrable: true},
+			}
+			s.client = &LanguageClient{notify: func(ctx context.Context, method string, params any) {
+				published <- params.(protocol.PublishDiagnosticsParams)
+			}}
+
+			receive := func() []string {
+				select {
+				case params := <-published:
+					return diagnosticMessages(params)
+				case <-time.After(5 * time.Second):
+					t.Fatal("diagnostics were not published")
+				}
+				return nil
+			}
+
+			_, err := s.docs.Write(ctx, u, protocol.DockerfileLanguage, 1, []byte("FROM alpine"))
+			require.NoError(t, err)
+			s.computeDiagnostics(ctx, protocol.DocumentUri(u), true)
+			require.Equal(t, tc.opened, receive())
+
+			_, err = s.docs.Write(ctx, u, protocol.DockerfileLanguage, 2, []byte("FROM alpine:3.21"))
+			require.NoError(t, err)
+			s.computeDiagnostics(ctx, protocol.DocumentUri(u), false)
+			require.Equal(t, tc.changed, receive())
+
+			err = s.TextDocumentDidSave(&glsp.Context{Context: ctx}, &protocol.DidSaveTextDocumentParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: protocol.DocumentUri(u)},
+			})
+			require.NoError(t, err)
+			if tc.saved == nil {
+				select {
+				case params := <-published:
+					t.Fatalf("unexpected diagnostics published on save: %v", diagnosticMessages(params))
+				case <-time.After(200 * time.Millisecond):
+				}
+			} else {
+				require.Equal(t, tc.saved, receive())
+			}
+		})
+	}
+}
+
+func TestComputeDiagnostics_ValidateOnSaveCrossFile(t *testing.T) {
+	ctx := context.Background()
+	folder := t.TempDir()
+	u := uri.URI(fmt.Sprintf("file:///%v", strings.TrimPrefix(filepath.ToSlash(filepath.Join(folder, "compose.yaml")), "/")))
+	published := make(chan protocol.PublishDiagnosticsParams, 1)
+	s := NewServer(document.NewDocumentManager())
+	s.updateTelemetrySetting("off")
+	s.validateOnSave = true
+	s.diagnosticsCollectors = []textdocument.DiagnosticsCollector{
+		compose.NewComposeDiagnosticsCollector(),
+		compose.NewComposeDeferrableDiagnosticsCollector(),
+	}
+	s.client = &LanguageClient{notify: func(ctx context.Context, method string, params any) {
+		published <- params.(protocol.PublishDiagnosticsParams)
+	}}
+
+	receive := func() []string {
+		select {
+		case params := <-published:
+			codes := []string{}
+			for _, diagnostic := range params.Diagnostics {
+				codes = append(codes, diagnostic.Code.Value.(string))
+			}
+			return codes
+		case <-time.After(5 * time.Second):
+			t.Fatal("diagnostics were not published")
+		}
+		return nil
+	}
+
+	_, err := s.docs.Write(ctx, u, protocol.DockerComposeLanguage, 1, []byte("services:\n  web:\n    image: alpine\n    env_file: app.env"))
+	require.NoError(t, err)
+	s.computeDiagnostics(ctx, protocol.DocumentUri(u), true)
+	require.Equal(t, []string{"FileNotFound"}, receive())
+
+	// the env file is only looked for again when the file is saved
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "app.env"), []byte("A=B"), 0644))
+	_, err = s.docs.Write(ctx, u, protocol.DockerComposeLanguage, 2, []byte("services:\n  web:\n    image: alpine\n    env_file: app.env\n    scale: 2"))
+	require.NoError(t, err)
+	s.computeDiagnostics(ctx, protocol.DocumentUri(u), false)
+	require.Equal(t, []string{"DeprecatedScale", "FileNotFound"}, receive())
+
+	err = s.TextDocumentDidSave(&glsp.Context{Context: ctx}, &protocol.DidSaveTextDocumentParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: protocol.DocumentUri(u)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"DeprecatedScale"}, receive())
+}
+
+func TestCollectDiagnostics_Codes(t *testing.T) {
+	testCases := []struct {
+		name       string
+		u          uri.URI
+		identifier protocol.LanguageIdentifier
+		content    string
+	}{
+		{
+			name:       "Dockerfile",
+			u:          "file:///tmp/Dockerfile",
+			identifier: protocol.DockerfileLanguage,
+			content:    "FROM scratch\nCOPY --chown=a:b:c src dest\nEXPOSE abc",
+		},
+		{
+			name:       "Compose file with a syntax error",
+			u:          "file:///tmp/compose.yaml",
+			identifier: protocol.DockerComposeLanguage,
+			content:    "services:\n\tweb:",
+		},
+		{
+			name:       "Compose file with validation errors",
+			u:          "file:///tmp/compose.yaml",
+			identifier: protocol.DockerComposeLanguage,
+			content:    "services:\n  web:\n    image: \"\"\n    scale: 2\n    unknown: value\n    ports:\n      - 80:80\n      - 80:80\n    depends_on:\n      - missing",
+		},
+		{
+			name:       "Bake file with a syntax error",
+			u:          "file:///tmp/docker-bake.hcl",
+			identifier: protocol.DockerBakeLanguage,
+			content:    "target {\n}",
+		},
+		{
+			name:       "Bake file with validation errors",
+			u:          "file:///tmp/docker-bake.hcl",
+			identifier: protocol.DockerBakeLanguage,
+			content:    "group \"default\" {\n  targets = [\"missing\"]\n}\ntarget \"t\" {\n  network = \"abc\"\n  entitlements = [\"abc\"]\n  dockerfile-inline = \"FROM scratch\"\n  dockerfile = \"Dockerfile\"\n}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewServer(document.NewDocumentManager())
+			s.updateTelemetrySetting("off")
+			_, err := s.docs.Write(context.Background(), tc.u, tc.identifier, 1, []byte(tc.content))
+			require.NoError(t, err)
+			doc := s.docs.Get(context.Background(), tc.u)
+			diagnostics := s.collectDiagnostics(protocol.DocumentUri(tc.u), "", doc, true)
+			require.NotEmpty(t, diagnostics)
+			for _, diagnostic := range diagnostics {
+				require.NotNil(t, diagnostic.Code, "diagnostic without a code: %v", diagnostic.Message)
+				require.NotEmpty(t, diagnostic.Code.Value, "diagnostic without a code: %v", diagnostic.Message)
+			}
+		})
+	}
+}
@@ -15,7 +15,7 @@ import (
 
 type LanguageGatewayClient interface {
 	PostImage(ctx context.Context, jwt, image string) (ImageResponse, error)
-	Fetch(key cache.Key) (ImageResponse, error)
+	Fetch(ctx context.Context, key cache.Key) (ImageResponse, error)
 }
 
 type LanguageGatewayClientImpl struct {
@@ -32,10 +32,10 @@ func NewLanguageGatewayClient() LanguageGatewayClient {
 	}
 }
 
-func (c LanguageGatewayClientImpl) Fetch(key cache.Key) (ImageResponse, error) {
+func (c LanguageGatewayClientImpl) Fetch(ctx context.Context, key cache.Key) (ImageResponse, error) {
 	scoutKey, ok := key.(*ScoutImageKey)
 	if ok {
-		return c.PostImage(context.Background(), "", scoutKey.Image)
+		return c.PostImage(ctx, "", scoutKey.Image)
 	}
 	return ImageResponse{}, errors.New("unrecognized key provided")
 }
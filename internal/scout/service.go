@@ -19,12 +19,16 @@ type Service interface {
 }
 
 type ServiceImpl struct {
+	// ctx is cancelled when the server shuts down so that lookups that
+	// are not tied to a request do not outlive it
+	ctx     context.Context
 	manager cache.CacheManager[ImageResponse]
 }
 
-func NewService() Service {
+func NewService(ctx context.Context) Service {
 	client := NewLanguageGatewayClient()
 	return &ServiceImpl{
+		ctx:     ctx,
 		manager: cache.NewManager(client),
 	}
 }
@@ -35,7 +39,7 @@ func (s *ServiceImpl) Hover(ctx context.Context, documentURI protocol.DocumentUr
 		return nil, nil
 	}
 
-	resp, err := s.manager.Get(&ScoutImageKey{Image: image})
+	resp, err := s.manager.Get(ctx, &ScoutImageKey{Image: image})
 	if err == nil {
 		hovers := []string{}
 		for _, info := range resp.Infos {
@@ -69,7 +73,7 @@ func (s *ServiceImpl) Analyze(documentURI protocol.DocumentUri, image string) ([
 		return nil, nil
 	}
 
-	resp, err := s.manager.Get(&ScoutImageKey{Image: image})
+	resp, err := s.manager.Get(s.ctx, &ScoutImageKey{Image: image})
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +107,7 @@ func (s *ServiceImpl) CalculateDiagnostics(ctx context.Context, source string, d
 	lines := strings.Split(string(doc.Input()), "\n")
 	for _, child := range doc.(document.DockerfileDocument).Nodes() {
 		if strings.EqualFold(child.Value, "FROM") && child.Next != nil {
-			resp, err := s.manager.Get(&ScoutImageKey{Image: child.Next.Value})
+			resp, err := s.manager.Get(ctx, &ScoutImageKey{Image: child.Next.Value})
 			if err == nil {
 				next := child.Next
 				prefix := []string{child.Value}
@@ -163,10 +167,16 @@ func (s *ServiceImpl) CalculateDiagnostics(ctx context.Context, source string, d
 }
 
 func (s *ServiceImpl) CollectDiagnostics(source, workspaceFolder string, doc document.Document, text string) []protocol.Diagnostic {
-	diagnostics, _ := s.CalculateDiagnostics(context.Background(), source, doc)
+	diagnostics, _ := s.CalculateDiagnostics(s.ctx, source, doc)
 	return diagnostics
 }
 
+// Deferrable returns true as analyzing images requires calls to Docker
+// Scout.
+func (s *ServiceImpl) Deferrable() bool {
+	return true
+}
+
 func (c *ServiceImpl) SupportsLanguageIdentifier(languageIdentifier protocol.LanguageIdentifier) bool {
 	return languageIdentifier == protocol.DockerfileLanguage
 }
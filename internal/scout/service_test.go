@@ -7,7 +7,9 @@ import (
 	"net"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/docker/docker-language-server/internal/cache"
 	"github.com/docker/docker-language-server/internal/configuration"
 	"github.com/docker/docker-language-server/internal/pkg/document"
 	"github.com/docker/docker-language-server/internal/tliron/glsp/protocol"
@@ -79,7 +81,7 @@ func TestCalculateDiagnostics(t *testing.T) {
 		},
 	}
 
-	c := NewService()
+	c := NewService(context.Background())
 	for _, tc := range testCases {
 		uri := uri.URI("uri:///Dockerfile")
 		doc := document.NewDocument(document.NewDocumentManager(), uri, protocol.DockerfileLanguage, 1, []byte(tc.content))
@@ -173,7 +175,7 @@ func TestCalculateDiagnostics_IgnoresSpecifics(t *testing.T) {
 		},
 	}
 
-	c := NewService()
+	c := NewService(context.Background())
 	for _, tc := range testCases {
 		uri := uri.URI("uri:///Dockerfile")
 		doc := document.NewDocument(document.NewDocumentManager(), uri, protocol.DockerfileLanguage, 1, []byte(tc.content))
@@ -283,7 +285,7 @@ func TestGetHovers(t *testing.T) {
 	}
 
 	u := "file:///tmp/Dockerfile"
-	s := NewService()
+	s := NewService(context.Background())
 	for _, tc := range testCases {
 		t.Run(tc.image, func(t *testing.T) {
 			hover, err := s.Hover(context.Background(), u, tc.image)
@@ -429,7 +431,7 @@ func TestGetHovers_IgnoresSpecifics(t *testing.T) {
 	}
 
 	u := "file:///tmp/Dockerfile"
-	s := NewService()
+	s := NewService(context.Background())
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			defer configuration.Remove(u)
@@ -473,3 +475,23 @@ func TestGetHovers_IgnoresSpecifics(t *testing.T) {
 		})
 	}
 }
+
+// hangingClient never responds until the request has been cancelled.
+type hangingClient struct{}
+
+func (c *hangingClient) Fetch(ctx context.Context, key cache.Key) (ImageResponse, error) {
+	<-ctx.Done()
+	return ImageResponse{}, ctx.Err()
+}
+
+func TestHover_Cancelled(t *testing.T) {
+	s := &ServiceImpl{ctx: context.Background(), manager: cache.NewManager[ImageResponse](&hangingClient{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	hover, err := s.Hover(ctx, "file:///tmp/Dockerfile", "alpine:3.16.1")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Nil(t, hover)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
@@ -69,7 +69,7 @@ func (self BoolOrString) MarshalJSON() ([]byte, error) {
 }
 
 // ([json.Unmarshaler] interface)
-func (self BoolOrString) UnmarshalJSON(data []byte) error {
+func (self *BoolOrString) UnmarshalJSON(data []byte) error {
 	var value bool
 	if err := json.Unmarshal(data, &value); err == nil {
 		self.Value = value
@@ -347,6 +347,13 @@ type ClientCapabilities struct {
 		 */
 		CodeLens *CodeLensWorkspaceClientCapabilities `json:"codeLens,omitempty"`
 
+		/**
+		 * Client workspace capabilities specific to diagnostics.
+		 *
+		 * @since 3.17.0
+		 */
+		Diagnostics *DiagnosticWorkspaceClientCapabilities `json:"diagnostics,omitempty"`
+
 		/**
 		 * The client has support for file requests/notifications.
 		 *
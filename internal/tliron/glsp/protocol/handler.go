@@ -29,6 +29,7 @@ type Handler struct {
 	WorkspaceDidChangeConfiguration    WorkspaceDidChangeConfigurationFunc
 	WorkspaceDidChangeWatchedFiles     WorkspaceDidChangeWatchedFilesFunc
 	WorkspaceSymbol                    WorkspaceSymbolFunc
+	WorkspaceDiagnostic                WorkspaceDiagnosticFunc
 	WorkspaceExecuteCommand            WorkspaceExecuteCommandFunc
 	WorkspaceWillCreateFiles           WorkspaceWillCreateFilesFunc
 	WorkspaceDidCreateFiles            WorkspaceDidCreateFilesFunc
@@ -245,6 +246,16 @@ func (self *Handler) Handle(context *glsp.Context) (r any, validMethod bool, val
 			}
 		}
 
+	case MethodWorkspaceDiagnostic:
+		if self.WorkspaceDiagnostic != nil {
+			validMethod = true
+			var params WorkspaceDiagnosticParams
+			if err = json.Unmarshal(context.Params, &params); err == nil {
+				validParams = true
+				r, err = self.WorkspaceDiagnostic(context, &params)
+			}
+		}
+
 	case MethodWorkspaceExecuteCommand:
 		if self.WorkspaceExecuteCommand != nil {
 			validMethod = true
@@ -1020,7 +1031,7 @@ func (self *Handler) CreateServerCapabilities() ServerCapabilities {
 	if self.TextDocumentDiagnostic != nil {
 		capabilities.DiagnosticProvider = DiagnosticOptions{
 			InterFileDependencies: false,
-			WorkspaceDiagnostics:  false,
+			WorkspaceDiagnostics:  self.WorkspaceDiagnostic != nil,
 		}
 	}
 
@@ -2,8 +2,10 @@ package server
 
 import (
 	contextpkg "context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
 	"github.com/sourcegraph/jsonrpc2"
@@ -12,7 +14,66 @@ import (
 // See: https://github.com/sourcegraph/go-langserver/blob/master/langserver/handler.go#L206
 
 func (self *Server) newHandler() jsonrpc2.Handler {
-	return jsonrpc2.HandlerWithError(self.handle)
+	return &cancellableHandler{server: self, handler: jsonrpc2.HandlerWithError(self.handle)}
+}
+
+// cancellableHandler gives every request its own context that is
+// cancelled when the client sends a $/cancelRequest for it. Requests
+// are handled in the order that they are received unless their method
+// is one of the server's asynchronous methods.
+type cancellableHandler struct {
+	server  *Server
+	handler jsonrpc2.Handler
+}
+
+func (self *cancellableHandler) Handle(context contextpkg.Context, connection *jsonrpc2.Conn, request *jsonrpc2.Request) {
+	if request.Method == "$/cancelRequest" {
+		self.server.cancelRequest(request)
+		return
+	}
+	if request.Notif {
+		self.handler.Handle(context, connection, request)
+		return
+	}
+
+	// the connection's context is not tied to the lifetime of a request
+	requestContext, cancel := contextpkg.WithCancel(contextpkg.WithoutCancel(context))
+	self.server.requestsMutex.Lock()
+	self.server.requests[request.ID] = cancel
+	self.server.requestsMutex.Unlock()
+	run := func() {
+		defer func() {
+			self.server.requestsMutex.Lock()
+			delete(self.server.requests, request.ID)
+			self.server.requestsMutex.Unlock()
+			cancel()
+		}()
+		self.handler.Handle(requestContext, connection, request)
+	}
+	if slices.Contains(self.server.AsyncMethods, request.Method) {
+		go run()
+	} else {
+		run()
+	}
+}
+
+// cancelRequest cancels the context of the request that the given
+// $/cancelRequest notification refers to if it is still running.
+func (self *Server) cancelRequest(request *jsonrpc2.Request) {
+	if request.Params == nil {
+		return
+	}
+	var params struct {
+		ID jsonrpc2.ID `json:"id"`
+	}
+	if err := json.Unmarshal(*request.Params, &params); err != nil {
+		return
+	}
+	self.requestsMutex.Lock()
+	defer self.requestsMutex.Unlock()
+	if cancel, ok := self.requests[params.ID]; ok {
+		cancel()
+	}
 }
 
 func (self *Server) handle(context contextpkg.Context, connection *jsonrpc2.Conn, request *jsonrpc2.Request) (any, error) {
@@ -1,9 +1,12 @@
 package server
 
 import (
+	contextpkg "context"
+	"sync"
 	"time"
 
 	"github.com/docker/docker-language-server/internal/tliron/glsp"
+	"github.com/sourcegraph/jsonrpc2"
 	"github.com/tliron/commonlog"
 )
 
@@ -23,6 +26,13 @@ type Server struct {
 	ReadTimeout   time.Duration
 	WriteTimeout  time.Duration
 	StreamTimeout time.Duration
+
+	// AsyncMethods are the methods whose requests are handled in their
+	// own goroutine so that they can be cancelled while they run.
+	AsyncMethods []string
+
+	requests      map[jsonrpc2.ID]contextpkg.CancelFunc
+	requestsMutex sync.Mutex
 }
 
 func NewServer(handler glsp.Handler, logName string, debug bool) *Server {
@@ -35,5 +45,6 @@ func NewServer(handler glsp.Handler, logName string, debug bool) *Server {
 		ReadTimeout:   DefaultTimeout,
 		WriteTimeout:  DefaultTimeout,
 		StreamTimeout: DefaultTimeout,
+		requests:      map[jsonrpc2.ID]contextpkg.CancelFunc{},
 	}
 }
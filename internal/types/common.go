@@ -15,6 +15,16 @@ const BakeBuildCommandId = "dockerLspClient.bake.build"
 
 const CodeActionDiagnosticCommandId = "server.textDocument.codeAction.diagnostics"
 
+const SortServiceKeysCommandId = "docker.compose.sortServiceKeys"
+
+const ToggleMappingFormCommandId = "docker.compose.toggleMappingForm"
+
+const StartupOrderCommandId = "docker.compose.startupOrder"
+
+const GenerateEnvTemplateCommandId = "docker.compose.generateEnvTemplate"
+
+const NormalizeReferencesCommandId = "docker.compose.normalizeReferences"
+
 const TelemetryCallbackCommandId = "dockerLspServer.telemetry.callback"
 
 func GitRepository(remoteUrl string) string {
@@ -56,14 +66,22 @@ func WorkspaceFolder(documentURI protocol.DocumentUri, workspaceFolders []string
 	length := 0
 	candidate := ""
 	for _, workspaceFolder := range workspaceFolders {
-		if strings.HasPrefix(parsed.Path, workspaceFolder) {
-			if length < len(workspaceFolder) {
-				length = len(workspaceFolder)
-				candidate = workspaceFolder
-			}
+		if !strings.HasPrefix(parsed.Path, workspaceFolder) {
+			continue
+		}
+		// a folder named /a/b must not claim the files of /a/bc
+		if !strings.HasSuffix(workspaceFolder, "/") && len(parsed.Path) > len(workspaceFolder) && parsed.Path[len(workspaceFolder)] != '/' {
+			continue
+		}
+		if length < len(workspaceFolder) {
+			length = len(workspaceFolder)
+			candidate = workspaceFolder
 		}
 	}
 
+	if candidate == "" {
+		return "", parsed.Path, ""
+	}
 	if strings.HasSuffix(candidate, "/") {
 		return candidate, parsed.Path, parsed.Path[length:]
 	}
@@ -101,6 +101,22 @@ func TestWorkspaceFolders(t *testing.T) {
 			absolutePath:     "/a/b/c/d/Dockerfile",
 			relativePath:     "d/Dockerfile",
 		},
+		{
+			name:             "sibling folder with a shared prefix",
+			uri:              "file:///a/b/c2/Dockerfile",
+			workspaceFolders: []string{"/a/b/c"},
+			folder:           "",
+			absolutePath:     "/a/b/c2/Dockerfile",
+			relativePath:     "",
+		},
+		{
+			name:             "multiple folders",
+			uri:              "file:///workspace/b/compose.yaml",
+			workspaceFolders: []string{"/workspace/a", "/workspace/b"},
+			folder:           "/workspace/b",
+			absolutePath:     "/workspace/b/compose.yaml",
+			relativePath:     "compose.yaml",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SemanticToken is a token of a document that should be highlighted.
+// The line and character of the token's start are zero-based and the
+// type is the index of the token's type in the server's legend.
+type SemanticToken struct {
+	Line      uint32
+	Character uint32
+	Length    uint32
+	Type      uint32
+}
+
+// EncodeSemanticTokens sorts the tokens by their positions and encodes
+// them into the relative format that the LSP specification requires
+// where each token's position is relative to the token before it.
+func EncodeSemanticTokens(tokens []SemanticToken) []uint32 {
+	tokens = slices.Clone(tokens)
+	slices.SortFunc(tokens, func(a, b SemanticToken) int {
+		if a.Line != b.Line {
+			return cmp.Compare(a.Line, b.Line)
+		}
+		return cmp.Compare(a.Character, b.Character)
+	})
+
+	data := []uint32{}
+	currentLine := uint32(0)
+	currentCharacter := uint32(0)
+	for _, token := range tokens {
+		if token.Line != currentLine {
+			currentCharacter = 0
+		}
+		data = append(data, token.Line-currentLine)
+		data = append(data, token.Character-currentCharacter)
+		data = append(data, token.Length)
+		data = append(data, token.Type)
+		data = append(data, uint32(0)) // no modifiers at the moment
+
+		currentLine = token.Line
+		currentCharacter = token.Character
+	}
+	return data
+}
@@ -183,12 +183,13 @@ func generateReleaseNotes(changelogPath string) (string, error) {
 		return "", fmt.Errorf("failed to read changelog: %w", err)
 	}
 
-	// Find the first two ## headers
+	// Find the first two ## headers, skipping the changes that have
+	// not been released yet
 	firstHeaderIndex := -1
 	secondHeaderIndex := -1
 
 	for i, line := range lines {
-		if strings.HasPrefix(line, "## ") {
+		if strings.HasPrefix(line, "## ") && strings.TrimSpace(line) != "## [Unreleased]" {
 			if firstHeaderIndex == -1 {
 				firstHeaderIndex = i
 			} else if secondHeaderIndex == -1 {